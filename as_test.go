@@ -0,0 +1,80 @@
+package godi
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type frenchGreeter struct{}
+
+func (frenchGreeter) String() string { return "bonjour" }
+
+func (frenchGreeter) Error() string { return "pas de bonjour" }
+
+func TestAs(t *testing.T) {
+	t.Run("it should only match the interfaces the component is exposed as", func(t *testing.T) {
+		// GIVEN
+		resolver := New()
+		resolver.MustRegister(func() englishGreeter { return englishGreeter{} })
+		resolver.MustRegister(func() frenchGreeter { return frenchGreeter{} }, As[error]())
+
+		// WHEN
+		stringer, err1 := Resolve[fmt.Stringer](resolver)
+		errComp, err2 := Resolve[error](resolver)
+		french, err3 := Resolve[frenchGreeter](resolver)
+
+		// THEN
+		require.NoError(t, err1)
+		require.NoError(t, err2)
+		require.NoError(t, err3)
+		assert.Equal(t, "hello", stringer.String())
+		assert.Equal(t, "pas de bonjour", errComp.Error())
+		assert.Equal(t, "bonjour", french.String())
+	})
+
+	t.Run("it should expose the component under several interfaces", func(t *testing.T) {
+		// GIVEN
+		resolver := New()
+		resolver.MustRegister(func() frenchGreeter { return frenchGreeter{} }, Named("greeter"), As[error](), As[fmt.Stringer]())
+		resolver.MustRegister(func() englishGreeter { return englishGreeter{} }, As[greeter]())
+
+		// WHEN
+		stringer, err1 := ResolveNamed[fmt.Stringer](resolver, "greeter")
+		_, err2 := ResolveNamed[error](resolver, "greeter")
+		_, found, err3 := TryResolve[fmt.Stringer](resolver)
+
+		// THEN
+		require.NoError(t, err1)
+		require.NoError(t, err2)
+		require.NoError(t, err3)
+		assert.Equal(t, "bonjour", stringer.String())
+		assert.True(t, found, "only the french greeter is exposed as a stringer")
+	})
+
+	t.Run("it should reject an interface not implemented by the component", func(t *testing.T) {
+		// GIVEN
+		resolver := New()
+
+		// WHEN
+		err := resolver.Register(func() englishGreeter { return englishGreeter{} }, As[error]())
+
+		// THEN
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "can not be exposed as error, it does not implement it")
+	})
+
+	t.Run("it should reject a type which is not an interface", func(t *testing.T) {
+		// GIVEN
+		resolver := New()
+
+		// WHEN
+		err := resolver.Register(func() englishGreeter { return englishGreeter{} }, As[englishGreeter]())
+
+		// THEN
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "components can only be exposed as interfaces")
+	})
+}
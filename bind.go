@@ -0,0 +1,35 @@
+package godi
+
+import (
+	"context"
+	"reflect"
+
+	"github.com/a-peyrard/godi/option"
+)
+
+// Bind registers the value as a component of type T, named after the type unless Named is given,
+// with the given registration options (Priority, Description...).
+//
+// The value is closed with the resolver (once resolved) if its concrete type is closeable or stoppable,
+// even if T is not, e.g. a file bound as an io.Reader, unless OwnedByContainer(false) is given.
+func Bind[T any](resolver *Resolver, value T, opts ...option.Option[RegistrableOptions]) error {
+	return BindNamed(resolver, TypeOf[T]().String(), value, opts...)
+}
+
+// BindNamed registers the value as a named component of type T, see Bind.
+func BindNamed[T any](resolver *Resolver, name string, value T, opts ...option.Option[RegistrableOptions]) error {
+	bindOpts := []option.Option[RegistrableOptions]{
+		Named(name),
+		closingConcreteValue(name, value),
+	}
+	return resolver.Register(ToStaticProvider(value), append(bindOpts, opts...)...)
+}
+
+// closingConcreteValue closes the value according to its concrete type, instead of the bound type.
+func closingConcreteValue[T any](name string, value T) option.Option[RegistrableOptions] {
+	return func(opts *RegistrableOptions) {
+		opts.onClose = func(ctx context.Context, _ reflect.Value) error {
+			return closeComponent(ctx, Name{name: name, typ: TypeOf[T]()}, reflect.ValueOf(value), nil)
+		}
+	}
+}
@@ -0,0 +1,85 @@
+package godi
+
+import (
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type closeableReader struct {
+	closed bool
+}
+
+func (c *closeableReader) Read([]byte) (int, error) {
+	return 0, io.EOF
+}
+
+func (c *closeableReader) Close() error {
+	c.closed = true
+	return nil
+}
+
+func TestBind(t *testing.T) {
+	t.Run("it should bind a value by type", func(t *testing.T) {
+		// GIVEN
+		resolver := New()
+		service := &TestService{Name: "bound"}
+
+		// WHEN
+		err := Bind(resolver, service)
+
+		// THEN
+		require.NoError(t, err)
+		resolved, err := Resolve[*TestService](resolver)
+		require.NoError(t, err)
+		assert.Same(t, service, resolved)
+	})
+
+	t.Run("it should bind a named value with options", func(t *testing.T) {
+		// GIVEN
+		resolver := New()
+		require.NoError(t, BindNamed(resolver, "url", "postgres://default"))
+
+		// WHEN
+		err := BindNamed(resolver, "url", "postgres://override", Priority(10), Description("the database url"))
+
+		// THEN
+		require.NoError(t, err)
+		url, err := ResolveNamed[string](resolver, "url")
+		require.NoError(t, err)
+		assert.Equal(t, "postgres://override", url)
+		assert.Contains(t, resolver.Describe(), "the database url")
+	})
+
+	t.Run("it should close the value according to its concrete type", func(t *testing.T) {
+		// GIVEN
+		resolver := New()
+		reader := &closeableReader{}
+		require.NoError(t, Bind[io.Reader](resolver, reader))
+		_, err := Resolve[io.Reader](resolver)
+		require.NoError(t, err)
+
+		// WHEN
+		err = resolver.Close()
+
+		// THEN
+		require.NoError(t, err)
+		assert.True(t, reader.closed)
+	})
+
+	t.Run("it should not bind the value if a condition does not hold", func(t *testing.T) {
+		// GIVEN
+		resolver := New()
+
+		// WHEN
+		err := Bind(resolver, &TestService{}, WhenOS("plan42"))
+
+		// THEN
+		require.NoError(t, err)
+		_, found, err := TryResolve[*TestService](resolver)
+		require.NoError(t, err)
+		assert.False(t, found)
+	})
+}
@@ -0,0 +1,131 @@
+package godi
+
+import (
+	"context"
+	"reflect"
+	"sync"
+)
+
+type (
+	// boundResolution is a construction in progress, the resolutions made by its provider through the resolver
+	// or the context injected into it continue the construction, so the cycles they introduce are detected,
+	// instead of waiting forever for the component being built.
+	boundResolution struct {
+		mu       sync.Mutex
+		tracker  *Tracker
+		released bool
+
+		// dependencies are the names of the stored components the component is built with, see Store.put
+		dependencies []Name
+	}
+
+	boundResolutionKey struct{}
+)
+
+// bind binds the construction of the component the tracker is on top of, until it is released.
+func bind(tracker *Tracker) *boundResolution {
+	return &boundResolution{tracker: tracker}
+}
+
+// release ends the construction, the resolver and the context injected into the provider might be kept by the
+// component, their later resolutions are independent.
+func (b *boundResolution) release() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.released = true
+}
+
+// dependsOn records that the component is built with the given stored component, unless the construction is done.
+func (b *boundResolution) dependsOn(name Name) {
+	if b == nil {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if !b.released {
+		b.dependencies = append(b.dependencies, name)
+	}
+}
+
+// builtWith returns the names of the stored components the component was built with.
+func (b *boundResolution) builtWith() []Name {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.dependencies
+}
+
+// active checks if the construction is in progress.
+func (b *boundResolution) active() bool {
+	if b == nil {
+		return false
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return !b.released
+}
+
+// continued returns a tracker continuing the construction, nil if there is no construction in progress.
+func (b *boundResolution) continued() *Tracker {
+	if b == nil {
+		return nil
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.released {
+		return nil
+	}
+	return NewTrackerFrom(b.tracker)
+}
+
+// boundTo returns the resolver injected into the provider of the construction.
+func (r *Resolver) boundTo(b *boundResolution) *Resolver {
+	return &Resolver{resolverState: r.resolverState, bound: b}
+}
+
+// bindDependency binds the resolver injected into a provider to its construction, the other dependencies are
+// returned as is.
+func (r *Resolver) bindDependency(dependency reflect.Value, tracker *Tracker) reflect.Value {
+	if tracker.bound == nil || !dependency.IsValid() || dependency.Type() != resolverType {
+		return dependency
+	}
+	resolver := dependency.Interface().(*Resolver)
+	if resolver == nil || resolver.resolverState != r.resolverState {
+		return dependency
+	}
+	return reflect.ValueOf(resolver.boundTo(tracker.bound))
+}
+
+// contextBoundTo returns the context injected into the provider of the construction.
+func contextBoundTo(ctx context.Context, b *boundResolution) context.Context {
+	if b == nil {
+		return ctx
+	}
+	return context.WithValue(ctx, boundResolutionKey{}, b)
+}
+
+func boundResolutionOf(ctx context.Context) *boundResolution {
+	if ctx == nil {
+		return nil
+	}
+	b, _ := ctx.Value(boundResolutionKey{}).(*boundResolution)
+	return b
+}
+
+// newTracker returns the tracker of a top level resolution, continuing the construction it is made from, if any,
+// i.e. when the resolution is made with the resolver or the context injected into a provider.
+func (r *Resolver) newTracker(ctx context.Context) *Tracker {
+	bound := boundResolutionOf(ctx)
+	if bound == nil {
+		bound = r.bound
+	}
+	tracker := bound.continued()
+	if tracker == nil {
+		tracker = NewTracker()
+	}
+	if ctx != nil {
+		tracker.ctx = ctx
+	} else if tracker.ctx == nil {
+		tracker.ctx = r.ctx
+	}
+	return tracker
+}
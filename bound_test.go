@@ -0,0 +1,92 @@
+package godi
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBoundResolution(t *testing.T) {
+	t.Run("it should detect a cycle introduced by a resolution through the injected resolver", func(t *testing.T) {
+		// GIVEN
+		resolver := New()
+		resolver.MustRegister(func(r *Resolver) (*TestService, error) {
+			repository, err := ResolveNamed[*TestRepository](r, "repository")
+			if err != nil {
+				return nil, err
+			}
+			return &TestService{Name: repository.Data}, nil
+		}, Named("service"))
+		resolver.MustRegister(func(*TestService) *TestRepository {
+			return &TestRepository{}
+		}, Named("repository"), Dependencies(Inject.Named("service")))
+
+		// WHEN
+		_, err := ResolveNamed[*TestService](resolver, "service")
+
+		// THEN
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "cycle found: (service, *godi.TestService) -> (repository, *godi.TestRepository) -> (service, *godi.TestService)")
+	})
+
+	t.Run("it should detect a cycle introduced by a resolution with the injected context", func(t *testing.T) {
+		// GIVEN
+		resolver := New().WithContext(context.Background())
+		resolver.MustRegister(func(ctx context.Context) (*TestService, error) {
+			repository, err := ResolveNamedCtx[*TestRepository](ctx, resolver, "repository")
+			if err != nil {
+				return nil, err
+			}
+			return &TestService{Name: repository.Data}, nil
+		}, Named("service"))
+		resolver.MustRegister(func(*TestService) *TestRepository {
+			return &TestRepository{}
+		}, Named("repository"), Dependencies(Inject.Named("service")))
+
+		// WHEN
+		_, err := ResolveNamed[*TestService](resolver, "service")
+
+		// THEN
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "cycle found: (service, *godi.TestService) -> (repository, *godi.TestRepository) -> (service, *godi.TestService)")
+	})
+
+	t.Run("it should resolve the components through the injected resolver", func(t *testing.T) {
+		// GIVEN
+		resolver := New()
+		resolver.MustRegister(func() *TestRepository { return &TestRepository{Data: "data"} }, Named("repository"))
+		resolver.MustRegister(func(r *Resolver) (*TestService, error) {
+			repository, err := ResolveNamed[*TestRepository](r, "repository")
+			if err != nil {
+				return nil, err
+			}
+			return &TestService{Name: repository.Data}, nil
+		}, Named("service"))
+
+		// WHEN
+		service, err := ResolveNamed[*TestService](resolver, "service")
+
+		// THEN
+		require.NoError(t, err)
+		assert.Equal(t, "data", service.Name)
+	})
+
+	t.Run("it should not track the resolutions made with the resolver kept by a component once it is built", func(t *testing.T) {
+		// GIVEN
+		type locator struct {
+			resolver *Resolver
+		}
+		resolver := New()
+		resolver.MustRegister(func(r *Resolver) *locator { return &locator{resolver: r} }, Named("locator"), Transient())
+		l, err := ResolveNamed[*locator](resolver, "locator")
+		require.NoError(t, err)
+
+		// WHEN
+		_, err = ResolveNamed[*locator](l.resolver, "locator")
+
+		// THEN
+		require.NoError(t, err)
+	})
+}
@@ -0,0 +1,166 @@
+package godi
+
+import (
+	"reflect"
+
+	"github.com/a-peyrard/godi/option"
+)
+
+// invoker calls a factory method directly, instead of through reflection, see Call0.
+type invoker struct {
+	// typ is the type of the factory method, to check it is the registered one
+	typ  reflect.Type
+	call func(args []reflect.Value) (reflect.Value, error)
+}
+
+// Call0 registers a factory method without parameter, called directly once its dependencies are resolved,
+// instead of through reflection. The generated registries use the CallN and CallNE options, see the -direct-calls
+// flag of the generator. The factory method must be the registered one, e.g.
+//
+//	resolver.MustRegister(NewService, godi.Call2E(NewService))
+func Call0[R any](fn func() R) option.Option[RegistrableOptions] {
+	return withInvoker(fn, func(_ []reflect.Value) (R, error) {
+		return fn(), nil
+	})
+}
+
+// Call0E is Call0 for a factory method returning an error.
+func Call0E[R any](fn func() (R, error)) option.Option[RegistrableOptions] {
+	return withInvoker(fn, func(_ []reflect.Value) (R, error) {
+		return fn()
+	})
+}
+
+// Call1 is Call0 for a factory method with 1 parameter.
+func Call1[A, R any](fn func(A) R) option.Option[RegistrableOptions] {
+	return withInvoker(fn, func(args []reflect.Value) (R, error) {
+		return fn(arg[A](args[0])), nil
+	})
+}
+
+// Call1E is Call1 for a factory method returning an error.
+func Call1E[A, R any](fn func(A) (R, error)) option.Option[RegistrableOptions] {
+	return withInvoker(fn, func(args []reflect.Value) (R, error) {
+		return fn(arg[A](args[0]))
+	})
+}
+
+// Call2 is Call0 for a factory method with 2 parameters.
+func Call2[A, B, R any](fn func(A, B) R) option.Option[RegistrableOptions] {
+	return withInvoker(fn, func(args []reflect.Value) (R, error) {
+		return fn(arg[A](args[0]), arg[B](args[1])), nil
+	})
+}
+
+// Call2E is Call2 for a factory method returning an error.
+func Call2E[A, B, R any](fn func(A, B) (R, error)) option.Option[RegistrableOptions] {
+	return withInvoker(fn, func(args []reflect.Value) (R, error) {
+		return fn(arg[A](args[0]), arg[B](args[1]))
+	})
+}
+
+// Call3 is Call0 for a factory method with 3 parameters.
+func Call3[A, B, C, R any](fn func(A, B, C) R) option.Option[RegistrableOptions] {
+	return withInvoker(fn, func(args []reflect.Value) (R, error) {
+		return fn(arg[A](args[0]), arg[B](args[1]), arg[C](args[2])), nil
+	})
+}
+
+// Call3E is Call3 for a factory method returning an error.
+func Call3E[A, B, C, R any](fn func(A, B, C) (R, error)) option.Option[RegistrableOptions] {
+	return withInvoker(fn, func(args []reflect.Value) (R, error) {
+		return fn(arg[A](args[0]), arg[B](args[1]), arg[C](args[2]))
+	})
+}
+
+// Call4 is Call0 for a factory method with 4 parameters.
+func Call4[A, B, C, D, R any](fn func(A, B, C, D) R) option.Option[RegistrableOptions] {
+	return withInvoker(fn, func(args []reflect.Value) (R, error) {
+		return fn(arg[A](args[0]), arg[B](args[1]), arg[C](args[2]), arg[D](args[3])), nil
+	})
+}
+
+// Call4E is Call4 for a factory method returning an error.
+func Call4E[A, B, C, D, R any](fn func(A, B, C, D) (R, error)) option.Option[RegistrableOptions] {
+	return withInvoker(fn, func(args []reflect.Value) (R, error) {
+		return fn(arg[A](args[0]), arg[B](args[1]), arg[C](args[2]), arg[D](args[3]))
+	})
+}
+
+// Call5 is Call0 for a factory method with 5 parameters.
+func Call5[A, B, C, D, E, R any](fn func(A, B, C, D, E) R) option.Option[RegistrableOptions] {
+	return withInvoker(fn, func(args []reflect.Value) (R, error) {
+		return fn(arg[A](args[0]), arg[B](args[1]), arg[C](args[2]), arg[D](args[3]), arg[E](args[4])), nil
+	})
+}
+
+// Call5E is Call5 for a factory method returning an error.
+func Call5E[A, B, C, D, E, R any](fn func(A, B, C, D, E) (R, error)) option.Option[RegistrableOptions] {
+	return withInvoker(fn, func(args []reflect.Value) (R, error) {
+		return fn(arg[A](args[0]), arg[B](args[1]), arg[C](args[2]), arg[D](args[3]), arg[E](args[4]))
+	})
+}
+
+// Call6 is Call0 for a factory method with 6 parameters.
+func Call6[A, B, C, D, E, F, R any](fn func(A, B, C, D, E, F) R) option.Option[RegistrableOptions] {
+	return withInvoker(fn, func(args []reflect.Value) (R, error) {
+		return fn(arg[A](args[0]), arg[B](args[1]), arg[C](args[2]), arg[D](args[3]), arg[E](args[4]), arg[F](args[5])), nil
+	})
+}
+
+// Call6E is Call6 for a factory method returning an error.
+func Call6E[A, B, C, D, E, F, R any](fn func(A, B, C, D, E, F) (R, error)) option.Option[RegistrableOptions] {
+	return withInvoker(fn, func(args []reflect.Value) (R, error) {
+		return fn(arg[A](args[0]), arg[B](args[1]), arg[C](args[2]), arg[D](args[3]), arg[E](args[4]), arg[F](args[5]))
+	})
+}
+
+// Call7 is Call0 for a factory method with 7 parameters.
+func Call7[A, B, C, D, E, F, G, R any](fn func(A, B, C, D, E, F, G) R) option.Option[RegistrableOptions] {
+	return withInvoker(fn, func(args []reflect.Value) (R, error) {
+		return fn(arg[A](args[0]), arg[B](args[1]), arg[C](args[2]), arg[D](args[3]), arg[E](args[4]), arg[F](args[5]), arg[G](args[6])), nil
+	})
+}
+
+// Call7E is Call7 for a factory method returning an error.
+func Call7E[A, B, C, D, E, F, G, R any](fn func(A, B, C, D, E, F, G) (R, error)) option.Option[RegistrableOptions] {
+	return withInvoker(fn, func(args []reflect.Value) (R, error) {
+		return fn(arg[A](args[0]), arg[B](args[1]), arg[C](args[2]), arg[D](args[3]), arg[E](args[4]), arg[F](args[5]), arg[G](args[6]))
+	})
+}
+
+// Call8 is Call0 for a factory method with 8 parameters.
+func Call8[A, B, C, D, E, F, G, H, R any](fn func(A, B, C, D, E, F, G, H) R) option.Option[RegistrableOptions] {
+	return withInvoker(fn, func(args []reflect.Value) (R, error) {
+		return fn(arg[A](args[0]), arg[B](args[1]), arg[C](args[2]), arg[D](args[3]), arg[E](args[4]), arg[F](args[5]), arg[G](args[6]), arg[H](args[7])), nil
+	})
+}
+
+// Call8E is Call8 for a factory method returning an error.
+func Call8E[A, B, C, D, E, F, G, H, R any](fn func(A, B, C, D, E, F, G, H) (R, error)) option.Option[RegistrableOptions] {
+	return withInvoker(fn, func(args []reflect.Value) (R, error) {
+		return fn(arg[A](args[0]), arg[B](args[1]), arg[C](args[2]), arg[D](args[3]), arg[E](args[4]), arg[F](args[5]), arg[G](args[6]), arg[H](args[7]))
+	})
+}
+
+func withInvoker[R any](fn any, call func(args []reflect.Value) (R, error)) option.Option[RegistrableOptions] {
+	inv := &invoker{
+		typ: reflect.TypeOf(fn),
+		call: func(args []reflect.Value) (reflect.Value, error) {
+			comp, err := call(args)
+			if err != nil {
+				return reflect.Value{}, err
+			}
+			return reflect.ValueOf(&comp).Elem(), nil
+		},
+	}
+	return func(opts *RegistrableOptions) {
+		opts.invoker = inv
+	}
+}
+
+// arg converts a resolved dependency to the type of the parameter, the zero value of an interface being nil.
+func arg[T any](v reflect.Value) T {
+	typed, _ := reflect.TypeAssert[T](v)
+	return typed
+}
@@ -0,0 +1,103 @@
+package godi
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCall(t *testing.T) {
+	newService := func(repository *TestRepository, stringer fmt.Stringer) (*TestService, error) {
+		if repository == nil {
+			return nil, errors.New("missing repository")
+		}
+		if stringer != nil {
+			return &TestService{Name: stringer.String()}, nil
+		}
+		return &TestService{Name: repository.Data}, nil
+	}
+
+	t.Run("it should call the factory method directly with its dependencies", func(t *testing.T) {
+		// GIVEN
+		newRepository := func() *TestRepository { return &TestRepository{Data: "data"} }
+		resolver := New()
+		resolver.MustRegister(newRepository, Call0(newRepository))
+		resolver.MustRegister(newService, Dependencies(Inject.Auto(), Inject.Auto().Optional()), Call2E(newService))
+
+		// WHEN
+		service, err := Resolve[*TestService](resolver)
+
+		// THEN
+		require.NoError(t, err)
+		assert.Equal(t, "data", service.Name)
+	})
+
+	t.Run("it should return the error of the factory method", func(t *testing.T) {
+		// GIVEN
+		resolver := New()
+		resolver.MustRegister(newService, Dependencies(Inject.Auto().Optional(), Inject.Auto().Optional()), Call2E(newService))
+
+		// WHEN
+		_, err := Resolve[*TestService](resolver)
+
+		// THEN
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "missing repository")
+	})
+
+	t.Run("it should keep the type of the result, even an interface", func(t *testing.T) {
+		// GIVEN
+		newCloseable := func() Closeable { return &TestService{Name: "closeable"} }
+		resolver := New()
+		resolver.MustRegister(newCloseable, Named("closeable"), Call0(newCloseable))
+
+		// WHEN
+		closeable, err := ResolveNamed[Closeable](resolver, "closeable")
+
+		// THEN
+		require.NoError(t, err)
+		assert.Equal(t, "closeable", closeable.(*TestService).Name)
+	})
+
+	t.Run("it should recover the panics of the factory method", func(t *testing.T) {
+		// GIVEN
+		newPanicking := func() *TestService { panic("boom") }
+		resolver := New()
+		resolver.MustRegister(newPanicking, Call0(newPanicking))
+
+		// WHEN
+		_, err := Resolve[*TestService](resolver)
+
+		// THEN
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "panic calling provider")
+	})
+
+	t.Run("it should fail to register if the direct call does not match the factory method", func(t *testing.T) {
+		// GIVEN
+		resolver := New()
+
+		// WHEN
+		err := resolver.Register(NewTestService, Call1(func(*TestRepository) *TestService { return nil }))
+
+		// THEN
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "does not match the factory method")
+	})
+}
+
+func BenchmarkResolve_TransientCalledDirectly(b *testing.B) {
+	newService := func(repository *TestRepository) *TestService {
+		return &TestService{Name: repository.Data}
+	}
+	resolver := New()
+	resolver.MustRegister(newService, Transient(), Call1(newService))
+	resolver.MustRegister(func() *TestRepository { return &TestRepository{} })
+
+	for b.Loop() {
+		_, _ = Resolve[*TestService](resolver)
+	}
+}
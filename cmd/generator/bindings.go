@@ -0,0 +1,210 @@
+package main
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// majorVersionRegex matches the major version suffix of an import path, which is not part of the package name.
+var majorVersionRegex = regexp.MustCompile(`^v[0-9]+$`)
+
+// TypeReference is a type named in an annotation, e.g. by the as property, resolved against the imports
+// of the file declaring the annotation.
+type TypeReference struct {
+	// ImportPath is empty for the predeclared types, e.g. error
+	ImportPath string
+	TypeName   string
+
+	// typ is the referenced type, once the types are loaded, see resolveBindingTypes
+	typ types.Type
+}
+
+func (r TypeReference) String() string {
+	if r.ImportPath == "" {
+		return r.TypeName
+	}
+	return r.ImportPath + "." + r.TypeName
+}
+
+// parseTypeReferences resolves the type names against the imports of the file: a qualified name must use the name
+// of one of its imports, and a name without qualifier is a type of the package, unless it is predeclared.
+func parseTypeReferences(file *ast.File, importPath string, names []string) (references []TypeReference, problems []string) {
+	for _, name := range names {
+		qualifier, typeName, qualified := strings.Cut(name, ".")
+		if !qualified {
+			if types.Universe.Lookup(name) != nil {
+				references = append(references, TypeReference{TypeName: name})
+			} else {
+				references = append(references, TypeReference{ImportPath: importPath, TypeName: name})
+			}
+			continue
+		}
+
+		imported, found := importedAs(file, qualifier)
+		if !found {
+			problems = append(problems, fmt.Sprintf("unknown package %q in as=%q, it must be imported by the file", qualifier, name))
+			continue
+		}
+		references = append(references, TypeReference{ImportPath: imported, TypeName: typeName})
+	}
+	return references, problems
+}
+
+// importedAs returns the path of the import of the file named so, by its alias or by the last element of its path.
+func importedAs(file *ast.File, name string) (importPath string, found bool) {
+	for _, spec := range file.Imports {
+		importPath, err := strconv.Unquote(spec.Path.Value)
+		if err != nil {
+			continue
+		}
+		if spec.Name != nil {
+			if spec.Name.Name == name {
+				return importPath, true
+			}
+			continue
+		}
+		base := path.Base(importPath)
+		if majorVersionRegex.MatchString(base) {
+			base = path.Base(path.Dir(importPath))
+		}
+		if base == name {
+			return importPath, true
+		}
+	}
+	return "", false
+}
+
+// hasBindings checks if a provider or a component is exposed as an interface.
+func hasBindings(providers []ProviderDefinition, components []ComponentDefinition) bool {
+	for _, p := range providers {
+		if len(p.As) > 0 {
+			return true
+		}
+	}
+	for _, c := range components {
+		if len(c.As) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// resolveBindingTypes types the providers and the components exposed as interfaces, along with the interfaces,
+// to check them with validateBindings. The packages declaring them are loaded again with their types,
+// unless they already are.
+func resolveBindingTypes(providers []ProviderDefinition, components []ComponentDefinition, typedPackages map[string]*packages.Package) error {
+	if !hasBindings(providers, components) {
+		return nil
+	}
+	if typedPackages == nil {
+		var importPaths []string
+		for _, p := range providers {
+			if len(p.As) > 0 {
+				importPaths = append(importPaths, p.ImportPath)
+			}
+		}
+		for _, c := range components {
+			if len(c.As) > 0 {
+				importPaths = append(importPaths, c.ImportPath)
+			}
+		}
+		pkgs, err := packages.Load(&packages.Config{Mode: typedLoadMode}, importPaths...)
+		if err != nil {
+			return fmt.Errorf("failed to load the types of the packages exposing components as interfaces:\n\t%w", err)
+		}
+		typedPackages = make(map[string]*packages.Package, len(pkgs))
+		for _, pkg := range pkgs {
+			typedPackages[pkg.ID] = pkg
+		}
+	}
+
+	for i, p := range providers {
+		pkg, found := typedPackages[p.ImportPath]
+		if len(p.As) == 0 || !found || pkg.Types == nil {
+			continue
+		}
+		if fn, ok := pkg.Types.Scope().Lookup(p.FnName).(*types.Func); ok && p.signature == nil {
+			providers[i].signature = fn.Type().(*types.Signature)
+		}
+		for j, ref := range p.As {
+			providers[i].As[j].typ = lookupType(pkg, ref)
+		}
+	}
+	for i, c := range components {
+		pkg, found := typedPackages[c.ImportPath]
+		if len(c.As) == 0 || !found || pkg.Types == nil {
+			continue
+		}
+		if obj, ok := pkg.Types.Scope().Lookup(c.TypeName).(*types.TypeName); ok && c.typ == nil {
+			components[i].typ = obj.Type()
+		}
+		for j, ref := range c.As {
+			components[i].As[j].typ = lookupType(pkg, ref)
+		}
+	}
+	return nil
+}
+
+// lookupType returns the referenced type, as seen from the package, nil if it is not found.
+func lookupType(pkg *packages.Package, ref TypeReference) types.Type {
+	scope := types.Universe
+	if ref.ImportPath == pkg.ID || ref.ImportPath == pkg.PkgPath {
+		scope = pkg.Types.Scope()
+	} else if ref.ImportPath != "" {
+		imported, found := pkg.Imports[ref.ImportPath]
+		if !found || imported.Types == nil {
+			return nil
+		}
+		scope = imported.Types.Scope()
+	}
+	if obj, ok := scope.Lookup(ref.TypeName).(*types.TypeName); ok {
+		return obj.Type()
+	}
+	return nil
+}
+
+// validateBindings checks that the providers and the components implement the interfaces they are exposed as,
+// returning the problems found, prefixed with their position. The definitions must be typed,
+// see resolveBindingTypes.
+func validateBindings(defs Definitions) []string {
+	var problems []string
+	validate := func(position token.Position, source string, provided types.Type, refs []TypeReference) {
+		if provided == nil || provided == types.Typ[types.Invalid] {
+			return // not typed, or the package does not type-check
+		}
+		for _, ref := range refs {
+			if ref.typ == nil {
+				problems = append(problems, fmt.Sprintf("%s:%d: %s is exposed as %s, but the type is not found", position.Filename, position.Line, source, ref))
+				continue
+			}
+			iface, ok := ref.typ.Underlying().(*types.Interface)
+			if !ok {
+				problems = append(problems, fmt.Sprintf("%s:%d: %s is exposed as %s, which is not an interface", position.Filename, position.Line, source, ref))
+				continue
+			}
+			if !types.Implements(provided, iface) {
+				problems = append(problems, fmt.Sprintf("%s:%d: %s provides %s, which does not implement %s", position.Filename, position.Line, source, provided, ref))
+			}
+		}
+	}
+
+	for _, p := range defs.Providers {
+		if p.signature != nil && p.signature.Results().Len() > 0 {
+			validate(p.position, p.FnName, p.signature.Results().At(0).Type(), p.As)
+		}
+	}
+	for _, c := range defs.Components {
+		if c.typ != nil {
+			validate(c.position, c.TypeName, types.NewPointer(c.typ), c.As)
+		}
+	}
+	return problems
+}
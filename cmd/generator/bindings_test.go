@@ -0,0 +1,119 @@
+package main
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const invalidBindings = `package registry
+
+type Clock interface {
+	Now() int64
+}
+
+// @provider named="service" as="Clock, Missing, sqlRepository"
+func NewService() *Service {
+	return &Service{}
+}
+
+type Service struct{}
+`
+
+func Test_parseTypeReferences(t *testing.T) {
+	parse := func(t *testing.T, src string) *ast.File {
+		file, err := parser.ParseFile(token.NewFileSet(), "provider.go", src, parser.ImportsOnly)
+		require.NoError(t, err)
+		return file
+	}
+
+	t.Run("it should resolve the types against the imports of the file", func(t *testing.T) {
+		// GIVEN
+		file := parse(t, `package services
+
+import (
+	"io"
+	repo "github.com/acme/app/repository"
+	"github.com/acme/app/cache/v2"
+)`)
+
+		// WHEN
+		references, problems := parseTypeReferences(file, "github.com/acme/app/services", []string{"io.Closer", "repo.Repository", "cache.Cache", "Service", "error"})
+
+		// THEN
+		assert.Empty(t, problems)
+		assert.Equal(t, []TypeReference{
+			{ImportPath: "io", TypeName: "Closer"},
+			{ImportPath: "github.com/acme/app/repository", TypeName: "Repository"},
+			{ImportPath: "github.com/acme/app/cache/v2", TypeName: "Cache"},
+			{ImportPath: "github.com/acme/app/services", TypeName: "Service"},
+			{TypeName: "error"},
+		}, references)
+	})
+
+	t.Run("it should report the packages not imported by the file", func(t *testing.T) {
+		// GIVEN
+		file := parse(t, "package services\n")
+
+		// WHEN
+		references, problems := parseTypeReferences(file, "github.com/acme/app/services", []string{"io.Closer"})
+
+		// THEN
+		assert.Empty(t, references)
+		assert.Equal(t, []string{`unknown package "io" in as="io.Closer", it must be imported by the file`}, problems)
+	})
+}
+
+func Test_validateBindings(t *testing.T) {
+	t.Run("it should accept the components implementing the interfaces they are exposed as", func(t *testing.T) {
+		// GIVEN
+		tempDir := setupTestProject(t, "as_bindings")
+		t.Chdir(tempDir)
+		defs := scanModule(zerolog.Nop(), tempDir, &RegistryDefinition{Include: []string{"./..."}}, scanOptions{})
+
+		// WHEN
+		problems := validateBindings(defs)
+
+		// THEN
+		assert.Empty(t, problems)
+	})
+
+	t.Run("it should report the components not implementing the interfaces they are exposed as", func(t *testing.T) {
+		// GIVEN
+		tempDir := setupTestProject(t, "as_bindings")
+		serviceFile := filepath.Join(tempDir, "service.go")
+		require.NoError(t, os.WriteFile(serviceFile, []byte(invalidBindings), 0644))
+		t.Chdir(tempDir)
+		defs := scanModule(zerolog.Nop(), tempDir, &RegistryDefinition{Include: []string{"./..."}}, scanOptions{})
+
+		// WHEN
+		problems := validateBindings(defs)
+
+		// THEN
+		assert.Equal(t, []string{
+			serviceFile + ":8: NewService provides *github.com/test/bindings.Service, which does not implement github.com/test/bindings.Clock",
+			serviceFile + ":8: NewService is exposed as github.com/test/bindings.Missing, but the type is not found",
+			serviceFile + ":8: NewService is exposed as github.com/test/bindings.sqlRepository, which is not an interface",
+		}, problems)
+	})
+
+	t.Run("it should fail the generation if a component does not implement an interface it is exposed as", func(t *testing.T) {
+		// GIVEN
+		tempDir := setupTestProject(t, "as_bindings")
+		require.NoError(t, os.WriteFile(filepath.Join(tempDir, "service.go"), []byte(invalidBindings), 0644))
+
+		// WHEN
+		err := runGenerator(t, findScriptPath(), tempDir, nil)
+
+		// THEN
+		require.Error(t, err)
+		assert.NoFileExists(t, filepath.Join(tempDir, "registry_gen.go"))
+	})
+}
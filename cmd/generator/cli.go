@@ -0,0 +1,498 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"path/filepath"
+	stdslices "slices"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/a-peyrard/godi"
+	"github.com/rs/zerolog"
+)
+
+const defaultCommand = "gen"
+
+type (
+	// command is a sub command of the generator, its setup registers its own flags, and returns the function
+	// running it once the flags are parsed.
+	command struct {
+		description string
+		setup       func(fs *flag.FlagSet) commandFunc
+		// noTarget is set for the commands not working on a registry, the target being left empty
+		noTarget bool
+	}
+
+	commandFunc func(logger zerolog.Logger, target target, options scanOptions, stdout io.Writer) error
+
+	// target is the file declaring the registries, along with the module it belongs to.
+	target struct {
+		filePath   string
+		moduleRoot string
+		registries []*RegistryDefinition
+	}
+)
+
+var commands = map[string]command{
+	"gen": {
+		description: "generate the registration code of the registries (default)",
+		setup:       genCommand,
+	},
+	"graph": {
+		description: "generate the dependency graph of the registries",
+		setup:       graphCommand,
+	},
+	"lint": {
+		description: "report the problems of the annotations, without generating anything",
+		setup:       lintCommand,
+	},
+	"describe": {
+		description: "list the components registered by the registries",
+		setup:       describeCommand,
+	},
+	"diff": {
+		description: "report the providers changed between two descriptions of a resolver dumped as JSON",
+		setup:       diffCommand,
+		noTarget:    true,
+	},
+}
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdout))
+}
+
+// run runs the command named by the first argument, gen if the first argument is a flag, as when the generator
+// is invoked by go generate. The flags default to the env variables, GOFILE being the target file.
+func run(args []string, stdout io.Writer) int {
+	name := defaultCommand
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		name, args = args[0], args[1:]
+	}
+	cmd, found := commands[name]
+	if !found {
+		printUsage(os.Stderr)
+		return 2
+	}
+
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	file := fs.String("file", os.Getenv("GOFILE"), "the file declaring the registries, defaults to $GOFILE")
+	scope := fs.String("scope", "", "comma separated package patterns to scan, overriding the @registry annotation")
+	exclude := fs.String("exclude", "", "comma separated package patterns to skip, overriding the @registry annotation")
+	logLevel := fs.String("log-level", envOr("GODI_LOG_LEVEL", "debug"), "the log level: debug, info, warn or error")
+	envBindings := fs.Bool("env-bindings", envBool("GODI_ENV_BINDINGS"), "register an env binding for the unresolved named injections looking like env vars")
+	envPrefix := fs.String("env-prefix", os.Getenv("GODI_ENV_PREFIX"), "the prefix of the env vars looked up by the env bindings")
+	configKeys := fs.Bool("config-keys", envBool("GODI_CONFIG_KEYS"), "generate the cfgkeys package exposing the names of the config fields")
+	runCommand := cmd.setup(fs)
+	fs.Usage = func() {
+		_, _ = fmt.Fprintf(fs.Output(), "Usage: generator %s [flags]\n\n%s.\n\nFlags:\n", name, cmd.description)
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return 0
+		}
+		return 2
+	}
+
+	level, err := zerolog.ParseLevel(*logLevel)
+	if err != nil {
+		_, _ = fmt.Fprintf(os.Stderr, "invalid log level %q\n", *logLevel)
+		return 2
+	}
+	logger := zerolog.New(zerolog.ConsoleWriter{Out: os.Stderr, TimeFormat: time.DateTime}).
+		Level(level).
+		With().
+		Timestamp().
+		Logger()
+
+	var target target
+	if !cmd.noTarget {
+		if target, err = findTarget(&logger, *file, *scope, *exclude); err != nil {
+			logger.Error().Msgf("❌ %s", err)
+			return 1
+		}
+	}
+	options := scanOptions{
+		envBindings:       *envBindings,
+		envBindingsPrefix: *envPrefix,
+		configKeys:        *configKeys,
+	}
+	if err := runCommand(logger, target, options, stdout); err != nil {
+		logger.Error().Msgf("❌ %s", err)
+		return 1
+	}
+	return 0
+}
+
+func printUsage(w io.Writer) {
+	_, _ = fmt.Fprintf(w, "Usage: generator <command> [flags]\n\nCommands:\n")
+	names := stdslices.Sorted(maps.Keys(commands))
+	for _, name := range names {
+		_, _ = fmt.Fprintf(w, "  %-10s %s\n", name, commands[name].description)
+	}
+	_, _ = fmt.Fprintf(w, "\nRun generator <command> -h for the flags of a command.\n")
+}
+
+// findTarget looks up the registries declared in the file, and switches to the root of its module,
+// as the scan patterns are relative to it.
+func findTarget(logger *zerolog.Logger, file string, scope string, exclude string) (target, error) {
+	if file == "" {
+		return target{}, errors.New("no target file, set it with -file, or run the generator with go generate")
+	}
+	filePath, err := filepath.Abs(file)
+	if err != nil {
+		return target{}, fmt.Errorf("failed to locate the target file %s:\n\t%w", file, err)
+	}
+
+	moduleRoot := findModuleRoot(filepath.Dir(filePath))
+	if err := os.Chdir(moduleRoot); err != nil {
+		return target{}, fmt.Errorf("failed to change directory to module root:\n\t%w", err)
+	}
+
+	// the registries are looked up first, as they might restrict the scope of the scan
+	registries := findRegistries(logger, filePath)
+	if len(registries) == 0 {
+		return target{}, fmt.Errorf("no Registry struct found in %s, make sure you have a struct like this:\ntype Registry {\n    godi.EmptyRegistry\n}", filePath)
+	}
+	for _, registry := range registries {
+		if scope != "" {
+			registry.Include = splitList(scope)
+		}
+		if exclude != "" {
+			registry.Exclude = splitList(exclude)
+		}
+	}
+
+	return target{filePath: filePath, moduleRoot: moduleRoot, registries: registries}, nil
+}
+
+// scan scans the scope of each registry, reporting the problems of the annotations,
+// which are errors in strict mode.
+func (t target) scan(logger zerolog.Logger, options scanOptions, strict bool) ([]Definitions, error) {
+	definitions := make([]Definitions, 0, len(t.registries))
+	for _, registry := range t.registries {
+		logger := logger.With().Str("registry", registry.StructName).Logger()
+		defs := scanModule(logger, t.moduleRoot, registry, options)
+		if len(defs.Diagnostics) > 0 {
+			if strict {
+				return nil, fmt.Errorf("the annotations have problems:\n%s", strings.Join(defs.Diagnostics, "\n"))
+			}
+			logger.Warn().Msgf("⚠️ the annotations have problems:\n%s", strings.Join(defs.Diagnostics, "\n"))
+		}
+		definitions = append(definitions, defs)
+	}
+	return definitions, nil
+}
+
+// outputPath returns the path of the code generated for the registry, out if set, which requires a single registry.
+func (t target) outputPath(registry *RegistryDefinition, out string) (string, error) {
+	if out == "" {
+		return registryOutputPath(t.filePath, registry, len(t.registries) > 1), nil
+	}
+	if len(t.registries) > 1 {
+		return "", fmt.Errorf("the output path can not be set, %s declares %d registries", t.filePath, len(t.registries))
+	}
+	return filepath.Abs(out)
+}
+
+func genCommand(fs *flag.FlagSet) commandFunc {
+	out := fs.String("out", "", "the path of the generated file, defaults to <file>_gen.go")
+	dryRun := fs.Bool("dry-run", envBool("DRY_RUN"), "write the generated files in /tmp instead")
+	check := fs.Bool("check", envBool("GODI_CHECK"), "check that the generated code is up-to-date, without writing anything, printing the diff if not")
+	validate := fs.Bool("validate", false, "load the types of the packages, and fail if the annotations do not match them")
+	strict := fs.Bool("strict", envBool("GODI_STRICT"), "fail if the annotations have problems (unknown properties, invalid values...)")
+	directCalls := fs.Bool("direct-calls", envBool("GODI_DIRECT_CALLS"), "call the providers directly instead of through reflection, with the godi.CallN options")
+	wire := fs.Bool("wire", envBool("GODI_WIRE"), "wire the static part of the graph with direct calls of the providers, built all at once on first use (loads the types, implies -direct-calls)")
+	graph := fs.String("graph", os.Getenv("GODI_GRAPH"), "also generate the dependency graph of the registry, as dot or svg (requires Graphviz)")
+	splitPackages := fs.Bool("split-packages", envBool("GODI_SPLIT_PACKAGES"), "generate one registration file per scanned package")
+	configManifest := fs.String("config-manifest", os.Getenv("GODI_CONFIG_MANIFEST"), "also generate the documentation of the config structs and their env vars, as md or json")
+
+	return func(logger zerolog.Logger, target target, options scanOptions, stdout io.Writer) error {
+		// the static part of the graph is found with the types, among the providers called directly, see findWiring
+		options.typed = *validate || *wire
+		options.directCalls = *directCalls || *wire
+		definitions, err := target.scan(logger, options, *strict)
+		if err != nil {
+			return err
+		}
+
+		// each registry is generated in its own file if the target file declares several of them
+		files := make(map[string][]byte)
+		for i, defs := range definitions {
+			if problems := validateBindings(defs); len(problems) > 0 {
+				return fmt.Errorf("the components do not implement the interfaces they are exposed as:\n%s", strings.Join(problems, "\n"))
+			}
+			if *validate {
+				if problems := validateTypes(defs); len(problems) > 0 {
+					return fmt.Errorf("the annotations do not match the types:\n%s", strings.Join(problems, "\n"))
+				}
+			}
+
+			outputPath, err := target.outputPath(target.registries[i], *out)
+			if err != nil {
+				return err
+			}
+			registryFiles, err := renderFiles(outputPath, defs, *splitPackages, *wire)
+			if err != nil {
+				return fmt.Errorf("failed to render code:\n\t%w", err)
+			}
+			for path, code := range registryFiles {
+				if code == nil && files[path] != nil {
+					continue // generated for another registry, so not stale
+				}
+				files[path] = code
+			}
+
+			if *graph != "" {
+				graphFile, err := renderGraphFile(defs, *graph)
+				if err != nil {
+					return fmt.Errorf("failed to render the dependency graph:\n\t%w", err)
+				}
+				files[graphOutputPath(outputPath, *graph)] = graphFile
+			}
+			if *configManifest != "" {
+				manifest, err := renderConfigManifest(defs.Configs, *configManifest)
+				if err != nil {
+					return fmt.Errorf("failed to render the config manifest:\n\t%w", err)
+				}
+				files[configManifestPath(outputPath, *configManifest)] = manifest
+			}
+		}
+
+		if *check {
+			upToDate, summary, diff, err := checkCode(files, target.moduleRoot)
+			if err != nil {
+				return fmt.Errorf("failed to check generated code:\n\t%w", err)
+			}
+			if !upToDate {
+				if _, err := io.WriteString(stdout, diff); err != nil {
+					return err
+				}
+				return fmt.Errorf("generated code is out-of-date, run go generate to update it:\n%s", summary)
+			}
+			logger.Info().Msg("✅ generated code is up-to-date")
+			return nil
+		}
+
+		if *dryRun {
+			dryRunFiles := make(map[string][]byte, len(files))
+			for path, code := range files {
+				if code == nil {
+					continue // nothing to delete in /tmp
+				}
+				name := filepath.Base(path)
+				if *splitPackages {
+					// avoid collisions between the registration files of the different packages
+					name = filepath.Base(filepath.Dir(path)) + "_" + name
+				}
+				dryRunFiles[filepath.Join("/tmp", name)] = code
+			}
+			files = dryRunFiles
+		}
+
+		if err := generateCode(files); err != nil {
+			return fmt.Errorf("failed to generate code:\n\t%w", err)
+		}
+		for path, code := range files {
+			if code == nil {
+				logger.Info().Msgf("🧹 Stale code removed from %s", path)
+				continue
+			}
+			logger.Info().Msgf("✅ Code generated successfully in %s", path)
+		}
+		return nil
+	}
+}
+
+func graphCommand(fs *flag.FlagSet) commandFunc {
+	format := fs.String("format", graphFormatDot, "the format of the graph, dot or svg (requires Graphviz)")
+	out := fs.String("out", "", "the path of the graph, defaults to <file>_graph.<format>")
+	dryRun := fs.Bool("dry-run", envBool("DRY_RUN"), "print the graph instead of writing it")
+
+	return func(logger zerolog.Logger, target target, options scanOptions, stdout io.Writer) error {
+		definitions, err := target.scan(logger, options, false)
+		if err != nil {
+			return err
+		}
+
+		files := make(map[string][]byte)
+		for i, defs := range definitions {
+			graph, err := renderGraphFile(defs, *format)
+			if err != nil {
+				return fmt.Errorf("failed to render the dependency graph:\n\t%w", err)
+			}
+			if *dryRun {
+				if _, err := stdout.Write(graph); err != nil {
+					return err
+				}
+				continue
+			}
+
+			path := graphOutputPath(registryOutputPath(target.filePath, target.registries[i], len(target.registries) > 1), *format)
+			if *out != "" {
+				if path, err = target.outputPath(target.registries[i], *out); err != nil {
+					return err
+				}
+			}
+			files[path] = graph
+		}
+
+		if err := generateCode(files); err != nil {
+			return fmt.Errorf("failed to write the dependency graph:\n\t%w", err)
+		}
+		for path := range files {
+			logger.Info().Msgf("✅ Dependency graph generated successfully in %s", path)
+		}
+		return nil
+	}
+}
+
+func lintCommand(fs *flag.FlagSet) commandFunc {
+	typed := fs.Bool("types", true, "load the types of the packages, to check the annotations against them")
+
+	return func(logger zerolog.Logger, target target, options scanOptions, stdout io.Writer) error {
+		options.typed = *typed
+		definitions, err := target.scan(logger, options, false)
+		if err != nil {
+			return err
+		}
+
+		var problems []string
+		for _, defs := range definitions {
+			problems = append(problems, defs.Diagnostics...)
+			problems = append(problems, validateConfigInjections(defs.Providers, defs.Decorators, defs.Configs)...)
+			problems = append(problems, validateTypes(defs)...)
+			problems = append(problems, validateBindings(defs)...)
+		}
+		for _, problem := range problems {
+			if _, err := fmt.Fprintln(stdout, problem); err != nil {
+				return err
+			}
+		}
+		if len(problems) > 0 {
+			return fmt.Errorf("%d problems found in the annotations", len(problems))
+		}
+		logger.Info().Msg("✅ no problem found in the annotations")
+		return nil
+	}
+}
+
+func describeCommand(_ *flag.FlagSet) commandFunc {
+	return func(logger zerolog.Logger, target target, options scanOptions, stdout io.Writer) error {
+		definitions, err := target.scan(logger, options, false)
+		if err != nil {
+			return err
+		}
+		for i, defs := range definitions {
+			if i > 0 {
+				_, _ = fmt.Fprintln(stdout)
+			}
+			if err := describe(stdout, defs); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+// describe writes a table of the components registered by the registry.
+func describe(w io.Writer, defs Definitions) error {
+	packageName := func(importPath string) string {
+		return defs.Packages[importPath].Name
+	}
+	dependencies := func(injections []InjectAnnotation) string {
+		var described []string
+		for _, injection := range injections {
+			dependency := injection.qualifiedType
+			if named, found := injection.Named(); found {
+				dependency = named
+			}
+			if multiple, _ := injection.Multiple(); multiple {
+				dependency += " (multiple)"
+			}
+			if optional, _ := injection.Optional(); optional {
+				dependency += " (optional)"
+			}
+			described = append(described, dependency)
+		}
+		return strings.Join(described, ", ")
+	}
+
+	_, _ = fmt.Fprintf(w, "%s.%s (scope: %s)\n", defs.Registry.PackageName, defs.Registry.StructName, strings.Join(defs.Registry.Include, ", "))
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	_, _ = fmt.Fprintln(tw, "KIND\tNAME\tSOURCE\tPRIORITY\tDEPENDENCIES")
+	for _, p := range defs.Providers {
+		_, _ = fmt.Fprintf(tw, "provider\t%s\t%s.%s\t%d\t%s\n", p.Named, packageName(p.ImportPath), p.FnName, p.Priority, dependencies(p.Dependencies))
+	}
+	for _, c := range defs.Components {
+		named := c.Named
+		if named == "" {
+			named = c.TypeName
+		}
+		_, _ = fmt.Fprintf(tw, "component\t%s\t%s.%s\t%d\t\n", named, packageName(c.ImportPath), c.TypeName, c.Priority)
+	}
+	for _, d := range defs.Decorators {
+		_, _ = fmt.Fprintf(tw, "decorator\t%s\t%s.%s\t%d\t%s\n", d.Decorate, packageName(d.ImportPath), d.FnName, d.Priority, dependencies(d.Dependencies))
+	}
+	for _, c := range defs.Configs {
+		_, _ = fmt.Fprintf(tw, "config\t%s\t%s.%s\t0\t\n", c.TypeName, packageName(c.ImportPath), c.TypeName)
+	}
+	return tw.Flush()
+}
+
+func diffCommand(fs *flag.FlagSet) commandFunc {
+	check := fs.Bool("check", false, "fail if the providers changed, e.g. to catch an unexpected drift in CI")
+	return func(_ zerolog.Logger, _ target, _ scanOptions, stdout io.Writer) error {
+		if fs.NArg() != 2 {
+			return fmt.Errorf("expected the descriptions before and after, got %d arguments", fs.NArg())
+		}
+		before, err := readDescription(fs.Arg(0))
+		if err != nil {
+			return err
+		}
+		after, err := readDescription(fs.Arg(1))
+		if err != nil {
+			return err
+		}
+
+		diff := godi.DiffDescriptions(before, after)
+		_, _ = fmt.Fprint(stdout, diff)
+		if *check && !diff.IsEmpty() {
+			return fmt.Errorf("the providers changed between %s and %s", fs.Arg(0), fs.Arg(1))
+		}
+		return nil
+	}
+}
+
+// readDescription reads the description of a resolver dumped by godi.Resolver.DescribeJSON.
+func readDescription(path string) (godi.ContainerDescription, error) {
+	var description godi.ContainerDescription
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return description, fmt.Errorf("failed to read the description %s:\n\t%w", path, err)
+	}
+	if err := json.Unmarshal(content, &description); err != nil {
+		return description, fmt.Errorf("failed to decode the description %s:\n\t%w", path, err)
+	}
+	return description, nil
+}
+
+func envOr(key string, fallback string) string {
+	if value, found := os.LookupEnv(key); found {
+		return value
+	}
+	return fallback
+}
+
+func envBool(key string) bool {
+	value, _ := strconv.ParseBool(os.Getenv(key))
+	return value
+}
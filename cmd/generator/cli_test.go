@@ -0,0 +1,136 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_run(t *testing.T) {
+	goldenDir, err := filepath.Abs(filepath.Join("etc", "gen"))
+	require.NoError(t, err)
+
+	t.Run("it should generate the code of the target file outside of go generate", func(t *testing.T) {
+		// GIVEN
+		tempDir := setupTestProject(t, "simple_provider")
+		out := filepath.Join(tempDir, "wiring", "godi_gen.go")
+		t.Chdir(t.TempDir())
+		t.Setenv("GOFILE", "")
+
+		// WHEN
+		code := run([]string{"gen", "-file", filepath.Join(tempDir, "registry.go"), "-out", out, "-log-level", "error"}, &bytes.Buffer{})
+
+		// THEN
+		require.Equal(t, 0, code)
+		assertGeneratedFile(t, out, filepath.Join(goldenDir, "simple_provider", "expected_gen.go.golden"))
+		assert.NoFileExists(t, filepath.Join(tempDir, "registry_gen.go"))
+	})
+
+	t.Run("it should run gen by default, and take the target file from GOFILE", func(t *testing.T) {
+		// GIVEN
+		tempDir := setupTestProject(t, "simple_provider")
+		t.Chdir(tempDir)
+		t.Setenv("GOFILE", "registry.go")
+
+		// WHEN
+		code := run([]string{"--check", "-log-level", "error"}, &bytes.Buffer{})
+
+		// THEN
+		assert.Equal(t, 1, code, "the generated code does not exist yet")
+	})
+
+	t.Run("it should print the diff of the stale generated code in check mode", func(t *testing.T) {
+		// GIVEN
+		tempDir := setupTestProject(t, "simple_provider")
+		t.Chdir(tempDir)
+		golden, err := os.ReadFile(filepath.Join(goldenDir, "simple_provider", "expected_gen.go.golden"))
+		require.NoError(t, err)
+		stale := bytes.Replace(golden, []byte("resolver.MustRegister("), []byte("resolver.Register("), 1)
+		require.NoError(t, os.WriteFile(filepath.Join(tempDir, "registry_gen.go"), stale, 0644))
+		var stdout bytes.Buffer
+
+		// WHEN
+		code := run([]string{"gen", "-file", "registry.go", "-check", "-log-level", "error"}, &stdout)
+
+		// THEN
+		assert.Equal(t, 1, code)
+		assert.Contains(t, stdout.String(), "--- a/registry_gen.go\n+++ b/registry_gen.go\n")
+		assert.Contains(t, stdout.String(), "\n-\tresolver.Register(\n+\tresolver.MustRegister(\n")
+	})
+
+	t.Run("it should describe the components of the registry", func(t *testing.T) {
+		// GIVEN
+		tempDir := setupTestProject(t, "complex")
+		t.Chdir(tempDir)
+		var stdout bytes.Buffer
+
+		// WHEN
+		code := run([]string{"describe", "-file", filepath.Join("registry", "registry.go"), "-log-level", "error"}, &stdout)
+
+		// THEN
+		require.Equal(t, 0, code)
+		assertGeneratedContent(t, stdout.Bytes(), filepath.Join(goldenDir, "complex", "expected_describe.golden"))
+	})
+
+	t.Run("it should report the problems of the annotations", func(t *testing.T) {
+		// GIVEN
+		tempDir := setupTestProject(t, "diagnostics")
+		t.Chdir(tempDir)
+		var stdout bytes.Buffer
+
+		// WHEN
+		code := run([]string{"lint", "-file", "registry.go", "-types=false", "-log-level", "error"}, &stdout)
+
+		// THEN
+		assert.Equal(t, 1, code)
+		assert.Contains(t, stdout.String(), filepath.Join(tempDir, "provider.go")+`:10: invalid priority "high" in @provider, expecting an integer`)
+	})
+
+	t.Run("it should print the dependency graph in dry-run mode", func(t *testing.T) {
+		// GIVEN
+		tempDir := setupTestProject(t, "complex")
+		t.Chdir(tempDir)
+		var stdout bytes.Buffer
+
+		// WHEN
+		code := run([]string{"graph", "-file", filepath.Join("registry", "registry.go"), "-dry-run", "-log-level", "error"}, &stdout)
+
+		// THEN
+		require.Equal(t, 0, code)
+		assertGeneratedContent(t, stdout.Bytes(), filepath.Join(goldenDir, "complex", "expected_graph.dot.golden"))
+		assert.NoFileExists(t, filepath.Join(tempDir, "registry", "registry_graph.dot"))
+	})
+
+	t.Run("it should report the providers changed between two descriptions", func(t *testing.T) {
+		// GIVEN
+		tempDir := t.TempDir()
+		before := filepath.Join(tempDir, "before.json")
+		require.NoError(t, os.WriteFile(before, []byte(`{"providers": [
+			{"source": "app.NewClient", "names": [{"name": "client", "type": "*app.Client"}], "priority": 0, "lifetime": "singleton"}
+		]}`), 0o644))
+		after := filepath.Join(tempDir, "after.json")
+		require.NoError(t, os.WriteFile(after, []byte(`{"providers": [
+			{"source": "app.NewClient", "names": [{"name": "client", "type": "*app.Client"}], "priority": 10, "lifetime": "singleton"}
+		]}`), 0o644))
+		var stdout bytes.Buffer
+
+		// WHEN
+		code := run([]string{"diff", "-check", "-log-level", "error", before, after}, &stdout)
+
+		// THEN
+		assert.Equal(t, 1, code)
+		assert.Equal(t, "~ (client, *app.Client): app.NewClient\n\tpriority: 0 -> 10\n", stdout.String())
+	})
+
+	t.Run("it should fail for an unknown command", func(t *testing.T) {
+		// WHEN
+		code := run([]string{"deploy"}, &bytes.Buffer{})
+
+		// THEN
+		assert.Equal(t, 2, code)
+	})
+}
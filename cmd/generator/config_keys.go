@@ -0,0 +1,221 @@
+package main
+
+import (
+	"fmt"
+	"go/ast"
+	"go/format"
+	"reflect"
+	stdslices "slices"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/a-peyrard/godi/set"
+	"github.com/a-peyrard/godi/slices"
+)
+
+const (
+	configKeysPackage    = "cfgkeys"
+	configKeysOutputFile = "cfgkeys_gen.go"
+)
+
+const configKeysTemplate = `// Code generated by go generate; DO NOT EDIT!
+
+// Package cfgkeys exposes the names of the config field components, to be used instead of raw strings.
+package cfgkeys
+
+const (
+{{range .Keys}}	{{.Constant}} = "{{.Key}}"
+{{end}})
+`
+
+// ConfigKeyDefinition is the name of a config field component, along with the constant exposing it.
+type ConfigKeyDefinition struct {
+	Constant string
+	Key      string
+}
+
+// collectConfigKeys lists the names of the components provided by the ConfigFieldProvider of the config struct,
+// i.e. the struct name followed by the path of each exported field, nested structs included.
+// As for the provider, the segments are renamed by the `godi` and `mapstructure` tags.
+//
+// Nested structs are only followed if they are declared inline or in the same package as the config struct,
+// the fields of structs declared in other packages are not listed.
+func collectConfigKeys(typeName string, structTypes map[string]*ast.StructType) []string {
+	st, found := structTypes[typeName]
+	if !found {
+		return nil
+	}
+	return collectStructKeys(typeName, st, structTypes, set.NewWithValues(typeName))
+}
+
+func collectStructKeys(prefix string, st *ast.StructType, structTypes map[string]*ast.StructType, visiting set.Set[string]) []string {
+	var keys []string
+	for _, field := range st.Fields.List {
+		names := slices.Map(field.Names, func(ident *ast.Ident) string { return ident.Name })
+		if len(names) == 0 {
+			names = []string{embeddedFieldName(field.Type)}
+		}
+
+		segment, skipped := taggedSegmentOf(field)
+		if skipped {
+			continue
+		}
+
+		for _, name := range names {
+			if !ast.IsExported(name) {
+				continue
+			}
+			key := prefix + "." + name
+			if segment != "" {
+				key = prefix + "." + segment
+			}
+			keys = append(keys, key)
+
+			fieldTyp := field.Type
+			if star, ok := fieldTyp.(*ast.StarExpr); ok {
+				fieldTyp = star.X
+			}
+			switch typ := fieldTyp.(type) {
+			case *ast.StructType:
+				keys = append(keys, collectStructKeys(key, typ, structTypes, visiting)...)
+			case *ast.Ident:
+				if nested, found := structTypes[typ.Name]; found && !visiting.Contains(typ.Name) {
+					visiting.Add(typ.Name)
+					keys = append(keys, collectStructKeys(key, nested, structTypes, visiting)...)
+					visiting.Remove(typ.Name)
+				}
+			}
+		}
+	}
+	return keys
+}
+
+// taggedSegmentOf returns the name given to the field by its tags, if any,
+// or reports the field to be skipped if it is tagged with "-".
+func taggedSegmentOf(field *ast.Field) (segment string, skipped bool) {
+	if field.Tag == nil {
+		return "", false
+	}
+	tags, err := strconv.Unquote(field.Tag.Value)
+	if err != nil {
+		return "", false
+	}
+	for _, key := range []string{"godi", "mapstructure"} {
+		value, tagged := reflect.StructTag(tags).Lookup(key)
+		if !tagged {
+			continue
+		}
+		if segment, _, _ = strings.Cut(value, ","); segment != "" {
+			return segment, segment == "-"
+		}
+	}
+	return "", false
+}
+
+func embeddedFieldName(expr ast.Expr) string {
+	switch typ := expr.(type) {
+	case *ast.StarExpr:
+		return embeddedFieldName(typ.X)
+	case *ast.SelectorExpr:
+		return typ.Sel.Name
+	case *ast.Ident:
+		return typ.Name
+	}
+	return ""
+}
+
+// findConfigKeys builds the constants for all the config keys.
+//
+// The constant is named after the path of the field (e.g. DatabaseURL for AppConfig.Database.URL),
+// unless another config struct has a field with the same path, the struct name is then prepended.
+func findConfigKeys(configs []ConfigDefinition) []ConfigKeyDefinition {
+	constantOf := func(key string) string {
+		return identifierOf(key[strings.Index(key, ".")+1:])
+	}
+
+	counts := make(map[string]int)
+	for _, config := range configs {
+		for _, key := range config.Keys {
+			counts[constantOf(key)]++
+		}
+	}
+
+	var definitions []ConfigKeyDefinition
+	for _, config := range configs {
+		for _, key := range config.Keys {
+			constant := constantOf(key)
+			if counts[constant] > 1 {
+				constant = identifierOf(key)
+			}
+			definitions = append(definitions, ConfigKeyDefinition{Constant: constant, Key: key})
+		}
+	}
+	stdslices.SortFunc(definitions, func(a, b ConfigKeyDefinition) int {
+		return strings.Compare(a.Constant, b.Constant)
+	})
+	return stdslices.CompactFunc(definitions, func(a, b ConfigKeyDefinition) bool {
+		return a == b
+	})
+}
+
+// identifierOf turns a config key into an exported identifier, e.g. "AppConfig.database.pool_size" into
+// "AppConfigDatabasePoolSize".
+func identifierOf(key string) string {
+	var b strings.Builder
+	words := strings.FieldsFunc(key, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	for _, word := range words {
+		runes := []rune(word)
+		b.WriteRune(unicode.ToUpper(runes[0]))
+		b.WriteString(string(runes[1:]))
+	}
+	return b.String()
+}
+
+// validateConfigInjections looks for named injections targeting a config struct field which does not exist.
+func validateConfigInjections(
+	providers []ProviderDefinition,
+	decorators []DecoratorDefinition,
+	configs []ConfigDefinition,
+) []string {
+	keysByConfig := make(map[string]set.Set[string])
+	for _, config := range configs {
+		if len(config.Keys) > 0 {
+			keysByConfig[config.TypeName] = set.NewFromSlice(config.Keys)
+		}
+	}
+
+	var problems []string
+	validate := func(fnName string, injections []InjectAnnotation) {
+		for _, injection := range injections {
+			named, found := injection.Named()
+			if !found {
+				continue
+			}
+			configName, _, isField := strings.Cut(named, ".")
+			keys, isConfig := keysByConfig[configName]
+			if isField && isConfig && !keys.Contains(named) {
+				problems = append(problems, fmt.Sprintf("%s injects %q, but %s has no such field", fnName, named, configName))
+			}
+		}
+	}
+	for _, p := range providers {
+		validate(p.FnName, p.Dependencies)
+	}
+	for _, d := range decorators {
+		validate(d.FnName, d.Dependencies)
+	}
+	return problems
+}
+
+func renderConfigKeysCode(keys []ConfigKeyDefinition) ([]byte, error) {
+	code, err := executeTemplate(configKeysTemplate, map[string]interface{}{
+		"Keys": keys,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return format.Source(code)
+}
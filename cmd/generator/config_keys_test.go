@@ -0,0 +1,67 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_validateConfigInjections(t *testing.T) {
+	t.Run("it should report injections of unknown config fields", func(t *testing.T) {
+		// GIVEN
+		configs := []ConfigDefinition{
+			{TypeName: "AppConfig", Keys: []string{"AppConfig.Port", "AppConfig.Database", "AppConfig.Database.URL"}},
+		}
+		providers := []ProviderDefinition{
+			{
+				FnName: "NewServer",
+				Dependencies: []InjectAnnotation{
+					{properties: map[string]string{"named": "AppConfig.Database.URL"}},
+					{properties: map[string]string{"named": "AppConfig.Prot"}},
+					{properties: map[string]string{"named": "OtherConfig.Port"}},
+				},
+			},
+		}
+
+		// WHEN
+		problems := validateConfigInjections(providers, nil, configs)
+
+		// THEN
+		assert.Equal(t, []string{`NewServer injects "AppConfig.Prot", but AppConfig has no such field`}, problems)
+	})
+}
+
+func Test_findConfigKeys(t *testing.T) {
+	t.Run("it should prefix the constants with the struct name only on collisions", func(t *testing.T) {
+		// GIVEN
+		configs := []ConfigDefinition{
+			{TypeName: "AppConfig", Keys: []string{"AppConfig.Port", "AppConfig.Database.URL"}},
+			{TypeName: "AdminConfig", Keys: []string{"AdminConfig.Port"}},
+		}
+
+		// WHEN
+		keys := findConfigKeys(configs)
+
+		// THEN
+		assert.Equal(t, []ConfigKeyDefinition{
+			{Constant: "AdminConfigPort", Key: "AdminConfig.Port"},
+			{Constant: "AppConfigPort", Key: "AppConfig.Port"},
+			{Constant: "DatabaseURL", Key: "AppConfig.Database.URL"},
+		}, keys)
+	})
+
+	t.Run("it should turn tagged keys into exported constants", func(t *testing.T) {
+		// GIVEN
+		configs := []ConfigDefinition{
+			{TypeName: "AppConfig", Keys: []string{"AppConfig.database.pool_size"}},
+		}
+
+		// WHEN
+		keys := findConfigKeys(configs)
+
+		// THEN
+		assert.Equal(t, []ConfigKeyDefinition{
+			{Constant: "DatabasePoolSize", Key: "AppConfig.database.pool_size"},
+		}, keys)
+	})
+}
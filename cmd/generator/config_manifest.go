@@ -0,0 +1,263 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"go/ast"
+	"go/types"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/a-peyrard/godi/set"
+	"github.com/a-peyrard/godi/str"
+)
+
+const (
+	configManifestMarkdown = "md"
+	configManifestJSON     = "json"
+)
+
+type (
+	// ConfigFieldDefinition is a field of a config struct, as loaded by config.Load: from the files with its key,
+	// or from its env var. EnvAlias is the env var viper also looks up when it differs, e.g. APP_DATABASE_POOLSIZE
+	// for APP_DATABASE_POOL_SIZE.
+	ConfigFieldDefinition struct {
+		Key         string `json:"key"`
+		Env         string `json:"env"`
+		EnvAlias    string `json:"envAlias,omitempty"`
+		Type        string `json:"type"`
+		Default     string `json:"default,omitempty"`
+		Description string `json:"description,omitempty"`
+	}
+
+	configManifest struct {
+		Configs []configManifestEntry `json:"configs"`
+	}
+
+	configManifestEntry struct {
+		Type        string                  `json:"type"`
+		ImportPath  string                  `json:"importPath"`
+		Prefix      string                  `json:"prefix,omitempty"`
+		Description string                  `json:"description,omitempty"`
+		Conditions  []string                `json:"conditions,omitempty"`
+		Fields      []ConfigFieldDefinition `json:"fields"`
+	}
+)
+
+// configManifestPath returns the path of the config manifest generated for the registry, next to its generated code,
+// e.g. registry_config.md for registry_gen.go.
+func configManifestPath(outputPath string, format string) string {
+	return strings.TrimSuffix(outputPath, "_gen.go") + "_config." + format
+}
+
+// collectConfigFields lists the fields of the config struct loaded by config.Load, i.e. the leaves of the struct,
+// named by their `mapstructure` tag, along with their env var and their `default` tag.
+//
+// As for collectConfigKeys, nested structs are only followed if they are declared inline or in the same package
+// as the config struct.
+func collectConfigFields(config ConfigDefinition, structTypes map[string]*ast.StructType) []ConfigFieldDefinition {
+	st, found := structTypes[config.TypeName]
+	if !found {
+		return nil
+	}
+	return collectStructFields(config.Annotation.Prefix(), nil, st, structTypes, set.NewWithValues(config.TypeName))
+}
+
+func collectStructFields(
+	prefix string,
+	parts []string,
+	st *ast.StructType,
+	structTypes map[string]*ast.StructType,
+	visiting set.Set[string],
+) []ConfigFieldDefinition {
+	var fields []ConfigFieldDefinition
+	for _, field := range st.Fields.List {
+		names := make([]string, 0, len(field.Names))
+		for _, ident := range field.Names {
+			names = append(names, ident.Name)
+		}
+		if len(names) == 0 {
+			names = []string{embeddedFieldName(field.Type)}
+		}
+		tags := fieldTags(field)
+
+		for _, name := range names {
+			if !ast.IsExported(name) {
+				continue
+			}
+			if tagged, found := tags.Lookup("mapstructure"); found {
+				name, _, _ = strings.Cut(tagged, ",")
+			}
+
+			nestedParts := append(append([]string(nil), parts...), name)
+			fieldTyp := field.Type
+			if star, ok := fieldTyp.(*ast.StarExpr); ok {
+				fieldTyp = star.X
+			}
+			switch typ := fieldTyp.(type) {
+			case *ast.StructType:
+				fields = append(fields, collectStructFields(prefix, nestedParts, typ, structTypes, visiting)...)
+				continue
+			case *ast.Ident:
+				if nested, found := structTypes[typ.Name]; found {
+					if !visiting.Contains(typ.Name) {
+						visiting.Add(typ.Name)
+						fields = append(fields, collectStructFields(prefix, nestedParts, nested, structTypes, visiting)...)
+						visiting.Remove(typ.Name)
+					}
+					continue
+				}
+			}
+
+			defaultValue, _ := tags.Lookup("default")
+			env, alias := envVarOf(prefix, parts, name), automaticEnvVarOf(prefix, nestedParts)
+			if alias == env {
+				alias = ""
+			}
+			fields = append(fields, ConfigFieldDefinition{
+				Key:         strings.ToLower(strings.Join(nestedParts, ".")),
+				Env:         env,
+				EnvAlias:    alias,
+				Type:        types.ExprString(field.Type),
+				Default:     defaultValue,
+				Description: fieldDescription(field),
+			})
+		}
+	}
+	return fields
+}
+
+// envVarOf returns the env var of the field, named as config.Load binds them, e.g. APP_SERVER_READ_TIMEOUT
+// for the field ReadTimeout of the nested struct Server, with the APP prefix.
+func envVarOf(prefix string, parts []string, name string) string {
+	envVar := strings.Join(append(append([]string(nil), parts...), str.ToScreamingSnakeCase(name)), "_")
+	if prefix != "" {
+		envVar = prefix + "_" + envVar
+	}
+	return strings.ToUpper(envVar)
+}
+
+// automaticEnvVarOf returns the env var viper looks up for the key of the field, e.g. APP_SERVER_READTIMEOUT
+// for the field ReadTimeout of the nested struct Server, with the APP prefix.
+func automaticEnvVarOf(prefix string, parts []string) string {
+	envVar := strings.Join(parts, "_")
+	if prefix != "" {
+		envVar = prefix + "_" + envVar
+	}
+	return strings.ToUpper(envVar)
+}
+
+func fieldTags(field *ast.Field) reflect.StructTag {
+	if field.Tag == nil {
+		return ""
+	}
+	tags, err := strconv.Unquote(field.Tag.Value)
+	if err != nil {
+		return ""
+	}
+	return reflect.StructTag(tags)
+}
+
+// fieldDescription returns the doc of the field, or its line comment.
+func fieldDescription(field *ast.Field) string {
+	doc := field.Doc
+	if doc == nil {
+		doc = field.Comment
+	}
+	if doc == nil {
+		return ""
+	}
+	return strings.Join(strings.Fields(doc.Text()), " ")
+}
+
+// renderConfigManifest renders the documentation of the config structs, and of the env vars they are loaded from,
+// either as markdown or as json.
+func renderConfigManifest(configs []ConfigDefinition, format string) ([]byte, error) {
+	manifest := configManifest{Configs: make([]configManifestEntry, 0, len(configs))}
+	for _, c := range configs {
+		entry := configManifestEntry{
+			Type:        c.TypeName,
+			ImportPath:  c.ImportPath,
+			Prefix:      c.Annotation.Prefix(),
+			Description: c.Annotation.description,
+			Fields:      c.Fields,
+		}
+		for _, condition := range c.Annotation.conditions {
+			entry.Conditions = append(entry.Conditions, conditionDescription(condition))
+		}
+		if entry.Fields == nil {
+			entry.Fields = []ConfigFieldDefinition{}
+		}
+		manifest.Configs = append(manifest.Configs, entry)
+	}
+
+	switch format {
+	case configManifestJSON:
+		content, err := json.MarshalIndent(manifest, "", "  ")
+		if err != nil {
+			return nil, err
+		}
+		return append(content, '\n'), nil
+	case configManifestMarkdown:
+		return renderConfigManifestMarkdown(manifest), nil
+	default:
+		return nil, fmt.Errorf("unsupported config manifest format %q, expecting %s or %s", format, configManifestMarkdown, configManifestJSON)
+	}
+}
+
+func renderConfigManifestMarkdown(manifest configManifest) []byte {
+	escape := func(value string) string {
+		return strings.ReplaceAll(value, "|", `\|`)
+	}
+	code := func(value string) string {
+		if value == "" {
+			return ""
+		}
+		return "`" + escape(value) + "`"
+	}
+
+	envVars := func(field ConfigFieldDefinition) string {
+		if field.EnvAlias == "" {
+			return code(field.Env)
+		}
+		return code(field.Env) + " or " + code(field.EnvAlias)
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString("<!-- Code generated by go generate; DO NOT EDIT! -->\n\n")
+	buf.WriteString("# Configuration\n")
+	for _, c := range manifest.Configs {
+		fmt.Fprintf(&buf, "\n## %s\n\n", c.Type)
+		if c.Description != "" {
+			fmt.Fprintf(&buf, "%s\n\n", c.Description)
+		}
+		fmt.Fprintf(&buf, "Loaded into `%s.%s`", c.ImportPath, c.Type)
+		if c.Prefix != "" {
+			fmt.Fprintf(&buf, ", from the env vars prefixed by `%s_`", c.Prefix)
+		}
+		buf.WriteString(".\n")
+		if len(c.Conditions) > 0 {
+			fmt.Fprintf(&buf, "Only registered when %s.\n", strings.Join(c.Conditions, " and "))
+		}
+		buf.WriteString("\n| Env var | Key | Type | Default | Description |\n")
+		buf.WriteString("|---------|-----|------|---------|-------------|\n")
+		for _, field := range c.Fields {
+			fmt.Fprintf(
+				&buf,
+				"| %s | %s | %s | %s | %s |\n",
+				envVars(field), code(field.Key), code(field.Type), code(field.Default), escape(field.Description),
+			)
+		}
+	}
+	return buf.Bytes()
+}
+
+func conditionDescription(condition WhenAnnotation) string {
+	subject := "`" + condition.named + "`"
+	if condition.env {
+		subject = "the env var " + subject
+	}
+	return fmt.Sprintf("%s %s `%s`", subject, strings.ReplaceAll(condition.operator, "_", " "), condition.value)
+}
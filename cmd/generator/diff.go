@@ -0,0 +1,180 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	maxDiffLinesInSummary = 20
+	diffContextLines      = 3
+)
+
+type (
+	diffOp int
+
+	diffLine struct {
+		op   diffOp
+		line string
+	}
+)
+
+const (
+	diffKeep diffOp = iota
+	diffAdd
+	diffRemove
+)
+
+// diffLines computes a line based diff between expected and actual, using the longest common subsequence.
+func diffLines(expected, actual string) []diffLine {
+	a := strings.Split(expected, "\n")
+	b := strings.Split(actual, "\n")
+
+	// lcs[i][j] is the length of the LCS of a[i:] and b[j:]
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	var (
+		lines []diffLine
+		i, j  int
+	)
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] == b[j]:
+			lines = append(lines, diffLine{op: diffKeep, line: a[i]})
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			lines = append(lines, diffLine{op: diffRemove, line: a[i]})
+			i++
+		default:
+			lines = append(lines, diffLine{op: diffAdd, line: b[j]})
+			j++
+		}
+	}
+	for ; i < len(a); i++ {
+		lines = append(lines, diffLine{op: diffRemove, line: a[i]})
+	}
+	for ; j < len(b); j++ {
+		lines = append(lines, diffLine{op: diffAdd, line: b[j]})
+	}
+	return lines
+}
+
+// diffSummary returns a human-readable summary of the differences, or an empty string if there are none.
+func diffSummary(expected, actual string) string {
+	var (
+		added, removed int
+		changes        []string
+	)
+	for _, l := range diffLines(expected, actual) {
+		switch l.op {
+		case diffAdd:
+			added++
+			changes = append(changes, "+ "+l.line)
+		case diffRemove:
+			removed++
+			changes = append(changes, "- "+l.line)
+		default:
+		}
+	}
+	if added == 0 && removed == 0 {
+		return ""
+	}
+
+	var b strings.Builder
+	b.WriteString(fmt.Sprintf("%d line(s) added, %d line(s) removed\n", added, removed))
+	for idx, change := range changes {
+		if idx == maxDiffLinesInSummary {
+			b.WriteString(fmt.Sprintf("... and %d more\n", len(changes)-maxDiffLinesInSummary))
+			break
+		}
+		b.WriteString(change + "\n")
+	}
+	return b.String()
+}
+
+// unifiedDiff returns the differences between the content of the file on disk and the generated one in the unified
+// format, with the path of the file prefixed by a/ and b/ as git does, or an empty string if there are none.
+func unifiedDiff(path string, expected, actual string) string {
+	if expected == actual {
+		return ""
+	}
+	var lines []diffLine
+	if expected == "" {
+		// the file is missing, it has no line, not a single empty one
+		for _, line := range strings.Split(strings.TrimSuffix(actual, "\n"), "\n") {
+			lines = append(lines, diffLine{op: diffAdd, line: line})
+		}
+	} else {
+		lines = diffLines(strings.TrimSuffix(expected, "\n"), strings.TrimSuffix(actual, "\n"))
+	}
+
+	var b strings.Builder
+	b.WriteString(fmt.Sprintf("--- a/%s\n+++ b/%s\n", path, path))
+	for start := 0; start < len(lines); {
+		first := start
+		for first < len(lines) && lines[first].op == diffKeep {
+			first++
+		}
+		if first == len(lines) {
+			break
+		}
+		// the hunk spans the changes separated by less than twice the context lines
+		hunkStart, last := max(first-diffContextLines, start), first
+		for next := first + 1; next < len(lines) && next <= last+2*diffContextLines; next++ {
+			if lines[next].op != diffKeep {
+				last = next
+			}
+		}
+		hunkEnd := min(last+1+diffContextLines, len(lines))
+
+		oldStart, newStart := 1, 1
+		for _, l := range lines[:hunkStart] {
+			if l.op != diffAdd {
+				oldStart++
+			}
+			if l.op != diffRemove {
+				newStart++
+			}
+		}
+		var oldLen, newLen int
+		var hunk strings.Builder
+		for _, l := range lines[hunkStart:hunkEnd] {
+			switch l.op {
+			case diffAdd:
+				newLen++
+				hunk.WriteString("+" + l.line + "\n")
+			case diffRemove:
+				oldLen++
+				hunk.WriteString("-" + l.line + "\n")
+			default:
+				oldLen++
+				newLen++
+				hunk.WriteString(" " + l.line + "\n")
+			}
+		}
+		// an empty range starts at the line before it
+		if oldLen == 0 {
+			oldStart--
+		}
+		if newLen == 0 {
+			newStart--
+		}
+		b.WriteString(fmt.Sprintf("@@ -%d,%d +%d,%d @@\n", oldStart, oldLen, newStart, newLen))
+		b.WriteString(hunk.String())
+		start = hunkEnd
+	}
+	return b.String()
+}
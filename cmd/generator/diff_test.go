@@ -0,0 +1,99 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_diffSummary(t *testing.T) {
+	t.Run("it should return an empty summary for identical content", func(t *testing.T) {
+		// GIVEN
+		content := "line 1\nline 2\n"
+
+		// WHEN
+		summary := diffSummary(content, content)
+
+		// THEN
+		assert.Empty(t, summary)
+	})
+
+	t.Run("it should report added and removed lines", func(t *testing.T) {
+		// GIVEN
+		expected := "package foo\n\nfunc a() {}\nfunc b() {}\n"
+		actual := "package foo\n\nfunc a() {}\nfunc c() {}\nfunc d() {}\n"
+
+		// WHEN
+		summary := diffSummary(expected, actual)
+
+		// THEN
+		assert.Equal(t, "2 line(s) added, 1 line(s) removed\n- func b() {}\n+ func c() {}\n+ func d() {}\n", summary)
+	})
+
+	t.Run("it should truncate long summaries", func(t *testing.T) {
+		// GIVEN
+		var actual string
+		for i := 0; i < maxDiffLinesInSummary+5; i++ {
+			actual += "new line\n"
+		}
+
+		// WHEN
+		summary := diffSummary("", actual)
+
+		// THEN
+		assert.Contains(t, summary, "... and 5 more")
+	})
+}
+
+func Test_unifiedDiff(t *testing.T) {
+	t.Run("it should return an empty diff for identical content", func(t *testing.T) {
+		// GIVEN
+		content := "line 1\nline 2\n"
+
+		// WHEN
+		diff := unifiedDiff("registry_gen.go", content, content)
+
+		// THEN
+		assert.Empty(t, diff)
+	})
+
+	t.Run("it should group the changes in hunks with their context", func(t *testing.T) {
+		// GIVEN
+		expected := "1\n2\n3\n4\n5\n6\n7\n8\n9\n10\n11\n12\n13\n14\n15\n"
+		actual := "1\n2\nthree\n4\n5\n6\n7\n8\n9\n10\n11\n12\n13\n15\n16\n"
+
+		// WHEN
+		diff := unifiedDiff("registry_gen.go", expected, actual)
+
+		// THEN
+		assert.Equal(t, `--- a/registry_gen.go
++++ b/registry_gen.go
+@@ -1,6 +1,6 @@
+ 1
+ 2
+-3
++three
+ 4
+ 5
+ 6
+@@ -11,5 +11,5 @@
+ 11
+ 12
+ 13
+-14
+ 15
++16
+`, diff)
+	})
+
+	t.Run("it should add all the lines of a missing file", func(t *testing.T) {
+		// GIVEN
+		actual := "package foo\n\nfunc a() {}\n"
+
+		// WHEN
+		diff := unifiedDiff("foo_gen.go", "", actual)
+
+		// THEN
+		assert.Equal(t, "--- a/foo_gen.go\n+++ b/foo_gen.go\n@@ -0,0 +1,3 @@\n+package foo\n+\n+func a() {}\n", diff)
+	})
+}
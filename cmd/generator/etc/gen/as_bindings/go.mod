@@ -0,0 +1,3 @@
+module github.com/test/bindings
+
+go 1.24
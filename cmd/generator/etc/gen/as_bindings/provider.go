@@ -0,0 +1,28 @@
+package registry
+
+import "io"
+
+type (
+	Repository interface {
+		Find(id string) string
+	}
+
+	sqlRepository struct{}
+)
+
+func (*sqlRepository) Find(id string) string { return id }
+
+func (*sqlRepository) Close() error { return nil }
+
+// @provider named="repository" as="Repository, io.Closer"
+func NewRepository() *sqlRepository {
+	return &sqlRepository{}
+}
+
+// Cache keeps the results in memory.
+// @component as="io.Closer"
+type Cache struct{}
+
+func (*Cache) Close() error { return nil }
+
+var _ io.Closer = (*Cache)(nil)
@@ -0,0 +1,26 @@
+package registry
+
+// Handler serves the requests.
+// @component named="handler" priority=10
+// @when env="FEATURE_HANDLER" equals="on"
+type Handler struct {
+	Service *Service
+	Logger  Logger `godi:"name=logger,optional"`
+}
+
+// @component named="service"
+// Service holds the business logic.
+type Service struct {
+	repository *Repository
+}
+
+func NewService(
+	repository *Repository, // @inject named="repository"
+) *Service {
+	return &Service{repository: repository}
+}
+
+// @component
+type Repository struct{}
+
+type Logger interface{}
@@ -0,0 +1,3 @@
+module github.com/test/component
+
+go 1.24
@@ -0,0 +1,16 @@
+package services
+
+// @config prefix="CLOUD"
+// @when named="DEPLOYMENT" equals="cloud"
+// CloudConfig is used when running in the cloud
+type CloudConfig struct {
+	Bucket string
+	Region string
+}
+
+// @config prefix="ONPREM"
+// @when named="DEPLOYMENT" not_equals="cloud"
+// OnPremConfig is used when running on premises
+type OnPremConfig struct {
+	StoragePath string
+}
@@ -0,0 +1,3 @@
+module github.com/test/conditionalconfig
+
+go 1.24
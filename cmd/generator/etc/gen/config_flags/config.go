@@ -0,0 +1,8 @@
+package app
+
+// @config prefix="APP" flags="true"
+// AppConfig contains application configuration, overridable from the command line
+type AppConfig struct {
+	DatabaseURL string `env:"DATABASE_URL"`
+	Port        int    `env:"PORT"`
+}
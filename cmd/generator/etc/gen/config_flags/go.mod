@@ -0,0 +1,3 @@
+module github.com/test/configflags
+
+go 1.24
@@ -0,0 +1,32 @@
+package app
+
+// @config prefix="APP"
+type AppConfig struct {
+	Database DatabaseConfig
+	Cache    *struct {
+		TTL int
+	}
+	Port     int
+	internal string
+}
+
+type DatabaseConfig struct {
+	URL      string
+	PoolSize int    `mapstructure:"pool_size"`
+	Password string `godi:"-"`
+}
+
+// @config prefix="ADMIN"
+type AdminConfig struct {
+	Port int
+}
+
+// @provider
+func NewServer(
+	url string, // @inject named="AppConfig.Database.URL"
+	port int, // @inject named="AppConfig.Port"
+) *Server {
+	return &Server{}
+}
+
+type Server struct{}
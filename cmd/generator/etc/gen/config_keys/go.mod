@@ -0,0 +1,3 @@
+module github.com/test/configkeys
+
+go 1.24
@@ -0,0 +1,28 @@
+package app
+
+import "time"
+
+// @config prefix="APP"
+// AppConfig contains the application settings
+type AppConfig struct {
+	// Port is the port the server listens on
+	Port     int    `default:"8080"`
+	LogLevel string `mapstructure:"log_level" default:"info"`
+	Database DatabaseConfig
+	Server   struct {
+		ReadTimeout time.Duration `default:"5s"` // 0 | negative disables it
+	}
+	internal string
+}
+
+type DatabaseConfig struct {
+	URL      string
+	PoolSize int `default:"10"`
+}
+
+// @config prefix="CLOUD"
+// @when env="DEPLOYMENT" equals="cloud"
+// CloudConfig contains the cloud settings
+type CloudConfig struct {
+	Region string `default:"eu-west-1"`
+}
@@ -0,0 +1,3 @@
+module github.com/test/manifest
+
+go 1.24
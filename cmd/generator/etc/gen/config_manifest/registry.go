@@ -0,0 +1,5 @@
+package app
+
+type Registry struct {
+	godi.EmptyRegistry
+}
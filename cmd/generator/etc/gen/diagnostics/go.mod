@@ -0,0 +1,3 @@
+module github.com/test/diagnostics
+
+go 1.24
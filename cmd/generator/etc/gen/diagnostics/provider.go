@@ -0,0 +1,26 @@
+package registry
+
+type (
+	Service struct{}
+	Handler struct{}
+)
+
+// @provider named="service" priority=high scope="request"
+// @when named="ENV" equals="production" fallback="dev"
+func NewService() *Service {
+	return &Service{}
+}
+
+// @provider named="handler"
+// @cached
+func NewHandler(
+	service *Service, // @inject named="service" lazy=true optional=maybe
+	port int, // @inject named="port" default="eighty"
+) *Handler {
+	return &Handler{}
+}
+
+// @decorator priority=1
+func DecorateHandler(handler *Handler) *Handler {
+	return handler
+}
@@ -0,0 +1,3 @@
+module github.com/test/direct
+
+go 1.24
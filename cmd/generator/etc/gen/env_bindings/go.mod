@@ -0,0 +1,3 @@
+module github.com/test/envbindings
+
+go 1.24
@@ -0,0 +1,28 @@
+package services
+
+// @provider named="server"
+// Server is listening on the configured port
+func NewServer(
+	host string, // @inject named="SERVER_HOST"
+	port int, // @inject named="SERVER_PORT"
+	debug bool, // @inject named="DEBUG" optional=true
+	store *Store, // @inject named="STORE"
+	cache string, // @inject named="cache"
+) *Server {
+	return &Server{}
+}
+
+// @provider named="STORE"
+// Store is a dummy store
+func NewStore() *Store {
+	return &Store{}
+}
+
+// @provider named="cache"
+// Cache is a dummy cache
+func NewCache() string {
+	return "cache"
+}
+
+type Server struct{}
+type Store struct{}
@@ -0,0 +1,5 @@
+package services
+
+type Registry struct {
+	godi.EmptyRegistry
+}
@@ -0,0 +1,3 @@
+module github.com/test/registries
+
+go 1.24
@@ -0,0 +1,9 @@
+package legacy
+
+// @provider named="legacy.service"
+// Service for legacy
+func NewService() *Service {
+	return &Service{}
+}
+
+type Service struct{}
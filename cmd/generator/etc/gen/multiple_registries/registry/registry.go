@@ -0,0 +1,15 @@
+package registry
+
+// APIRegistry only wires the API services.
+//
+// @registry scope="./services/api/..."
+type APIRegistry struct {
+	godi.EmptyRegistry
+}
+
+// WorkerRegistry wires the worker, and the non legacy internals.
+//
+// @registry scope="./services/worker/...,./internal/..." exclude="./internal/legacy/..."
+type WorkerRegistry struct {
+	godi.EmptyRegistry
+}
@@ -0,0 +1,9 @@
+package worker
+
+// @provider named="worker.service"
+// Service for worker
+func NewService() *Service {
+	return &Service{}
+}
+
+type Service struct{}
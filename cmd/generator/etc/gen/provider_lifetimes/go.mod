@@ -0,0 +1,3 @@
+module github.com/test/lifetimes
+
+go 1.24
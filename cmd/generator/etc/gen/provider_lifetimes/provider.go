@@ -0,0 +1,45 @@
+package registry
+
+import "time"
+
+type (
+	Clock struct{}
+
+	Request struct {
+		StartedAt time.Time
+	}
+
+	Pool struct{}
+
+	Logger struct{}
+)
+
+// @provider named="clock" scope="singleton"
+func NewClock() *Clock {
+	return &Clock{}
+}
+
+// @provider named="request" scope="scoped"
+func NewRequest() *Request {
+	return &Request{StartedAt: time.Now()}
+}
+
+// @provider named="pool" eager=true priority=10
+func NewPool() *Pool {
+	return &Pool{}
+}
+
+// @provider named="logger" scope="per-consumer"
+func NewLogger() *Logger {
+	return &Logger{}
+}
+
+// Buffer is a new buffer for each injection.
+// @component scope="transient"
+type Buffer struct{}
+
+// Warmer is instantiated at the warmup of the resolver.
+// @component scope="scoped" eager=true
+type Warmer struct {
+	Pool *Pool
+}
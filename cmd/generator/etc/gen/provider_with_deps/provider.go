@@ -8,6 +8,8 @@ func NewDatabaseConnection(
 	ctx context.Context,
 	config *Config, // @inject named="app.config"
 	logger Logger, // @inject named="logger" optional=true
+	name string, // @inject named="database.name" default="app"
+	poolSize int64, // @inject named="database.pool.size" default="10"
 ) (*DatabaseConnection, error) {
 	return &DatabaseConnection{}, nil
 }
@@ -0,0 +1,3 @@
+module github.com/test/scope
+
+go 1.24
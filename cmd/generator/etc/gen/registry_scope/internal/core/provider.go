@@ -0,0 +1,9 @@
+package core
+
+// @provider named="core.service"
+// Service for core
+func NewService() *Service {
+	return &Service{}
+}
+
+type Service struct{}
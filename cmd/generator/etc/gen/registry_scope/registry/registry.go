@@ -0,0 +1,8 @@
+package registry
+
+// Registry only wires the API services, and the non legacy internals.
+//
+// @registry include="./services/api/...,./internal/..." exclude="./internal/legacy/..."
+type Registry struct {
+	godi.EmptyRegistry
+}
@@ -0,0 +1,9 @@
+package api
+
+// @provider named="api.service"
+// Service for api
+func NewService() *Service {
+	return &Service{}
+}
+
+type Service struct{}
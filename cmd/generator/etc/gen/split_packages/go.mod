@@ -0,0 +1,3 @@
+module github.com/test/split
+
+go 1.24
@@ -0,0 +1,5 @@
+package registry
+
+type Registry struct {
+	godi.EmptyRegistry
+}
@@ -0,0 +1,3 @@
+module github.com/test/validation
+
+go 1.24
@@ -0,0 +1,66 @@
+package registry
+
+import "context"
+
+type (
+	Repository interface {
+		Find() string
+	}
+	Clock interface {
+		Now() int64
+	}
+
+	sqlRepository struct{}
+	Service       struct{}
+	Handler       struct{}
+	Cache         struct{}
+)
+
+func (sqlRepository) Find() string { return "" }
+
+// @provider named="repository"
+func NewRepository() *sqlRepository {
+	return &sqlRepository{}
+}
+
+// @provider named="service"
+func NewService(
+	ctx context.Context,
+	repository Repository, // @inject named="repository"
+	cache *Cache, // @inject named="cache"
+	port int, // @inject named="PORT"
+) *Service {
+	return &Service{}
+}
+
+// @provider named="handler"
+func NewHandler(
+	service string, // @inject named="service"
+	clock Clock,
+) *Handler {
+	return &Handler{}
+}
+
+// @decorator named="service"
+func DecorateService(handler *Handler) *Handler {
+	return handler
+}
+
+type (
+	Reader struct{}
+	Writer struct{}
+	Copier struct{}
+)
+
+// @provider named="pipe"
+func NewPipe() (*Reader, *Writer, error) {
+	return &Reader{}, &Writer{}, nil
+}
+
+// @provider named="copier"
+func NewCopier(
+	writer *Writer, // @inject named="pipe"
+	reader *Reader,
+) *Copier {
+	return &Copier{}
+}
@@ -0,0 +1,3 @@
+module github.com/test/wiring
+
+go 1.24
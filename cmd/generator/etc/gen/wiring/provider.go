@@ -0,0 +1,52 @@
+package registry
+
+import "context"
+
+type (
+	Config     struct{}
+	Repository struct{}
+	Service    struct{}
+	Plugin     struct{}
+	Cache      struct{}
+	Handler    struct{}
+)
+
+func (c *Cache) Close() error {
+	return nil
+}
+
+// @provider named="config"
+func NewConfig() *Config {
+	return &Config{}
+}
+
+// @provider named="repository"
+func NewRepository(
+	ctx context.Context,
+	config *Config, // @inject named="config"
+	url string, // @inject named="DATABASE_URL"
+) (*Repository, error) {
+	return &Repository{}, nil
+}
+
+// @provider named="service"
+func NewService(repository, fallback *Repository) (service *Service, err error) {
+	return &Service{}, nil
+}
+
+// @provider named="plugins"
+// Variadic providers are called through reflection
+func NewPlugins(plugins ...*Plugin) []*Plugin {
+	return plugins
+}
+
+// @provider named="cache"
+// Closeable components are built by the resolver
+func NewCache() *Cache {
+	return &Cache{}
+}
+
+// @provider named="handler"
+func NewHandler(ctx context.Context, service *Service, cache *Cache) *Handler {
+	return &Handler{}
+}
@@ -0,0 +1,245 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/types"
+	"os/exec"
+	"strings"
+	"unicode"
+
+	"github.com/a-peyrard/godi/set"
+)
+
+const (
+	graphFormatDot = "dot"
+	graphFormatSVG = "svg"
+)
+
+// runtimeTypeExprs are the source representations of the types provided by the resolver itself.
+var runtimeTypeExprs = set.NewWithValues("context.Context", "*godi.Resolver", "godi.InjectionContext")
+
+// graphOutputPath returns the path of the graph generated for the registry, next to its generated code,
+// e.g. registry_graph.dot for registry_gen.go.
+func graphOutputPath(outputPath string, format string) string {
+	return strings.TrimSuffix(outputPath, "_gen.go") + "_graph." + format
+}
+
+// renderGraphFile renders the dependency graph of the definitions in the given format, the svg format requires
+// the dot command of Graphviz.
+func renderGraphFile(defs Definitions, format string) ([]byte, error) {
+	graph := renderGraph(defs)
+	switch format {
+	case graphFormatDot:
+		return graph, nil
+	case graphFormatSVG:
+		dot, err := exec.LookPath("dot")
+		if err != nil {
+			return nil, fmt.Errorf("the dot command of Graphviz is required to render the graph as svg:\n\t%w", err)
+		}
+		var stdout, stderr bytes.Buffer
+		cmd := exec.Command(dot, "-Tsvg")
+		cmd.Stdin = bytes.NewReader(graph)
+		cmd.Stdout = &stdout
+		cmd.Stderr = &stderr
+		if err := cmd.Run(); err != nil {
+			return nil, fmt.Errorf("failed to render the graph as svg: %s:\n\t%w", strings.TrimSpace(stderr.String()), err)
+		}
+		return stdout.Bytes(), nil
+	default:
+		return nil, fmt.Errorf("unsupported graph format %q, expecting %s or %s", format, graphFormatDot, graphFormatSVG)
+	}
+}
+
+// dependencyGraph builds the DOT representation of the definitions.
+type dependencyGraph struct {
+	buf   bytes.Buffer
+	nodes set.Set[string]
+	edges set.Set[string]
+
+	// named are the nodes by the names they provide, and typed by the qualified types they provide
+	named map[string][]string
+	typed map[string][]string
+}
+
+// renderGraph renders the DOT graph of the providers, decorators, components and configs, with an edge from each
+// of them to its dependencies. The dependencies nothing provides (env vars, runtime components...) are dashed.
+//
+// The dependencies by type are matched on the source representation of the types, so a type imported
+// with an alias is not matched.
+func renderGraph(defs Definitions) []byte {
+	g := &dependencyGraph{
+		nodes: set.New[string](),
+		edges: set.New[string](),
+		named: make(map[string][]string),
+		typed: make(map[string][]string),
+	}
+	g.buf.WriteString("// Code generated by go generate; DO NOT EDIT!\n\n")
+	g.buf.WriteString("digraph godi {\n")
+	g.buf.WriteString("\trankdir=LR;\n")
+	g.buf.WriteString("\tnode [shape=box, fontname=\"Helvetica\"];\n")
+	g.buf.WriteString("\tedge [fontname=\"Helvetica\", fontsize=10];\n\n")
+
+	packageName := func(importPath string) string {
+		return defs.Packages[importPath].Name
+	}
+
+	for _, p := range defs.Providers {
+		id := p.ImportPath + "." + p.FnName
+		label := packageName(p.ImportPath) + "." + p.FnName
+		if p.Named != "" {
+			label = p.Named + "\\n" + label
+			g.named[p.Named] = append(g.named[p.Named], id)
+		}
+		for _, provided := range p.provides {
+			g.typed[provided] = append(g.typed[provided], id)
+		}
+		g.node(id, label, "")
+	}
+	for _, c := range defs.Components {
+		id := c.ImportPath + "." + c.TypeName
+		typ := packageName(c.ImportPath) + "." + c.TypeName
+		named := c.Named
+		if named == "" {
+			named = c.TypeName
+		}
+		g.named[named] = append(g.named[named], id)
+		g.typed[typ] = append(g.typed[typ], id)
+		g.node(id, named+"\\n"+typ, "shape=component")
+	}
+	for _, c := range defs.Configs {
+		id := c.ImportPath + "." + c.TypeName
+		g.named[c.TypeName] = append(g.named[c.TypeName], id)
+		for _, key := range c.Keys {
+			g.named[key] = append(g.named[key], id)
+		}
+		g.node(id, c.TypeName+"\\n"+packageName(c.ImportPath)+"."+c.TypeName, "shape=note")
+	}
+	for _, d := range defs.Decorators {
+		g.node(d.ImportPath+"."+d.FnName, packageName(d.ImportPath)+"."+d.FnName, "shape=cds")
+	}
+	g.buf.WriteString("\n")
+
+	for _, p := range defs.Providers {
+		g.dependencies(p.ImportPath+"."+p.FnName, p.Dependencies)
+	}
+	for _, d := range defs.Decorators {
+		id := d.ImportPath + "." + d.FnName
+		for _, target := range g.namedNodes(d.Decorate) {
+			g.edge(id, target, "style=bold, label=\"decorates\"")
+		}
+		g.dependencies(id, d.Dependencies)
+	}
+
+	g.buf.WriteString("}\n")
+	return g.buf.Bytes()
+}
+
+func (g *dependencyGraph) node(id string, label string, attributes string) {
+	if g.nodes.Contains(id) {
+		return
+	}
+	g.nodes.Add(id)
+	if attributes != "" {
+		attributes = ", " + attributes
+	}
+	fmt.Fprintf(&g.buf, "\t%q [label=\"%s\"%s];\n", id, label, attributes)
+}
+
+func (g *dependencyGraph) edge(from string, to string, attributes string) {
+	line := fmt.Sprintf("\t%q -> %q", from, to)
+	if attributes != "" {
+		line += " [" + attributes + "]"
+	}
+	if g.edges.Contains(line) {
+		return
+	}
+	g.edges.Add(line)
+	g.buf.WriteString(line + ";\n")
+}
+
+// namedNodes returns the nodes providing the name, an unresolved node is added if nothing provides it.
+func (g *dependencyGraph) namedNodes(name string) []string {
+	if ids, found := g.named[name]; found {
+		return ids
+	}
+	label := name
+	if envVarNameRegex.MatchString(name) {
+		label = "env: " + name
+	}
+	id := "unresolved." + name
+	g.node(id, label, "style=dashed")
+	return []string{id}
+}
+
+func (g *dependencyGraph) dependencies(from string, dependencies []InjectAnnotation) {
+	for _, dependency := range dependencies {
+		var attributes []string
+		_, withDefault := dependency.Default()
+		if optional, _ := dependency.Optional(); optional || withDefault {
+			attributes = append(attributes, "style=dotted")
+		}
+		if multiple, _ := dependency.Multiple(); multiple {
+			attributes = append(attributes, "label=\"multiple\"")
+		}
+
+		if named, found := dependency.Named(); found {
+			for _, target := range g.namedNodes(named) {
+				g.edge(from, target, strings.Join(attributes, ", "))
+			}
+			continue
+		}
+		if dependency.qualifiedType == "" || runtimeTypeExprs.Contains(dependency.typeExpr) {
+			continue
+		}
+		typ := dependency.qualifiedType
+		if multiple, _ := dependency.Multiple(); multiple {
+			typ = strings.TrimPrefix(typ, "[]")
+		}
+		targets := g.typed[typ]
+		if len(targets) == 0 {
+			id := "unresolved." + typ
+			g.node(id, typ, "style=dashed")
+			targets = []string{id}
+		}
+		for _, target := range targets {
+			g.edge(from, target, strings.Join(attributes, ", "))
+		}
+	}
+}
+
+// providedTypesOf returns the qualified source representations of the results of the function, but the error.
+func providedTypesOf(packageName string, fn *ast.FuncDecl) []string {
+	if fn.Type.Results == nil {
+		return nil
+	}
+	var provided []string
+	for _, field := range fn.Type.Results.List {
+		if types.ExprString(field.Type) == "error" {
+			continue
+		}
+		for range max(len(field.Names), 1) {
+			provided = append(provided, qualifiedTypeExpr(packageName, field.Type))
+		}
+	}
+	return provided
+}
+
+// qualifiedTypeExpr returns the source representation of the type, with the exported types of the package
+// qualified by its name, e.g. "*Service" becomes "*services.Service" in the services package.
+func qualifiedTypeExpr(packageName string, expr ast.Expr) string {
+	switch e := expr.(type) {
+	case *ast.Ident:
+		if unicode.IsUpper([]rune(e.Name)[0]) {
+			return packageName + "." + e.Name
+		}
+	case *ast.StarExpr:
+		return "*" + qualifiedTypeExpr(packageName, e.X)
+	case *ast.ArrayType:
+		if e.Len == nil {
+			return "[]" + qualifiedTypeExpr(packageName, e.Elt)
+		}
+	}
+	return types.ExprString(expr)
+}
@@ -2,14 +2,18 @@ package main
 
 import (
 	"fmt"
+	"github.com/a-peyrard/godi/set"
 	"github.com/a-peyrard/godi/slices"
 	"github.com/rs/zerolog"
 	"go/ast"
+	"go/parser"
 	"go/token"
+	"go/types"
 	"golang.org/x/tools/go/packages"
-	"log"
 	"os"
 	"path/filepath"
+	"regexp"
+	stdslices "slices"
 	"strings"
 	"time"
 )
@@ -20,6 +24,21 @@ const (
 	whenAnnotationTag      = "@when"
 	injectAnnotationTag    = "@inject"
 	configAnnotationTag    = "@config"
+	registryAnnotationTag  = "@registry"
+	componentAnnotationTag = "@component"
+)
+
+// typedLoadMode are the load modes required to type the packages, see scanOptions.typed.
+const typedLoadMode = packages.NeedName | packages.NeedTypes | packages.NeedTypesInfo | packages.NeedImports | packages.NeedDeps
+
+var (
+	envVarNameRegex = regexp.MustCompile(`^[A-Z_][A-Z0-9_]*$`)
+	primitiveTypes  = set.NewWithValues(
+		"string", "bool",
+		"int", "int8", "int16", "int32", "int64",
+		"uint", "uint8", "uint16", "uint32", "uint64",
+		"float32", "float64",
+	)
 )
 
 type (
@@ -34,6 +53,21 @@ type (
 		Priority     int
 
 		Conditions []WhenAnnotation
+		// As are the interfaces the components are exposed as, see godi.As
+		As []TypeReference
+		// Scope is the lifetime of the components (singleton, transient or scoped), and Eager makes them
+		// instantiated by Resolver.Warmup
+		Scope string
+		Eager bool
+
+		// position locates the function, and signature types it when the types are loaded (see --validate)
+		position  token.Position
+		signature *types.Signature
+		// provides are the qualified source representations of the provided types, e.g. "*services.Service",
+		// a function returning several components provides each of them
+		provides []string
+		// DirectCall is the option calling the function without reflection, e.g. Call2E, see --direct-calls
+		DirectCall string
 	}
 
 	DecoratorDefinition struct {
@@ -47,17 +81,90 @@ type (
 		Priority     int
 
 		Conditions []WhenAnnotation
+
+		position  token.Position
+		signature *types.Signature
+	}
+
+	// ComponentDefinition is a struct annotated with @component, without a NewX constructor, a provider injecting
+	// its exported fields is registered for it.
+	ComponentDefinition struct {
+		Named       string
+		Description string
+
+		TypeName   string
+		ImportPath string
+
+		Priority int
+
+		Conditions []WhenAnnotation
+		As         []TypeReference
+		Scope      string
+		Eager      bool
+
+		// position locates the struct, and typ is its type when the types are loaded (see --validate)
+		position token.Position
+		typ      types.Type
 	}
 
 	ConfigDefinition struct {
 		TypeName   string
 		ImportPath string
 		Annotation ConfigAnnotation
+
+		// Keys are the names of the config field components, e.g. AppConfig.Database.URL
+		Keys []string
+		// Fields are the fields loaded by config.Load, with their env vars, see collectConfigFields
+		Fields []ConfigFieldDefinition
+
+		typ types.Type
 	}
 
 	RegistryDefinition struct {
 		PackageName string
 		StructName  string
+
+		// Include are the package patterns to scan, relative to the module root
+		Include []string
+		// Exclude are the package patterns to skip, relative to the module root
+		Exclude []string
+	}
+
+	PackageDefinition struct {
+		ImportPath string
+		Name       string
+		Dir        string
+	}
+
+	// Definitions gathers everything found while scanning the module.
+	Definitions struct {
+		Registry    *RegistryDefinition
+		Providers   []ProviderDefinition
+		Decorators  []DecoratorDefinition
+		Components  []ComponentDefinition
+		Configs     []ConfigDefinition
+		EnvBindings []EnvBindingDefinition
+		ConfigKeys  []ConfigKeyDefinition
+		Packages    map[string]PackageDefinition
+		// Diagnostics are the problems found in the annotations, formatted as "file:line: problem"
+		Diagnostics []string
+	}
+
+	EnvBindingDefinition struct {
+		Named  string
+		Type   string
+		Prefix string
+	}
+
+	// scanOptions are the optional definitions to look for while scanning the module.
+	scanOptions struct {
+		envBindings       bool
+		envBindingsPrefix string
+		configKeys        bool
+		// typed loads the types of the packages, to validate the definitions, see validateTypes
+		typed bool
+		// directCalls registers the providers with an option calling them without reflection, see directCallOf
+		directCalls bool
 	}
 )
 
@@ -95,6 +202,31 @@ Dependencies: [%s]`,
 	)
 }
 
+func (c ComponentDefinition) String() string {
+	return fmt.Sprintf(
+		`🧩 Component: %s
+Description: %s
+Import Path: %s
+Named: %s
+Priority: %d`,
+		c.TypeName,
+		c.Description,
+		c.ImportPath,
+		c.Named,
+		c.Priority,
+	)
+}
+
+func (e EnvBindingDefinition) String() string {
+	return fmt.Sprintf(
+		`🌱 Env binding: %s%s
+Type: %s`,
+		e.Prefix,
+		e.Named,
+		e.Type,
+	)
+}
+
 func (c ConfigDefinition) String() string {
 	return fmt.Sprintf(
 		`📦 Config: %s
@@ -104,6 +236,130 @@ Import Path: %s`,
 	)
 }
 
+// parseParamDependencies parses the @inject annotations of the parameters of the function, skipping the first ones.
+func parseParamDependencies(logger *zerolog.Logger, pkg *packages.Package, file *ast.File, fn *ast.FuncDecl, skip int) []InjectAnnotation {
+	if fn.Type.Params == nil || len(fn.Type.Params.List) < skip {
+		return nil
+	}
+	dependencies := make([]InjectAnnotation, len(fn.Type.Params.List)-skip)
+	for idx, param := range fn.Type.Params.List {
+		if idx < skip {
+			continue
+		}
+		for _, paramName := range param.Names {
+			loggerParam := logger.With().Str("param", paramName.Name).Logger()
+
+			dependencies[idx-skip] = parseInjectAnnotation(
+				&loggerParam,
+				findCommentForParam(pkg.Fset, file, param),
+			)
+			dependencies[idx-skip].typeExpr = types.ExprString(param.Type)
+			dependencies[idx-skip].qualifiedType = qualifiedTypeExpr(file.Name.Name, param.Type)
+			dependencies[idx-skip].position = pkg.Fset.Position(param.Pos())
+			if pkg.TypesInfo != nil {
+				dependencies[idx-skip].typ = pkg.TypesInfo.TypeOf(param.Type)
+			}
+		}
+	}
+	return dependencies
+}
+
+// maxDirectCallParams is the number of parameters of the largest godi.CallN option.
+const maxDirectCallParams = 8
+
+// directCallOf returns the godi option calling the function without reflection, e.g. Call2E for a function
+// with two parameters returning an error. It is empty if there is no such option: the generic and variadic functions,
+// or the functions with more than maxDirectCallParams parameters, are called through reflection.
+func directCallOf(fn *ast.FuncDecl) string {
+	if fn.Recv != nil || fn.Type.TypeParams != nil || fn.Type.Results == nil {
+		return ""
+	}
+	params := 0
+	for _, field := range fn.Type.Params.List {
+		if _, variadic := field.Type.(*ast.Ellipsis); variadic {
+			return ""
+		}
+		params += max(len(field.Names), 1)
+	}
+	if params > maxDirectCallParams {
+		return ""
+	}
+
+	var results []ast.Expr
+	for _, field := range fn.Type.Results.List {
+		for range max(len(field.Names), 1) {
+			results = append(results, field.Type)
+		}
+	}
+	switch {
+	case len(results) == 1:
+		return fmt.Sprintf("Call%d", params)
+	case len(results) == 2 && types.ExprString(results[1]) == "error":
+		return fmt.Sprintf("Call%dE", params)
+	default:
+		return ""
+	}
+}
+
+// signatureOf returns the signature of the function, nil if the types are not loaded.
+func signatureOf(pkg *packages.Package, fn *ast.FuncDecl) *types.Signature {
+	if pkg.TypesInfo == nil {
+		return nil
+	}
+	if obj, ok := pkg.TypesInfo.Defs[fn.Name].(*types.Func); ok {
+		return obj.Type().(*types.Signature)
+	}
+	return nil
+}
+
+// typeOf returns the type declared by the type spec, nil if the types are not loaded.
+func typeOf(pkg *packages.Package, typeSpec *ast.TypeSpec) types.Type {
+	if pkg.TypesInfo == nil {
+		return nil
+	}
+	if obj := pkg.TypesInfo.Defs[typeSpec.Name]; obj != nil {
+		return obj.Type()
+	}
+	return nil
+}
+
+// resolveComponents registers the @component structs with their NewX constructor if they have one, the other ones
+// are returned to be registered with a provider injecting their exported fields.
+func resolveComponents(
+	logger *zerolog.Logger,
+	components []ComponentDefinition,
+	constructors map[string]map[string]ProviderDefinition,
+	providers []ProviderDefinition,
+) ([]ProviderDefinition, []ComponentDefinition) {
+	annotated := set.New[string]()
+	for _, p := range providers {
+		annotated.Add(p.ImportPath + "." + p.FnName)
+	}
+
+	var synthesized []ComponentDefinition
+	for _, c := range components {
+		constructor := "New" + c.TypeName
+		if annotated.Contains(c.ImportPath + "." + constructor) {
+			logger.Warn().Msgf("⚠️ component %s is skipped, its constructor %s is already annotated with @provider", c.TypeName, constructor)
+			continue
+		}
+		provider, found := constructors[c.ImportPath][constructor]
+		if !found {
+			synthesized = append(synthesized, c)
+			continue
+		}
+		provider.Named = c.Named
+		provider.Description = c.Description
+		provider.Priority = c.Priority
+		provider.Conditions = c.Conditions
+		provider.As = c.As
+		provider.Scope = c.Scope
+		provider.Eager = c.Eager
+		providers = append(providers, provider)
+	}
+	return providers, synthesized
+}
+
 func findCommentForParam(fset *token.FileSet, file *ast.File, param *ast.Field) string {
 	paramLine := fset.Position(param.Pos()).Line
 
@@ -118,8 +374,111 @@ func findCommentForParam(fset *token.FileSet, file *ast.File, param *ast.Field)
 	return ""
 }
 
-func findModuleRoot() string {
-	dir, _ := os.Getwd()
+// findRegistries looks for the structs embedding godi.EmptyRegistry in the file triggering the generation.
+func findRegistries(logger *zerolog.Logger, filePath string) []*RegistryDefinition {
+	file, err := parser.ParseFile(token.NewFileSet(), filePath, nil, parser.ParseComments)
+	if err != nil {
+		logger.Error().Err(err).Msgf("Failed to parse %s", filePath)
+		return nil
+	}
+
+	var registryDefinitions []*RegistryDefinition
+	ast.Inspect(file, func(n ast.Node) bool {
+		genDecl, ok := n.(*ast.GenDecl)
+		if !ok || genDecl.Tok != token.TYPE {
+			return true
+		}
+		for _, spec := range genDecl.Specs {
+			typeSpec, ok := spec.(*ast.TypeSpec)
+			if !ok {
+				continue
+			}
+			structType, ok := typeSpec.Type.(*ast.StructType)
+			if !ok || !embedsEmptyRegistry(structType) {
+				continue
+			}
+
+			logger := logger.With().Str("struct", typeSpec.Name.Name).Logger()
+			logger.Debug().Msg("=> Found Registry")
+
+			doc := typeSpec.Doc
+			if doc == nil {
+				doc = genDecl.Doc
+			}
+			annotation := parseRegistryAnnotation(&logger, doc.Text())
+			registryDefinitions = append(registryDefinitions, &RegistryDefinition{
+				PackageName: file.Name.Name,
+				StructName:  typeSpec.Name.Name,
+				Include:     annotation.Include(),
+				Exclude:     annotation.Exclude(),
+			})
+		}
+		return true
+	})
+
+	return registryDefinitions
+}
+
+// registryOutputPath returns the path of the file generated for the registry, next to the target file.
+//
+// When the target file declares several registries, each one gets its own file, suffixed by its name.
+func registryOutputPath(targetFilePath string, registry *RegistryDefinition, several bool) string {
+	base := strings.TrimSuffix(filepath.Base(targetFilePath), ".go")
+	if several {
+		base += "_" + strings.ToLower(registry.StructName)
+	}
+	return filepath.Join(filepath.Dir(targetFilePath), base+"_gen.go")
+}
+
+func embedsEmptyRegistry(structType *ast.StructType) bool {
+	for _, field := range structType.Fields.List {
+		if len(field.Names) != 0 { // not an embedded field
+			continue
+		}
+		if sel, ok := field.Type.(*ast.SelectorExpr); ok {
+			if ident, ok := sel.X.(*ast.Ident); ok && ident.Name == "godi" && sel.Sel.Name == "EmptyRegistry" {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// excludePackages removes the packages matching any of the exclude patterns.
+func excludePackages(pkgs []*packages.Package, moduleRoot string, excludes []string) []*packages.Package {
+	if len(excludes) == 0 {
+		return pkgs
+	}
+	return slices.Filter(pkgs, func(pkg *packages.Package) bool {
+		if len(pkg.GoFiles) == 0 {
+			return true
+		}
+		relDir, err := filepath.Rel(moduleRoot, filepath.Dir(pkg.GoFiles[0]))
+		if err != nil {
+			return true
+		}
+		for _, exclude := range excludes {
+			if matchPackagePattern(exclude, relDir) {
+				return false
+			}
+		}
+		return true
+	})
+}
+
+// matchPackagePattern checks if a directory relative to the module root matches a go package pattern,
+// i.e. either "./some/dir" or "./some/dir/..." to match the directory and all its subdirectories.
+func matchPackagePattern(pattern string, relDir string) bool {
+	relDir = filepath.ToSlash(filepath.Clean(relDir))
+	if recursive, found := strings.CutSuffix(pattern, "/..."); found {
+		base := filepath.ToSlash(filepath.Clean(recursive))
+		return base == "." || relDir == base || strings.HasPrefix(relDir, base+"/")
+	}
+	return relDir == filepath.ToSlash(filepath.Clean(pattern))
+}
+
+// findModuleRoot returns the closest directory containing a go.mod, starting from dir.
+func findModuleRoot(dir string) string {
 	for {
 		if _, err := os.Stat(filepath.Join(dir, "go.mod")); err == nil {
 			return dir
@@ -133,45 +492,92 @@ func findModuleRoot() string {
 	return "."
 }
 
-func main() {
-	dryRun := os.Getenv("DRY_RUN") == "true"
-
-	zerolog.SetGlobalLevel(zerolog.DebugLevel)
-	logger := zerolog.New(zerolog.ConsoleWriter{Out: os.Stderr, TimeFormat: time.DateTime}).
-		With().
-		Timestamp().
-		Logger()
+// findEnvBindings looks for named injections that no provider can satisfy, and that look like environment variables.
+//
+// Only primitive types are considered, as those are the only ones that can be converted from the raw env value.
+func findEnvBindings(
+	providers []ProviderDefinition,
+	decorators []DecoratorDefinition,
+	prefix string,
+) []EnvBindingDefinition {
+	provided := set.New[string]()
+	for _, p := range providers {
+		provided.Add(p.Named)
+	}
 
-	startScan := time.Now()
+	var injections []InjectAnnotation
+	for _, p := range providers {
+		injections = append(injections, p.Dependencies...)
+	}
+	for _, d := range decorators {
+		injections = append(injections, d.Dependencies...)
+	}
 
-	// capture the target file/package, where the generator is invoked
-	targetFile := os.Getenv("GOFILE")
-	targetPackage := os.Getenv("GOPACKAGE")
-	currentDir, _ := os.Getwd()
-	targetFilePath := filepath.Join(currentDir, targetFile)
+	bindings := make(map[string]EnvBindingDefinition)
+	for _, injection := range injections {
+		named, found := injection.Named()
+		if !found || provided.Contains(named) || !envVarNameRegex.MatchString(named) {
+			continue
+		}
+		if primitiveTypes.DoesNotContain(injection.typeExpr) {
+			continue
+		}
+		if _, exists := bindings[named]; !exists {
+			bindings[named] = EnvBindingDefinition{
+				Named:  named,
+				Type:   injection.typeExpr,
+				Prefix: prefix,
+			}
+		}
+	}
 
-	// no switch to the root of the module as we want to be able to scan the whole module
-	moduleRoot := findModuleRoot()
-	err := os.Chdir(moduleRoot)
-	if err != nil {
-		log.Fatalf("Failed to change directory to module root: %v\n", err)
+	definitions := make([]EnvBindingDefinition, 0, len(bindings))
+	for _, binding := range bindings {
+		definitions = append(definitions, binding)
 	}
+	stdslices.SortFunc(definitions, func(a, b EnvBindingDefinition) int {
+		return strings.Compare(a.Named, b.Named)
+	})
+	return definitions
+}
+
+// scanModule scans the packages in the scope of the registry, looking for the definitions to register.
+func scanModule(logger zerolog.Logger, moduleRoot string, registryDefinition *RegistryDefinition, options scanOptions) Definitions {
+	startScan := time.Now()
 
 	// analyze all the packages in the module
 	// we are looking for multiple things:
 	// - functions annotated with @provider
 	// - functions annotated with @decorator
-	// - a struct that embeds gogodi.EmptyRegistry
 	// - struct with @config annotation
+	// - struct with @component annotation
 	var providerDefinitions []ProviderDefinition
 	var decoratorDefinitions []DecoratorDefinition
 	var configDefinitions []ConfigDefinition
-	var registryDefinition *RegistryDefinition
+	var componentDefinitions []ComponentDefinition
+	packageDefinitions := make(map[string]PackageDefinition)
+	structTypes := make(map[string]map[string]*ast.StructType)     // import path -> struct name -> struct
+	constructors := make(map[string]map[string]ProviderDefinition) // import path -> function name -> constructor
+	var diagnostics []string
+	diagnose := func(position token.Position, problems ...string) {
+		for _, problem := range problems {
+			diagnostics = append(diagnostics, fmt.Sprintf("%s:%d: %s", position.Filename, position.Line, problem))
+		}
+	}
+	diagnoseDependencies := func(dependencies []InjectAnnotation) {
+		for _, dependency := range dependencies {
+			diagnose(dependency.position, dependency.Problems()...)
+		}
+	}
 
 	cfg := &packages.Config{
 		Mode: packages.NeedFiles | packages.NeedSyntax,
 	}
-	pkgs, _ := packages.Load(cfg, "./...")
+	if options.typed {
+		cfg.Mode |= typedLoadMode
+	}
+	pkgs, _ := packages.Load(cfg, registryDefinition.Include...)
+	pkgs = excludePackages(pkgs, moduleRoot, registryDefinition.Exclude)
 
 	allPackages := make(map[string]*packages.Package)
 	for _, pkg := range pkgs {
@@ -186,38 +592,10 @@ func main() {
 			filePath := pkg.Fset.Position(file.Pos()).Filename
 			packageName := file.Name.Name
 			importPath := pkg.ID
-
-			// only look for Registry struct in the file triggering the generation
-			if filePath == targetFilePath {
-				// Look for struct embedding gogodi.EmptyRegistry
-				ast.Inspect(file, func(n ast.Node) bool {
-					if genDecl, ok := n.(*ast.GenDecl); ok && genDecl.Tok == token.TYPE {
-						for _, spec := range genDecl.Specs {
-							if typeSpec, ok := spec.(*ast.TypeSpec); ok {
-								if structType, ok := typeSpec.Type.(*ast.StructType); ok {
-									for _, field := range structType.Fields.List {
-										if len(field.Names) == 0 { // Embedded field
-											if sel, ok := field.Type.(*ast.SelectorExpr); ok {
-												if ident, ok := sel.X.(*ast.Ident); ok {
-													if ident.Name == "godi" && sel.Sel.Name == "EmptyRegistry" {
-														logger := logger.With().Str("struct", typeSpec.Name.Name).Logger()
-
-														logger.Debug().Msg("=> Found Registry")
-														registryDefinition = &RegistryDefinition{
-															PackageName: packageName,
-															StructName:  typeSpec.Name.Name,
-														}
-													}
-												}
-											}
-										}
-									}
-								}
-							}
-						}
-					}
-					return true
-				})
+			packageDefinitions[importPath] = PackageDefinition{
+				ImportPath: importPath,
+				Name:       packageName,
+				Dir:        filepath.Dir(filePath),
 			}
 
 			// look for @provider functions
@@ -240,19 +618,11 @@ func main() {
 							priority = p
 						}
 
-						dependencies := make([]InjectAnnotation, len(fn.Type.Params.List))
-						if fn.Type.Params != nil {
-							for idx, param := range fn.Type.Params.List {
-								for _, paramName := range param.Names {
-									loggerParam := logger.With().Str("param", paramName.Name).Logger()
-
-									dependencies[idx] = parseInjectAnnotation(
-										&loggerParam,
-										findCommentForParam(pkg.Fset, file, param),
-									)
-								}
-							}
-						}
+						dependencies := parseParamDependencies(&logger, pkg, file, fn, 0)
+						diagnose(pkg.Fset.Position(fn.Pos()), providerAnnotation.Problems()...)
+						diagnoseDependencies(dependencies)
+						as, problems := parseTypeReferences(file, importPath, providerAnnotation.As())
+						diagnose(pkg.Fset.Position(fn.Pos()), problems...)
 
 						providerDefinitions = append(providerDefinitions, ProviderDefinition{
 							FnName:       fn.Name.Name,
@@ -262,12 +632,22 @@ func main() {
 							Priority:     priority,
 							Dependencies: dependencies,
 							Conditions:   providerAnnotation.conditions,
+							As:           as,
+							Scope:        providerAnnotation.Scope(),
+							Eager:        providerAnnotation.Eager(),
+							position:     pkg.Fset.Position(fn.Pos()),
+							signature:    signatureOf(pkg, fn),
+							provides:     providedTypesOf(packageName, fn),
 						})
+						if options.directCalls {
+							providerDefinitions[len(providerDefinitions)-1].DirectCall = directCallOf(fn)
+						}
 					} else if fn.Doc != nil && strings.Contains(fn.Doc.Text(), decoratorAnnotationTag) {
 						logger := logger.With().Str("provider", fn.Name.Name).Logger()
 
 						logger.Debug().Msg("=> Found decorator")
 						decoratorAnnotation := parseProviderDecoratorAnnotation(&logger, fn.Name.Name, fn.Doc.Text(), decoratorAnnotationTag)
+						diagnose(pkg.Fset.Position(fn.Pos()), decoratorAnnotation.Problems()...)
 
 						var (
 							decorate string
@@ -276,30 +656,16 @@ func main() {
 						if n, found := decoratorAnnotation.Named(); found {
 							decorate = n
 						} else {
-							logger.Error().Msgf("Decorator %s must have a named property to name the component being decorated", fn.Name.Name)
+							diagnose(pkg.Fset.Position(fn.Pos()), fmt.Sprintf("decorator %s must have a named property to name the component being decorated, skipping it", fn.Name.Name))
 							return true
 						}
 						if p, found := decoratorAnnotation.Priority(); found {
 							priority = p
 						}
 
-						dependencies := make([]InjectAnnotation, len(fn.Type.Params.List)-1) // skip the first parameter
-						if fn.Type.Params != nil {
-							for idx, param := range fn.Type.Params.List {
-								for _, paramName := range param.Names {
-									if idx == 0 {
-										// skip the first parameter as it's the component being decorated
-										continue
-									}
-									loggerParam := logger.With().Str("param", paramName.Name).Logger()
-
-									dependencies[idx-1] = parseInjectAnnotation(
-										&loggerParam,
-										findCommentForParam(pkg.Fset, file, param),
-									)
-								}
-							}
-						}
+						// skip the first parameter as it's the component being decorated
+						dependencies := parseParamDependencies(&logger, pkg, file, fn, 1)
+						diagnoseDependencies(dependencies)
 
 						decoratorDefinitions = append(decoratorDefinitions, DecoratorDefinition{
 							FnName:       fn.Name.Name,
@@ -309,27 +675,81 @@ func main() {
 							Priority:     priority,
 							Dependencies: dependencies,
 							Conditions:   decoratorAnnotation.conditions,
+							position:     pkg.Fset.Position(fn.Pos()),
+							signature:    signatureOf(pkg, fn),
 						})
+					} else if fn.Recv == nil && strings.HasPrefix(fn.Name.Name, "New") {
+						// the constructors are kept, to register the @component structs with them
+						logger := logger.With().Str("constructor", fn.Name.Name).Logger()
+						if constructors[importPath] == nil {
+							constructors[importPath] = make(map[string]ProviderDefinition)
+						}
+						constructors[importPath][fn.Name.Name] = ProviderDefinition{
+							FnName:       fn.Name.Name,
+							ImportPath:   importPath,
+							Dependencies: parseParamDependencies(&logger, pkg, file, fn, 0),
+							position:     pkg.Fset.Position(fn.Pos()),
+							signature:    signatureOf(pkg, fn),
+							provides:     providedTypesOf(packageName, fn),
+						}
 					}
 				} else if genDecl, ok := n.(*ast.GenDecl); ok && genDecl.Tok == token.TYPE {
 					// look for structs annotated with @config
 					for _, spec := range genDecl.Specs {
 						if typeSpec, ok := spec.(*ast.TypeSpec); ok {
-							if _, ok := typeSpec.Type.(*ast.StructType); ok {
+							if structType, ok := typeSpec.Type.(*ast.StructType); ok {
+								// all the structs are kept, to find the fields of the nested structs of the configs
+								if structTypes[importPath] == nil {
+									structTypes[importPath] = make(map[string]*ast.StructType)
+								}
+								structTypes[importPath][typeSpec.Name.Name] = structType
+
 								if genDecl.Doc != nil && strings.Contains(genDecl.Doc.Text(), configAnnotationTag) {
 									logger := logger.With().Str("struct", typeSpec.Name.Name).Logger()
 
 									logger.Debug().Msg("=> Found config")
+									configAnnotation := parseConfigAnnotation(&logger, typeSpec.Name.Name, genDecl.Doc.Text())
+									diagnose(pkg.Fset.Position(typeSpec.Pos()), configAnnotation.Problems()...)
 
 									configDefinitions = append(
 										configDefinitions,
 										ConfigDefinition{
 											TypeName:   typeSpec.Name.Name,
 											ImportPath: importPath,
-											Annotation: parseConfigAnnotation(&logger, typeSpec.Name.Name, genDecl.Doc.Text()),
+											Annotation: configAnnotation,
+											typ:        typeOf(pkg, typeSpec),
 										},
 									)
 								}
+
+								if genDecl.Doc != nil && strings.Contains(genDecl.Doc.Text(), componentAnnotationTag) {
+									logger := logger.With().Str("struct", typeSpec.Name.Name).Logger()
+
+									logger.Debug().Msg("=> Found component")
+									componentAnnotation := parseProviderDecoratorAnnotation(&logger, typeSpec.Name.Name, genDecl.Doc.Text(), componentAnnotationTag)
+									diagnose(pkg.Fset.Position(typeSpec.Pos()), componentAnnotation.Problems()...)
+									as, problems := parseTypeReferences(file, importPath, componentAnnotation.As())
+									diagnose(pkg.Fset.Position(typeSpec.Pos()), problems...)
+
+									component := ComponentDefinition{
+										TypeName:    typeSpec.Name.Name,
+										ImportPath:  importPath,
+										Description: componentAnnotation.description,
+										Conditions:  componentAnnotation.conditions,
+										As:          as,
+										Scope:       componentAnnotation.Scope(),
+										Eager:       componentAnnotation.Eager(),
+										position:    pkg.Fset.Position(typeSpec.Pos()),
+										typ:         typeOf(pkg, typeSpec),
+									}
+									if n, found := componentAnnotation.Named(); found {
+										component.Named = n
+									}
+									if p, found := componentAnnotation.Priority(); found {
+										component.Priority = p
+									}
+									componentDefinitions = append(componentDefinitions, component)
+								}
 							}
 						}
 					}
@@ -339,11 +759,30 @@ func main() {
 		}
 	}
 
-	stopScan := time.Now()
+	providerDefinitions, componentDefinitions = resolveComponents(&logger, componentDefinitions, constructors, providerDefinitions)
+	var typedPackages map[string]*packages.Package
+	if options.typed {
+		typedPackages = allPackages
+	}
+	if err := resolveBindingTypes(providerDefinitions, componentDefinitions, typedPackages); err != nil {
+		logger.Warn().Err(err).Msg("⚠️ the interfaces the components are exposed as can not be checked")
+	}
+
+	for i, config := range configDefinitions {
+		configDefinitions[i].Keys = collectConfigKeys(config.TypeName, structTypes[config.ImportPath])
+		configDefinitions[i].Fields = collectConfigFields(config, structTypes[config.ImportPath])
+	}
+	for _, problem := range validateConfigInjections(providerDefinitions, decoratorDefinitions, configDefinitions) {
+		logger.Warn().Msgf("⚠️ %s", problem)
+	}
+	var configKeyDefinitions []ConfigKeyDefinition
+	if options.configKeys {
+		configKeyDefinitions = findConfigKeys(configDefinitions)
+	}
 
-	if registryDefinition == nil {
-		logger.Error().Msgf("No Registry struct found in the target package: %s, make sure you have a struct like this:\ntype Registry {\n    gogodi.EmptyRegistry\n}", targetPackage)
-		os.Exit(1)
+	var envBindingDefinitions []EnvBindingDefinition
+	if options.envBindings {
+		envBindingDefinitions = findEnvBindings(providerDefinitions, decoratorDefinitions, options.envBindingsPrefix)
 	}
 
 	logger.Info().Msgf("👨‍🔧 Registry found: %+v", registryDefinition)
@@ -353,25 +792,29 @@ func main() {
 	logger.Info().Msgf("🎯 %d decorators found in the module", len(decoratorDefinitions))
 	decoratorDefinitionsLogs := slices.Map(decoratorDefinitions, DecoratorDefinition.String)
 	logger.Debug().Msgf("Decorators:\n%s", strings.Join(decoratorDefinitionsLogs, "\n----\n"))
+	logger.Info().Msgf("🎯 %d components without constructor found in the module", len(componentDefinitions))
+	componentsLogs := slices.Map(componentDefinitions, ComponentDefinition.String)
+	logger.Debug().Msgf("Components:\n%s", strings.Join(componentsLogs, "\n----\n"))
 	logger.Info().Msgf("🎯 %d config found in the module", len(configDefinitions))
 	configsLogs := slices.Map(configDefinitions, ConfigDefinition.String)
 	logger.Debug().Msgf("Configs:\n%s", strings.Join(configsLogs, "\n----\n"))
-	logger.Info().Msgf("🕵️‍♂️ Scanning completed in %s", stopScan.Sub(startScan))
-
-	// generate the code
-	outputPath := filepath.Join(
-		filepath.Dir(targetFilePath),
-		strings.TrimSuffix(filepath.Base(targetFilePath), ".go")+"_gen.go",
-	)
-	if dryRun {
-		outputPath = filepath.Join("/tmp", filepath.Base(outputPath))
+	if options.envBindings {
+		logger.Info().Msgf("🎯 %d env bindings found in the module", len(envBindingDefinitions))
+		envBindingsLogs := slices.Map(envBindingDefinitions, EnvBindingDefinition.String)
+		logger.Debug().Msgf("Env bindings:\n%s", strings.Join(envBindingsLogs, "\n----\n"))
 	}
-
-	err = generateCode(outputPath, registryDefinition, providerDefinitions, decoratorDefinitions, configDefinitions)
-	if err != nil {
-		logger.Error().Err(err).Msgf("Failed to generate code in %s", outputPath)
-		os.Exit(1)
-	} else {
-		logger.Info().Msgf("✅ Code generated successfully in %s", outputPath)
+	logger.Info().Msgf("🕵️‍♂️ Scanning completed in %s", time.Since(startScan))
+
+	return Definitions{
+		Registry:    registryDefinition,
+		Providers:   providerDefinitions,
+		Decorators:  decoratorDefinitions,
+		Components:  componentDefinitions,
+		Configs:     configDefinitions,
+		EnvBindings: envBindingDefinitions,
+		ConfigKeys:  configKeyDefinitions,
+		Packages:    packageDefinitions,
+		Diagnostics: diagnostics,
 	}
+
 }
@@ -8,6 +8,7 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/rs/zerolog"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -27,7 +28,8 @@ func TestCodeGeneration(t *testing.T) {
 
 	testCases := []struct {
 		name    string
-		fixture string // directory name in etc/gen/
+		fixture string   // directory name in etc/gen/
+		env     []string // additional env vars for the generator
 	}{
 		{
 			name:    "simple provider",
@@ -49,6 +51,14 @@ func TestCodeGeneration(t *testing.T) {
 			name:    "config struct",
 			fixture: "config",
 		},
+		{
+			name:    "config struct with command-line flags",
+			fixture: "config_flags",
+		},
+		{
+			name:    "config structs with conditions",
+			fixture: "conditional_config",
+		},
 		{
 			name:    "provider with conditions",
 			fixture: "conditional_provider",
@@ -57,10 +67,41 @@ func TestCodeGeneration(t *testing.T) {
 			name:    "multiple providers same name",
 			fixture: "multiple_providers",
 		},
+		{
+			name:    "components with and without constructor",
+			fixture: "component",
+		},
+		{
+			name:    "providers and components exposed as interfaces",
+			fixture: "as_bindings",
+		},
+		{
+			name:    "providers and components with lifetimes",
+			fixture: "provider_lifetimes",
+		},
 		{
 			name:    "complex scenario",
 			fixture: "complex",
 		},
+		{
+			name:    "registry with include and exclude scopes",
+			fixture: "registry_scope",
+		},
+		{
+			name:    "env bindings for unresolved named injections",
+			fixture: "env_bindings",
+			env:     []string{"GODI_ENV_BINDINGS=true", "GODI_ENV_PREFIX=APP_"},
+		},
+		{
+			name:    "providers called directly",
+			fixture: "direct_calls",
+			env:     []string{"GODI_DIRECT_CALLS=true"},
+		},
+		{
+			name:    "static part of the graph wired",
+			fixture: "wiring",
+			env:     []string{"GODI_WIRE=true"},
+		},
 	}
 
 	for _, tc := range testCases {
@@ -69,7 +110,7 @@ func TestCodeGeneration(t *testing.T) {
 			tempDir := setupTestProject(t, tc.fixture)
 
 			// WHEN
-			err := runGenerator(t, scriptPath, tempDir)
+			err := runGenerator(t, scriptPath, tempDir, tc.env)
 
 			// THEN
 			require.NoError(t, err)
@@ -78,6 +119,286 @@ func TestCodeGeneration(t *testing.T) {
 	}
 }
 
+func TestSplitPackages(t *testing.T) {
+	t.Run("it should generate one registration file per package and an aggregator", func(t *testing.T) {
+		// GIVEN
+		scriptPath := findScriptPath()
+		fixture := "split_packages"
+		tempDir := setupTestProject(t, fixture)
+
+		// WHEN
+		err := runGenerator(t, scriptPath, tempDir, []string{"GODI_SPLIT_PACKAGES=true"})
+
+		// THEN
+		require.NoError(t, err)
+		for _, generated := range []string{
+			filepath.Join("registry", "registry_gen.go"),
+			filepath.Join("providers", "godi_gen.go"),
+			filepath.Join("config", "godi_gen.go"),
+			filepath.Join("decorators", "godi_gen.go"),
+		} {
+			assertGeneratedFile(
+				t,
+				filepath.Join(tempDir, generated),
+				filepath.Join("etc", "gen", fixture, filepath.Dir(generated), "expected_"+filepath.Base(generated)+".golden"),
+			)
+		}
+	})
+
+	t.Run("it should delete the registration files of the packages once not split anymore", func(t *testing.T) {
+		// GIVEN
+		scriptPath := findScriptPath()
+		tempDir := setupTestProject(t, "split_packages")
+		require.NoError(t, runGenerator(t, scriptPath, tempDir, []string{"GODI_SPLIT_PACKAGES=true"}))
+		handwritten := []byte("package config\n")
+		require.NoError(t, os.WriteFile(filepath.Join(tempDir, "config", "godi_gen.go"), handwritten, 0644))
+
+		// WHEN
+		err := runGenerator(t, scriptPath, tempDir, nil)
+
+		// THEN
+		require.NoError(t, err)
+		assert.NoFileExists(t, filepath.Join(tempDir, "providers", "godi_gen.go"))
+		assert.NoFileExists(t, filepath.Join(tempDir, "decorators", "godi_gen.go"))
+		actual, err := os.ReadFile(filepath.Join(tempDir, "config", "godi_gen.go"))
+		require.NoError(t, err)
+		assert.Equal(t, handwritten, actual, "a file not generated should be kept")
+	})
+
+	t.Run("it should report the stale registration files in check mode", func(t *testing.T) {
+		// GIVEN
+		scriptPath := findScriptPath()
+		tempDir := setupTestProject(t, "split_packages")
+		require.NoError(t, runGenerator(t, scriptPath, tempDir, []string{"GODI_SPLIT_PACKAGES=true"}))
+		require.NoError(t, runGenerator(t, scriptPath, tempDir, nil))
+		stale := []byte("// Code generated by go generate; DO NOT EDIT!\n\npackage providers\n")
+		stalePath := filepath.Join(tempDir, "providers", "godi_gen.go")
+		require.NoError(t, os.WriteFile(stalePath, stale, 0644))
+
+		// WHEN
+		err := runGenerator(t, scriptPath, tempDir, nil, "--check")
+
+		// THEN
+		require.Error(t, err)
+		assert.FileExists(t, stalePath)
+	})
+}
+
+func TestMultipleRegistries(t *testing.T) {
+	t.Run("it should generate each registry of the target file with its own scope", func(t *testing.T) {
+		// GIVEN
+		scriptPath := findScriptPath()
+		fixture := "multiple_registries"
+		tempDir := setupTestProject(t, fixture)
+
+		// WHEN
+		err := runGenerator(t, scriptPath, tempDir, nil)
+
+		// THEN
+		require.NoError(t, err)
+		for _, generated := range []string{"registry_apiregistry_gen.go", "registry_workerregistry_gen.go"} {
+			assertGeneratedFile(
+				t,
+				filepath.Join(tempDir, "registry", generated),
+				filepath.Join("etc", "gen", fixture, "registry", "expected_"+generated+".golden"),
+			)
+		}
+	})
+}
+
+func TestDependencyGraph(t *testing.T) {
+	t.Run("it should generate the dependency graph next to the registry", func(t *testing.T) {
+		// GIVEN
+		scriptPath := findScriptPath()
+		fixture := "complex"
+		tempDir := setupTestProject(t, fixture)
+
+		// WHEN
+		err := runGenerator(t, scriptPath, tempDir, []string{"GODI_GRAPH=dot"})
+
+		// THEN
+		require.NoError(t, err)
+		assertGeneratedCode(t, tempDir, fixture)
+		assertGeneratedFile(
+			t,
+			filepath.Join(tempDir, "registry", "registry_graph.dot"),
+			filepath.Join("etc", "gen", fixture, "expected_graph.dot.golden"),
+		)
+	})
+
+	t.Run("it should fail for an unsupported format", func(t *testing.T) {
+		// GIVEN
+		scriptPath := findScriptPath()
+		tempDir := setupTestProject(t, "simple_provider")
+
+		// WHEN
+		err := runGenerator(t, scriptPath, tempDir, nil, "--graph", "png")
+
+		// THEN
+		require.Error(t, err)
+		assert.NoFileExists(t, filepath.Join(tempDir, "registry_gen.go"))
+	})
+}
+
+func TestConfigKeys(t *testing.T) {
+	t.Run("it should generate the config keys package next to the registry", func(t *testing.T) {
+		// GIVEN
+		scriptPath := findScriptPath()
+		fixture := "config_keys"
+		tempDir := setupTestProject(t, fixture)
+
+		// WHEN
+		err := runGenerator(t, scriptPath, tempDir, []string{"GODI_CONFIG_KEYS=true"})
+
+		// THEN
+		require.NoError(t, err)
+		assertGeneratedFile(
+			t,
+			filepath.Join(tempDir, "registry_gen.go"),
+			filepath.Join("etc", "gen", fixture, "expected_gen.go.golden"),
+		)
+		assertGeneratedFile(
+			t,
+			filepath.Join(tempDir, configKeysPackage, configKeysOutputFile),
+			filepath.Join("etc", "gen", fixture, "expected_cfgkeys_gen.go.golden"),
+		)
+	})
+}
+
+func TestConfigManifest(t *testing.T) {
+	for _, format := range []string{"md", "json"} {
+		t.Run("it should generate the "+format+" config manifest next to the registry", func(t *testing.T) {
+			// GIVEN
+			scriptPath := findScriptPath()
+			fixture := "config_manifest"
+			tempDir := setupTestProject(t, fixture)
+
+			// WHEN
+			err := runGenerator(t, scriptPath, tempDir, []string{"GODI_CONFIG_MANIFEST=" + format})
+
+			// THEN
+			require.NoError(t, err)
+			assertGeneratedCode(t, tempDir, fixture)
+			assertGeneratedFile(
+				t,
+				filepath.Join(tempDir, "registry_config."+format),
+				filepath.Join("etc", "gen", fixture, "expected_config."+format+".golden"),
+			)
+		})
+	}
+}
+
+func TestCheckMode(t *testing.T) {
+	scriptPath := findScriptPath()
+
+	t.Run("it should succeed if the generated code is up-to-date", func(t *testing.T) {
+		// GIVEN
+		tempDir := setupTestProject(t, "simple_provider")
+		golden, err := os.ReadFile(filepath.Join("etc", "gen", "simple_provider", "expected_gen.go.golden"))
+		require.NoError(t, err)
+		require.NoError(t, os.WriteFile(filepath.Join(tempDir, "registry_gen.go"), golden, 0644))
+
+		// WHEN
+		err = runGenerator(t, scriptPath, tempDir, nil, "--check")
+
+		// THEN
+		require.NoError(t, err)
+	})
+
+	t.Run("it should fail without touching the file if the generated code is stale", func(t *testing.T) {
+		// GIVEN
+		tempDir := setupTestProject(t, "simple_provider")
+		stale := []byte("// Code generated by go generate; DO NOT EDIT!\n\npackage services\n")
+		generatedPath := filepath.Join(tempDir, "registry_gen.go")
+		require.NoError(t, os.WriteFile(generatedPath, stale, 0644))
+
+		// WHEN
+		err := runGenerator(t, scriptPath, tempDir, nil, "--check")
+
+		// THEN
+		require.Error(t, err)
+		actual, err := os.ReadFile(generatedPath)
+		require.NoError(t, err)
+		assert.Equal(t, stale, actual)
+	})
+
+	t.Run("it should fail if the generated code does not exist", func(t *testing.T) {
+		// GIVEN
+		tempDir := setupTestProject(t, "simple_provider")
+
+		// WHEN
+		err := runGenerator(t, scriptPath, tempDir, nil, "--check")
+
+		// THEN
+		require.Error(t, err)
+		assert.NoFileExists(t, filepath.Join(tempDir, "registry_gen.go"))
+	})
+}
+
+func TestStrictMode(t *testing.T) {
+	scriptPath := findScriptPath()
+
+	t.Run("it should generate the code despite the annotation problems by default", func(t *testing.T) {
+		// GIVEN
+		tempDir := setupTestProject(t, "diagnostics")
+
+		// WHEN
+		err := runGenerator(t, scriptPath, tempDir, nil)
+
+		// THEN
+		require.NoError(t, err)
+		assert.FileExists(t, filepath.Join(tempDir, "registry_gen.go"))
+	})
+
+	t.Run("it should fail without generating anything if the annotations have problems", func(t *testing.T) {
+		// GIVEN
+		tempDir := setupTestProject(t, "diagnostics")
+
+		// WHEN
+		err := runGenerator(t, scriptPath, tempDir, []string{"GODI_STRICT=true"})
+
+		// THEN
+		require.Error(t, err)
+		assert.NoFileExists(t, filepath.Join(tempDir, "registry_gen.go"))
+	})
+
+	t.Run("it should succeed if the annotations have no problem", func(t *testing.T) {
+		// GIVEN
+		tempDir := setupTestProject(t, "simple_provider")
+
+		// WHEN
+		err := runGenerator(t, scriptPath, tempDir, nil, "--strict")
+
+		// THEN
+		require.NoError(t, err)
+		assertGeneratedCode(t, tempDir, "simple_provider")
+	})
+}
+
+func Test_scanModuleDiagnostics(t *testing.T) {
+	t.Run("it should report the problems of the annotations with their position", func(t *testing.T) {
+		// GIVEN
+		tempDir := setupTestProject(t, "diagnostics")
+		t.Chdir(tempDir)
+		providerFile := filepath.Join(tempDir, "provider.go")
+
+		// WHEN
+		defs := scanModule(zerolog.Nop(), tempDir, &RegistryDefinition{Include: []string{"./..."}}, scanOptions{})
+
+		// THEN
+		assert.Equal(t, []string{
+			providerFile + `:10: unknown property "fallback" in @when`,
+			providerFile + `:10: invalid priority "high" in @provider, expecting an integer`,
+			providerFile + `:10: invalid scope "request" in @provider, expecting one of: singleton, transient, scoped, per-consumer`,
+			providerFile + `:16: unknown annotation @cached`,
+			providerFile + `:17: unknown property "lazy" in @inject`,
+			providerFile + `:17: invalid optional "maybe" in @inject, expecting a boolean`,
+			providerFile + `:18: invalid default value "eighty" for a dependency of type int in @inject`,
+			providerFile + `:24: decorator DecorateHandler must have a named property to name the component being decorated, skipping it`,
+		}, defs.Diagnostics)
+	})
+}
+
 func setupTestProject(t *testing.T, fixture string) string {
 	tempDir := t.TempDir()
 
@@ -120,7 +441,7 @@ func copyDir(src, dst string) error {
 	})
 }
 
-func runGenerator(t *testing.T, scriptPath string, projectDir string) error {
+func runGenerator(t *testing.T, scriptPath string, projectDir string, env []string, args ...string) error {
 	// Find the registry file
 	registryFile := "registry.go"
 	registryPath := filepath.Join(projectDir, registryFile)
@@ -165,13 +486,14 @@ func runGenerator(t *testing.T, scriptPath string, projectDir string) error {
 	}
 
 	// Now run the built binary in the test directory
-	cmd := exec.Command(generatorBinary)
+	cmd := exec.Command(generatorBinary, args...)
 	cmd.Dir = registryDir
 	cmd.Env = append(os.Environ(),
 		"GOFILE="+registryFile,
 		"GOPACKAGE="+registryPackage,
 		"DRY_RUN=false",
 	)
+	cmd.Env = append(cmd.Env, env...)
 
 	output, err := cmd.CombinedOutput()
 	if err != nil {
@@ -216,13 +538,19 @@ func assertGeneratedCode(t *testing.T, projectDir string, fixture string) {
 	require.NoError(t, err)
 	require.NotEmpty(t, generatedFile, "Generated file not found")
 
+	assertGeneratedFile(t, generatedFile, filepath.Join("etc", "gen", fixture, "expected_gen.go.golden"))
+}
+
+func assertGeneratedFile(t *testing.T, generatedFile string, goldenFile string) {
 	actual, err := os.ReadFile(generatedFile)
 	require.NoError(t, err)
 
-	goldenFile := filepath.Join("etc", "gen", fixture, "expected_gen.go.golden")
+	assertGeneratedContent(t, actual, goldenFile)
+}
 
+func assertGeneratedContent(t *testing.T, actual []byte, goldenFile string) {
 	if *updateGolden {
-		err = os.WriteFile(goldenFile, actual, 0644)
+		err := os.WriteFile(goldenFile, actual, 0644)
 		require.NoError(t, err, "Failed to update golden file")
 		t.Logf("Updated golden file: %s", goldenFile)
 		return
@@ -247,3 +575,28 @@ func normalizeCode(code string) string {
 	}
 	return strings.Join(lines, "\n")
 }
+
+func Test_matchPackagePattern(t *testing.T) {
+	testCases := []struct {
+		pattern  string
+		relDir   string
+		expected bool
+	}{
+		{pattern: "./...", relDir: "services/api", expected: true},
+		{pattern: "./services/...", relDir: "services", expected: true},
+		{pattern: "./services/...", relDir: "services/api/v1", expected: true},
+		{pattern: "./services/...", relDir: "servicesfoo", expected: false},
+		{pattern: "./services", relDir: "services", expected: true},
+		{pattern: "./services", relDir: "services/api", expected: false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.pattern+" on "+tc.relDir, func(t *testing.T) {
+			// WHEN
+			result := matchPackagePattern(tc.pattern, tc.relDir)
+
+			// THEN
+			assert.Equal(t, tc.expected, result)
+		})
+	}
+}
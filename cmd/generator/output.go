@@ -1,6 +1,7 @@
 package main
 
 import (
+	"bytes"
 	"fmt"
 	"github.com/a-peyrard/godi/set"
 	"github.com/a-peyrard/godi/slices"
@@ -15,6 +16,7 @@ import (
 const (
 	diImportPath           = "github.com/a-peyrard/godi"
 	configLoaderImportPath = "github.com/a-peyrard/godi/config"
+	flagImportPath         = "flag"
 )
 
 const registryTemplate = `// Code generated by go generate; DO NOT EDIT!
@@ -26,7 +28,37 @@ import (
 {{end}})
 
 func ({{.StructName}}) Register(resolver *godi.Resolver) {
-{{range .Providers}}{{if .Options}}	resolver.MustRegister(
+{{range .Declarations}}	{{.}}
+{{end}}{{range .Packages}}	{{.}}.RegisterGodiComponents(resolver)
+{{end}}{{range .Providers}}{{if .Options}}	resolver.MustRegister(
+		{{.FnName}},
+{{range .Options}}		{{.}},
+{{end}}	)
+{{else}}	resolver.MustRegister({{.FnName}})
+{{end}}{{end}}}
+
+// With composes the registry with the registries of other modules, see godi.Compose.
+func (r {{.StructName}}) With(others ...godi.Registry) godi.CompositeRegistry {
+	return godi.Compose(r).With(others...)
+}
+{{.Wiring}}`
+
+const packageOutputFile = "godi_gen.go"
+
+const generatedHeader = "// Code generated by go generate; DO NOT EDIT!"
+
+const packageRegistrationTemplate = `// Code generated by go generate; DO NOT EDIT!
+
+package {{.PackageName}}
+
+import (
+{{range .Imports}}	{{.}}
+{{end}})
+
+// RegisterGodiComponents registers the components declared in this package.
+func RegisterGodiComponents(resolver *godi.Resolver) {
+{{range .Declarations}}	{{.}}
+{{end}}{{range .Providers}}{{if .Options}}	resolver.MustRegister(
 		{{.FnName}},
 {{range .Options}}		{{.}},
 {{end}}	)
@@ -47,6 +79,7 @@ func providerToRegistrationTemplate(p ProviderDefinition, importWithAlias map[st
 	if p.Priority != 0 {
 		options = append(options, fmt.Sprintf("godi.Priority(%d)", p.Priority))
 	}
+	options = appendLifetimeToOptions(options, p.Scope, p.Eager)
 	if p.Conditions != nil && len(p.Conditions) > 0 {
 		for _, condition := range p.Conditions {
 			options = append(options, whenAnnotationToOption(condition))
@@ -55,36 +88,52 @@ func providerToRegistrationTemplate(p ProviderDefinition, importWithAlias map[st
 	if p.Description != "" {
 		options = append(options, fmt.Sprintf("godi.Description(`%s`)", p.Description))
 	}
+	options = appendBindingsToOptions(options, p.As, importWithAlias)
 
 	var dependencies []string
 	for _, dep := range p.Dependencies {
-		multiple, found := dep.Multiple()
-		if found && multiple {
-			dependencies = append(dependencies, "godi.Inject.Multiple()")
-			continue
-		}
-
-		var dependencyToAdd string
-		named, found := dep.Named()
-		if found {
-			dependencyToAdd = fmt.Sprintf("godi.Inject.Named(\"%s\")", named)
-		} else {
-			dependencyToAdd = "godi.Inject.Auto()"
-		}
-		optional, found := dep.Optional()
-		if found && optional {
-			dependencyToAdd += ".Optional()"
-		}
-		dependencies = append(dependencies, dependencyToAdd)
+		dependencies = append(dependencies, injectionToDependency(dep))
 	}
 	options = appendDependenciesToOptions(options, dependencies)
 
+	fnName := generateFQN(p.ImportPath, p.FnName, importWithAlias)
+	if p.DirectCall != "" {
+		options = append(options, fmt.Sprintf("godi.%s(%s)", p.DirectCall, fnName))
+	}
 	return RegistrationTemplate{
-		FnName:  generateFQN(p.ImportPath, p.FnName, importWithAlias),
+		FnName:  fnName,
 		Options: options,
 	}
 }
 
+// injectionToDependency returns the dependency builder of the injection, e.g. godi.Inject.Named("foo").Optional().
+func injectionToDependency(dep InjectAnnotation) string {
+	multiple, found := dep.Multiple()
+	if found && multiple {
+		return "godi.Inject.Multiple()"
+	}
+
+	var dependency string
+	named, found := dep.Named()
+	if found {
+		dependency = fmt.Sprintf("godi.Inject.Named(\"%s\")", named)
+	} else {
+		dependency = "godi.Inject.Auto()"
+	}
+	if _, found := dep.Default(); found {
+		literal, err := dep.DefaultLiteral()
+		if err != nil {
+			return dependency + ".Optional()" // the invalid default value is reported, see InjectAnnotation.Problems
+		}
+		return dependency + fmt.Sprintf(".Default(%s)", literal)
+	}
+	optional, found := dep.Optional()
+	if found && optional {
+		dependency += ".Optional()"
+	}
+	return dependency
+}
+
 func decoratorToRegistrationTemplate(d DecoratorDefinition, importWithAlias map[string]string) RegistrationTemplate {
 	var options []string
 	if d.Decorate != "" {
@@ -106,24 +155,7 @@ func decoratorToRegistrationTemplate(d DecoratorDefinition, importWithAlias map[
 
 	var dependencies []string
 	for _, dep := range d.Dependencies {
-		multiple, found := dep.Multiple()
-		if found && multiple {
-			dependencies = append(dependencies, "godi.Inject.Multiple()")
-			continue
-		}
-
-		var dependencyToAdd string
-		named, found := dep.Named()
-		if found {
-			dependencyToAdd = fmt.Sprintf("godi.Inject.Named(\"%s\")", named)
-		} else {
-			dependencyToAdd = "godi.Inject.Auto()"
-		}
-		optional, found := dep.Optional()
-		if found && optional {
-			dependencyToAdd += ".Optional()"
-		}
-		dependencies = append(dependencies, dependencyToAdd)
+		dependencies = append(dependencies, injectionToDependency(dep))
 	}
 	options = appendDependenciesToOptions(options, dependencies)
 
@@ -133,8 +165,51 @@ func decoratorToRegistrationTemplate(d DecoratorDefinition, importWithAlias map[
 	}
 }
 
+func componentToRegistrationTemplate(c ComponentDefinition, importWithAlias map[string]string) RegistrationTemplate {
+	var providerOptions []string
+	if c.Named != "" {
+		providerOptions = append(providerOptions, fmt.Sprintf("godi.Named(\"%s\")", c.Named))
+	}
+	if c.Priority != 0 {
+		providerOptions = append(providerOptions, fmt.Sprintf("godi.Priority(%d)", c.Priority))
+	}
+	if c.Description != "" {
+		providerOptions = append(providerOptions, fmt.Sprintf("godi.Description(`%s`)", c.Description))
+	}
+
+	// the conditions are the only options applying to the registration of a Provider implementation
+	var options []string
+	options = appendLifetimeToOptions(options, c.Scope, c.Eager)
+	for _, condition := range c.Conditions {
+		options = append(options, whenAnnotationToOption(condition))
+	}
+	options = appendBindingsToOptions(options, c.As, importWithAlias)
+
+	return RegistrationTemplate{
+		FnName: fmt.Sprintf(
+			"godi.MustNewComponentProvider[%s](%s)",
+			generateFQN(c.ImportPath, c.TypeName, importWithAlias),
+			strings.Join(providerOptions, ", "),
+		),
+		Options: options,
+	}
+}
+
 func whenAnnotationToOption(condition WhenAnnotation) string {
-	return fmt.Sprintf("godi.When(\"%s\").%s(\"%s\")", condition.named, toOperator(condition.operator), condition.value)
+	builder := "godi.When"
+	if condition.env {
+		builder = "godi.WhenEnv"
+	}
+
+	var values []string
+	if condition.operator == "in" {
+		for _, value := range splitList(condition.value) {
+			values = append(values, fmt.Sprintf("%q", value))
+		}
+	} else {
+		values = []string{fmt.Sprintf("%q", condition.value)}
+	}
+	return fmt.Sprintf("%s(%q).%s(%s)", builder, condition.named, toOperator(condition.operator), strings.Join(values, ", "))
 }
 
 func toOperator(operator string) any {
@@ -143,10 +218,38 @@ func toOperator(operator string) any {
 		return "Equals"
 	case "not_equals":
 		return "NotEquals"
+	case "matches":
+		return "Matches"
+	case "in":
+		return "In"
 	}
 	return fmt.Sprintf("UnknownOperator(%q)", operator)
 }
 
+// appendLifetimeToOptions adds the options of the lifetime of the components, nothing for the singletons.
+func appendLifetimeToOptions(options []string, scope string, eager bool) []string {
+	switch scope {
+	case transientScope:
+		options = append(options, "godi.Transient()")
+	case scopedScope:
+		options = append(options, "godi.Scoped()")
+	case perConsumerScope:
+		options = append(options, "godi.PerConsumer()")
+	}
+	if eager {
+		options = append(options, "godi.Eager()")
+	}
+	return options
+}
+
+// appendBindingsToOptions exposes the components as the interfaces, see godi.As.
+func appendBindingsToOptions(options []string, as []TypeReference, importWithAlias map[string]string) []string {
+	for _, ref := range as {
+		options = append(options, fmt.Sprintf("godi.As[%s]()", generateFQN(ref.ImportPath, ref.TypeName, importWithAlias)))
+	}
+	return options
+}
+
 func appendDependenciesToOptions(options []string, dependencies []string) []string {
 	if len(dependencies) > 0 {
 		depStr := "godi.Dependencies(\n\t\t\t" + strings.Join(dependencies, ",\n\t\t\t") + ",\n\t\t)"
@@ -165,18 +268,26 @@ func configToRegistrationTemplate(config ConfigDefinition, importWithAlias map[s
 	prefixName := "EnvPrefix4" + config.TypeName
 	configStructFQN := generateFQN(config.ImportPath, config.TypeName, importWithAlias)
 
+	// all the registrations share the conditions of the config struct, alternative configurations
+	// can then be registered depending on the environment
+	var conditions []string
+	for _, condition := range config.Annotation.conditions {
+		conditions = append(conditions, whenAnnotationToOption(condition))
+	}
+
+	prefixOptions := []string{fmt.Sprintf("godi.Named(\"%s\")", prefixName)}
+	prefixOptions = append(prefixOptions, conditions...)
+	prefixOptions = append(prefixOptions, "godi.Description(`Provides configuration prefix, i.e. the env vars prefix`)")
 	providers = append(providers, RegistrationTemplate{
-		FnName: fmt.Sprintf("godi.ToStaticProvider(\"%s\")", config.Annotation.Prefix()),
-		Options: []string{
-			fmt.Sprintf("godi.Named(\"%s\")", prefixName),
-			"godi.Description(`Provides configuration prefix, i.e. the env vars prefix`)",
-		},
+		FnName:  fmt.Sprintf("godi.ToStaticProvider(\"%s\")", config.Annotation.Prefix()),
+		Options: prefixOptions,
 	})
 
 	// now we should load the config struct itself, with config.Load which is actually a factory method
 	options := []string{
 		fmt.Sprintf("godi.Named(\"%s\")", config.TypeName),
 	}
+	options = append(options, conditions...)
 	if config.Annotation.description != "" {
 		options = append(options, fmt.Sprintf("godi.Description(`%s`)", config.Annotation.description))
 	}
@@ -184,8 +295,12 @@ func configToRegistrationTemplate(config ConfigDefinition, importWithAlias map[s
 		fmt.Sprintf("godi.Inject.Named(\"%s\")", prefixName),
 	})
 
+	loadOptions := fmt.Sprintf("%s.WithEnvPrefix(envPrefix)", configLoaderImportAlias)
+	if config.Annotation.Flags() {
+		loadOptions += fmt.Sprintf(", %s.WithFlags(%s.CommandLine)", configLoaderImportAlias, importWithAlias[flagImportPath])
+	}
 	providers = append(providers, RegistrationTemplate{
-		FnName:  fmt.Sprintf("func(envPrefix string) (*%s, error) {\n\t\t\treturn %s.Load[%s](%s.WithEnvPrefix(envPrefix))\n\t\t}", configStructFQN, configLoaderImportAlias, configStructFQN, configLoaderImportAlias),
+		FnName:  fmt.Sprintf("func(envPrefix string) (*%s, error) {\n\t\t\treturn %s.Load[%s](%s)\n\t\t}", configStructFQN, configLoaderImportAlias, configStructFQN, loadOptions),
 		Options: options,
 	})
 
@@ -193,51 +308,323 @@ func configToRegistrationTemplate(config ConfigDefinition, importWithAlias map[s
 	providers = append(
 		providers,
 		RegistrationTemplate{
-			FnName: fmt.Sprintf("&godi.ConfigFieldProvider[%s]{}", configStructFQN),
+			FnName:  fmt.Sprintf("&godi.ConfigFieldProvider[%s]{}", configStructFQN),
+			Options: conditions,
 		},
 	)
 
 	return providers
 }
 
-func generateCode(
-	outputPath string,
-	registryDef *RegistryDefinition,
-	providers []ProviderDefinition,
-	decorators []DecoratorDefinition,
-	configs []ConfigDefinition,
-) error {
-	tmpl := template.Must(template.New("registry").Parse(registryTemplate))
+// flagDeclarations declares the command-line flags of the config structs annotated with flags="true", they are
+// declared on the registration, so the registry must be registered before the flags are parsed.
+func flagDeclarations(configs []ConfigDefinition, importWithAlias map[string]string) []string {
+	var declarations []string
+	for _, config := range configs {
+		if config.Annotation.Flags() {
+			declarations = append(declarations, fmt.Sprintf(
+				"%s.DeclareFlags[%s](%s.CommandLine)",
+				importWithAlias[configLoaderImportPath],
+				generateFQN(config.ImportPath, config.TypeName, importWithAlias),
+				importWithAlias[flagImportPath],
+			))
+		}
+	}
+	return declarations
+}
+
+func envBindingToRegistrationTemplate(binding EnvBindingDefinition) RegistrationTemplate {
+	fields := fmt.Sprintf("Name: \"%s\"", binding.Named)
+	if binding.Prefix != "" {
+		fields += fmt.Sprintf(", Prefix: \"%s\"", binding.Prefix)
+	}
+	return RegistrationTemplate{
+		FnName: fmt.Sprintf("&godi.EnvBindingProvider[%s]{%s}", binding.Type, fields),
+	}
+}
+
+// generateCode writes the generated files on disk, the stale ones (nil content) being deleted.
+func generateCode(files map[string][]byte) error {
+	for path, code := range files {
+		if code == nil {
+			if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+				return err
+			}
+			continue
+		}
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			return err
+		}
+		if err := os.WriteFile(path, code, 0644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// checkCode verifies that the generated files on disk are up-to-date, returning a summary of the differences if not,
+// along with their unified diff, the paths being relative to root. The stale files (nil content) must not exist.
+func checkCode(files map[string][]byte, root string) (upToDate bool, summary string, diff string, err error) {
+	paths := make([]string, 0, len(files))
+	for path := range files {
+		paths = append(paths, path)
+	}
+	stdslices.Sort(paths)
+
+	var (
+		summaries []string
+		diffs     strings.Builder
+	)
+	for _, path := range paths {
+		existing, err := os.ReadFile(path)
+		if err != nil && !os.IsNotExist(err) {
+			return false, "", "", err
+		}
+		if fileSummary := diffSummary(string(existing), string(files[path])); fileSummary != "" {
+			if files[path] == nil {
+				fileSummary = "stale, it should be deleted"
+			}
+			summaries = append(summaries, path+": "+fileSummary)
+
+			relative, err := filepath.Rel(root, path)
+			if err != nil {
+				relative = path
+			}
+			diffs.WriteString(unifiedDiff(filepath.ToSlash(relative), string(existing), string(files[path])))
+		}
+	}
+	return len(summaries) == 0, strings.Join(summaries, "\n"), diffs.String(), nil
+}
+
+// renderFiles renders all the files to generate in memory, keyed by their path, without writing anything on disk.
+//
+// By default, a single file is generated next to the registry, but if split is true,
+// each scanned package gets its own registration file, and the registry file only aggregates them.
+// If wire is true, the static part of the graph is wired by the registry (see findWiring), unless split is true.
+// The config keys, if any, are generated in the cfgkeys package, next to the registry.
+// The registration files previously generated in the scanned packages but not generated anymore are stale,
+// they are returned with a nil content, see staleRegistrationFiles.
+func renderFiles(outputPath string, defs Definitions, split bool, wire bool) (map[string][]byte, error) {
+	files, err := renderRegistrationFiles(outputPath, defs, split, wire)
+	if err != nil {
+		return nil, err
+	}
+	stale, err := staleRegistrationFiles(defs, files)
+	if err != nil {
+		return nil, err
+	}
+	for _, path := range stale {
+		files[path] = nil
+	}
+
+	if len(defs.ConfigKeys) > 0 {
+		code, err := renderConfigKeysCode(defs.ConfigKeys)
+		if err != nil {
+			return nil, err
+		}
+		files[filepath.Join(filepath.Dir(outputPath), configKeysPackage, configKeysOutputFile)] = code
+	}
+	return files, nil
+}
+
+// staleRegistrationFiles returns the registration files of the scanned packages which are not part of the files
+// to generate, e.g. after disabling -split-packages, or when a package does not declare any component anymore.
+// Only the files starting with the generated header are returned, to never delete a file written by hand.
+func staleRegistrationFiles(defs Definitions, files map[string][]byte) ([]string, error) {
+	var stale []string
+	for _, pkg := range defs.Packages {
+		path := filepath.Join(pkg.Dir, packageOutputFile)
+		if _, generated := files[path]; generated {
+			continue
+		}
+		existing, err := os.ReadFile(path)
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		if bytes.HasPrefix(existing, []byte(generatedHeader)) {
+			stale = append(stale, path)
+		}
+	}
+	stdslices.Sort(stale)
+	return stale, nil
+}
+
+func renderRegistrationFiles(outputPath string, defs Definitions, split bool, wire bool) (map[string][]byte, error) {
+	if !split {
+		var wiring *WiringDefinition
+		if wire {
+			wiring = findWiring(defs)
+		}
+		code, err := renderAggregatorCode(defs, nil, wiring)
+		if err != nil {
+			return nil, err
+		}
+		return map[string][]byte{outputPath: code}, nil
+	}
+
+	var (
+		files          = make(map[string][]byte)
+		registryDir    = filepath.Dir(outputPath)
+		packagesByPath = make(map[string]Definitions)
+	)
+	for _, p := range defs.Providers {
+		pkgDefs := packagesByPath[p.ImportPath]
+		pkgDefs.Providers = append(pkgDefs.Providers, p)
+		packagesByPath[p.ImportPath] = pkgDefs
+	}
+	for _, c := range defs.Configs {
+		pkgDefs := packagesByPath[c.ImportPath]
+		pkgDefs.Configs = append(pkgDefs.Configs, c)
+		packagesByPath[c.ImportPath] = pkgDefs
+	}
+	for _, d := range defs.Decorators {
+		pkgDefs := packagesByPath[d.ImportPath]
+		pkgDefs.Decorators = append(pkgDefs.Decorators, d)
+		packagesByPath[d.ImportPath] = pkgDefs
+	}
+	for _, c := range defs.Components {
+		pkgDefs := packagesByPath[c.ImportPath]
+		pkgDefs.Components = append(pkgDefs.Components, c)
+		packagesByPath[c.ImportPath] = pkgDefs
+	}
+
+	aggregated := Definitions{
+		Registry:    defs.Registry,
+		EnvBindings: defs.EnvBindings,
+	}
+	var registeredPackages []string
+	for importPath, pkgDefs := range packagesByPath {
+		pkg, found := defs.Packages[importPath]
+		if !found || pkg.Dir == registryDir {
+			// definitions living in the registry package are registered directly by the aggregator
+			aggregated.Providers = append(aggregated.Providers, pkgDefs.Providers...)
+			aggregated.Configs = append(aggregated.Configs, pkgDefs.Configs...)
+			aggregated.Decorators = append(aggregated.Decorators, pkgDefs.Decorators...)
+			aggregated.Components = append(aggregated.Components, pkgDefs.Components...)
+			continue
+		}
+
+		code, err := renderPackageCode(pkg, pkgDefs)
+		if err != nil {
+			return nil, err
+		}
+		files[filepath.Join(pkg.Dir, packageOutputFile)] = code
+		registeredPackages = append(registeredPackages, importPath)
+	}
+	stdslices.Sort(registeredPackages)
+
+	code, err := renderAggregatorCode(aggregated, registeredPackages, nil)
+	if err != nil {
+		return nil, err
+	}
+	files[outputPath] = code
+
+	return files, nil
+}
+
+func renderAggregatorCode(defs Definitions, registeredPackages []string, wiring *WiringDefinition) ([]byte, error) {
+	imports := append(collectImports(defs), registeredPackages...)
+	var renderedWiring string
+	if wiring != nil {
+		imports = append(imports, wiring.imports()...)
+	}
+	importWithAlias, importsForTemplate := prepareImports(imports, "")
+	if wiring != nil {
+		renderedWiring = wiring.render(defs.Registry, importWithAlias)
+	}
+
+	data := map[string]interface{}{
+		"PackageName":  defs.Registry.PackageName,
+		"StructName":   defs.Registry.StructName,
+		"DIImportPath": diImportPath,
+		"Imports":      importsForTemplate,
+		"Packages": slices.Map(registeredPackages, func(importPath string) string {
+			return importWithAlias[importPath]
+		}),
+		"Declarations": flagDeclarations(defs.Configs, importWithAlias),
+		"Providers":    collectRegistrationTemplates(defs, wiring, importWithAlias),
+		"Wiring":       renderedWiring,
+	}
 
+	return executeTemplate(registryTemplate, data)
+}
+
+func renderPackageCode(pkg PackageDefinition, defs Definitions) ([]byte, error) {
+	importWithAlias, importsForTemplate := prepareImports(collectImports(defs), pkg.ImportPath)
+
+	data := map[string]interface{}{
+		"PackageName":  pkg.Name,
+		"Imports":      importsForTemplate,
+		"Declarations": flagDeclarations(defs.Configs, importWithAlias),
+		"Providers":    collectRegistrationTemplates(defs, nil, importWithAlias),
+	}
+
+	return executeTemplate(packageRegistrationTemplate, data)
+}
+
+func collectImports(defs Definitions) []string {
 	imports := []string{diImportPath}
-	for _, p := range providers {
+	for _, p := range defs.Providers {
 		imports = append(imports, p.ImportPath)
+		for _, ref := range p.As {
+			if ref.ImportPath != "" {
+				imports = append(imports, ref.ImportPath)
+			}
+		}
 	}
-	for _, d := range decorators {
+	for _, d := range defs.Decorators {
 		imports = append(imports, d.ImportPath)
 	}
-	if len(configs) > 0 {
+	for _, c := range defs.Components {
+		imports = append(imports, c.ImportPath)
+		for _, ref := range c.As {
+			if ref.ImportPath != "" {
+				imports = append(imports, ref.ImportPath)
+			}
+		}
+	}
+	if len(defs.Configs) > 0 {
 		imports = append(imports, configLoaderImportPath)
-		for _, config := range configs {
+		for _, config := range defs.Configs {
 			if config.ImportPath != "" {
 				imports = append(imports, config.ImportPath)
 			}
+			if config.Annotation.Flags() {
+				imports = append(imports, flagImportPath)
+			}
 		}
 	}
+	return imports
+}
+
+// prepareImports dedups and sorts the imports, and finds a unique alias for each of them.
+//
+// The ownImportPath (if any) is the import path of the package the code is generated into,
+// it is not imported, and references to it are not qualified.
+func prepareImports(imports []string, ownImportPath string) (importWithAlias map[string]string, importsForTemplate []string) {
 	imports = set.NewFromSlice(imports).ToSlice()
 	stdslices.Sort(imports)
 
-	importWithAlias := map[string]string{}
+	importWithAlias = map[string]string{}
 	aliases := set.New[string]()
 	for _, imp := range imports {
+		if imp == ownImportPath {
+			importWithAlias[imp] = ""
+			continue
+		}
 		alias := findSuitableAlias(imp, aliases)
 		importWithAlias[imp] = alias
 		aliases.Add(alias)
 	}
 
-	var importsForTemplate []string
 	for _, imp := range imports {
 		alias := importWithAlias[imp]
+		if imp == ownImportPath {
+			continue
+		}
 		if alias == "" || alias == filepath.Base(imp) {
 			// don't use redundant aliases
 			importsForTemplate = append(importsForTemplate, fmt.Sprintf("\"%s\"", imp))
@@ -247,28 +634,42 @@ func generateCode(
 	}
 	stdslices.Sort(importsForTemplate)
 
-	// gather the data for the template
-	var registrationTemplates []RegistrationTemplate
-	registrationTemplates = append(registrationTemplates, slices.Map(providers, curryLastArg(providerToRegistrationTemplate, importWithAlias))...)
-	registrationTemplates = append(registrationTemplates, slices.FlatMap(configs, curryLastArg(configToRegistrationTemplate, importWithAlias))...)
-	registrationTemplates = append(registrationTemplates, slices.Map(decorators, curryLastArg(decoratorToRegistrationTemplate, importWithAlias))...)
+	return importWithAlias, importsForTemplate
+}
 
-	data := map[string]interface{}{
-		"PackageName":  registryDef.PackageName,
-		"StructName":   registryDef.StructName,
-		"DIImportPath": "github.com/a-peyrard/godi",
-		"Imports":      importsForTemplate,
-		"Providers":    registrationTemplates,
+// collectRegistrationTemplates returns the registrations of the definitions, the providers of the static part of
+// the graph (if any) being registered with their component taken from the wiring.
+func collectRegistrationTemplates(defs Definitions, wiring *WiringDefinition, importWithAlias map[string]string) []RegistrationTemplate {
+	var registrationTemplates []RegistrationTemplate
+	wired := make(map[string]wiredCall)
+	if wiring != nil {
+		registrationTemplates = append(registrationTemplates, wiring.registrationTemplate(defs.Registry))
+		for _, call := range wiring.Calls {
+			wired[call.provider.ImportPath+"."+call.provider.FnName] = call
+		}
 	}
-
-	file, err := os.Create(outputPath)
-	if err != nil {
-		return err
+	for _, p := range defs.Providers {
+		if call, found := wired[p.ImportPath+"."+p.FnName]; found {
+			registrationTemplates = append(registrationTemplates, wiredProviderToRegistrationTemplate(call, defs.Registry, importWithAlias))
+		} else {
+			registrationTemplates = append(registrationTemplates, providerToRegistrationTemplate(p, importWithAlias))
+		}
 	}
-	//goland:noinspection GoUnhandledErrorResult
-	defer file.Close()
+	registrationTemplates = append(registrationTemplates, slices.Map(defs.Components, curryLastArg(componentToRegistrationTemplate, importWithAlias))...)
+	registrationTemplates = append(registrationTemplates, slices.FlatMap(defs.Configs, curryLastArg(configToRegistrationTemplate, importWithAlias))...)
+	registrationTemplates = append(registrationTemplates, slices.Map(defs.EnvBindings, envBindingToRegistrationTemplate)...)
+	registrationTemplates = append(registrationTemplates, slices.Map(defs.Decorators, curryLastArg(decoratorToRegistrationTemplate, importWithAlias))...)
+	return registrationTemplates
+}
 
-	return tmpl.Execute(file, data)
+func executeTemplate(text string, data map[string]interface{}) ([]byte, error) {
+	tmpl := template.Must(template.New("registry").Parse(text))
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
 }
 
 func findSuitableAlias(importPath string, aliases set.Set[string]) string {
@@ -299,7 +700,7 @@ func findSuitableAlias(importPath string, aliases set.Set[string]) string {
 }
 
 func generateFQN(importPath string, typeName string, importWithAlias map[string]string) string {
-	if importPath == "" {
+	if importPath == "" || importWithAlias[importPath] == "" {
 		return typeName
 	}
 	if strings.HasPrefix(typeName, "*") {
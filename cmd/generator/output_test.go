@@ -112,3 +112,38 @@ func Test_generateFQN(t *testing.T) {
 		assert.Equal(t, "*pkg.MyType", result)
 	})
 }
+
+func Test_whenAnnotationToOption(t *testing.T) {
+	t.Run("it should render a condition on a named component", func(t *testing.T) {
+		// GIVEN
+		condition := WhenAnnotation{named: "APP_ENV", operator: "not_equals", value: "prod"}
+
+		// WHEN
+		option := whenAnnotationToOption(condition)
+
+		// THEN
+		assert.Equal(t, `godi.When("APP_ENV").NotEquals("prod")`, option)
+	})
+
+	t.Run("it should render a condition on an env var", func(t *testing.T) {
+		// GIVEN
+		condition := WhenAnnotation{named: "APP_ENV", env: true, operator: "matches", value: `^prod-\d+$`}
+
+		// WHEN
+		option := whenAnnotationToOption(condition)
+
+		// THEN
+		assert.Equal(t, `godi.WhenEnv("APP_ENV").Matches("^prod-\\d+$")`, option)
+	})
+
+	t.Run("it should render the values of an in condition", func(t *testing.T) {
+		// GIVEN
+		condition := WhenAnnotation{named: "REGION", operator: "in", value: "eu-west-1, eu-central-1"}
+
+		// WHEN
+		option := whenAnnotationToOption(condition)
+
+		// THEN
+		assert.Equal(t, `godi.When("REGION").In("eu-west-1", "eu-central-1")`, option)
+	})
+}
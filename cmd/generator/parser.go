@@ -4,7 +4,11 @@ import (
 	"fmt"
 	"github.com/a-peyrard/godi/set"
 	"github.com/rs/zerolog"
+	"go/token"
+	"go/types"
+	"math"
 	"regexp"
+	"sort"
 	"strconv"
 	"strings"
 )
@@ -12,26 +16,39 @@ import (
 type (
 	ProviderDecoratorAnnotation struct {
 		logger      *zerolog.Logger
+		tag         string
 		description string
 		properties  map[string]string
 
 		conditions []WhenAnnotation
+		// problems are the problems found while parsing the annotations, see Problems
+		problems []string
 	}
 
 	WhenAnnotation struct {
 		logger   *zerolog.Logger
 		named    string
+		env      bool // the condition is on an env var, instead of a named component
 		operator string
 		value    string
 	}
 )
 
+// whenOperators are the supported operators of the @when annotation, "in" takes a comma separated list of values.
+var whenOperators = []string{"equals", "not_equals", "matches", "in"}
+
+var (
+	knownAnnotationTags   = set.NewWithValues(providerAnnotationTag, decoratorAnnotationTag, componentAnnotationTag, whenAnnotationTag, injectAnnotationTag, configAnnotationTag, registryAnnotationTag)
+	knownWhenProperties   = set.NewWithValues(append([]string{"named", "env"}, whenOperators...)...)
+	knownInjectProperties = set.NewWithValues("named", "multiple", "optional", "default")
+	knownConfigProperties = set.NewWithValues("prefix", "flags")
+)
+
+// Priority returns the priority property, an invalid priority is ignored (see Problems).
 func (p ProviderDecoratorAnnotation) Priority() (priority int, found bool) {
 	if priorityStr, exists := p.properties["priority"]; exists {
 		if priority, err := strconv.Atoi(priorityStr); err == nil {
 			return priority, true
-		} else {
-			p.logger.Warn().Msgf("Error parsing priority property: %s, skipping it", priorityStr)
 		}
 	}
 	return 0, false
@@ -42,16 +59,70 @@ func (p ProviderDecoratorAnnotation) Named() (named string, found bool) {
 	return named, found
 }
 
-var knownProperties = set.NewWithValues("priority", "named")
+// As returns the interfaces the components are exposed as, e.g. as="io.Reader, Repository".
+func (p ProviderDecoratorAnnotation) As() []string {
+	return splitList(p.properties["as"])
+}
+
+// Scope returns the lifetime of the components, one of lifetimeScopes, singleton by default.
+func (p ProviderDecoratorAnnotation) Scope() string {
+	if scope, found := p.properties["scope"]; found && lifetimeScopes.Contains(scope) {
+		return scope
+	}
+	return singletonScope
+}
+
+// Eager reports if the components are instantiated by Resolver.Warmup, see godi.Eager.
+func (p ProviderDecoratorAnnotation) Eager() bool {
+	eager, _ := strconv.ParseBool(p.properties["eager"])
+	return eager
+}
+
+// knownProperties are the properties of the annotations, by tag, the decorators have neither a lifetime,
+// nor interfaces to be exposed as.
+var knownProperties = map[string]set.Set[string]{
+	providerAnnotationTag:  set.NewWithValues("priority", "named", "as", "scope", "eager"),
+	componentAnnotationTag: set.NewWithValues("priority", "named", "as", "scope", "eager"),
+	decoratorAnnotationTag: set.NewWithValues("priority", "named"),
+}
+
+const (
+	singletonScope   = "singleton"
+	transientScope   = "transient"
+	scopedScope      = "scoped"
+	perConsumerScope = "per-consumer"
+)
+
+var lifetimeScopes = set.NewWithValues(singletonScope, transientScope, scopedScope, perConsumerScope)
 
 func (p ProviderDecoratorAnnotation) UnknownProperties() []string {
-	var unknown []string
-	for key := range p.properties {
-		if knownProperties.DoesNotContain(key) {
-			unknown = append(unknown, key)
+	return unknownProperties(p.properties, knownProperties[p.tag])
+}
+
+// Problems returns the problems of the annotations: unknown annotations and properties, invalid values,
+// and malformed @when conditions.
+func (p ProviderDecoratorAnnotation) Problems() []string {
+	problems := append([]string(nil), p.problems...)
+	for _, key := range p.UnknownProperties() {
+		problems = append(problems, fmt.Sprintf("unknown property %q in %s", key, p.tag))
+	}
+	if priorityStr, exists := p.properties["priority"]; exists {
+		if _, err := strconv.Atoi(priorityStr); err != nil {
+			problems = append(problems, fmt.Sprintf("invalid priority %q in %s, expecting an integer", priorityStr, p.tag))
 		}
 	}
-	return unknown
+	if p.tag == decoratorAnnotationTag {
+		return problems
+	}
+	if scope, exists := p.properties["scope"]; exists && lifetimeScopes.DoesNotContain(scope) {
+		problems = append(problems, fmt.Sprintf("invalid scope %q in %s, expecting one of: %s, %s, %s, %s", scope, p.tag, singletonScope, transientScope, scopedScope, perConsumerScope))
+	}
+	if eager, exists := p.properties["eager"]; exists {
+		if _, err := strconv.ParseBool(eager); err != nil {
+			problems = append(problems, fmt.Sprintf("invalid eager %q in %s, expecting a boolean", eager, p.tag))
+		}
+	}
+	return problems
 }
 
 func parseProviderDecoratorAnnotation(logger *zerolog.Logger, fnName string, docText string, providerOrDecoratorTag string) ProviderDecoratorAnnotation {
@@ -61,6 +132,7 @@ func parseProviderDecoratorAnnotation(logger *zerolog.Logger, fnName string, doc
 		descriptionLines []string
 		providerLine     string
 		conditionLines   []string
+		problems         []string
 	)
 	// separate @provider line, and @when lines from description
 	for _, line := range lines {
@@ -70,17 +142,36 @@ func parseProviderDecoratorAnnotation(logger *zerolog.Logger, fnName string, doc
 			providerLine = line
 		} else if strings.HasPrefix(line, whenAnnotationTag) {
 			conditionLines = append(conditionLines, line)
-		} else if line != "" && !strings.HasPrefix(line, "@") {
+		} else if strings.HasPrefix(line, "@") {
+			if tag := strings.Fields(line)[0]; knownAnnotationTags.DoesNotContain(tag) {
+				problems = append(problems, fmt.Sprintf("unknown annotation %s", tag))
+			}
+		} else if line != "" {
 			descriptionLines = append(descriptionLines, line)
 		}
 	}
 
+	conditions, conditionProblems := parseWhenAnnotations(logger, conditionLines)
 	return ProviderDecoratorAnnotation{
 		logger:      logger,
+		tag:         providerOrDecoratorTag,
 		description: formatDescription(fnName, descriptionLines),
 		properties:  parseProperties(providerLine, providerOrDecoratorTag),
-		conditions:  parseWhenAnnotations(logger, conditionLines),
+		conditions:  conditions,
+		problems:    append(problems, conditionProblems...),
+	}
+}
+
+// unknownProperties returns the sorted properties which are not known.
+func unknownProperties(properties map[string]string, known set.Set[string]) []string {
+	var unknown []string
+	for key := range properties {
+		if known.DoesNotContain(key) {
+			unknown = append(unknown, key)
+		}
 	}
+	sort.Strings(unknown)
+	return unknown
 }
 
 func parseProperties(line string, tag string) map[string]string {
@@ -118,6 +209,14 @@ func parseProperties(line string, tag string) map[string]string {
 type InjectAnnotation struct {
 	logger     *zerolog.Logger
 	properties map[string]string
+
+	// typeExpr is the source representation of the parameter type, e.g. "string" or "*config.AppConfig",
+	// and qualifiedType is the same with the types of the package qualified by its name, see qualifiedTypeExpr
+	typeExpr      string
+	qualifiedType string
+	// position locates the parameter, and typ is its type when the types are loaded (see --validate)
+	position token.Position
+	typ      types.Type
 }
 
 func (a InjectAnnotation) String() string {
@@ -148,6 +247,82 @@ func (a InjectAnnotation) Optional() (value bool, found bool) {
 	return optionalStr == "true", found
 }
 
+// Default returns the value injected if the dependency is missing, making it optional.
+func (a InjectAnnotation) Default() (value string, found bool) {
+	value, found = a.properties["default"]
+	return value, found
+}
+
+// DefaultLiteral returns the Go literal of the default value, typed as the parameter, e.g. "fallback" for a string,
+// or int64(42) for an int64. Only the primitive types can have a default value, and the value must fit in the type.
+func (a InjectAnnotation) DefaultLiteral() (literal string, err error) {
+	value, _ := a.Default()
+	bitSize := bitSizeOf(a.typeExpr)
+	switch a.typeExpr {
+	case "string":
+		return strconv.Quote(value), nil
+	case "bool":
+		var parsed bool
+		if parsed, err = strconv.ParseBool(value); err == nil {
+			return strconv.FormatBool(parsed), nil
+		}
+	case "int", "int8", "int16", "int32", "int64":
+		var parsed int64
+		if parsed, err = strconv.ParseInt(value, 0, bitSize); err == nil {
+			literal = strconv.FormatInt(parsed, 10)
+		}
+	case "uint", "uint8", "uint16", "uint32", "uint64":
+		var parsed uint64
+		if parsed, err = strconv.ParseUint(value, 0, bitSize); err == nil {
+			literal = strconv.FormatUint(parsed, 10)
+		}
+	case "float32", "float64":
+		var parsed float64
+		if parsed, err = strconv.ParseFloat(value, bitSize); err == nil && (math.IsInf(parsed, 0) || math.IsNaN(parsed)) {
+			err = fmt.Errorf("%q is not a finite number", value)
+		}
+		literal = strconv.FormatFloat(parsed, 'g', -1, bitSize)
+	default:
+		return "", fmt.Errorf("default value %q for a dependency of type %s, expecting a primitive type", value, a.typeExpr)
+	}
+	if err != nil {
+		return "", fmt.Errorf("invalid default value %q for a dependency of type %s", value, a.typeExpr)
+	}
+	if a.typeExpr == "int" {
+		return literal, nil
+	}
+	return fmt.Sprintf("%s(%s)", a.typeExpr, literal), nil
+}
+
+// bitSizeOf returns the size in bits of the sized numeric types, e.g. 8 for int8, or the size of an int otherwise.
+func bitSizeOf(typeExpr string) int {
+	if size, err := strconv.Atoi(strings.TrimLeft(typeExpr, "uintfloa")); err == nil {
+		return size
+	}
+	return strconv.IntSize
+}
+
+// Problems returns the unknown properties, and the invalid values of the annotation.
+func (a InjectAnnotation) Problems() []string {
+	var problems []string
+	for _, key := range unknownProperties(a.properties, knownInjectProperties) {
+		problems = append(problems, fmt.Sprintf("unknown property %q in %s", key, injectAnnotationTag))
+	}
+	for _, key := range []string{"multiple", "optional"} {
+		if raw, found := a.properties[key]; found {
+			if _, err := strconv.ParseBool(raw); err != nil {
+				problems = append(problems, fmt.Sprintf("invalid %s %q in %s, expecting a boolean", key, raw, injectAnnotationTag))
+			}
+		}
+	}
+	if _, found := a.Default(); found {
+		if _, err := a.DefaultLiteral(); err != nil {
+			problems = append(problems, fmt.Sprintf("%s in %s", err, injectAnnotationTag))
+		}
+	}
+	return problems
+}
+
 func parseInjectAnnotation(logger *zerolog.Logger, comment string) InjectAnnotation {
 	content := strings.TrimPrefix(comment, "//")
 	content = strings.TrimSpace(content)
@@ -165,6 +340,9 @@ type ConfigAnnotation struct {
 	logger      *zerolog.Logger
 	description string
 	properties  map[string]string
+
+	conditions []WhenAnnotation
+	problems   []string
 }
 
 func (a ConfigAnnotation) String() string {
@@ -179,19 +357,42 @@ func (a ConfigAnnotation) Prefix() string {
 	return prefix
 }
 
+// Flags reports if a command-line flag is declared for each field of the config struct, see config.DeclareFlags.
+func (a ConfigAnnotation) Flags() bool {
+	flags, _ := strconv.ParseBool(a.properties["flags"])
+	return flags
+}
+
+// Problems returns the unknown properties of the annotation, the invalid values, and the malformed @when conditions.
+func (a ConfigAnnotation) Problems() []string {
+	problems := append([]string(nil), a.problems...)
+	for _, key := range unknownProperties(a.properties, knownConfigProperties) {
+		problems = append(problems, fmt.Sprintf("unknown property %q in %s", key, configAnnotationTag))
+	}
+	if flags, exists := a.properties["flags"]; exists {
+		if _, err := strconv.ParseBool(flags); err != nil {
+			problems = append(problems, fmt.Sprintf("invalid flags %q in %s, expecting a boolean", flags, configAnnotationTag))
+		}
+	}
+	return problems
+}
+
 func parseConfigAnnotation(logger *zerolog.Logger, configType string, docText string) ConfigAnnotation {
 	lines := strings.Split(docText, "\n")
 
 	var (
 		configLine       string
 		descriptionLines []string
+		conditionLines   []string
 	)
-	// separate @config line from description
+	// separate @config line, and @when lines from description
 	for _, line := range lines {
 		line = strings.TrimSpace(line)
 
 		if strings.HasPrefix(line, configAnnotationTag) {
 			configLine = line
+		} else if strings.HasPrefix(line, whenAnnotationTag) {
+			conditionLines = append(conditionLines, line)
 		} else if line != "" && !strings.HasPrefix(line, "@") {
 			descriptionLines = append(descriptionLines, line)
 		}
@@ -207,28 +408,35 @@ func parseConfigAnnotation(logger *zerolog.Logger, configType string, docText st
 		}
 	}
 
+	conditions, problems := parseWhenAnnotations(logger, conditionLines)
 	return ConfigAnnotation{
 		logger:      logger,
 		description: formatDescription(configType, descriptionLines),
 		properties:  parseProperties(configLine, configAnnotationTag),
+		conditions:  conditions,
+		problems:    problems,
 	}
 }
 
-func parseWhenAnnotations(logger *zerolog.Logger, lines []string) []WhenAnnotation {
+// parseWhenAnnotations parses the @when lines, the malformed ones are skipped and reported as problems.
+func parseWhenAnnotations(logger *zerolog.Logger, lines []string) (conditions []WhenAnnotation, problems []string) {
 	if len(lines) == 0 {
-		return nil
+		return nil, nil
 	}
-	conditions := make([]WhenAnnotation, 0, len(lines))
+	conditions = make([]WhenAnnotation, 0, len(lines))
 	for _, line := range lines {
 		annotation, err := parseWhenAnnotation(logger, line)
 		if err != nil {
-			logger.Warn().Err(err).Msgf("Failed to parse @when annotation: %s", line)
+			problems = append(problems, fmt.Sprintf("invalid %s annotation, skipping it: %v", whenAnnotationTag, err))
 			continue
 		}
+		for _, key := range unknownProperties(parseProperties(line, whenAnnotationTag), knownWhenProperties) {
+			problems = append(problems, fmt.Sprintf("unknown property %q in %s", key, whenAnnotationTag))
+		}
 		conditions = append(conditions, annotation)
 	}
 
-	return conditions
+	return conditions, problems
 }
 
 func parseWhenAnnotation(logger *zerolog.Logger, line string) (WhenAnnotation, error) {
@@ -246,30 +454,26 @@ func parseWhenAnnotation(logger *zerolog.Logger, line string) (WhenAnnotation, e
 
 	properties := parseProperties(content, whenAnnotationTag)
 	named, found := properties["named"]
-	if !found {
-		return WhenAnnotation{}, fmt.Errorf("missing 'named' property in @when annotation: %s", line)
+	env, envFound := properties["env"]
+	if !found && !envFound {
+		return WhenAnnotation{}, fmt.Errorf("missing 'named' or 'env' property in @when annotation: %s", line)
 	}
-	valueEq, equalsFound := properties["equals"]
-	valueNotEq, notEqualsFound := properties["not_equals"]
-	if !equalsFound && !notEqualsFound {
-		return WhenAnnotation{}, fmt.Errorf("missing 'equals' or 'not_equals' property in @when annotation: %s", line)
+	if envFound {
+		named = env
 	}
 
-	operator := "equals"
-	if notEqualsFound {
-		operator = "not_equals"
-	}
-	value := strings.TrimSpace(valueEq)
-	if notEqualsFound {
-		value = strings.TrimSpace(valueNotEq)
+	for _, operator := range whenOperators {
+		if value, found := properties[operator]; found {
+			return WhenAnnotation{
+				logger:   logger,
+				named:    named,
+				env:      envFound,
+				operator: operator,
+				value:    strings.TrimSpace(value),
+			}, nil
+		}
 	}
-
-	return WhenAnnotation{
-		logger:   logger,
-		named:    named,
-		operator: operator,
-		value:    value,
-	}, nil
+	return WhenAnnotation{}, fmt.Errorf("missing 'equals', 'not_equals', 'matches' or 'in' property in @when annotation: %s", line)
 }
 
 func formatDescription(typeStr string, descriptionLines []string) string {
@@ -279,3 +483,50 @@ func formatDescription(typeStr string, descriptionLines []string) string {
 
 	return normalized
 }
+
+type RegistryAnnotation struct {
+	logger     *zerolog.Logger
+	properties map[string]string
+}
+
+// Include returns the package patterns to scan, given by the include or the scope property,
+// defaults to the whole module.
+func (a RegistryAnnotation) Include() []string {
+	include := append(splitList(a.properties["scope"]), splitList(a.properties["include"])...)
+	if len(include) == 0 {
+		return []string{"./..."}
+	}
+	return include
+}
+
+// Exclude returns the package patterns to skip.
+func (a RegistryAnnotation) Exclude() []string {
+	return splitList(a.properties["exclude"])
+}
+
+func parseRegistryAnnotation(logger *zerolog.Logger, docText string) RegistryAnnotation {
+	var registryLine string
+	for _, line := range strings.Split(docText, "\n") {
+		line = strings.TrimSpace(line)
+		if strings.HasPrefix(line, registryAnnotationTag) {
+			registryLine = line
+			break
+		}
+	}
+
+	return RegistryAnnotation{
+		logger:     logger,
+		properties: parseProperties(registryLine, registryAnnotationTag),
+	}
+}
+
+// splitList splits a comma separated list of values, ignoring the empty ones.
+func splitList(raw string) []string {
+	var values []string
+	for _, value := range strings.Split(raw, ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+	return values
+}
@@ -77,6 +77,37 @@ func Test_parseWhenAnnotation(t *testing.T) {
 		assert.Equal(t, "true", result.value)
 	})
 
+	t.Run("it should parse a condition on an env var", func(t *testing.T) {
+		// GIVEN
+		logger := zerolog.Nop()
+		line := `@when env="APP_ENV" matches="^prod"`
+
+		// WHEN
+		result, err := parseWhenAnnotation(&logger, line)
+
+		// THEN
+		assert.NoError(t, err)
+		assert.Equal(t, "APP_ENV", result.named)
+		assert.True(t, result.env)
+		assert.Equal(t, "matches", result.operator)
+		assert.Equal(t, "^prod", result.value)
+	})
+
+	t.Run("it should parse in condition", func(t *testing.T) {
+		// GIVEN
+		logger := zerolog.Nop()
+		line := `@when named="REGION" in="eu-west-1, eu-central-1"`
+
+		// WHEN
+		result, err := parseWhenAnnotation(&logger, line)
+
+		// THEN
+		assert.NoError(t, err)
+		assert.False(t, result.env)
+		assert.Equal(t, "in", result.operator)
+		assert.Equal(t, "eu-west-1, eu-central-1", result.value)
+	})
+
 	t.Run("it should return error for missing named property", func(t *testing.T) {
 		// GIVEN
 		logger := zerolog.Nop()
@@ -87,7 +118,7 @@ func Test_parseWhenAnnotation(t *testing.T) {
 
 		// THEN
 		assert.Error(t, err)
-		assert.Contains(t, err.Error(), "missing 'named' property")
+		assert.Contains(t, err.Error(), "missing 'named' or 'env' property")
 	})
 
 	t.Run("it should return error for missing operator", func(t *testing.T) {
@@ -100,6 +131,154 @@ func Test_parseWhenAnnotation(t *testing.T) {
 
 		// THEN
 		assert.Error(t, err)
-		assert.Contains(t, err.Error(), "missing 'equals' or 'not_equals'")
+		assert.Contains(t, err.Error(), "missing 'equals', 'not_equals', 'matches' or 'in' property")
+	})
+}
+
+func Test_parseRegistryAnnotation(t *testing.T) {
+	t.Run("it should scan the whole module by default", func(t *testing.T) {
+		// GIVEN
+		logger := zerolog.Nop()
+		doc := "Registry is the main registry"
+
+		// WHEN
+		result := parseRegistryAnnotation(&logger, doc)
+
+		// THEN
+		assert.Equal(t, []string{"./..."}, result.Include())
+		assert.Empty(t, result.Exclude())
 	})
+
+	t.Run("it should parse include and exclude lists", func(t *testing.T) {
+		// GIVEN
+		logger := zerolog.Nop()
+		doc := "Registry is the main registry\n\n@registry include=\"./services/api/..., ./internal/...\" exclude=\"./internal/legacy/...\""
+
+		// WHEN
+		result := parseRegistryAnnotation(&logger, doc)
+
+		// THEN
+		assert.Equal(t, []string{"./services/api/...", "./internal/..."}, result.Include())
+		assert.Equal(t, []string{"./internal/legacy/..."}, result.Exclude())
+	})
+
+	t.Run("it should parse the scope as the packages to include", func(t *testing.T) {
+		// GIVEN
+		logger := zerolog.Nop()
+		doc := "PaymentRegistry wires the payment services\n\n@registry scope=\"./internal/payment/...\""
+
+		// WHEN
+		result := parseRegistryAnnotation(&logger, doc)
+
+		// THEN
+		assert.Equal(t, []string{"./internal/payment/..."}, result.Include())
+	})
+}
+
+func TestProviderDecoratorAnnotation_Problems(t *testing.T) {
+	t.Run("it should not report anything for valid annotations", func(t *testing.T) {
+		// GIVEN
+		logger := zerolog.Nop()
+		doc := "NewService creates the service\n@provider named=\"service\" priority=10\n@when named=\"ENV\" equals=\"production\""
+
+		// WHEN
+		result := parseProviderDecoratorAnnotation(&logger, "NewService", doc, providerAnnotationTag)
+
+		// THEN
+		assert.Empty(t, result.Problems())
+	})
+
+	t.Run("it should report unknown properties and annotations, and invalid values", func(t *testing.T) {
+		// GIVEN
+		logger := zerolog.Nop()
+		doc := "@provider named=\"service\" priority=high scope=\"request\"\n@when named=\"ENV\" equals=\"production\" fallback=\"dev\"\n@when named=\"DEBUG\"\n@cached"
+
+		// WHEN
+		result := parseProviderDecoratorAnnotation(&logger, "NewService", doc, providerAnnotationTag)
+
+		// THEN
+		assert.Equal(t, []string{
+			"unknown annotation @cached",
+			`unknown property "fallback" in @when`,
+			`invalid @when annotation, skipping it: missing 'equals', 'not_equals', 'matches' or 'in' property in @when annotation: @when named="DEBUG"`,
+			`invalid priority "high" in @provider, expecting an integer`,
+			`invalid scope "request" in @provider, expecting one of: singleton, transient, scoped, per-consumer`,
+		}, result.Problems())
+		_, found := result.Priority()
+		assert.False(t, found)
+	})
+
+	t.Run("it should report the lifetimes of the decorators, and the invalid eager values", func(t *testing.T) {
+		// GIVEN
+		logger := zerolog.Nop()
+
+		// WHEN
+		decorator := parseProviderDecoratorAnnotation(&logger, "DecorateService", `@decorator named="service" scope="transient"`, decoratorAnnotationTag)
+		provider := parseProviderDecoratorAnnotation(&logger, "NewService", `@provider scope="transient" eager=yes`, providerAnnotationTag)
+
+		// THEN
+		assert.Equal(t, []string{`unknown property "scope" in @decorator`}, decorator.Problems())
+		assert.Equal(t, []string{`invalid eager "yes" in @provider, expecting a boolean`}, provider.Problems())
+		assert.Equal(t, transientScope, provider.Scope())
+		assert.False(t, provider.Eager())
+	})
+}
+
+func TestInjectAnnotation_Problems(t *testing.T) {
+	t.Run("it should report unknown properties and invalid booleans", func(t *testing.T) {
+		// GIVEN
+		logger := zerolog.Nop()
+
+		// WHEN
+		result := parseInjectAnnotation(&logger, `@inject named="service" lazy=true optional=maybe`)
+
+		// THEN
+		assert.Equal(t, []string{
+			`unknown property "lazy" in @inject`,
+			`invalid optional "maybe" in @inject, expecting a boolean`,
+		}, result.Problems())
+	})
+}
+
+func TestInjectAnnotation_DefaultLiteral(t *testing.T) {
+	tests := []struct {
+		typeExpr string
+		value    string
+		want     string
+		wantErr  bool
+	}{
+		{typeExpr: "string", value: "fallback", want: `"fallback"`},
+		{typeExpr: "bool", value: "1", want: "true"},
+		{typeExpr: "bool", value: "TRUE", want: "true"},
+		{typeExpr: "bool", value: "f", want: "false"},
+		{typeExpr: "int", value: "0x10", want: "16"},
+		{typeExpr: "int64", value: "42", want: "int64(42)"},
+		{typeExpr: "int8", value: "-128", want: "int8(-128)"},
+		{typeExpr: "int8", value: "300", wantErr: true},
+		{typeExpr: "uint8", value: "255", want: "uint8(255)"},
+		{typeExpr: "uint8", value: "256", wantErr: true},
+		{typeExpr: "float32", value: "1.5", want: "float32(1.5)"},
+		{typeExpr: "float32", value: "1e39", wantErr: true},
+		{typeExpr: "float64", value: "NaN", wantErr: true},
+		{typeExpr: "*Config", value: "nil", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.typeExpr+" "+tt.value, func(t *testing.T) {
+			// GIVEN
+			logger := zerolog.Nop()
+			annotation := parseInjectAnnotation(&logger, `@inject default="`+tt.value+`"`)
+			annotation.typeExpr = tt.typeExpr
+
+			// WHEN
+			literal, err := annotation.DefaultLiteral()
+
+			// THEN
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, literal)
+		})
+	}
 }
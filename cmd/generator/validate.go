@@ -0,0 +1,174 @@
+package main
+
+import (
+	"fmt"
+	"go/token"
+	"go/types"
+	"strings"
+
+	"github.com/a-peyrard/godi/set"
+)
+
+// runtimeTypes are the types always provided by the resolver itself.
+var runtimeTypes = set.NewWithValues(
+	"context.Context",
+	"*"+diImportPath+".Resolver",
+	diImportPath+".InjectionContext",
+)
+
+var errorType = types.Universe.Lookup("error").Type()
+
+// optionalComponentType returns the type of the component of a godi.Optional, false if the type is not one.
+func optionalComponentType(typ types.Type) (types.Type, bool) {
+	named, ok := typ.(*types.Named)
+	if !ok || named.Obj().Pkg() == nil || named.Obj().Pkg().Path() != diImportPath || named.Obj().Name() != "Optional" {
+		return nil, false
+	}
+	if named.TypeArgs().Len() != 1 {
+		return nil, false
+	}
+	return named.TypeArgs().At(0), true
+}
+
+// validateTypes checks the definitions against the types of the packages (see --validate), returning the problems
+// found, prefixed with their position:
+//   - a named injection must target a known name, with a type the injected component is assignable to
+//   - an injection by type must be satisfied by at least one provider, unless it is optional
+//   - a decorator must take and return the type of the component it decorates
+//
+// The names looking like env vars, and the config fields, are provided at runtime, so they are only checked
+// if they can be typed.
+func validateTypes(defs Definitions) []string {
+	provided := make(map[string][]types.Type)
+	var providedTypes []types.Type
+	provide := func(name string, typ types.Type) {
+		if typ == nil {
+			return
+		}
+		if name != "" {
+			provided[name] = append(provided[name], typ)
+		}
+		providedTypes = append(providedTypes, typ)
+	}
+	for _, p := range defs.Providers {
+		if p.signature == nil {
+			continue
+		}
+		// a function returning several components provides each of them
+		for result := range p.signature.Results().Variables() {
+			if !types.Identical(result.Type(), errorType) {
+				provide(p.Named, result.Type())
+			}
+		}
+	}
+	for _, c := range defs.Components {
+		if c.typ != nil {
+			provide(c.Named, types.NewPointer(c.typ))
+		}
+	}
+	configKeys := set.New[string]()
+	for _, c := range defs.Configs {
+		if c.typ != nil {
+			provide(c.TypeName, types.NewPointer(c.typ))
+		}
+		for _, key := range c.Keys {
+			configKeys.Add(key)
+		}
+	}
+
+	var problems []string
+	report := func(position token.Position, format string, args ...any) {
+		problems = append(problems, fmt.Sprintf("%s:%d: %s", position.Filename, position.Line, fmt.Sprintf(format, args...)))
+	}
+	validate := func(fnName string, injection InjectAnnotation) {
+		if injection.typ == nil || injection.typ == types.Typ[types.Invalid] {
+			return // not typed, or the package does not type-check
+		}
+		if multiple, _ := injection.Multiple(); multiple {
+			return
+		}
+		optional, _ := injection.Optional()
+		if _, found := injection.Default(); found {
+			optional = true
+		}
+		if componentTyp, ok := optionalComponentType(injection.typ); ok {
+			injection.typ, optional = componentTyp, true
+		}
+
+		named, found := injection.Named()
+		if !found {
+			if optional || runtimeTypes.Contains(injection.typ.String()) || anyAssignable(providedTypes, injection.typ) {
+				return
+			}
+			report(injection.position, "%s depends on %s, but no provider provides it", fnName, injection.typ)
+			return
+		}
+
+		candidates, known := provided[named]
+		if !known {
+			if optional || envVarNameRegex.MatchString(named) || configKeys.Contains(named) {
+				return
+			}
+			report(injection.position, "%s injects %q, but no provider is named so", fnName, named)
+			return
+		}
+		if !anyAssignable(candidates, injection.typ) {
+			report(injection.position, "%s injects %q as %s, but it is provided as %s", fnName, named, injection.typ, typesString(candidates))
+		}
+	}
+
+	for _, p := range defs.Providers {
+		for _, injection := range p.Dependencies {
+			validate(p.FnName, injection)
+		}
+	}
+	for _, d := range defs.Decorators {
+		for _, injection := range d.Dependencies {
+			validate(d.FnName, injection)
+		}
+
+		candidates, known := provided[d.Decorate]
+		if d.signature == nil || !known {
+			continue
+		}
+		params, results := d.signature.Params(), d.signature.Results()
+		if params.Len() == 0 || results.Len() == 0 {
+			report(d.position, "%s decorates %q, but does not take and return the decorated component", d.FnName, d.Decorate)
+			continue
+		}
+		if !anyIdentical(candidates, params.At(0).Type()) || !anyIdentical(candidates, results.At(0).Type()) {
+			report(
+				d.position,
+				"%s decorates %q as %s, but it is provided as %s",
+				d.FnName, d.Decorate, params.At(0).Type(), typesString(candidates),
+			)
+		}
+	}
+	return problems
+}
+
+func anyAssignable(candidates []types.Type, target types.Type) bool {
+	for _, candidate := range candidates {
+		if types.AssignableTo(candidate, target) {
+			return true
+		}
+	}
+	return false
+}
+
+func anyIdentical(candidates []types.Type, target types.Type) bool {
+	for _, candidate := range candidates {
+		if types.Identical(candidate, target) {
+			return true
+		}
+	}
+	return false
+}
+
+func typesString(typs []types.Type) string {
+	names := make([]string, len(typs))
+	for i, typ := range typs {
+		names[i] = typ.String()
+	}
+	return strings.Join(names, " or ")
+}
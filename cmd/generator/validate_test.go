@@ -0,0 +1,72 @@
+package main
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_validateTypes(t *testing.T) {
+	t.Run("it should report the annotations not matching the types", func(t *testing.T) {
+		// GIVEN
+		tempDir := setupTestProject(t, "type_validation")
+		t.Chdir(tempDir)
+		defs := scanModule(zerolog.Nop(), tempDir, &RegistryDefinition{Include: []string{"./..."}}, scanOptions{typed: true})
+		providerFile := filepath.Join(tempDir, "provider.go")
+
+		// WHEN
+		problems := validateTypes(defs)
+
+		// THEN
+		assert.ElementsMatch(t, []string{
+			providerFile + `:30: NewService injects "cache", but no provider is named so`,
+			providerFile + `:38: NewHandler injects "service" as string, but it is provided as *github.com/test/validation.Service`,
+			providerFile + `:39: NewHandler depends on github.com/test/validation.Clock, but no provider provides it`,
+			providerFile + `:45: DecorateService decorates "service" as *github.com/test/validation.Handler, but it is provided as *github.com/test/validation.Service`,
+		}, problems)
+	})
+
+	t.Run("it should not validate anything if the types are not loaded", func(t *testing.T) {
+		// GIVEN
+		tempDir := setupTestProject(t, "type_validation")
+		t.Chdir(tempDir)
+		defs := scanModule(zerolog.Nop(), tempDir, &RegistryDefinition{Include: []string{"./..."}}, scanOptions{})
+
+		// WHEN
+		problems := validateTypes(defs)
+
+		// THEN
+		require.Empty(t, problems)
+	})
+}
+
+func TestValidateMode(t *testing.T) {
+	scriptPath := findScriptPath()
+
+	t.Run("it should generate the code if the annotations match the types", func(t *testing.T) {
+		// GIVEN
+		tempDir := setupTestProject(t, "simple_provider")
+
+		// WHEN
+		err := runGenerator(t, scriptPath, tempDir, nil, "--validate")
+
+		// THEN
+		require.NoError(t, err)
+		assertGeneratedCode(t, tempDir, "simple_provider")
+	})
+
+	t.Run("it should fail without generating anything if the annotations do not match the types", func(t *testing.T) {
+		// GIVEN
+		tempDir := setupTestProject(t, "type_validation")
+
+		// WHEN
+		err := runGenerator(t, scriptPath, tempDir, nil, "--validate")
+
+		// THEN
+		require.Error(t, err)
+		assert.NoFileExists(t, filepath.Join(tempDir, "registry_gen.go"))
+	})
+}
@@ -0,0 +1,503 @@
+package main
+
+import (
+	"fmt"
+	"go/token"
+	"go/types"
+	"strings"
+	"unicode"
+
+	"github.com/a-peyrard/godi/set"
+)
+
+type (
+	// WiringDefinition is the static part of the graph of a registry, built by a generated function calling
+	// the providers directly, in topological order, see findWiring.
+	WiringDefinition struct {
+		// Calls are the providers of the static part, in topological order
+		Calls []wiredCall
+		// Runtime are the dependencies of the static part resolved by the resolver, e.g. the env vars or the context
+		Runtime []runtimeDependency
+	}
+
+	// wiredCall is a provider of the static part, whose component is kept in the given field of the wiring.
+	wiredCall struct {
+		provider ProviderDefinition
+		field    string
+		// args are the arguments of the provider: the index of a call of the static part, or of a runtime dependency
+		args []wiredArg
+	}
+
+	wiredArg struct {
+		call    int
+		runtime int
+	}
+
+	// runtimeDependency is a dependency of the static part resolved by the resolver, injected in the wiring,
+	// named after the first parameter it is injected in.
+	runtimeDependency struct {
+		name      string
+		injection string
+		typ       types.Type
+	}
+)
+
+// lifecycleMethods are the methods of the components the resolver calls along their lifecycle, see
+// godi.PostConstructor, godi.Closeable and godi.Stoppable.
+var lifecycleMethods = set.NewWithValues("PostConstruct", "Close", "Stop")
+
+// findWiring finds the static part of the graph, nil if there is none or if the types are not loaded.
+//
+// The static part is made of the providers called directly (see directCallOf), which are singletons without
+// conditions, bindings or decorators, providing a concrete type without lifecycle methods, so the resolver has
+// nothing to do with their components but to store them. Each of their dependencies is either a provider of
+// the static part (matched by name, or by type as the resolver does), or something no definition of the registry
+// provides (env vars, the context, the components of other modules...), which is resolved by the resolver.
+func findWiring(defs Definitions) *WiringDefinition {
+	for _, p := range defs.Providers {
+		if p.signature == nil {
+			return nil
+		}
+	}
+	for _, c := range defs.Components {
+		if c.typ == nil {
+			return nil
+		}
+	}
+
+	decorated := set.New[string]()
+	for _, d := range defs.Decorators {
+		decorated.Add(d.Decorate)
+	}
+
+	// the providers of the static part, with the index of the provider of each parameter, -1 for a runtime dependency
+	candidates := make(map[int][]int)
+	for i, p := range defs.Providers {
+		if !isWirable(p, decorated) {
+			continue
+		}
+		if edges, static := wiredEdges(defs, p); static {
+			candidates[i] = edges
+		}
+	}
+
+	// the providers are called once all the providers of their parameters are, the others (e.g. depending on
+	// a provider which is not static, or in a cycle) are left to the resolver
+	wiring := &WiringDefinition{}
+	calls := make(map[int]int)
+	fields := set.New[string]()
+	runtime := make(map[string]int)
+	for progress := true; progress; {
+		progress = false
+		for i, p := range defs.Providers {
+			edges, candidate := candidates[i]
+			if _, called := calls[i]; !candidate || called || !allCalled(edges, calls) {
+				continue
+			}
+			call := wiredCall{provider: p, field: uniqueIdentifier(fieldNameOf(p.FnName), fields)}
+			for param, edge := range edges {
+				if edge >= 0 {
+					call.args = append(call.args, wiredArg{call: calls[edge], runtime: -1})
+					continue
+				}
+				v := p.signature.Params().At(param)
+				injection := injectionOf(p, param)
+				key := injection + "|" + types.TypeString(v.Type(), nil)
+				if _, found := runtime[key]; !found {
+					runtime[key] = len(wiring.Runtime)
+					wiring.Runtime = append(wiring.Runtime, runtimeDependency{
+						name:      v.Name(),
+						injection: injection,
+						typ:       v.Type(),
+					})
+				}
+				call.args = append(call.args, wiredArg{call: -1, runtime: runtime[key]})
+			}
+			calls[i] = len(wiring.Calls)
+			wiring.Calls = append(wiring.Calls, call)
+			progress = true
+		}
+	}
+	if len(wiring.Calls) == 0 {
+		return nil
+	}
+	return wiring
+}
+
+// isWirable checks if the provider itself can be part of the static part, whatever its dependencies.
+func isWirable(p ProviderDefinition, decorated set.Set[string]) bool {
+	if p.DirectCall == "" || len(p.Conditions) > 0 || len(p.As) > 0 || (p.Scope != "" && p.Scope != singletonScope) {
+		return false
+	}
+	if decorated.Contains(p.Named) || decorated.Contains(defaultNameOf(p)) {
+		return false
+	}
+	provided := providedTypes(p.signature)
+	if len(provided) != 1 || types.IsInterface(provided[0]) || !isQualifiable(provided[0]) {
+		return false
+	}
+	methods := types.NewMethodSet(provided[0])
+	for i := range methods.Len() {
+		if lifecycleMethods.Contains(methods.At(i).Obj().Name()) {
+			return false
+		}
+	}
+	params := p.signature.Params()
+	for i := range params.Len() {
+		if !isQualifiable(params.At(i).Type()) {
+			return false
+		}
+	}
+	return true
+}
+
+// wiredEdges returns the index of the provider of each parameter of the provider, -1 if it is resolved by the
+// resolver, and false if a parameter can't be part of the static part, e.g. injected with all the components
+// of its type, or matching a component.
+func wiredEdges(defs Definitions, p ProviderDefinition) ([]int, bool) {
+	params := p.signature.Params()
+	edges := make([]int, params.Len())
+	for i := range params.Len() {
+		var dependency InjectAnnotation
+		if i < len(p.Dependencies) {
+			dependency = p.Dependencies[i]
+		}
+		multiple, _ := dependency.Multiple()
+		optional, _ := dependency.Optional()
+		_, withDefault := dependency.Default()
+		exact := !multiple && !optional && !withDefault
+
+		var (
+			providers  []int
+			components bool
+		)
+		if named, found := dependency.Named(); found {
+			providers, components = definitionsNamed(defs, named)
+		} else {
+			typ := params.At(i).Type()
+			providers, components = definitionsMatching(defs, typ)
+			if _, basic := typ.Underlying().(*types.Basic); basic {
+				// the env vars or the config fields might match as well
+				exact = false
+			}
+		}
+
+		switch {
+		case len(providers) == 0 && !components:
+			edges[i] = -1
+		case len(providers) == 1 && !components && exact:
+			edges[i] = providers[0]
+		default:
+			return nil, false
+		}
+	}
+	return edges, true
+}
+
+// definitionsNamed returns the providers of the name, and if a component has the name, the configs being left
+// to the resolver.
+func definitionsNamed(defs Definitions, name string) (providers []int, components bool) {
+	for i, p := range defs.Providers {
+		if p.Named == name || (p.Named == "" && defaultNameOf(p) == name) {
+			providers = append(providers, i)
+		}
+	}
+	for _, c := range defs.Components {
+		if c.Named == name || (c.Named == "" && c.TypeName == name) {
+			components = true
+		}
+	}
+	return providers, components
+}
+
+// definitionsMatching returns the providers of a component matching the type, and if a component matches it,
+// the configs being left to the resolver.
+func definitionsMatching(defs Definitions, typ types.Type) (providers []int, components bool) {
+	for i, p := range defs.Providers {
+		for _, provided := range providedTypes(p.signature) {
+			if matchesType(typ, provided) {
+				providers = append(providers, i)
+				break
+			}
+		}
+	}
+	for _, c := range defs.Components {
+		if matchesType(typ, types.NewPointer(c.typ)) {
+			components = true
+		}
+	}
+	return providers, components
+}
+
+// matchesType checks if a component of the provided type matches the queried type, as the resolver does,
+// see godi.matchType.
+func matchesType(queried, provided types.Type) bool {
+	if types.Identical(queried, provided) {
+		return true
+	}
+	iface, ok := queried.Underlying().(*types.Interface)
+	return ok && types.Implements(provided, iface)
+}
+
+// providedTypes returns the types of the components of the function, i.e. its results but the error, the fields
+// of a godi.Out struct being provided instead of the struct.
+func providedTypes(signature *types.Signature) []types.Type {
+	var provided []types.Type
+	results := signature.Results()
+	for i := range results.Len() {
+		typ := results.At(i).Type()
+		if types.Identical(typ, types.Universe.Lookup("error").Type()) {
+			continue
+		}
+		st, ok := typ.Underlying().(*types.Struct)
+		if !ok || !embedsOut(st) {
+			provided = append(provided, typ)
+			continue
+		}
+		for f := range st.NumFields() {
+			if field := st.Field(f); !field.Embedded() && field.Exported() {
+				provided = append(provided, field.Type())
+			}
+		}
+	}
+	return provided
+}
+
+func embedsOut(st *types.Struct) bool {
+	for f := range st.NumFields() {
+		field := st.Field(f)
+		if named, ok := field.Type().(*types.Named); ok && field.Embedded() &&
+			named.Obj().Name() == "Out" && named.Obj().Pkg() != nil && named.Obj().Pkg().Path() == diImportPath {
+			return true
+		}
+	}
+	return false
+}
+
+// isQualifiable checks if the type can be written in the generated code, i.e. without unexported types.
+func isQualifiable(typ types.Type) bool {
+	switch t := types.Unalias(typ).(type) {
+	case *types.Basic:
+		return true
+	case *types.Named:
+		if t.Obj().Pkg() != nil && !t.Obj().Exported() {
+			return false
+		}
+		for i := range t.TypeArgs().Len() {
+			if !isQualifiable(t.TypeArgs().At(i)) {
+				return false
+			}
+		}
+		return true
+	case *types.Pointer:
+		return isQualifiable(t.Elem())
+	case *types.Slice:
+		return isQualifiable(t.Elem())
+	case *types.Array:
+		return isQualifiable(t.Elem())
+	case *types.Map:
+		return isQualifiable(t.Key()) && isQualifiable(t.Elem())
+	case *types.Chan:
+		return isQualifiable(t.Elem())
+	default:
+		return false
+	}
+}
+
+func allCalled(edges []int, calls map[int]int) bool {
+	for _, edge := range edges {
+		if _, called := calls[edge]; edge >= 0 && !called {
+			return false
+		}
+	}
+	return true
+}
+
+// injectionOf returns the dependency builder of the parameter, the parameters without annotation being
+// injected by type, as the resolver does.
+func injectionOf(p ProviderDefinition, param int) string {
+	if param < len(p.Dependencies) {
+		return injectionToDependency(p.Dependencies[param])
+	}
+	return "godi.Inject.Auto()"
+}
+
+// defaultNameOf returns the name the resolver gives to the component of a provider registered without a name,
+// i.e. the name of the function qualified by the last element of its import path.
+func defaultNameOf(p ProviderDefinition) string {
+	return p.ImportPath[strings.LastIndex(p.ImportPath, "/")+1:] + "." + p.FnName
+}
+
+// fieldNameOf returns the name of the field keeping the component of the provider, e.g. repository for NewRepository.
+func fieldNameOf(fnName string) string {
+	name := strings.TrimPrefix(fnName, "New")
+	if name == "" {
+		name = fnName
+	}
+	runes := []rune(name)
+	runes[0] = unicode.ToLower(runes[0])
+	return string(runes)
+}
+
+// uniqueIdentifier returns the name, suffixed with a number if it is already used, and records it as used.
+func uniqueIdentifier(name string, used set.Set[string]) string {
+	if name == "" || name == "_" || token.IsKeyword(name) {
+		name = "dep"
+	}
+	unique := name
+	for i := 2; used.Contains(unique); i++ {
+		unique = fmt.Sprintf("%s%d", name, i)
+	}
+	used.Add(unique)
+	return unique
+}
+
+// wiringNames returns the name of the type of the wiring, of the function building it, and of its component,
+// e.g. registryWiring, newRegistryWiring and app.Registry.wiring for the Registry of the app package.
+func wiringNames(registry *RegistryDefinition) (typeName string, fnName string, componentName string) {
+	runes := []rune(registry.StructName)
+	runes[0] = unicode.ToLower(runes[0])
+	return string(runes) + "Wiring", "new" + registry.StructName + "Wiring", registry.PackageName + "." + registry.StructName + ".wiring"
+}
+
+// imports returns the import paths of the types of the wiring, and fmt to wrap the errors of the providers.
+func (w *WiringDefinition) imports() []string {
+	var imports []string
+	record := func(pkg *types.Package) string {
+		imports = append(imports, pkg.Path())
+		return pkg.Name()
+	}
+	for _, call := range w.Calls {
+		imports = append(imports, call.provider.ImportPath)
+		types.TypeString(call.provider.signature.Results().At(0).Type(), record)
+		if call.provider.signature.Results().Len() == 2 {
+			imports = append(imports, "fmt")
+		}
+	}
+	for _, dependency := range w.Runtime {
+		types.TypeString(dependency.typ, record)
+	}
+	return imports
+}
+
+// registrationTemplate returns the registration of the wiring, injecting its runtime dependencies.
+func (w *WiringDefinition) registrationTemplate(registry *RegistryDefinition) RegistrationTemplate {
+	_, fnName, componentName := wiringNames(registry)
+	options := []string{
+		fmt.Sprintf("godi.Named(\"%s\")", componentName),
+		"godi.Hidden()",
+		"godi.Description(`Builds the static part of the graph, calling the providers directly`)",
+	}
+	var dependencies []string
+	for _, dependency := range w.Runtime {
+		dependencies = append(dependencies, dependency.injection)
+	}
+	return RegistrationTemplate{
+		FnName:  fnName,
+		Options: appendDependenciesToOptions(options, dependencies),
+	}
+}
+
+// wiredProviderToRegistrationTemplate registers the component of a provider of the static part, taken from
+// the wiring. It is named as the resolver would name the component of the provider, so it can be injected by name,
+// and marked as wired, so the resolver rejects what would replace it, see godi.Wired.
+func wiredProviderToRegistrationTemplate(
+	call wiredCall,
+	registry *RegistryDefinition,
+	importWithAlias map[string]string,
+) RegistrationTemplate {
+	p := call.provider
+	named := p.Named
+	if named == "" {
+		named = defaultNameOf(p)
+	}
+	options := []string{fmt.Sprintf("godi.Named(\"%s\")", named)}
+	if p.Priority != 0 {
+		options = append(options, fmt.Sprintf("godi.Priority(%d)", p.Priority))
+	}
+	options = appendLifetimeToOptions(options, p.Scope, p.Eager)
+	if p.Description != "" {
+		options = append(options, fmt.Sprintf("godi.Description(`%s`)", p.Description))
+	}
+	typeName, _, componentName := wiringNames(registry)
+	options = append(options, fmt.Sprintf("godi.Wired(\"%s\")", componentName))
+
+	return RegistrationTemplate{
+		FnName: fmt.Sprintf(
+			"func(w *%s) %s {\n\t\t\treturn w.%s\n\t\t}",
+			typeName,
+			types.TypeString(p.signature.Results().At(0).Type(), qualifierOf(importWithAlias)),
+			call.field,
+		),
+		Options: options,
+	}
+}
+
+// render renders the type of the wiring, and the function building it.
+func (w *WiringDefinition) render(registry *RegistryDefinition, importWithAlias map[string]string) string {
+	typeName, fnName, _ := wiringNames(registry)
+	qualifier := qualifierOf(importWithAlias)
+
+	var b strings.Builder
+	width := 0
+	for _, call := range w.Calls {
+		width = max(width, len(call.field))
+	}
+	fmt.Fprintf(&b, "\n// %s holds the components of the static part of the graph, see %s.\n", typeName, fnName)
+	fmt.Fprintf(&b, "type %s struct {\n", typeName)
+	for _, call := range w.Calls {
+		fmt.Fprintf(&b, "\t%-*s %s\n", width, call.field, types.TypeString(call.provider.signature.Results().At(0).Type(), qualifier))
+	}
+	b.WriteString("}\n\n")
+
+	// the parameters must not shadow the packages used by the function
+	used := set.NewWithValues("w", "err")
+	for _, alias := range importWithAlias {
+		used.Add(alias)
+	}
+	var params, names []string
+	for _, dependency := range w.Runtime {
+		names = append(names, uniqueIdentifier(dependency.name, used))
+		params = append(params, names[len(names)-1]+" "+types.TypeString(dependency.typ, qualifier))
+	}
+	fmt.Fprintf(&b, "// %s builds the static part of the graph, calling the providers directly in topological order,\n", fnName)
+	b.WriteString("// its other dependencies are resolved by the resolver.\n")
+	fmt.Fprintf(&b, "func %s(%s) (*%s, error) {\n", fnName, strings.Join(params, ", "), typeName)
+
+	failing := false
+	for _, call := range w.Calls {
+		failing = failing || call.provider.signature.Results().Len() == 2
+	}
+	if failing {
+		fmt.Fprintf(&b, "\tvar (\n\t\tw   %s\n\t\terr error\n\t)\n", typeName)
+	} else {
+		fmt.Fprintf(&b, "\tvar w %s\n", typeName)
+	}
+	for _, call := range w.Calls {
+		var args []string
+		for _, arg := range call.args {
+			if arg.call >= 0 {
+				args = append(args, "w."+w.Calls[arg.call].field)
+			} else {
+				args = append(args, names[arg.runtime])
+			}
+		}
+		fn := generateFQN(call.provider.ImportPath, call.provider.FnName, importWithAlias)
+		if call.provider.signature.Results().Len() == 1 {
+			fmt.Fprintf(&b, "\tw.%s = %s(%s)\n", call.field, fn, strings.Join(args, ", "))
+			continue
+		}
+		fmt.Fprintf(&b, "\tif w.%s, err = %s(%s); err != nil {\n", call.field, fn, strings.Join(args, ", "))
+		fmt.Fprintf(&b, "\t\treturn nil, %s.Errorf(\"failed to call %s:\\n\\t%%w\", err)\n", importWithAlias["fmt"], defaultNameOf(call.provider))
+		b.WriteString("\t}\n")
+	}
+	b.WriteString("\treturn &w, nil\n}\n")
+	return b.String()
+}
+
+// qualifierOf qualifies the types with the aliases of their packages.
+func qualifierOf(importWithAlias map[string]string) types.Qualifier {
+	return func(pkg *types.Package) string {
+		return importWithAlias[pkg.Path()]
+	}
+}
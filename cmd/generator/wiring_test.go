@@ -0,0 +1,108 @@
+package main
+
+import (
+	"go/types"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var appPackage = types.NewPackage("example.com/app", "app")
+
+func newStructType(name string) *types.Named {
+	return types.NewNamed(types.NewTypeName(0, appPackage, name, nil), types.NewStruct(nil, nil), nil)
+}
+
+func newSignature(results []types.Type, params ...types.Type) *types.Signature {
+	var paramVars, resultVars []*types.Var
+	for _, param := range params {
+		paramVars = append(paramVars, types.NewParam(0, appPackage, "", param))
+	}
+	for _, result := range results {
+		resultVars = append(resultVars, types.NewParam(0, appPackage, "", result))
+	}
+	return types.NewSignatureType(nil, nil, nil, types.NewTuple(paramVars...), types.NewTuple(resultVars...), false)
+}
+
+func newWirableProvider(fnName string, signature *types.Signature) ProviderDefinition {
+	return ProviderDefinition{
+		FnName:     fnName,
+		ImportPath: appPackage.Path(),
+		Scope:      singletonScope,
+		DirectCall: "Call",
+		signature:  signature,
+	}
+}
+
+func Test_findWiring(t *testing.T) {
+	var (
+		config  = types.NewPointer(newStructType("Config"))
+		service = types.NewPointer(newStructType("Service"))
+		cache   = newStructType("Cache")
+	)
+	cache.AddMethod(types.NewFunc(0, appPackage, "Close", newSignature([]types.Type{types.Universe.Lookup("error").Type()})))
+
+	t.Run("it should call the providers in topological order", func(t *testing.T) {
+		// GIVEN
+		defs := Definitions{Providers: []ProviderDefinition{
+			newWirableProvider("NewService", newSignature([]types.Type{service}, config)),
+			newWirableProvider("NewConfig", newSignature([]types.Type{config})),
+		}}
+
+		// WHEN
+		wiring := findWiring(defs)
+
+		// THEN
+		require.NotNil(t, wiring)
+		require.Len(t, wiring.Calls, 2)
+		assert.Equal(t, "config", wiring.Calls[0].field)
+		assert.Equal(t, "service", wiring.Calls[1].field)
+		assert.Equal(t, []wiredArg{{call: 0, runtime: -1}}, wiring.Calls[1].args)
+		assert.Empty(t, wiring.Runtime)
+	})
+
+	t.Run("it should leave the dependencies provided by nothing in the registry to the resolver", func(t *testing.T) {
+		// GIVEN
+		defs := Definitions{Providers: []ProviderDefinition{
+			newWirableProvider("NewService", newSignature([]types.Type{service}, config)),
+		}}
+
+		// WHEN
+		wiring := findWiring(defs)
+
+		// THEN
+		require.NotNil(t, wiring)
+		require.Len(t, wiring.Runtime, 1)
+		assert.Equal(t, "godi.Inject.Auto()", wiring.Runtime[0].injection)
+		assert.Equal(t, []wiredArg{{call: -1, runtime: 0}}, wiring.Calls[0].args)
+	})
+
+	t.Run("it should leave the components with a lifecycle, and their dependents, to the resolver", func(t *testing.T) {
+		// GIVEN
+		defs := Definitions{Providers: []ProviderDefinition{
+			newWirableProvider("NewCache", newSignature([]types.Type{types.NewPointer(cache)})),
+			newWirableProvider("NewService", newSignature([]types.Type{service}, types.NewPointer(cache))),
+		}}
+
+		// WHEN
+		wiring := findWiring(defs)
+
+		// THEN
+		assert.Nil(t, wiring)
+	})
+
+	t.Run("it should not wire anything if the types are not loaded", func(t *testing.T) {
+		// GIVEN
+		defs := Definitions{Providers: []ProviderDefinition{
+			newWirableProvider("NewConfig", newSignature([]types.Type{config})),
+			{FnName: "NewService", ImportPath: appPackage.Path(), DirectCall: "Call1"},
+		}}
+
+		// WHEN
+		wiring := findWiring(defs)
+
+		// THEN
+		assert.Nil(t, wiring)
+	})
+}
@@ -3,6 +3,7 @@ package godi
 import (
 	"fmt"
 	"reflect"
+	"sort"
 )
 
 type (
@@ -17,6 +18,24 @@ type (
 	collectorMultipleAsSlice struct{}
 
 	collectorMultipleAsMap struct{}
+
+	// collectorFactory collects a function building a fresh component on every call, see Inject.Factory.
+	collectorFactory struct {
+		factoryTyp reflect.Type
+	}
+
+	// collectorPostProcessed filters and sorts the components collected by the inner collector,
+	// see Inject.Multiple.
+	collectorPostProcessed struct {
+		inner  collector
+		filter reflect.Value
+		cmp    reflect.Value
+	}
+
+	// collectorOptional collects an Optional wrapping the component, if it is found, see Optional.
+	collectorOptional struct {
+		optionalTyp reflect.Type
+	}
 )
 
 func (c collectorUnique) collect(unitaryTyp reflect.Type, r *Resolver, results []*queryResult, tracker *Tracker) (val reflect.Value, found bool, err error) {
@@ -68,9 +87,103 @@ func (c collectorMultipleAsMap) String() string {
 	return "<📦 multiple as map>"
 }
 
+func (c collectorFactory) collect(_ reflect.Type, r *Resolver, results []*queryResult, tracker *Tracker) (val reflect.Value, found bool, err error) {
+	if len(results) == 0 {
+		return reflect.Zero(c.factoryTyp), false, nil
+	}
+
+	result := results[0]
+	bound := tracker.bound
+	factory := reflect.MakeFunc(c.factoryTyp, func([]reflect.Value) []reflect.Value {
+		// the factory called by the provider it is injected into continues its construction, so the cycles are
+		// detected, but it outlives the resolution, so once the construction is done, it does not use the tracker
+		// nor the context of the resolution anymore, see boundResolution
+		// the caller owns the components it builds, the resolver does not close them
+		tracker := bound.continued()
+		if tracker == nil {
+			tracker = NewTracker()
+			tracker.ctx = r.ctx
+		}
+		comp, err := r.provide(result.provider, result.name, tracker, retainNone)
+		if err != nil {
+			err = fmt.Errorf("failed to provide using %s:\n\t%w", result.provider, err)
+			return []reflect.Value{reflect.Zero(c.factoryTyp.Out(0)), reflect.ValueOf(&err).Elem()}
+		}
+		typed := reflect.New(c.factoryTyp.Out(0)).Elem()
+		typed.Set(comp)
+		return []reflect.Value{typed, reflect.Zero(ErrorType)}
+	})
+	return factory, true, nil
+}
+
+func (c collectorFactory) String() string {
+	return fmt.Sprintf("<📦 factory %s>", c.factoryTyp)
+}
+
+func (c collectorOptional) collect(unitaryTyp reflect.Type, r *Resolver, results []*queryResult, tracker *Tracker) (val reflect.Value, found bool, err error) {
+	optional := reflect.New(c.optionalTyp).Elem()
+	comp, found, err := collectorUnique{}.collect(unitaryTyp, r, results, tracker)
+	if err != nil {
+		return reflect.Value{}, false, err
+	}
+	if found {
+		optional.FieldByName("Value").Set(comp)
+		optional.FieldByName("Found").SetBool(true)
+	}
+	return optional, found, nil
+}
+
+func (c collectorOptional) String() string {
+	return fmt.Sprintf("<📦 optional %s>", c.optionalTyp)
+}
+
+func (c collectorPostProcessed) collect(unitaryTyp reflect.Type, r *Resolver, results []*queryResult, tracker *Tracker) (val reflect.Value, found bool, err error) {
+	val, found, err = c.inner.collect(unitaryTyp, r, results, tracker)
+	if err != nil || !found {
+		return val, found, err
+	}
+	if c.filter.IsValid() {
+		val = c.filtered(val)
+	}
+	if c.cmp.IsValid() {
+		sort.SliceStable(val.Interface(), func(i, j int) bool {
+			return c.cmp.Call([]reflect.Value{val.Index(i), val.Index(j)})[0].Int() < 0
+		})
+	}
+	return val, true, nil
+}
+
+// filtered returns the components matching the filter, in a new slice or map.
+func (c collectorPostProcessed) filtered(val reflect.Value) reflect.Value {
+	matches := func(comp reflect.Value) bool {
+		return c.filter.Call([]reflect.Value{comp})[0].Bool()
+	}
+	if val.Kind() == reflect.Map {
+		filtered := reflect.MakeMapWithSize(val.Type(), val.Len())
+		for iter := val.MapRange(); iter.Next(); {
+			if matches(iter.Value()) {
+				filtered.SetMapIndex(iter.Key(), iter.Value())
+			}
+		}
+		return filtered
+	}
+	filtered := reflect.MakeSlice(val.Type(), 0, val.Len())
+	for i := range val.Len() {
+		if matches(val.Index(i)) {
+			filtered = reflect.Append(filtered, val.Index(i))
+		}
+	}
+	return filtered
+}
+
+func (c collectorPostProcessed) String() string {
+	return fmt.Sprintf("%s post-processed", c.inner)
+}
+
 func extractComponentFromResult(r *Resolver, result *queryResult, tracker *Tracker) (comp reflect.Value, found bool, err error) {
 	if result.component != nil {
 		comp = *result.component
+		tracker.bound.dependsOn(result.name)
 	} else {
 		comp, err = r.provideUsing(result.provider, result.name, tracker)
 		if err != nil {
@@ -0,0 +1,115 @@
+package godi
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/a-peyrard/godi/option"
+)
+
+type (
+	// Builder collects registrations to build an immutable resolver, see Compile.
+	Builder struct {
+		options       []option.Option[ResolverOptions]
+		registrations []registration
+	}
+
+	registration struct {
+		reg  Registrable
+		opts []option.Option[RegistrableOptions]
+	}
+)
+
+// NewBuilder creates a builder for an immutable resolver, built with the given options.
+func NewBuilder(opts ...option.Option[ResolverOptions]) *Builder {
+	return &Builder{options: opts}
+}
+
+// Providers adds providers, either as functions or as Provider implementations.
+func (b *Builder) Providers(providers ...Registrable) *Builder {
+	for _, p := range providers {
+		b.registrations = append(b.registrations, registration{reg: p})
+	}
+	return b
+}
+
+// Decorators adds decorators implementing Decorator, decorators as functions can be added with Register.
+func (b *Builder) Decorators(decorators ...Decorator) *Builder {
+	for _, d := range decorators {
+		b.registrations = append(b.registrations, registration{reg: d})
+	}
+	return b
+}
+
+// Register adds a provider or a decorator with registration options, see Resolver.Register.
+func (b *Builder) Register(reg Registrable, opts ...option.Option[RegistrableOptions]) *Builder {
+	b.registrations = append(b.registrations, registration{reg: reg, opts: opts})
+	return b
+}
+
+// Compile builds the resolver, registering everything in order (so the conditions are evaluated),
+// then validating the whole dependency graph without building any component.
+//
+// The compiled resolver does not accept any registration anymore, which allows it to plan the lookup of the providers
+// once for all: the queries of the dependencies and of the registered components are planned by Compile, the other
+// ones on their first resolution. The dynamic providers (e.g. EnvProvider, FlagProvider or SecretProvider) are
+// still looked up on every resolution, their answers being able to change over time.
+func (b *Builder) Compile() (*Resolver, error) {
+	r := New(b.options...)
+
+	var errs []error
+	for _, reg := range b.registrations {
+		if err := r.Register(reg.reg, reg.opts...); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if len(errs) > 0 {
+		return nil, fmt.Errorf("failed to compile resolver, some registrations failed:\n\t%w", errors.Join(errs...))
+	}
+
+	if err := r.Validate(); err != nil {
+		return nil, fmt.Errorf("failed to compile resolver, the dependency graph is invalid:\n\t%w", err)
+	}
+
+	r.compiled.Store(true)
+	r.precomputePlans()
+	return r, nil
+}
+
+// precomputePlans plans the queries of the dependencies of the providers and of the decorators, along with the
+// queries of the components with fixed names, by name and by type, see Resolver.candidatesFor.
+func (r *Resolver) precomputePlans() {
+	index := r.indexOfProviders()
+	planAll := func(requests []Request) {
+		for _, req := range requests {
+			if q, planned := req.query.(plannedQuery); planned {
+				r.fixedCandidatesFor(q, index)
+			}
+		}
+	}
+	for _, p := range r.providers.All() {
+		planAll(p.Dependencies())
+		if !hasFixedNames(p) {
+			continue
+		}
+		for _, n := range p.ListProvidableNames() {
+			r.fixedCandidatesFor(queryByName{name: n}, index)
+			r.fixedCandidatesFor(queryByType{typ: n.typ}, index)
+		}
+	}
+	r.decorators.Range(func(_, decorators any) bool {
+		for _, d := range decorators.(*SortedCOWSlice[Decorator]).All() {
+			planAll(d.Dependencies())
+		}
+		return true
+	})
+}
+
+// MustCompile builds the resolver, see Compile.
+//
+// It panics if the compilation fails.
+func (b *Builder) MustCompile() *Resolver {
+	r, err := b.Compile()
+	mustSucceed(loggerOrNop(option.Build(&ResolverOptions{}, b.options...).logger), err, "failed to compile resolver")
+	return r
+}
@@ -0,0 +1,137 @@
+package godi
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuilder_Compile(t *testing.T) {
+	t.Run("it should compile a resolver able to resolve the components", func(t *testing.T) {
+		// GIVEN
+		builder := NewBuilder().
+			Providers(NewTestService, NewTestRepository, NewTestController)
+
+		// WHEN
+		resolver, err := builder.Compile()
+
+		// THEN
+		require.NoError(t, err)
+		controller, err := Resolve[*TestController](resolver)
+		require.NoError(t, err)
+		assert.NotNil(t, controller.Service)
+		assert.NotNil(t, controller.Repo)
+	})
+
+	t.Run("it should report missing dependencies without building anything", func(t *testing.T) {
+		// GIVEN
+		built := false
+		builder := NewBuilder().
+			Providers(NewTestController, func() *TestService {
+				built = true
+				return &TestService{}
+			})
+
+		// WHEN
+		_, err := builder.Compile()
+
+		// THEN
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "no providers found for <unique mandatory>")
+		assert.Contains(t, err.Error(), "*godi.TestRepository")
+		assert.False(t, built)
+	})
+
+	t.Run("it should report cycles", func(t *testing.T) {
+		// GIVEN
+		builder := NewBuilder().
+			Register(func(string) int { return 0 }, Named("a"), Dependencies(Inject.Named("b"))).
+			Register(func(int) string { return "" }, Named("b"), Dependencies(Inject.Named("a")))
+
+		// WHEN
+		_, err := builder.Compile()
+
+		// THEN
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "cycle found")
+	})
+
+	t.Run("it should refuse registrations once compiled", func(t *testing.T) {
+		// GIVEN
+		resolver, err := NewBuilder().Providers(NewTestService).Compile()
+		require.NoError(t, err)
+
+		// WHEN
+		err = resolver.Register(NewTestRepository)
+
+		// THEN
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "the resolver is compiled")
+	})
+
+	t.Run("it should see the env vars set after the first resolution", func(t *testing.T) {
+		// GIVEN
+		resolver := NewBuilder().Providers(NewEnvProvider()).MustCompile()
+		_, err := ResolveNamed[string](resolver, "GODI_COMPILED_ENV")
+		require.Error(t, err)
+
+		// WHEN
+		t.Setenv("GODI_COMPILED_ENV", "set")
+		value, err := ResolveNamed[string](resolver, "GODI_COMPILED_ENV")
+
+		// THEN
+		require.NoError(t, err)
+		assert.Equal(t, "set", value)
+	})
+
+	t.Run("it should plan the queries of the dependencies at compile time", func(t *testing.T) {
+		// GIVEN
+		builder := NewBuilder().
+			Providers(NewTestService, NewTestRepository, NewTestController)
+
+		// WHEN
+		resolver := builder.MustCompile()
+
+		// THEN
+		cached, found := resolver.plans.Load(queryByType{typ: TypeOf[*TestRepository]()})
+		require.True(t, found)
+		assert.Len(t, cached.([]candidate), 1)
+	})
+
+	t.Run("it should keep the plans of the fixed providers along with dynamic providers", func(t *testing.T) {
+		// GIVEN
+		resolver := NewBuilder().
+			Providers(NewEnvProvider(), NewConfigFieldProvider[TestConfig](), func() *TestConfig {
+				return &TestConfig{Port: 8080}
+			}).
+			MustCompile()
+
+		// WHEN
+		port, err := ResolveNamed[int](resolver, "TestConfig.Port")
+
+		// THEN
+		require.NoError(t, err)
+		assert.Equal(t, 8080, port)
+		_, found := resolver.plans.Load(queryByName{name: Name{name: "TestConfig.Port", typ: intType}})
+		assert.True(t, found)
+	})
+
+	t.Run("it should rank the dynamic providers with the fixed ones once they answer a query", func(t *testing.T) {
+		// GIVEN
+		resolver := NewBuilder().
+			Providers(NewEnvProvider()).
+			Register(func() string { return "fixed" }, Named("GODI_COMPILED_RANKED"), Priority(-1)).
+			MustCompile()
+		q := queryByName{name: Name{name: "GODI_COMPILED_RANKED", typ: StringType}}
+		require.Equal(t, -1, resolver.candidatesFor(q)[0].provider.Priority())
+
+		// WHEN
+		t.Setenv("GODI_COMPILED_RANKED", "env")
+		candidates := resolver.candidatesFor(q)
+
+		// THEN
+		require.Len(t, candidates, 1)
+		assert.Equal(t, 0, candidates[0].provider.Priority(), "the env var wins over the component of a lower priority")
+	})
+}
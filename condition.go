@@ -1,20 +1,71 @@
 package godi
 
-import "github.com/a-peyrard/godi/option"
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"regexp"
+	"runtime"
+	"runtime/debug"
+	"slices"
+	"strings"
+
+	"github.com/a-peyrard/godi/fn"
+	"github.com/a-peyrard/godi/option"
+)
 
 type (
-	condition struct {
+	// Condition gates a registration, the component is registered only if the condition holds.
+	//
+	// Conditions are evaluated at registration time, an error makes the registration fail.
+	Condition interface {
+		Evaluate(r *Resolver) (bool, error)
+	}
+
+	// ConditionFunc is a helper to create Condition from a function.
+	ConditionFunc func(r *Resolver) (bool, error)
+
+	// namedStringCondition compares a named string component with a value.
+	namedStringCondition struct {
 		namedStringComponent string
 		operator             operator
 		value                string
 	}
 
+	// factCondition checks a build or runtime fact, which does not depend on the resolver.
+	factCondition func() bool
+
+	// allCondition holds if all its conditions hold, see And.
+	allCondition []Condition
+
+	// anyCondition holds if any of its conditions holds, see Or.
+	anyCondition []Condition
+
+	// notCondition holds if its conditions do not all hold, see Not.
+	notCondition []Condition
+
+	// describedCondition is a condition with a human-readable description, see Resolver.Providers.
+	describedCondition struct {
+		Condition
+		description string
+	}
+
 	operator = func(string, string) bool
 
 	ConditionBuilder     struct{}
 	ConditionNameBuilder struct {
 		namedStringComponent string
 	}
+
+	// EnvConditionBuilder builds conditions on an environment variable, see WhenEnv.
+	EnvConditionBuilder struct {
+		name string
+	}
+
+	// TypeConditionBuilder builds conditions on the registered providers, see WhenType.
+	TypeConditionBuilder struct {
+		typ reflect.Type
+	}
 )
 
 //goland:noinspection GoVarAndConstTypeMayBeOmitted
@@ -35,27 +86,339 @@ func When(namedStringComponent string) ConditionNameBuilder {
 }
 
 func (cn ConditionNameBuilder) Equals(value string) option.Option[RegistrableOptions] {
+	return cn.withCondition("equals", value, namedStringCondition{
+		namedStringComponent: cn.namedStringComponent,
+		operator:             equals,
+		value:                value,
+	})
+}
+
+func (cn ConditionNameBuilder) NotEquals(value string) option.Option[RegistrableOptions] {
+	return cn.withCondition("not equals", value, namedStringCondition{
+		namedStringComponent: cn.namedStringComponent,
+		operator:             notEquals,
+		value:                value,
+	})
+}
+
+// Matches registers the component only if the named string component matches the regular expression.
+func (cn ConditionNameBuilder) Matches(pattern string) option.Option[RegistrableOptions] {
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return withCondition(invalidCondition(fmt.Errorf("invalid pattern for condition on %s:\n\t%w", cn.namedStringComponent, err)))
+	}
+	return cn.withCondition("matches", pattern, namedStringCondition{
+		namedStringComponent: cn.namedStringComponent,
+		operator: func(actual, _ string) bool {
+			return re.MatchString(actual)
+		},
+		value: pattern,
+	})
+}
+
+// In registers the component only if the named string component is one of the given values.
+func (cn ConditionNameBuilder) In(values ...string) option.Option[RegistrableOptions] {
+	return cn.withCondition("in", values, namedStringCondition{
+		namedStringComponent: cn.namedStringComponent,
+		operator: func(actual, _ string) bool {
+			return slices.Contains(values, actual)
+		},
+		value: strings.Join(values, ","),
+	})
+}
+
+func (cn ConditionNameBuilder) withCondition(operator string, value any, cond Condition) option.Option[RegistrableOptions] {
+	return withCondition(describedCondition{
+		Condition:   cond,
+		description: fmt.Sprintf("%s %s %q", cn.namedStringComponent, operator, value),
+	})
+}
+
+// WhenEnv builds conditions on the given environment variable, an unset variable never satisfies them,
+// except NotExists.
+//
+// Unlike When, the variable is read from the environment, not from the components of the resolver.
+func WhenEnv(name string) EnvConditionBuilder {
+	return EnvConditionBuilder{name: name}
+}
+
+func (ce EnvConditionBuilder) Equals(value string) option.Option[RegistrableOptions] {
+	return ce.matching(fmt.Sprintf("equals %q", value), func(actual string) bool {
+		return actual == value
+	})
+}
+
+func (ce EnvConditionBuilder) NotEquals(value string) option.Option[RegistrableOptions] {
+	return ce.matching(fmt.Sprintf("not equals %q", value), func(actual string) bool {
+		return actual != value
+	})
+}
+
+// Matches registers the component only if the environment variable matches the regular expression.
+func (ce EnvConditionBuilder) Matches(pattern string) option.Option[RegistrableOptions] {
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return withCondition(invalidCondition(fmt.Errorf("invalid pattern for condition on env var %s:\n\t%w", ce.name, err)))
+	}
+	return ce.matching(fmt.Sprintf("matches %q", pattern), re.MatchString)
+}
+
+// In registers the component only if the environment variable is one of the given values.
+func (ce EnvConditionBuilder) In(values ...string) option.Option[RegistrableOptions] {
+	return ce.matching(fmt.Sprintf("in %q", values), func(actual string) bool {
+		return slices.Contains(values, actual)
+	})
+}
+
+// Exists registers the component only if the environment variable is set, even if empty.
+func (ce EnvConditionBuilder) Exists() option.Option[RegistrableOptions] {
+	return ce.matching("exists", func(string) bool {
+		return true
+	})
+}
+
+// NotExists registers the component only if the environment variable is not set.
+func (ce EnvConditionBuilder) NotExists() option.Option[RegistrableOptions] {
+	return withDescribedCondition("env "+ce.name+" does not exist", factCondition(func() bool {
+		_, found := os.LookupEnv(ce.name)
+		return !found
+	}))
+}
+
+func (ce EnvConditionBuilder) matching(description string, predicate func(string) bool) option.Option[RegistrableOptions] {
+	return withDescribedCondition("env "+ce.name+" "+description, factCondition(func() bool {
+		actual, found := os.LookupEnv(ce.name)
+		return found && predicate(actual)
+	}))
+}
+
+// WhenType builds conditions on the providers of type T.
+//
+// Conditions are evaluated at registration time, so only the providers registered before are considered.
+func WhenType[T any]() TypeConditionBuilder {
+	return TypeConditionBuilder{typ: TypeOf[T]()}
+}
+
+// Exists registers the component only if a provider of the type is registered.
+func (ct TypeConditionBuilder) Exists() option.Option[RegistrableOptions] {
+	return withDescribedCondition("type "+ct.typ.String()+" exists", ConditionFunc(func(r *Resolver) (bool, error) {
+		return len(r.candidatesFor(queryByType{typ: ct.typ})) > 0, nil
+	}))
+}
+
+// NotExists registers the component only if no provider of the type is registered, e.g. to register a default.
+func (ct TypeConditionBuilder) NotExists() option.Option[RegistrableOptions] {
+	return withDescribedCondition("type "+ct.typ.String()+" does not exist", ConditionFunc(func(r *Resolver) (bool, error) {
+		return len(r.candidatesFor(queryByType{typ: ct.typ})) == 0, nil
+	}))
+}
+
+// And registers the component only if all the conditions of the given options hold.
+//
+// The options are expected to be conditions (When, WhenEnv, WhenOS...), their other settings are ignored.
+func And(conditions ...option.Option[RegistrableOptions]) option.Option[RegistrableOptions] {
+	return withCondition(allCondition(conditionsOf(conditions)))
+}
+
+// Or registers the component if any of the conditions of the given options holds.
+//
+// The options are expected to be conditions (When, WhenEnv, WhenOS...), their other settings are ignored.
+func Or(conditions ...option.Option[RegistrableOptions]) option.Option[RegistrableOptions] {
+	return withCondition(anyCondition(conditionsOf(conditions)))
+}
+
+// Not registers the component only if the conditions of the given option do not hold.
+func Not(condition option.Option[RegistrableOptions]) option.Option[RegistrableOptions] {
+	return withCondition(notCondition(conditionsOf([]option.Option[RegistrableOptions]{condition})))
+}
+
+// WhenOS registers the component only if the program runs on one of the given operating systems (see runtime.GOOS).
+func WhenOS(goos ...string) option.Option[RegistrableOptions] {
+	return withDescribedCondition(fmt.Sprintf("os in %q", goos), factCondition(func() bool {
+		return slices.Contains(goos, runtime.GOOS)
+	}))
+}
+
+// WhenArch registers the component only if the program runs on one of the given architectures (see runtime.GOARCH).
+func WhenArch(goarch ...string) option.Option[RegistrableOptions] {
+	return withDescribedCondition(fmt.Sprintf("arch in %q", goarch), factCondition(func() bool {
+		return slices.Contains(goarch, runtime.GOARCH)
+	}))
+}
+
+// WhenBuildTag registers the component only if the program was built with the given build tag.
+//
+// The tags are read from the build information embedded in the binary, so this condition never holds
+// for binaries built without build information.
+func WhenBuildTag(tag string) option.Option[RegistrableOptions] {
+	return withDescribedCondition("build tag "+tag, factCondition(func() bool {
+		return slices.Contains(buildTags(), tag)
+	}))
+}
+
+// WhenGoVersionAtLeast registers the component only if the program was built with a Go version
+// greater or equal to the given one (e.g. "1.23"). Development versions of Go always satisfy it.
+func WhenGoVersionAtLeast(version string) option.Option[RegistrableOptions] {
+	return withDescribedCondition("go version at least "+version, factCondition(func() bool {
+		return goVersionAtLeast(runtime.Version(), version)
+	}))
+}
+
+// WhenProvided registers the provider only if a component with the given name is provided, or applies
+// the decorator only if a component with the given name is provided when it decorates a component.
+//
+// As the decorators are applied when the components are built, the components registered after the decorator
+// are considered, e.g. a decorator can wrap the components with metrics only if a metrics registry is registered.
+func WhenProvided(name string) option.Option[RegistrableOptions] {
+	cond := describedCondition{
+		Condition: ConditionFunc(func(r *Resolver) (bool, error) {
+			return slices.ContainsFunc(r.providers.All(), func(p Provider) bool {
+				return providesName(p, name)
+			}), nil
+		}),
+		description: name + " is provided",
+	}
 	return func(opts *RegistrableOptions) {
-		opts.conditions = append(
-			opts.conditions,
-			condition{
-				namedStringComponent: cn.namedStringComponent,
-				operator:             equals,
-				value:                value,
-			},
-		)
+		opts.appliedConditions = append(opts.appliedConditions, cond)
 	}
 }
 
-func (cn ConditionNameBuilder) NotEquals(value string) option.Option[RegistrableOptions] {
+// WhenCond registers the component only if the given condition holds.
+func WhenCond(cond Condition) option.Option[RegistrableOptions] {
+	return withCondition(cond)
+}
+
+func withCondition(cond Condition) option.Option[RegistrableOptions] {
 	return func(opts *RegistrableOptions) {
-		opts.conditions = append(
-			opts.conditions,
-			condition{
-				namedStringComponent: cn.namedStringComponent,
-				operator:             notEquals,
-				value:                value,
+		opts.conditions = append(opts.conditions, cond)
+	}
+}
+
+func withDescribedCondition(description string, cond Condition) option.Option[RegistrableOptions] {
+	return withCondition(describedCondition{Condition: cond, description: description})
+}
+
+func (f ConditionFunc) Evaluate(r *Resolver) (bool, error) {
+	return f(r)
+}
+
+func (c namedStringCondition) Evaluate(r *Resolver) (bool, error) {
+	val, found, err := r.resolve(Request{
+		unitaryTyp: StringType,
+		query: queryByName{
+			name: Name{
+				name: c.namedStringComponent,
+				typ:  StringType,
 			},
-		)
+		},
+		validator: validatorUniqueOptional{},
+		collector: collectorUnique{},
+	})
+	if err != nil || !found {
+		return false, nil // a missing or unresolvable component does not satisfy the condition
+	}
+
+	return c.operator(val.String(), c.value), nil
+}
+
+func (c factCondition) Evaluate(*Resolver) (bool, error) {
+	return c(), nil
+}
+
+func (c allCondition) Evaluate(r *Resolver) (bool, error) {
+	for _, cond := range c {
+		holds, err := cond.Evaluate(r)
+		if err != nil || !holds {
+			return false, err
+		}
 	}
+	return true, nil
+}
+
+func (c anyCondition) Evaluate(r *Resolver) (bool, error) {
+	for _, cond := range c {
+		holds, err := cond.Evaluate(r)
+		if err != nil || holds {
+			return holds, err
+		}
+	}
+	return false, nil
+}
+
+func (c notCondition) Evaluate(r *Resolver) (bool, error) {
+	holds, err := allCondition(c).Evaluate(r)
+	return !holds && err == nil, err
+}
+
+func (c describedCondition) String() string {
+	return c.description
+}
+
+func (c allCondition) String() string {
+	return "(" + joinConditions(c, " and ") + ")"
+}
+
+func (c anyCondition) String() string {
+	return "(" + joinConditions(c, " or ") + ")"
+}
+
+func (c notCondition) String() string {
+	return "not (" + joinConditions(c, " and ") + ")"
+}
+
+// describeCondition describes the condition, by its type if it is not a fmt.Stringer.
+func describeCondition(cond Condition) string {
+	if stringer, ok := cond.(fmt.Stringer); ok {
+		return stringer.String()
+	}
+	return fmt.Sprintf("%T", cond)
+}
+
+func joinConditions(conditions []Condition, separator string) string {
+	descriptions := make([]string, 0, len(conditions))
+	for _, cond := range conditions {
+		descriptions = append(descriptions, describeCondition(cond))
+	}
+	return strings.Join(descriptions, separator)
+}
+
+// conditionsOf extracts the conditions set by the given options.
+func conditionsOf(opts []option.Option[RegistrableOptions]) []Condition {
+	options := option.Build(&RegistrableOptions{}, opts...)
+	return append(options.conditions, options.appliedConditions...)
+}
+
+// invalidCondition is a condition failing the registration, for conditions which can not be built.
+func invalidCondition(err error) Condition {
+	return ConditionFunc(func(*Resolver) (bool, error) {
+		return false, err
+	})
+}
+
+func buildTags() []string {
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return nil
+	}
+	for _, setting := range info.Settings {
+		if setting.Key == "-tags" {
+			return strings.Split(setting.Value, ",")
+		}
+	}
+	return nil
+}
+
+func goVersionAtLeast(goVersion string, version string) bool {
+	if !strings.HasPrefix(goVersion, "go") {
+		return true // development version
+	}
+	// ignore the pre-release and experiment suffixes, e.g. go1.23rc1 or go1.23.0 X:nocoverageredesign
+	goVersion = strings.TrimPrefix(goVersion, "go")
+	if end := strings.IndexFunc(goVersion, func(r rune) bool {
+		return r != '.' && (r < '0' || r > '9')
+	}); end >= 0 {
+		goVersion = goVersion[:end]
+	}
+
+	return compareVersions(goVersion, strings.TrimPrefix(version, "go")) != fn.Less
 }
@@ -0,0 +1,317 @@
+package godi
+
+import (
+	"errors"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRuntimeConditions(t *testing.T) {
+	t.Run("it should register the component only on the matching OS", func(t *testing.T) {
+		// GIVEN
+		resolver := New()
+		resolver.MustRegister(ToStaticProvider("current"), Named("os.current"), WhenOS(runtime.GOOS))
+		resolver.MustRegister(ToStaticProvider("other"), Named("os.other"), WhenOS("plan42"))
+
+		// WHEN
+		_, currentFound, err1 := TryResolveNamed[string](resolver, "os.current")
+		_, otherFound, err2 := TryResolveNamed[string](resolver, "os.other")
+
+		// THEN
+		require.NoError(t, err1)
+		require.NoError(t, err2)
+		assert.True(t, currentFound)
+		assert.False(t, otherFound)
+	})
+
+	t.Run("it should register the component only on the matching architecture", func(t *testing.T) {
+		// GIVEN
+		resolver := New()
+		resolver.MustRegister(ToStaticProvider("current"), Named("arch"), WhenArch("unknown", runtime.GOARCH))
+
+		// WHEN
+		arch, err := ResolveNamed[string](resolver, "arch")
+
+		// THEN
+		require.NoError(t, err)
+		assert.Equal(t, "current", arch)
+	})
+
+	t.Run("it should not register the component if the build tag is missing", func(t *testing.T) {
+		// GIVEN
+		resolver := New()
+		resolver.MustRegister(ToStaticProvider("debug"), Named("debug"), WhenBuildTag("godi_surely_not_set"))
+
+		// WHEN
+		_, found, err := TryResolveNamed[string](resolver, "debug")
+
+		// THEN
+		require.NoError(t, err)
+		assert.False(t, found)
+	})
+
+	t.Run("it should compare the go versions", func(t *testing.T) {
+		assert.True(t, goVersionAtLeast("go1.23.4", "1.23"))
+		assert.True(t, goVersionAtLeast("go1.24rc1", "go1.23"))
+		assert.False(t, goVersionAtLeast("go1.22.9", "1.23"))
+		assert.True(t, goVersionAtLeast("devel go1.25-abcdef", "1.30"))
+	})
+}
+
+func TestWhenCond(t *testing.T) {
+	t.Run("it should register the component if the condition holds", func(t *testing.T) {
+		// GIVEN
+		resolver := New()
+		resolver.MustRegister(ToStaticProvider(true), Named("feature.enabled"))
+		featureEnabled := ConditionFunc(func(r *Resolver) (bool, error) {
+			return ResolveNamed[bool](r, "feature.enabled")
+		})
+		resolver.MustRegister(ToStaticProvider("feature"), Named("feature"), WhenCond(featureEnabled))
+
+		// WHEN
+		feature, err := ResolveNamed[string](resolver, "feature")
+
+		// THEN
+		require.NoError(t, err)
+		assert.Equal(t, "feature", feature)
+	})
+
+	t.Run("it should not register the component if the condition does not hold", func(t *testing.T) {
+		// GIVEN
+		resolver := New()
+		never := ConditionFunc(func(*Resolver) (bool, error) { return false, nil })
+		resolver.MustRegister(ToStaticProvider("feature"), Named("feature"), WhenCond(never))
+
+		// WHEN
+		_, found, err := TryResolveNamed[string](resolver, "feature")
+
+		// THEN
+		require.NoError(t, err)
+		assert.False(t, found)
+	})
+
+	t.Run("it should fail the registration if the condition fails", func(t *testing.T) {
+		// GIVEN
+		resolver := New()
+		failing := ConditionFunc(func(*Resolver) (bool, error) { return false, errors.New("license server unreachable") })
+
+		// WHEN
+		err := resolver.Register(ToStaticProvider("feature"), Named("feature"), WhenCond(failing))
+
+		// THEN
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "license server unreachable")
+	})
+}
+
+func TestWhen(t *testing.T) {
+	t.Run("it should register the component if the named string matches the pattern", func(t *testing.T) {
+		// GIVEN
+		resolver := New()
+		resolver.MustRegister(ToStaticProvider("eu-west-1"), Named("REGION"))
+		resolver.MustRegister(ToStaticProvider("eu"), Named("gdpr"), When("REGION").Matches(`^eu-`))
+		resolver.MustRegister(ToStaticProvider("us"), Named("ccpa"), When("REGION").Matches(`^us-`))
+
+		// WHEN
+		_, gdprFound, err1 := TryResolveNamed[string](resolver, "gdpr")
+		_, ccpaFound, err2 := TryResolveNamed[string](resolver, "ccpa")
+
+		// THEN
+		require.NoError(t, err1)
+		require.NoError(t, err2)
+		assert.True(t, gdprFound)
+		assert.False(t, ccpaFound)
+	})
+
+	t.Run("it should fail the registration if the pattern is invalid", func(t *testing.T) {
+		// GIVEN
+		resolver := New()
+
+		// WHEN
+		err := resolver.Register(ToStaticProvider("eu"), Named("gdpr"), When("REGION").Matches(`(`))
+
+		// THEN
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "invalid pattern for condition on REGION")
+	})
+
+	t.Run("it should register the component if the named string is one of the values", func(t *testing.T) {
+		// GIVEN
+		resolver := New()
+		resolver.MustRegister(ToStaticProvider("staging"), Named("APP_ENV"))
+		resolver.MustRegister(ToStaticProvider("debug"), Named("debug"), When("APP_ENV").In("dev", "staging"))
+
+		// WHEN
+		_, found, err := TryResolveNamed[string](resolver, "debug")
+
+		// THEN
+		require.NoError(t, err)
+		assert.True(t, found)
+	})
+}
+
+func TestWhenEnv(t *testing.T) {
+	t.Run("it should evaluate the conditions against the environment", func(t *testing.T) {
+		// GIVEN
+		t.Setenv("GODI_TEST_ENV", "production")
+		resolver := New()
+		resolver.MustRegister(ToStaticProvider("eq"), Named("eq"), WhenEnv("GODI_TEST_ENV").Equals("production"))
+		resolver.MustRegister(ToStaticProvider("neq"), Named("neq"), WhenEnv("GODI_TEST_ENV").NotEquals("production"))
+		resolver.MustRegister(ToStaticProvider("matches"), Named("matches"), WhenEnv("GODI_TEST_ENV").Matches(`^prod`))
+		resolver.MustRegister(ToStaticProvider("in"), Named("in"), WhenEnv("GODI_TEST_ENV").In("staging", "production"))
+		resolver.MustRegister(ToStaticProvider("exists"), Named("exists"), WhenEnv("GODI_TEST_ENV").Exists())
+		resolver.MustRegister(ToStaticProvider("unset"), Named("unset"), WhenEnv("GODI_SURELY_NOT_SET").NotEquals("production"))
+		resolver.MustRegister(ToStaticProvider("not exists"), Named("not exists"), WhenEnv("GODI_SURELY_NOT_SET").NotExists())
+
+		// WHEN
+		found := make(map[string]bool)
+		for _, name := range []string{"eq", "neq", "matches", "in", "exists", "unset", "not exists"} {
+			_, found[name], _ = TryResolveNamed[string](resolver, name)
+		}
+
+		// THEN
+		assert.Equal(t, map[string]bool{
+			"eq":         true,
+			"neq":        false,
+			"matches":    true,
+			"in":         true,
+			"exists":     true,
+			"unset":      false,
+			"not exists": true,
+		}, found)
+	})
+}
+
+func TestWhenType(t *testing.T) {
+	t.Run("it should register the component depending on the registered types", func(t *testing.T) {
+		// GIVEN
+		resolver := New()
+		resolver.MustRegister(NewTestService)
+		resolver.MustRegister(ToStaticProvider("with service"), Named("with service"), WhenType[*TestService]().Exists())
+		resolver.MustRegister(ToStaticProvider("with repository"), Named("with repository"), WhenType[*TestRepository]().Exists())
+		resolver.MustRegister(NewTestRepository, WhenType[*TestRepository]().NotExists())
+
+		// WHEN
+		_, withService, err1 := TryResolveNamed[string](resolver, "with service")
+		_, withRepository, err2 := TryResolveNamed[string](resolver, "with repository")
+		_, repositoryFound, err3 := TryResolve[*TestRepository](resolver)
+
+		// THEN
+		require.NoError(t, err1)
+		require.NoError(t, err2)
+		require.NoError(t, err3)
+		assert.True(t, withService)
+		assert.False(t, withRepository)
+		assert.True(t, repositoryFound)
+	})
+}
+
+func TestComposedConditions(t *testing.T) {
+	t.Run("it should compose the conditions", func(t *testing.T) {
+		// GIVEN
+		resolver := New()
+		resolver.MustRegister(ToStaticProvider("dev"), Named("APP_ENV"))
+		resolver.MustRegister(ToStaticProvider("and"), Named("and"), And(When("APP_ENV").Equals("dev"), WhenOS(runtime.GOOS)))
+		resolver.MustRegister(ToStaticProvider("and false"), Named("and false"), And(When("APP_ENV").Equals("dev"), WhenOS("plan42")))
+		resolver.MustRegister(ToStaticProvider("or"), Named("or"), Or(When("APP_ENV").Equals("prod"), WhenOS(runtime.GOOS)))
+		resolver.MustRegister(ToStaticProvider("or false"), Named("or false"), Or(When("APP_ENV").Equals("prod"), WhenOS("plan42")))
+		resolver.MustRegister(ToStaticProvider("not"), Named("not"), Not(When("APP_ENV").Equals("prod")))
+		resolver.MustRegister(ToStaticProvider("nested"), Named("nested"), Or(Not(WhenOS(runtime.GOOS)), And(When("APP_ENV").In("dev"))))
+
+		// WHEN
+		found := make(map[string]bool)
+		for _, name := range []string{"and", "and false", "or", "or false", "not", "nested"} {
+			_, found[name], _ = TryResolveNamed[string](resolver, name)
+		}
+
+		// THEN
+		assert.Equal(t, map[string]bool{
+			"and":       true,
+			"and false": false,
+			"or":        true,
+			"or false":  false,
+			"not":       true,
+			"nested":    true,
+		}, found)
+	})
+
+	t.Run("it should propagate the errors of the composed conditions", func(t *testing.T) {
+		// GIVEN
+		resolver := New()
+
+		// WHEN
+		err := resolver.Register(ToStaticProvider("feature"), Named("feature"), Not(When("REGION").Matches(`(`)))
+
+		// THEN
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "invalid pattern")
+	})
+}
+
+func TestWhenProvided(t *testing.T) {
+	decorateGreeting := func(greeting string) string { return greeting + " (measured)" }
+
+	t.Run("it should apply the decorator if the component is provided, even if registered after it", func(t *testing.T) {
+		// GIVEN
+		resolver := New()
+		resolver.MustRegister(func() string { return "hello" }, Named("greeting"))
+		resolver.MustRegister(decorateGreeting, Decorate("greeting"), WhenProvided("metrics.registry"))
+		resolver.MustRegister(func() int { return 42 }, Named("metrics.registry"))
+
+		// WHEN
+		greeting, err := ResolveNamed[string](resolver, "greeting")
+
+		// THEN
+		require.NoError(t, err)
+		assert.Equal(t, "hello (measured)", greeting)
+	})
+
+	t.Run("it should not apply the decorator if the component is not provided", func(t *testing.T) {
+		// GIVEN
+		resolver := New()
+		resolver.MustRegister(func() string { return "hello" }, Named("greeting"))
+		resolver.MustRegister(decorateGreeting, Decorate("greeting"), WhenProvided("metrics.registry"))
+
+		// WHEN
+		greeting, err := ResolveNamed[string](resolver, "greeting")
+
+		// THEN
+		require.NoError(t, err)
+		assert.Equal(t, "hello", greeting)
+		assert.Len(t, resolver.DecoratorsFor("greeting"), 1, "the decorator is registered anyway")
+	})
+
+	t.Run("it should not apply the decorator by type if the component is not provided", func(t *testing.T) {
+		// GIVEN
+		resolver := New()
+		resolver.MustRegister(func() *TestService { return &TestService{Name: "service"} })
+		resolver.MustRegister(func(s *TestService) *TestService {
+			return &TestService{Name: s.Name + " (measured)"}
+		}, DecorateType[*TestService](), WhenProvided("metrics.registry"))
+
+		// WHEN
+		service, err := Resolve[*TestService](resolver)
+
+		// THEN
+		require.NoError(t, err)
+		assert.Equal(t, "service", service.Name)
+	})
+
+	t.Run("it should evaluate the condition at registration for a provider", func(t *testing.T) {
+		// GIVEN
+		resolver := New()
+		resolver.MustRegister(func() string { return "measured" }, Named("greeting"), WhenProvided("metrics.registry"))
+		resolver.MustRegister(func() int { return 42 }, Named("metrics.registry"))
+
+		// WHEN
+		_, found, err := TryResolveNamed[string](resolver, "greeting")
+
+		// THEN
+		require.NoError(t, err)
+		assert.False(t, found)
+		assert.Equal(t, "metrics.registry is provided", resolver.Explain("greeting").Skipped[0].Condition)
+	})
+}
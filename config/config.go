@@ -1,7 +1,11 @@
 package config
 
 import (
+	"flag"
 	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
 	"strings"
 
 	"github.com/a-peyrard/godi/fn"
@@ -20,6 +24,24 @@ type (
 
 	Options struct {
 		prefix string
+
+		files         []string
+		configType    string
+		searchPaths   []string
+		envOverlay    bool
+		envOverlayVar string
+
+		flags *flag.FlagSet
+
+		effective *Effective
+	}
+
+	// Effective describes the configuration actually loaded, and where it comes from.
+	Effective struct {
+		// Sources lists the configuration sources in merge order, the last one having the highest precedence.
+		Sources []string
+		// Settings are all the settings known by the loader, after merging all the sources.
+		Settings map[string]any
 	}
 
 	WithDefault interface {
@@ -33,6 +55,68 @@ func WithEnvPrefix(prefix string) option.Option[Options] {
 	}
 }
 
+// WithFile loads the configuration from the given file, see WithFiles.
+func WithFile(file string) option.Option[Options] {
+	return WithFiles(file)
+}
+
+// WithFiles loads the configuration from the given files, in order, each file overriding the previous ones.
+//
+// The files can be glob patterns (e.g. conf.d/*.yaml), the matching files are loaded in lexical order,
+// and a pattern matching no file is ignored. A file which is not a pattern must exist.
+//
+// The format of the files is deduced from their extension (yaml, json, toml...), unless set with WithConfigType.
+// Env vars always take precedence over the files.
+func WithFiles(files ...string) option.Option[Options] {
+	return func(opts *Options) {
+		opts.files = append(opts.files, files...)
+	}
+}
+
+// WithConfigType sets the format of the configuration files (yaml, json, toml...),
+// for files without extension, or with an extension not matching their format.
+func WithConfigType(configType string) option.Option[Options] {
+	return func(opts *Options) {
+		opts.configType = configType
+	}
+}
+
+// WithSearchPaths looks up the relative configuration files in the given directories, in order,
+// the first directory containing the file (or matching the pattern) is used.
+func WithSearchPaths(paths ...string) option.Option[Options] {
+	return func(opts *Options) {
+		opts.searchPaths = append(opts.searchPaths, paths...)
+	}
+}
+
+// WithEnvOverlay loads, after each file, an optional overlay file for the current environment,
+// i.e. for base.yaml and APP_ENV=prod, base.prod.yaml is loaded (if it exists) on top of base.yaml.
+func WithEnvOverlay() option.Option[Options] {
+	return WithEnvOverlayFrom(defaultEnvOverlayVar)
+}
+
+// WithEnvOverlayFrom is like WithEnvOverlay, but the current environment is read from the given env var.
+func WithEnvOverlayFrom(envVar string) option.Option[Options] {
+	return func(opts *Options) {
+		opts.envOverlay = true
+		opts.envOverlayVar = envVar
+	}
+}
+
+// WithEffectiveConfig fills the given Effective with the loaded configuration, and the sources it was merged from.
+func WithEffectiveConfig(effective *Effective) option.Option[Options] {
+	return func(opts *Options) {
+		opts.effective = effective
+	}
+}
+
+const (
+	defaultEnvOverlayVar = "APP_ENV"
+	envSource            = "env"
+	// defaultTag gives the value of a field when neither the files nor the env vars set it, e.g. `default:"8080"`
+	defaultTag = "default"
+)
+
 func Load[T any](opts ...option.Option[Options]) (*T, error) {
 	options := option.Build(&Options{}, opts...)
 
@@ -40,9 +124,27 @@ func Load[T any](opts ...option.Option[Options]) (*T, error) {
 	v.SetEnvPrefix(options.prefix)
 	v.SetEnvKeyReplacer(strings.NewReplacer(".", "_"))
 	v.AutomaticEnv()
+	if options.configType != "" {
+		v.SetConfigType(options.configType)
+	}
+
+	sources, err := mergeFiles(v, options)
+	if err != nil {
+		return nil, err
+	}
 
 	var vT T
 	bindEnvs(v, options.prefix, reflect.New(reflect.TypeOf(vT)).Elem().Interface())
+	sources = append(sources, envSource)
+
+	if options.flags != nil && mergeFlags(v, options.flags, reflect.TypeOf(vT)) {
+		sources = append(sources, flagsSource)
+	}
+
+	if options.effective != nil {
+		options.effective.Sources = sources
+		options.effective.Settings = v.AllSettings()
+	}
 
 	if err := v.Unmarshal(&vT); err != nil {
 		return nil, fmt.Errorf("unable to unmarshal config: %w", err)
@@ -67,6 +169,129 @@ func Load[T any](opts ...option.Option[Options]) (*T, error) {
 	return &vT, nil
 }
 
+// mergeFiles merges all the configuration files in viper, and returns the files actually loaded, in merge order.
+func mergeFiles(v *viper.Viper, options *Options) ([]string, error) {
+	files, err := findFiles(options)
+	if err != nil {
+		return nil, err
+	}
+
+	var sources []string
+	for _, file := range files {
+		if err := mergeFile(v, file); err != nil {
+			return nil, err
+		}
+		sources = append(sources, file)
+
+		if !options.envOverlay {
+			continue
+		}
+		env := os.Getenv(options.envOverlayVar)
+		if env == "" {
+			continue
+		}
+		overlay := overlayFileFor(file, env)
+		if _, err := os.Stat(overlay); os.IsNotExist(err) {
+			continue // overlays are optional
+		}
+		if err := mergeFile(v, overlay); err != nil {
+			return nil, err
+		}
+		sources = append(sources, overlay)
+	}
+	return sources, nil
+}
+
+// findFiles expands the patterns and looks up the relative files in the search paths.
+func findFiles(options *Options) ([]string, error) {
+	var files []string
+	for _, file := range options.files {
+		searched := len(options.searchPaths) > 0 && !filepath.IsAbs(file)
+		dirs := []string{""}
+		if searched {
+			dirs = options.searchPaths
+		}
+
+		if isPattern(file) {
+			for _, dir := range dirs {
+				matches, err := filepath.Glob(filepath.Join(dir, file))
+				if err != nil {
+					return nil, fmt.Errorf("invalid config file pattern %s: %w", file, err)
+				}
+				if len(matches) > 0 {
+					files = append(files, matches...)
+					break
+				}
+			}
+			continue
+		}
+
+		if !searched {
+			files = append(files, file)
+			continue
+		}
+		found := false
+		for _, dir := range dirs {
+			candidate := filepath.Join(dir, file)
+			if _, err := os.Stat(candidate); err == nil {
+				files = append(files, candidate)
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("unable to find config file %s in %s", file, strings.Join(dirs, ", "))
+		}
+	}
+	return files, nil
+}
+
+func isPattern(file string) bool {
+	return strings.ContainsAny(file, "*?[")
+}
+
+func mergeFile(v *viper.Viper, file string) error {
+	v.SetConfigFile(file)
+	if err := v.MergeInConfig(); err != nil {
+		return fmt.Errorf("unable to load config file %s: %w", file, err)
+	}
+	return nil
+}
+
+// overlayFileFor returns the overlay file for the given environment, i.e. base.yaml => base.<env>.yaml
+func overlayFileFor(file string, env string) string {
+	ext := filepath.Ext(file)
+	return strings.TrimSuffix(file, ext) + "." + env + ext
+}
+
+// String dumps the effective configuration, starting with the merge order of the sources.
+func (e Effective) String() string {
+	var b strings.Builder
+	b.WriteString(fmt.Sprintf("merge order: %s\n", strings.Join(e.Sources, " < ")))
+
+	flattened := make(map[string]any)
+	flattenSettings(e.Settings, "", flattened)
+	keys := make([]string, 0, len(flattened))
+	for key := range flattened {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		b.WriteString(fmt.Sprintf("%s = %v\n", key, flattened[key]))
+	}
+	return b.String()
+}
+
+func flattenSettings(settings map[string]any, prefix string, flattened map[string]any) {
+	for key, value := range settings {
+		if nested, ok := value.(map[string]any); ok {
+			flattenSettings(nested, prefix+key+".", flattened)
+			continue
+		}
+		flattened[prefix+key] = value
+	}
+}
+
 func bindEnvs(viperI *viper.Viper, envPrefix string, myStruct any, parts ...string) {
 	ifv := reflect.ValueOf(myStruct)
 	ift := reflect.TypeOf(myStruct)
@@ -88,6 +313,9 @@ func bindEnvs(viperI *viper.Viper, envPrefix string, myStruct any, parts ...stri
 			key := strings.Join(append(parts, tv), ".")
 			join := strings.Join(append(parts, str.ToScreamingSnakeCase(tv)), ".")
 			_ = viperI.BindEnv(key, mergeWithEnvPrefix(envPrefix, join))
+			if defaultValue, ok := t.Tag.Lookup(defaultTag); ok {
+				viperI.SetDefault(key, defaultValue)
+			}
 		}
 	}
 }
@@ -1,6 +1,9 @@
 package config
 
 import (
+	"flag"
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -24,6 +27,13 @@ type (
 		FooBar     int
 		CustomerId int
 	}
+	DefaultTagConfig struct {
+		Host   string `default:"localhost"`
+		Port   int    `default:"8080"`
+		Server struct {
+			Timeout string `default:"30s"`
+		}
+	}
 )
 
 func (c *BarTestConfig) ApplyDefault() {
@@ -108,4 +118,205 @@ func TestLoad(t *testing.T) {
 		assert.Equal(t, 12, conf.FooBar)
 		assert.Equal(t, 66, conf.CustomerId)
 	})
+
+	t.Run("it should use the default tag of the fields not set by the env vars", func(t *testing.T) {
+		// GIVEN
+		t.Setenv("DEFAULTS_PORT", "9090")
+
+		// WHEN
+		conf, err := Load[DefaultTagConfig](WithEnvPrefix("DEFAULTS"))
+
+		// THEN
+		require.NoError(t, err)
+		assert.Equal(t, "localhost", conf.Host)
+		assert.Equal(t, 9090, conf.Port)
+		assert.Equal(t, "30s", conf.Server.Timeout)
+	})
+}
+
+func writeFile(t *testing.T, dir string, name string, content string) string {
+	path := filepath.Join(dir, name)
+	require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+	return path
+}
+
+func TestLoad_Files(t *testing.T) {
+	t.Run("it should load the config from a file", func(t *testing.T) {
+		// GIVEN
+		dir := t.TempDir()
+		base := writeFile(t, dir, "base.yaml", "foo:\n  hello: from-base\n  world: 1\n")
+
+		// WHEN
+		conf, err := Load[TestConfig](WithEnvPrefix("FILES"), WithFiles(base))
+
+		// THEN
+		require.NoError(t, err)
+		assert.Equal(t, "from-base", conf.Foo.Hello)
+		assert.Equal(t, 1, conf.Foo.World)
+	})
+
+	t.Run("it should layer the env overlay file, then the env vars", func(t *testing.T) {
+		// GIVEN
+		dir := t.TempDir()
+		base := writeFile(t, dir, "base.yaml", "foo:\n  hello: from-base\n  world: 1\nbar:\n  first: 10\n")
+		writeFile(t, dir, "base.prod.yaml", "foo:\n  world: 2\nbar:\n  first: 20\n")
+		t.Setenv("APP_ENV", "prod")
+		t.Setenv("FILES_BAR_FIRST", "30")
+
+		// WHEN
+		var effective Effective
+		conf, err := Load[TestConfig](
+			WithEnvPrefix("FILES"),
+			WithFiles(base),
+			WithEnvOverlay(),
+			WithEffectiveConfig(&effective),
+		)
+
+		// THEN
+		require.NoError(t, err)
+		assert.Equal(t, "from-base", conf.Foo.Hello)
+		assert.Equal(t, 2, conf.Foo.World)
+		assert.Equal(t, 30, conf.Bar.First)
+		assert.Equal(t, []string{base, filepath.Join(dir, "base.prod.yaml"), "env"}, effective.Sources)
+		assert.Contains(t, effective.String(), "merge order: "+base+" < "+filepath.Join(dir, "base.prod.yaml")+" < env\n")
+		assert.Contains(t, effective.String(), "foo.world = 2\n")
+	})
+
+	t.Run("it should ignore missing overlay files", func(t *testing.T) {
+		// GIVEN
+		dir := t.TempDir()
+		base := writeFile(t, dir, "base.json", `{"foo": {"hello": "from-json"}}`)
+		t.Setenv("DEPLOY_ENV", "staging")
+
+		// WHEN
+		var effective Effective
+		conf, err := Load[TestConfig](
+			WithEnvPrefix("FILES"),
+			WithFiles(base),
+			WithEnvOverlayFrom("DEPLOY_ENV"),
+			WithEffectiveConfig(&effective),
+		)
+
+		// THEN
+		require.NoError(t, err)
+		assert.Equal(t, "from-json", conf.Foo.Hello)
+		assert.Equal(t, []string{base, "env"}, effective.Sources)
+	})
+
+	t.Run("it should fail if a base file does not exist", func(t *testing.T) {
+		// WHEN
+		_, err := Load[TestConfig](WithFiles(filepath.Join(t.TempDir(), "missing.yaml")))
+
+		// THEN
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "unable to load config file")
+	})
+
+	t.Run("it should load the files matching a pattern in lexical order", func(t *testing.T) {
+		// GIVEN
+		dir := t.TempDir()
+		first := writeFile(t, dir, "10-base.yaml", "foo:\n  hello: from-base\n  world: 1\n")
+		second := writeFile(t, dir, "20-override.yaml", "foo:\n  world: 2\n")
+
+		// WHEN
+		var effective Effective
+		conf, err := Load[TestConfig](
+			WithEnvPrefix("FILES"),
+			WithFiles(filepath.Join(dir, "*.yaml")),
+			WithEffectiveConfig(&effective),
+		)
+
+		// THEN
+		require.NoError(t, err)
+		assert.Equal(t, "from-base", conf.Foo.Hello)
+		assert.Equal(t, 2, conf.Foo.World)
+		assert.Equal(t, []string{first, second, "env"}, effective.Sources)
+	})
+
+	t.Run("it should look up the file in the search paths", func(t *testing.T) {
+		// GIVEN
+		empty := t.TempDir()
+		dir := t.TempDir()
+		file := writeFile(t, dir, "app.yaml", "foo:\n  hello: from-search-path\n")
+
+		// WHEN
+		var effective Effective
+		conf, err := Load[TestConfig](
+			WithEnvPrefix("FILES"),
+			WithFile("app.yaml"),
+			WithSearchPaths(empty, dir),
+			WithEffectiveConfig(&effective),
+		)
+
+		// THEN
+		require.NoError(t, err)
+		assert.Equal(t, "from-search-path", conf.Foo.Hello)
+		assert.Equal(t, []string{file, "env"}, effective.Sources)
+	})
+
+	t.Run("it should fail if the file is in none of the search paths", func(t *testing.T) {
+		// WHEN
+		_, err := Load[TestConfig](WithFile("app.yaml"), WithSearchPaths(t.TempDir()))
+
+		// THEN
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "unable to find config file app.yaml")
+	})
+
+	t.Run("it should read the files with the given config type", func(t *testing.T) {
+		// GIVEN
+		dir := t.TempDir()
+		file := writeFile(t, dir, "app.conf", "[foo]\nhello = \"from-toml\"\n")
+
+		// WHEN
+		conf, err := Load[TestConfig](WithEnvPrefix("FILES"), WithFile(file), WithConfigType("toml"))
+
+		// THEN
+		require.NoError(t, err)
+		assert.Equal(t, "from-toml", conf.Foo.Hello)
+	})
+}
+
+func TestLoad_Flags(t *testing.T) {
+	t.Run("it should declare a flag for each field of the config", func(t *testing.T) {
+		// GIVEN
+		flags := flag.NewFlagSet("test", flag.ContinueOnError)
+
+		// WHEN
+		DeclareFlags[TestConfig](flags)
+
+		// THEN
+		var names []string
+		flags.VisitAll(func(f *flag.Flag) {
+			names = append(names, f.Name)
+		})
+		assert.Equal(t, []string{"bar.first", "bar.second", "foo.hello", "foo.world"}, names)
+	})
+
+	t.Run("it should layer the flags set on the command line above the env vars", func(t *testing.T) {
+		// GIVEN
+		dir := t.TempDir()
+		base := writeFile(t, dir, "base.yaml", "foo:\n  hello: from-base\n  world: 1\n")
+		t.Setenv("FLAGS_FOO_WORLD", "2")
+		t.Setenv("FLAGS_BAR_SECOND", "3")
+		flags := flag.NewFlagSet("test", flag.ContinueOnError)
+		DeclareFlags[TestConfig](flags)
+		require.NoError(t, flags.Parse([]string{"-foo.world", "4"}))
+
+		// WHEN
+		var effective Effective
+		conf, err := Load[TestConfig](
+			WithEnvPrefix("FLAGS"),
+			WithFiles(base),
+			WithFlags(flags),
+			WithEffectiveConfig(&effective),
+		)
+
+		// THEN
+		require.NoError(t, err)
+		assert.Equal(t, "from-base", conf.Foo.Hello)
+		assert.Equal(t, 4, conf.Foo.World)
+		assert.Equal(t, 3, conf.Bar.Second)
+		assert.Equal(t, []string{base, "env", "flags"}, effective.Sources)
+	})
 }
@@ -0,0 +1,77 @@
+package config
+
+import (
+	"flag"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/a-peyrard/godi/option"
+	"github.com/spf13/viper"
+)
+
+const flagsSource = "flags"
+
+// DeclareFlags declares a flag for each field of the config struct T, named after the key of the field
+// in the configuration, e.g. -server.port for the field Port of the nested struct Server.
+//
+// The flags are loaded by Load with WithFlags, once parsed.
+func DeclareFlags[T any](flags *flag.FlagSet) {
+	for _, key := range configKeys(reflect.TypeOf((*T)(nil)).Elem()) {
+		if flags.Lookup(key) == nil {
+			flags.String(key, "", fmt.Sprintf("overrides the configuration %s", key))
+		}
+	}
+}
+
+// WithFlags loads the flags set on the command line matching a configuration key, see DeclareFlags.
+// The flags take precedence over the env vars and the files.
+func WithFlags(flags *flag.FlagSet) option.Option[Options] {
+	return func(opts *Options) {
+		opts.flags = flags
+	}
+}
+
+// mergeFlags sets in viper the flags matching the keys of the config, and reports if any flag was set.
+func mergeFlags(v *viper.Viper, flags *flag.FlagSet, typ reflect.Type) bool {
+	keys := make(map[string]bool)
+	for _, key := range configKeys(typ) {
+		keys[key] = true
+	}
+
+	merged := false
+	flags.Visit(func(f *flag.Flag) {
+		if keys[f.Name] {
+			v.Set(f.Name, f.Value.String())
+			merged = true
+		}
+	})
+	return merged
+}
+
+// configKeys lists the keys of all the fields of the config struct, as viper names them.
+func configKeys(typ reflect.Type, parts ...string) []string {
+	var keys []string
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		name, ok := field.Tag.Lookup("mapstructure")
+		if !ok {
+			name = field.Name
+		}
+		name = strings.ToLower(name)
+
+		fieldTyp := field.Type
+		if fieldTyp.Kind() == reflect.Pointer && fieldTyp.Elem().Kind() == reflect.Struct {
+			fieldTyp = fieldTyp.Elem()
+		}
+		if fieldTyp.Kind() == reflect.Struct {
+			keys = append(keys, configKeys(fieldTyp, append(parts, name)...)...)
+			continue
+		}
+		keys = append(keys, strings.Join(append(parts, name), "."))
+	}
+	return keys
+}
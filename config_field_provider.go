@@ -3,33 +3,112 @@ package godi
 import (
 	"fmt"
 	"reflect"
+	"slices"
+	"strconv"
 	"strings"
 	"sync"
 
 	"github.com/a-peyrard/godi/fn"
+	"github.com/a-peyrard/godi/option"
 	"github.com/a-peyrard/godi/reflectutils"
 	"github.com/a-peyrard/godi/structs"
 )
 
-// ConfigFieldProvider is a provider that provides all config fields as components.
-type ConfigFieldProvider[T any] struct {
-	once          sync.Once
-	names         []Name
-	fieldWithType map[string]reflect.Type
-	prefix        string
+// configKeyTags are the struct tags naming a config field, by order of precedence.
+// A field tagged with "-" is not provided.
+var configKeyTags = []string{"godi", "mapstructure"}
+
+type (
+	// ConfigFieldProvider is a provider that provides all config fields as components.
+	ConfigFieldProvider[T any] struct {
+		once   sync.Once
+		names  []Name
+		fields map[string]configField
+		// patterns are the keys of the fields of the elements of the slices and maps, see collectElements
+		patterns []string
+		prefix   string
+		options  ConfigFieldOptions
+	}
+
+	// ConfigFieldOptions configures how the config fields are named.
+	ConfigFieldOptions struct {
+		prefix *string
+		naming ConfigNaming
+	}
+
+	// ConfigNaming names the segment of a config field which is not tagged.
+	ConfigNaming func(field reflect.StructField) string
+
+	configField struct {
+		typ  reflect.Type
+		path string
+	}
+)
+
+const (
+	// indexWildcard is the segment of the keys and paths of the fields standing for the index of a slice element.
+	indexWildcard = "#"
+	// keyWildcard is the segment of the keys and paths of the fields standing for the key of a map element.
+	keyWildcard = "*"
+)
+
+// NewConfigFieldProvider creates a provider for the fields of the config struct T.
+//
+// By default, the components are named after the struct name and the path of the field,
+// e.g. "AppConfig.Database.URL". The segments can be renamed with the `godi` or `mapstructure` tags.
+//
+// The elements of the slices and maps (with string keys) are provided too, along with their fields, named after
+// their index or key, e.g. "AppConfig.Brokers.0.Host" or "AppConfig.Labels.env". As they depend on the content of the
+// config, they are not listed by ListProvidableNames, unlike the whole slices and maps. The fields of elements
+// referencing their own type are not provided, only the elements themselves.
+func NewConfigFieldProvider[T any](opts ...option.Option[ConfigFieldOptions]) *ConfigFieldProvider[T] {
+	return &ConfigFieldProvider[T]{
+		options: *option.Build(&ConfigFieldOptions{}, opts...),
+	}
+}
+
+// WithConfigPrefix replaces the struct name by the given prefix in the names of the config fields,
+// an empty prefix names the fields with their path only.
+func WithConfigPrefix(prefix string) option.Option[ConfigFieldOptions] {
+	return func(opts *ConfigFieldOptions) {
+		opts.prefix = &prefix
+	}
+}
+
+// WithConfigNaming names the segments of the fields which are not tagged with the given strategy.
+func WithConfigNaming(naming ConfigNaming) option.Option[ConfigFieldOptions] {
+	return func(opts *ConfigFieldOptions) {
+		opts.naming = naming
+	}
+}
+
+// FieldNaming names the segments after the Go field names, this is the default strategy.
+func FieldNaming(field reflect.StructField) string {
+	return field.Name
+}
+
+// LowerCaseNaming names the segments after the lower-cased Go field names, as viper does,
+// e.g. "config.database.url".
+func LowerCaseNaming(field reflect.StructField) string {
+	return strings.ToLower(field.Name)
 }
 
 func (c *ConfigFieldProvider[T]) CanProvide(name Name) bool {
 	c.loadNamesIfNeeded()
 
-	knownName, found := c.fieldWithType[name.name]
-	return found && matchType(name.typ, knownName)
+	field, found := c.fieldNamed(name.name)
+	return found && matchType(name.typ, field.typ)
 }
 
 func (c *ConfigFieldProvider[T]) Provide(name Name, dependencies []reflect.Value) (comp reflect.Value, err error) {
 	cfg := dependencies[0].Interface()
 
-	value, err := structs.Get(cfg, strings.TrimPrefix(name.name, c.prefix))
+	c.loadNamesIfNeeded()
+	field, found := c.fieldNamed(name.name)
+	if !found {
+		return reflect.Zero(name.typ), fmt.Errorf("no config field named %s", name.name)
+	}
+	value, err := structs.Get(cfg, field.path)
 	if err != nil {
 		return reflect.Zero(name.typ), err
 	}
@@ -66,7 +145,7 @@ func (c *ConfigFieldProvider[T]) Priority() int {
 
 func (c *ConfigFieldProvider[T]) Description() string {
 	c.loadNamesIfNeeded()
-	return fmt.Sprintf("Provides config fields for %s", strings.TrimSuffix(c.prefix, "."))
+	return fmt.Sprintf("Provides config fields for %s", reflect.TypeOf((*T)(nil)).Elem().Name())
 }
 
 func (c *ConfigFieldProvider[T]) loadNamesIfNeeded() {
@@ -81,31 +160,179 @@ func (c *ConfigFieldProvider[T]) loadNamesInternal() {
 	// so if one want to get the value of the field "Port" in the struct "TestConfig",
 	// the provider will be named "TestConfig.Port".
 	c.prefix = reflect.TypeOf(emptyConfig).Elem().Name() + "."
+	if c.options.prefix != nil {
+		c.prefix = *c.options.prefix
+		if c.prefix != "" {
+			c.prefix += "."
+		}
+	}
+	naming := c.options.naming
+	if naming == nil {
+		naming = FieldNaming
+	}
 
-	reflectutils.WalkStruct(emptyConfig, reflectutils.CreateNilStructs)
+	c.fields = make(map[string]configField)
+	c.collectFields(emptyConfig, nil, naming, []reflect.Type{reflect.TypeOf(emptyConfig).Elem()})
+
+	c.names = make([]Name, 0, len(c.fields))
+	for fieldPath, field := range c.fields {
+		if slices.Contains(c.patterns, fieldPath) {
+			continue
+		}
+		c.names = append(
+			c.names,
+			Name{
+				name: fieldPath,
+				typ:  field.typ,
+			},
+		)
+	}
+}
 
-	c.fieldWithType = make(map[string]reflect.Type)
+// collectFields collects the fields of the config struct, under the given parent path. The structs and the elements
+// of the slices and maps being collected are given as ancestors, so the self-referencing configs are not walked forever.
+func (c *ConfigFieldProvider[T]) collectFields(config any, parent []string, naming ConfigNaming, ancestors []reflect.Type) {
 	reflectutils.WalkStruct(
-		emptyConfig,
+		config,
 		fn.AllTriConsumer(
 			reflectutils.CreateNilStructs,
 			func(_ reflect.Value, fieldTyp reflect.Type, path []string) {
-				if len(path) > 0 {
-					fieldPath := c.prefix + strings.Join(path, ".")
-					c.fieldWithType[fieldPath] = fieldTyp
+				if len(path) == 0 {
+					return
+				}
+				path = append(slices.Clone(parent), path...)
+				if c.collectField(path, fieldTyp, naming) {
+					c.collectElements(path, fieldTyp, naming, ancestors)
 				}
 			},
 		),
 	)
+}
 
-	c.names = make([]Name, 0, len(c.fieldWithType))
-	for fieldPath, fieldTyp := range c.fieldWithType {
-		c.names = append(
-			c.names,
-			Name{
-				name: fieldPath,
-				typ:  fieldTyp,
-			},
-		)
+// collectElements collects the elements of the slice or map at the given path, if it is one, along with their
+// fields, under a wildcard standing for their index or key, e.g. "Brokers.#.Host".
+func (c *ConfigFieldProvider[T]) collectElements(path []string, typ reflect.Type, naming ConfigNaming, ancestors []reflect.Type) {
+	var wildcard string
+	switch {
+	case typ.Kind() == reflect.Slice || typ.Kind() == reflect.Array:
+		wildcard = indexWildcard
+	case typ.Kind() == reflect.Map && typ.Key().Kind() == reflect.String:
+		wildcard = keyWildcard
+	default:
+		return
+	}
+
+	elemPath := append(slices.Clone(path), wildcard)
+	elemTyp := typ.Elem()
+	if !c.collectField(elemPath, elemTyp, naming) {
+		return
+	}
+	c.collectElements(elemPath, elemTyp, naming, ancestors)
+
+	structTyp := elemTyp
+	for structTyp.Kind() == reflect.Pointer {
+		structTyp = structTyp.Elem()
+	}
+	if structTyp.Kind() != reflect.Struct || slices.Contains(ancestors, structTyp) {
+		return
+	}
+	c.collectFields(reflect.New(structTyp).Interface(), elemPath, naming, append(slices.Clone(ancestors), structTyp))
+}
+
+// collectField collects the field at the given path, unless it is not provided, see configKeyOf.
+func (c *ConfigFieldProvider[T]) collectField(path []string, typ reflect.Type, naming ConfigNaming) bool {
+	key, provided := configKeyOf(reflect.TypeOf(new(T)), path, naming)
+	if !provided {
+		return false
+	}
+	c.fields[c.prefix+key] = configField{
+		typ:  typ,
+		path: strings.Join(path, "."),
+	}
+	if slices.Contains(path, indexWildcard) || slices.Contains(path, keyWildcard) {
+		c.patterns = append(c.patterns, c.prefix+key)
+	}
+	return true
+}
+
+// fieldNamed returns the field with the given name, the elements of the slices and maps getting the path of their
+// index or key.
+func (c *ConfigFieldProvider[T]) fieldNamed(name string) (configField, bool) {
+	if field, found := c.fields[name]; found && !slices.Contains(c.patterns, name) {
+		return field, true
+	}
+	segments := strings.Split(name, ".")
+	for _, pattern := range c.patterns {
+		if captured, matched := matchConfigPattern(strings.Split(pattern, "."), segments); matched {
+			field := c.fields[pattern]
+			path := strings.Split(field.path, ".")
+			for i, segment := range path {
+				if segment == indexWildcard || segment == keyWildcard {
+					path[i], captured = captured[0], captured[1:]
+				}
+			}
+			return configField{typ: field.typ, path: strings.Join(path, ".")}, true
+		}
+	}
+	return configField{}, false
+}
+
+// matchConfigPattern matches the segments of a name with the ones of a pattern, returning the segments matching its
+// wildcards, in order. An index wildcard only matches a non-negative integer.
+func matchConfigPattern(pattern []string, segments []string) (captured []string, matched bool) {
+	if len(pattern) != len(segments) {
+		return nil, false
+	}
+	for i, segment := range pattern {
+		switch segment {
+		case indexWildcard:
+			if index, err := strconv.Atoi(segments[i]); err != nil || index < 0 {
+				return nil, false
+			}
+			captured = append(captured, segments[i])
+		case keyWildcard:
+			captured = append(captured, segments[i])
+		default:
+			if segment != segments[i] {
+				return nil, false
+			}
+		}
+	}
+	return captured, true
+}
+
+// configKeyOf names the field at the given path, the field is not provided if one of the segments is tagged with "-".
+func configKeyOf(typ reflect.Type, path []string, naming ConfigNaming) (string, bool) {
+	segments := make([]string, 0, len(path))
+	for _, fieldName := range path {
+		for typ.Kind() == reflect.Pointer {
+			typ = typ.Elem()
+		}
+		if fieldName == indexWildcard || fieldName == keyWildcard {
+			segments = append(segments, fieldName)
+			typ = typ.Elem()
+			continue
+		}
+		field, _ := typ.FieldByName(fieldName)
+		segment := configSegmentOf(field, naming)
+		if segment == "-" {
+			return "", false
+		}
+		segments = append(segments, segment)
+		typ = field.Type
+	}
+	return strings.Join(segments, "."), true
+}
+
+func configSegmentOf(field reflect.StructField, naming ConfigNaming) string {
+	for _, tag := range configKeyTags {
+		value, tagged := field.Tag.Lookup(tag)
+		if !tagged {
+			continue
+		}
+		if segment, _, _ := strings.Cut(value, ","); segment != "" {
+			return segment
+		}
 	}
+	return naming(field)
 }
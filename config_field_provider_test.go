@@ -22,6 +22,30 @@ type NestedConfig struct {
 	MaxRetries int
 }
 
+type TaggedConfig struct {
+	DatabaseURL string `mapstructure:"database_url"`
+	Port        int    `godi:"listen_port" mapstructure:"port"`
+	Secret      string `godi:"-"`
+	Nested      *NestedConfig
+}
+
+type BrokerConfig struct {
+	Host string `mapstructure:"host"`
+	Port int
+}
+
+type CollectionsConfig struct {
+	Brokers []BrokerConfig `mapstructure:"brokers"`
+	Regions map[string]*BrokerConfig
+	Hosts   []string
+	Tree    []CollectionsConfig
+}
+
+type LinkedConfig struct {
+	Name string
+	Next *LinkedConfig
+}
+
 func TestConfigFieldProvider(t *testing.T) {
 	t.Run("it should list all buildable names from config struct with correct types", func(t *testing.T) {
 		// GIVEN
@@ -164,4 +188,183 @@ func TestConfigFieldProvider(t *testing.T) {
 		// Verify it's the same slice (cached)
 		assert.Same(t, &names1[0], &names2[0])
 	})
+
+	t.Run("it should only register config fields when the conditions are met", func(t *testing.T) {
+		// GIVEN
+		resolver := New()
+		resolver.MustRegister(ToStaticProvider("onprem"), Named("DEPLOYMENT"))
+		resolver.MustRegister(func() *TestConfig {
+			return &TestConfig{DatabaseURL: "postgres://localhost"}
+		})
+
+		// WHEN
+		resolver.MustRegister(&ConfigFieldProvider[TestConfig]{}, When("DEPLOYMENT").Equals("cloud"))
+
+		// THEN
+		_, found, err := TryResolveNamed[string](resolver, "TestConfig.DatabaseURL")
+		require.NoError(t, err)
+		assert.False(t, found)
+	})
+
+	t.Run("it should name the fields after their tags", func(t *testing.T) {
+		// GIVEN
+		resolver := New()
+		resolver.MustRegister(func() *TaggedConfig {
+			return &TaggedConfig{DatabaseURL: "postgres://localhost", Port: 8080, Secret: "s3cr3t"}
+		})
+
+		// WHEN
+		resolver.MustRegister(&ConfigFieldProvider[TaggedConfig]{})
+
+		// THEN
+		assert.Equal(t, "postgres://localhost", MustResolveNamed[string](resolver, "TaggedConfig.database_url"))
+		assert.Equal(t, 8080, MustResolveNamed[int](resolver, "TaggedConfig.listen_port"))
+		_, found, err := TryResolveNamed[string](resolver, "TaggedConfig.Secret")
+		require.NoError(t, err)
+		assert.False(t, found)
+	})
+
+	t.Run("it should name the fields with the configured prefix and naming strategy", func(t *testing.T) {
+		// GIVEN
+		resolver := New()
+		resolver.MustRegister(func() *TaggedConfig {
+			return &TaggedConfig{Nested: &NestedConfig{APIKey: "secret-key-123"}, Port: 8080}
+		})
+
+		// WHEN
+		resolver.MustRegister(NewConfigFieldProvider[TaggedConfig](
+			WithConfigPrefix("config"),
+			WithConfigNaming(LowerCaseNaming),
+		))
+
+		// THEN
+		assert.Equal(t, "secret-key-123", MustResolveNamed[string](resolver, "config.nested.apikey"))
+		assert.Equal(t, 8080, MustResolveNamed[int](resolver, "config.listen_port"))
+	})
+
+	t.Run("it should name the fields with their path only for an empty prefix", func(t *testing.T) {
+		// GIVEN
+		provider := NewConfigFieldProvider[TestConfig](WithConfigPrefix(""))
+		name := Name{name: "Nested.APIKey", typ: reflect.TypeOf("")}
+		testConfig := &TestConfig{Nested: &NestedConfig{APIKey: "secret-key-123"}}
+
+		// WHEN
+		canProvide := provider.CanProvide(name)
+		require.True(t, canProvide)
+		val, err := provider.Provide(name, []reflect.Value{reflect.ValueOf(testConfig)})
+
+		// THEN
+		require.NoError(t, err)
+		assert.Equal(t, "secret-key-123", val.Interface())
+	})
+
+	t.Run("it should build the fields of the elements of slices", func(t *testing.T) {
+		// GIVEN
+		provider := &ConfigFieldProvider[CollectionsConfig]{}
+		name := Name{name: "CollectionsConfig.brokers.1.host", typ: reflect.TypeOf("")}
+		testConfig := &CollectionsConfig{Brokers: []BrokerConfig{{Host: "kafka-0"}, {Host: "kafka-1"}}}
+
+		// WHEN
+		canProvide := provider.CanProvide(name)
+		require.True(t, canProvide)
+		val, err := provider.Provide(name, []reflect.Value{reflect.ValueOf(testConfig)})
+
+		// THEN
+		require.NoError(t, err)
+		assert.Equal(t, "kafka-1", val.Interface())
+	})
+
+	t.Run("it should build the elements of maps and slices", func(t *testing.T) {
+		// GIVEN
+		provider := &ConfigFieldProvider[CollectionsConfig]{}
+		testConfig := &CollectionsConfig{
+			Regions: map[string]*BrokerConfig{"eu": {Host: "eu.kafka", Port: 9092}},
+			Hosts:   []string{"a", "b"},
+		}
+
+		// WHEN
+		region, regionErr := provider.Provide(Name{name: "CollectionsConfig.Regions.eu", typ: reflect.TypeOf(&BrokerConfig{})}, []reflect.Value{reflect.ValueOf(testConfig)})
+		port, portErr := provider.Provide(Name{name: "CollectionsConfig.Regions.eu.Port", typ: reflect.TypeOf(0)}, []reflect.Value{reflect.ValueOf(testConfig)})
+		host, hostErr := provider.Provide(Name{name: "CollectionsConfig.Hosts.1", typ: reflect.TypeOf("")}, []reflect.Value{reflect.ValueOf(testConfig)})
+
+		// THEN
+		require.NoError(t, regionErr)
+		assert.Equal(t, "eu.kafka", region.Interface().(*BrokerConfig).Host)
+		require.NoError(t, portErr)
+		assert.Equal(t, 9092, port.Interface())
+		require.NoError(t, hostErr)
+		assert.Equal(t, "b", host.Interface())
+	})
+
+	t.Run("it should list the whole collections but not their elements", func(t *testing.T) {
+		// GIVEN
+		provider := &ConfigFieldProvider[CollectionsConfig]{}
+
+		// WHEN
+		names := provider.ListProvidableNames()
+
+		// THEN
+		var listed []string
+		for _, n := range names {
+			listed = append(listed, n.name)
+		}
+		assert.ElementsMatch(t, []string{
+			"CollectionsConfig.brokers",
+			"CollectionsConfig.Regions",
+			"CollectionsConfig.Hosts",
+			"CollectionsConfig.Tree",
+		}, listed)
+	})
+
+	t.Run("it should not provide the elements with an invalid index or type", func(t *testing.T) {
+		// GIVEN
+		provider := &ConfigFieldProvider[CollectionsConfig]{}
+
+		// WHEN / THEN
+		assert.False(t, provider.CanProvide(Name{name: "CollectionsConfig.brokers.first.host", typ: reflect.TypeOf("")}))
+		assert.False(t, provider.CanProvide(Name{name: "CollectionsConfig.brokers.0.host", typ: reflect.TypeOf(0)}))
+		assert.True(t, provider.CanProvide(Name{name: "CollectionsConfig.Tree.0", typ: reflect.TypeOf(CollectionsConfig{})}))
+		assert.False(t, provider.CanProvide(Name{name: "CollectionsConfig.Tree.0.Hosts.0", typ: reflect.TypeOf("")}), "self-referencing elements are not walked")
+	})
+
+	t.Run("it should fail to build the elements missing from the config", func(t *testing.T) {
+		// GIVEN
+		provider := &ConfigFieldProvider[CollectionsConfig]{}
+		testConfig := &CollectionsConfig{}
+
+		// WHEN
+		_, err := provider.Provide(Name{name: "CollectionsConfig.brokers.0.host", typ: reflect.TypeOf("")}, []reflect.Value{reflect.ValueOf(testConfig)})
+
+		// THEN
+		assert.ErrorContains(t, err, "index 0 out of range")
+	})
+
+	t.Run("it should resolve the fields of the elements of slices", func(t *testing.T) {
+		// GIVEN
+		resolver := New()
+		resolver.MustRegister(ToStaticProvider(&CollectionsConfig{Brokers: []BrokerConfig{{Host: "kafka-0", Port: 9092}}}))
+		resolver.MustRegister(&ConfigFieldProvider[CollectionsConfig]{})
+
+		// WHEN
+		port, err := ResolveNamed[int](resolver, "CollectionsConfig.brokers.0.Port")
+
+		// THEN
+		require.NoError(t, err)
+		assert.Equal(t, 9092, port)
+	})
+
+	t.Run("it should list the fields of self-referencing configs once", func(t *testing.T) {
+		// GIVEN
+		provider := &ConfigFieldProvider[LinkedConfig]{}
+
+		// WHEN
+		names := provider.ListProvidableNames()
+
+		// THEN
+		var listed []string
+		for _, n := range names {
+			listed = append(listed, n.name)
+		}
+		assert.ElementsMatch(t, []string{"LinkedConfig.Name", "LinkedConfig.Next"}, listed)
+	})
 }
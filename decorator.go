@@ -1,8 +1,16 @@
 package godi
 
-import "reflect"
+import (
+	"fmt"
+	"reflect"
+	"slices"
+	"sort"
+	"strings"
+)
 
 type (
+	// Decorator decorates the component named by ForName, or all the components implementing its type
+	// if the name is empty, see DecorateType.
 	Decorator interface {
 		ForName() Name
 		Decorate(toDecorate reflect.Value, dependencies []reflect.Value) (comp reflect.Value, err error)
@@ -10,4 +18,140 @@ type (
 		Priority() int
 		Description() string
 	}
+
+	// conditionalDecorator is a decorator applied only if its conditions hold when it decorates a component,
+	// see WhenProvided.
+	conditionalDecorator struct {
+		Decorator
+		conditions []Condition
+	}
 )
+
+// applies checks if the decorator is to be applied, i.e. if it has no conditions, or if they hold.
+func (r *Resolver) applies(decorator Decorator) (bool, error) {
+	conditional, ok := decorator.(*conditionalDecorator)
+	if !ok {
+		return true, nil
+	}
+	holds, err := allCondition(conditional.conditions).Evaluate(r)
+	if err != nil {
+		return false, fmt.Errorf("failed to evaluate the conditions of decorator %s:\n\t%w", decorator, err)
+	}
+	return holds, nil
+}
+
+func (c *conditionalDecorator) String() string {
+	if stringer, ok := c.Decorator.(fmt.Stringer); ok {
+		return stringer.String()
+	}
+	return fmt.Sprintf("%T", c.Decorator)
+}
+
+// DecoratorsFor returns the decorators of the components with the given name, in the order they are applied,
+// i.e. from the lowest to the highest priority. The decorators by type (see DecorateType) are applied afterward.
+func (r *Resolver) DecoratorsFor(name string) []Decorator {
+	var decorators []Decorator
+	r.decorators.Range(func(forName, chain any) bool {
+		if forName.(Name).name == name {
+			decorators = append(decorators, chain.(*SortedCOWSlice[Decorator]).All()...)
+		}
+		return true
+	})
+	slices.SortStableFunc(decorators, func(d1, d2 Decorator) int {
+		return int(compareByPriority(d1, d2))
+	})
+	return decorators
+}
+
+// describeDecorators describes the decorator chains, one per decorated name, in the order they are applied.
+func (r *Resolver) describeDecorators(b *strings.Builder) {
+	chains := make(map[string][]Decorator)
+	r.decorators.Range(func(forName, chain any) bool {
+		key := fmt.Sprintf("by type %s", forName.(Name).typ)
+		if name := forName.(Name); name.name != "" {
+			key = name.String()
+		}
+		chains[key] = append(chains[key], chain.(*SortedCOWSlice[Decorator]).All()...)
+		return true
+	})
+	keys := make([]string, 0, len(chains))
+	for key := range chains {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	b.WriteString("* Decorators:\n")
+	for _, key := range keys {
+		b.WriteString(fmt.Sprintf("\t- %s\n", key))
+		for idx, d := range chains[key] {
+			b.WriteString(fmt.Sprintf("\t\t%d. %s (priority=%d)\n", idx+1, d, d.Priority()))
+			if desc := d.Description(); desc != "" {
+				b.WriteString(fmt.Sprintf("\t\t\tdescription: %s\n", desc))
+			}
+			if deps := d.Dependencies(); len(deps) > 0 {
+				b.WriteString("\t\t\tdependencies:\n")
+				for _, dep := range deps {
+					b.WriteString(fmt.Sprintf("\t\t\t\t- %s\n", dep))
+				}
+			}
+		}
+	}
+}
+
+// typeDecorators returns the decorators by type, from the lowest to the highest priority.
+func (r *Resolver) typeDecorators() []Decorator {
+	var decorators []Decorator
+	r.decorators.Range(func(name, forName any) bool {
+		if name.(Name).name == "" {
+			decorators = append(decorators, forName.(*SortedCOWSlice[Decorator]).All()...)
+		}
+		return true
+	})
+	slices.SortStableFunc(decorators, func(d1, d2 Decorator) int {
+		return int(compareByPriority(d1, d2))
+	})
+	return decorators
+}
+
+// decorateByType applies the decorators by type to the component, if it implements their type,
+// the hidden components (see Hidden) are not decorated.
+func (r *Resolver) decorateByType(p Provider, name Name, comp reflect.Value, tracker *Tracker) (reflect.Value, error) {
+	if attributesOf(p).hidden {
+		return comp, nil
+	}
+	for _, decorator := range r.typeDecorators() {
+		concrete := comp
+		if concrete.Kind() == reflect.Interface {
+			concrete = concrete.Elem()
+		}
+		if !concrete.IsValid() || !concrete.Type().AssignableTo(decorator.ForName().typ) {
+			continue
+		}
+		applies, err := r.applies(decorator)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		if !applies {
+			continue
+		}
+
+		dependencies, err := r.resolveDependencies(decorator.Dependencies(), tracker)
+		if err != nil {
+			return reflect.Value{}, fmt.Errorf("failed to resolve dependencies for decorator %s:\n\t%w", decorator, err)
+		}
+		decorated, err := decorator.Decorate(concrete, dependencies)
+		if err != nil {
+			return reflect.Value{}, fmt.Errorf("failed to apply decorator %s to component %s:\n\t%w", decorator, name, err)
+		}
+		if decorated.Kind() == reflect.Interface {
+			decorated = decorated.Elem()
+		}
+		if !decorated.IsValid() || !decorated.Type().AssignableTo(name.typ) {
+			r.logger.Warn("decoration skipped, the decorated component is not assignable to the provided type", "decorator", decorator, "component", name)
+			continue
+		}
+		r.recordDecoration(name, decorator)
+		comp = decorated
+	}
+	return comp, nil
+}
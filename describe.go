@@ -0,0 +1,193 @@
+package godi
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+
+	"gopkg.in/yaml.v3"
+)
+
+// DescriptionFormat is a format of the description of a resolver, see Resolver.DescribeAs.
+type DescriptionFormat string
+
+const (
+	// DescriptionText is the human-readable format of Resolver.Describe.
+	DescriptionText DescriptionFormat = "text"
+	// DescriptionJSON is the JSON encoding of ContainerDescription.
+	DescriptionJSON DescriptionFormat = "json"
+	// DescriptionYAML is the YAML encoding of ContainerDescription.
+	DescriptionYAML DescriptionFormat = "yaml"
+)
+
+type (
+	// ContainerDescription is the machine-readable description of a resolver, e.g. for ops tooling,
+	// see Resolver.Description. Its schema is stable, fields might be added but are never renamed nor removed.
+	// The types are qualified by their package path.
+	ContainerDescription struct {
+		Providers  []ProviderDescription  `json:"providers" yaml:"providers"`
+		Decorators []DecoratorDescription `json:"decorators" yaml:"decorators"`
+		// Components are the components instantiated so far, in their instantiation order, see Snapshot.
+		Components []NameDescription `json:"components" yaml:"components"`
+	}
+
+	// ProviderDescription describes a registered provider, see ProviderInfo.
+	ProviderDescription struct {
+		Source       string                  `json:"source" yaml:"source"`
+		Names        []NameDescription       `json:"names" yaml:"names"`
+		Priority     int                     `json:"priority" yaml:"priority"`
+		Version      string                  `json:"version,omitempty" yaml:"version,omitempty"`
+		Description  string                  `json:"description,omitempty" yaml:"description,omitempty"`
+		Lifetime     string                  `json:"lifetime" yaml:"lifetime"`
+		Eager        bool                    `json:"eager,omitempty" yaml:"eager,omitempty"`
+		Hidden       bool                    `json:"hidden,omitempty" yaml:"hidden,omitempty"`
+		Tags         []string                `json:"tags,omitempty" yaml:"tags,omitempty"`
+		ExposedAs    []string                `json:"exposedAs,omitempty" yaml:"exposedAs,omitempty"`
+		Dependencies []DependencyDescription `json:"dependencies,omitempty" yaml:"dependencies,omitempty"`
+		Conditions   []string                `json:"conditions,omitempty" yaml:"conditions,omitempty"`
+	}
+
+	// DecoratorDescription describes a registered decorator, in the order of the chain of the decorated components.
+	DecoratorDescription struct {
+		Source string `json:"source" yaml:"source"`
+		// Decorates is the name of the decorated components, its name is empty for the decorators by type.
+		Decorates    NameDescription         `json:"decorates" yaml:"decorates"`
+		Priority     int                     `json:"priority" yaml:"priority"`
+		Description  string                  `json:"description,omitempty" yaml:"description,omitempty"`
+		Dependencies []DependencyDescription `json:"dependencies,omitempty" yaml:"dependencies,omitempty"`
+	}
+
+	// NameDescription describes the Name of a component.
+	NameDescription struct {
+		Name    string `json:"name,omitempty" yaml:"name,omitempty"`
+		Type    string `json:"type" yaml:"type"`
+		Version string `json:"version,omitempty" yaml:"version,omitempty"`
+	}
+
+	// DependencyDescription describes a dependency, see DependencyInfo.
+	DependencyDescription struct {
+		Name     string `json:"name,omitempty" yaml:"name,omitempty"`
+		Type     string `json:"type" yaml:"type"`
+		Optional bool   `json:"optional,omitempty" yaml:"optional,omitempty"`
+		Multiple bool   `json:"multiple,omitempty" yaml:"multiple,omitempty"`
+	}
+)
+
+// Description returns the machine-readable description of the providers, decorators and instantiated components,
+// see DescribeAs to encode it.
+func (r *Resolver) Description() ContainerDescription {
+	description := ContainerDescription{
+		Providers:  []ProviderDescription{},
+		Decorators: r.decoratorDescriptions(),
+		Components: []NameDescription{},
+	}
+	for _, info := range r.Providers() {
+		description.Providers = append(description.Providers, providerDescriptionOf(info))
+	}
+	for _, entry := range r.Snapshot().Components {
+		description.Components = append(description.Components, NameDescription(entry))
+	}
+	return description
+}
+
+// DescribeAs describes the resolver in the given format, see ContainerDescription for the schema of the JSON and YAML
+// formats, and Describe for the text format.
+func (r *Resolver) DescribeAs(format DescriptionFormat) ([]byte, error) {
+	switch format {
+	case DescriptionText:
+		return []byte(r.Describe()), nil
+	case DescriptionJSON:
+		return json.MarshalIndent(r.Description(), "", "  ")
+	case DescriptionYAML:
+		return yaml.Marshal(r.Description())
+	default:
+		return nil, fmt.Errorf("unknown description format %q, expected one of %s, %s or %s", format, DescriptionText, DescriptionJSON, DescriptionYAML)
+	}
+}
+
+// DescribeJSON describes the resolver as JSON, see DescribeAs.
+func (r *Resolver) DescribeJSON() ([]byte, error) {
+	return r.DescribeAs(DescriptionJSON)
+}
+
+func (r *Resolver) decoratorDescriptions() []DecoratorDescription {
+	var forNames []Name
+	chains := make(map[Name][]Decorator)
+	r.decorators.Range(func(forName, chain any) bool {
+		forNames = append(forNames, forName.(Name))
+		chains[forName.(Name)] = chain.(*SortedCOWSlice[Decorator]).All()
+		return true
+	})
+	sort.Slice(forNames, func(i, j int) bool {
+		return nameDescriptionOf(forNames[i]).less(nameDescriptionOf(forNames[j]))
+	})
+
+	descriptions := []DecoratorDescription{}
+	for _, forName := range forNames {
+		for _, d := range chains[forName] {
+			description := DecoratorDescription{
+				Source:      fmt.Sprint(d),
+				Decorates:   nameDescriptionOf(forName),
+				Priority:    d.Priority(),
+				Description: d.Description(),
+			}
+			for _, request := range d.Dependencies() {
+				description.Dependencies = append(description.Dependencies, dependencyDescriptionOf(dependencyInfoOf(request)))
+			}
+			descriptions = append(descriptions, description)
+		}
+	}
+	return descriptions
+}
+
+func providerDescriptionOf(info ProviderInfo) ProviderDescription {
+	description := ProviderDescription{
+		Source:      info.Source,
+		Priority:    info.Priority,
+		Version:     info.Version,
+		Description: info.Description,
+		Lifetime:    info.Lifetime,
+		Eager:       info.Eager,
+		Hidden:      info.Hidden,
+		Tags:        info.Tags,
+		Conditions:  info.Conditions,
+	}
+	for _, n := range info.Names {
+		description.Names = append(description.Names, nameDescriptionOf(n))
+	}
+	for _, typ := range info.ExposedAs {
+		description.ExposedAs = append(description.ExposedAs, describedType(typ))
+	}
+	for _, dependency := range info.Dependencies {
+		description.Dependencies = append(description.Dependencies, dependencyDescriptionOf(dependency))
+	}
+	return description
+}
+
+func nameDescriptionOf(n Name) NameDescription {
+	return NameDescription{Name: n.name, Type: describedType(n.typ), Version: n.version}
+}
+
+func dependencyDescriptionOf(info DependencyInfo) DependencyDescription {
+	return DependencyDescription{
+		Name:     info.Name,
+		Type:     describedType(info.Type),
+		Optional: info.Optional,
+		Multiple: info.Multiple,
+	}
+}
+
+func describedType(typ reflect.Type) string {
+	if typ == nil {
+		return ""
+	}
+	return qualifiedTypeName(typ)
+}
+
+func (n NameDescription) less(other NameDescription) bool {
+	if n.Name != other.Name {
+		return n.Name < other.Name
+	}
+	return n.Type < other.Type
+}
@@ -0,0 +1,113 @@
+package godi
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+func newDescribedResolver() *Resolver {
+	resolver := New()
+	resolver.MustRegister(func() *TestRepository { return &TestRepository{} }, Named("repository"), Priority(5))
+	resolver.MustRegister(
+		func(repository *TestRepository) *TestService { return &TestService{} },
+		Named("service"),
+		Description("the service"),
+		Dependencies(Inject.Named("repository")),
+		Tagged("services"),
+	)
+	resolver.MustRegister(
+		func(toDecorate *TestService) *TestService { return toDecorate },
+		Decorate("service"),
+	)
+	MustResolveNamed[*TestService](resolver, "service")
+	return resolver
+}
+
+func TestResolver_Description(t *testing.T) {
+	t.Run("it should describe the providers, decorators and instantiated components", func(t *testing.T) {
+		// GIVEN
+		resolver := newDescribedResolver()
+
+		// WHEN
+		description := resolver.Description()
+
+		// THEN
+		var service ProviderDescription
+		for _, p := range description.Providers {
+			if p.Names[0].Name == "service" {
+				service = p
+			}
+		}
+		assert.Equal(t, []NameDescription{{Name: "service", Type: "*github.com/a-peyrard/godi.TestService"}}, service.Names)
+		assert.Equal(t, "the service", service.Description)
+		assert.Equal(t, LifetimeSingleton, service.Lifetime)
+		assert.Equal(t, []string{"services"}, service.Tags)
+		assert.Equal(t, []DependencyDescription{{Name: "repository", Type: "*github.com/a-peyrard/godi.TestRepository"}}, service.Dependencies)
+		require.Len(t, description.Decorators, 1)
+		assert.Equal(t, NameDescription{Name: "service", Type: "*github.com/a-peyrard/godi.TestService"}, description.Decorators[0].Decorates)
+		assert.Equal(t, []NameDescription{
+			{Name: "repository", Type: "*github.com/a-peyrard/godi.TestRepository"},
+			{Name: "service", Type: "*github.com/a-peyrard/godi.TestService"},
+		}, description.Components)
+	})
+}
+
+func TestResolver_DescribeAs(t *testing.T) {
+	t.Run("it should describe the resolver as JSON", func(t *testing.T) {
+		// GIVEN
+		resolver := newDescribedResolver()
+
+		// WHEN
+		encoded, err := resolver.DescribeJSON()
+
+		// THEN
+		require.NoError(t, err)
+		var decoded ContainerDescription
+		require.NoError(t, json.Unmarshal(encoded, &decoded))
+		assert.Equal(t, resolver.Description(), decoded)
+		assert.Contains(t, string(encoded), `"lifetime": "singleton"`)
+	})
+
+	t.Run("it should describe the resolver as YAML", func(t *testing.T) {
+		// GIVEN
+		resolver := newDescribedResolver()
+
+		// WHEN
+		encoded, err := resolver.DescribeAs(DescriptionYAML)
+
+		// THEN
+		require.NoError(t, err)
+		var decoded ContainerDescription
+		require.NoError(t, yaml.Unmarshal(encoded, &decoded))
+		assert.Equal(t, resolver.Description(), decoded)
+		assert.Contains(t, string(encoded), "lifetime: singleton")
+	})
+
+	t.Run("it should describe the resolver as text", func(t *testing.T) {
+		// GIVEN
+		resolver := newDescribedResolver()
+
+		// WHEN
+		encoded, err := resolver.DescribeAs(DescriptionText)
+
+		// THEN
+		require.NoError(t, err)
+		assert.Equal(t, resolver.Describe(), string(encoded))
+	})
+
+	t.Run("it should fail with an unknown format", func(t *testing.T) {
+		// GIVEN
+		resolver := New()
+
+		// WHEN
+		_, err := resolver.DescribeAs("xml")
+
+		// THEN
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), `unknown description format "xml"`)
+	})
+}
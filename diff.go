@@ -0,0 +1,138 @@
+package godi
+
+import (
+	"fmt"
+	"strings"
+)
+
+type (
+	// ContainerDiff lists the providers added, removed and changed between two resolvers, see Diff.
+	ContainerDiff struct {
+		Added   []ProviderDescription `json:"added" yaml:"added"`
+		Removed []ProviderDescription `json:"removed" yaml:"removed"`
+		Changed []ProviderChange      `json:"changed" yaml:"changed"`
+	}
+
+	// ProviderChange is a provider of the same names in both resolvers, whose registration changed.
+	ProviderChange struct {
+		Before ProviderDescription `json:"before" yaml:"before"`
+		After  ProviderDescription `json:"after" yaml:"after"`
+		// Fields are the changed fields, e.g. priority or dependencies, as named in the JSON schema.
+		Fields []string `json:"fields" yaml:"fields"`
+	}
+)
+
+// Diff reports the providers added, removed and changed (priority, lifetime, conditions, dependencies...) from the
+// resolver a to the resolver b, e.g. to review the drift of the configuration between two releases or environments.
+// See DiffDescriptions to compare the descriptions dumped by DescribeJSON.
+func Diff(a, b *Resolver) ContainerDiff {
+	return DiffDescriptions(a.Description(), b.Description())
+}
+
+// DiffDescriptions reports the providers added, removed and changed from the description a to the description b.
+//
+// The providers are matched by their names and version, several providers of the same names being matched in order
+// of precedence. Their source is not compared, as it moves along with the code.
+func DiffDescriptions(a, b ContainerDescription) ContainerDiff {
+	diff := ContainerDiff{Added: []ProviderDescription{}, Removed: []ProviderDescription{}, Changed: []ProviderChange{}}
+
+	// the positions of the providers of b by key, the matched ones being consumed
+	positions := make(map[string][]int)
+	for i, p := range b.Providers {
+		positions[p.key()] = append(positions[p.key()], i)
+	}
+	matched := make([]bool, len(b.Providers))
+	for _, before := range a.Providers {
+		key := before.key()
+		if len(positions[key]) == 0 {
+			diff.Removed = append(diff.Removed, before)
+			continue
+		}
+		position := positions[key][0]
+		positions[key] = positions[key][1:]
+		matched[position] = true
+		if fields := changedFields(before, b.Providers[position]); len(fields) > 0 {
+			diff.Changed = append(diff.Changed, ProviderChange{Before: before, After: b.Providers[position], Fields: fields})
+		}
+	}
+	for i, p := range b.Providers {
+		if !matched[i] {
+			diff.Added = append(diff.Added, p)
+		}
+	}
+	return diff
+}
+
+// IsEmpty returns true if the resolvers have the same providers.
+func (d ContainerDiff) IsEmpty() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0 && len(d.Changed) == 0
+}
+
+func (d ContainerDiff) String() string {
+	var b strings.Builder
+	for _, p := range d.Added {
+		b.WriteString(fmt.Sprintf("+ %s: %s\n", p.describedNames(), p.Source))
+	}
+	for _, p := range d.Removed {
+		b.WriteString(fmt.Sprintf("- %s: %s\n", p.describedNames(), p.Source))
+	}
+	for _, c := range d.Changed {
+		b.WriteString(fmt.Sprintf("~ %s: %s\n", c.After.describedNames(), c.After.Source))
+		for _, field := range c.Fields {
+			b.WriteString(fmt.Sprintf("\t%s: %v -> %v\n", field, c.Before.field(field), c.After.field(field)))
+		}
+	}
+	return b.String()
+}
+
+// comparedFields are the fields of the providers compared by DiffDescriptions, in order.
+var comparedFields = []string{"priority", "description", "lifetime", "eager", "hidden", "tags", "exposedAs", "dependencies", "conditions"}
+
+func changedFields(before, after ProviderDescription) []string {
+	var fields []string
+	for _, field := range comparedFields {
+		// compared as printed, so the missing lists of a JSON dump equal the empty ones
+		if fmt.Sprint(before.field(field)) != fmt.Sprint(after.field(field)) {
+			fields = append(fields, field)
+		}
+	}
+	return fields
+}
+
+// field returns the value of the field of the provider, see comparedFields.
+func (p ProviderDescription) field(name string) any {
+	switch name {
+	case "priority":
+		return p.Priority
+	case "description":
+		return p.Description
+	case "lifetime":
+		return p.Lifetime
+	case "eager":
+		return p.Eager
+	case "hidden":
+		return p.Hidden
+	case "tags":
+		return p.Tags
+	case "exposedAs":
+		return p.ExposedAs
+	case "dependencies":
+		return p.Dependencies
+	case "conditions":
+		return p.Conditions
+	}
+	return nil
+}
+
+// key identifies the provider among the providers of a resolver, along with its precedence.
+func (p ProviderDescription) key() string {
+	return p.describedNames() + "@" + p.Version
+}
+
+func (p ProviderDescription) describedNames() string {
+	names := make([]string, len(p.Names))
+	for i, n := range p.Names {
+		names[i] = fmt.Sprintf("(%s, %s)", n.Name, n.Type)
+	}
+	return strings.Join(names, ", ")
+}
@@ -0,0 +1,86 @@
+package godi
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiff(t *testing.T) {
+	t.Run("it should report the providers added, removed and changed", func(t *testing.T) {
+		// GIVEN
+		before := New()
+		before.MustRegister(func() *TestRepository { return &TestRepository{} }, Named("repository"), Priority(5))
+		before.MustRegister(func() string { return "old" }, Named("legacy"))
+		before.MustRegister(func(repository *TestRepository) *TestService { return &TestService{} },
+			Named("service"),
+			Dependencies(Inject.Named("repository")),
+		)
+
+		after := New()
+		after.MustRegister(func() *TestRepository { return &TestRepository{} }, Named("repository"), Priority(10))
+		after.MustRegister(func() int { return 42 }, Named("answer"))
+		after.MustRegister(func() *TestService { return &TestService{} }, Named("service"))
+
+		// WHEN
+		diff := Diff(before, after)
+
+		// THEN
+		require.Len(t, diff.Added, 1)
+		assert.Equal(t, "answer", diff.Added[0].Names[0].Name)
+		require.Len(t, diff.Removed, 1)
+		assert.Equal(t, "legacy", diff.Removed[0].Names[0].Name)
+		require.Len(t, diff.Changed, 2)
+		assert.Equal(t, "repository", diff.Changed[0].After.Names[0].Name)
+		assert.Equal(t, []string{"priority"}, diff.Changed[0].Fields)
+		assert.Equal(t, "service", diff.Changed[1].After.Names[0].Name)
+		assert.Equal(t, []string{"dependencies"}, diff.Changed[1].Fields)
+		assert.Contains(t, diff.String(), "\tpriority: 5 -> 10\n")
+	})
+
+	t.Run("it should report nothing for the same registrations", func(t *testing.T) {
+		// WHEN
+		diff := Diff(newDescribedResolver(), newDescribedResolver())
+
+		// THEN
+		assert.True(t, diff.IsEmpty())
+		assert.Empty(t, diff.String())
+	})
+
+	t.Run("it should match the providers of the same names in order of precedence", func(t *testing.T) {
+		// GIVEN
+		before := New()
+		before.MustRegister(func() string { return "a" }, Named("value"), Priority(1))
+		before.MustRegister(func() string { return "b" }, Named("value"), Priority(2))
+		after := New()
+		after.MustRegister(func() string { return "b" }, Named("value"), Priority(2))
+
+		// WHEN
+		diff := Diff(before, after)
+
+		// THEN
+		assert.Empty(t, diff.Added)
+		require.Len(t, diff.Removed, 1)
+		assert.Equal(t, 1, diff.Removed[0].Priority)
+		assert.Empty(t, diff.Changed)
+	})
+}
+
+func TestDiffDescriptions(t *testing.T) {
+	t.Run("it should compare the descriptions dumped as JSON", func(t *testing.T) {
+		// GIVEN
+		resolver := newDescribedResolver()
+		encoded, err := resolver.DescribeJSON()
+		require.NoError(t, err)
+		var decoded ContainerDescription
+		require.NoError(t, json.Unmarshal(encoded, &decoded))
+
+		// WHEN
+		diff := DiffDescriptions(decoded, resolver.Description())
+
+		// THEN
+		assert.True(t, diff.IsEmpty())
+	})
+}
@@ -0,0 +1,173 @@
+package godi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/a-peyrard/godi/fn"
+)
+
+type (
+	// DryRunReport is the outcome of a dry run of the resolver, see Resolver.DryRun.
+	DryRunReport struct {
+		// Steps are the constructions and decorations of the components, in the order they would happen.
+		Steps []DryRunStep
+		// Failures are the components which could not be resolved, e.g. because of a missing dependency or a cycle.
+		Failures []error
+	}
+
+	// DryRunStep is the construction of a component by its provider, or the application of a decorator to it.
+	DryRunStep struct {
+		Name Name
+		// Provider is the provider building the component, empty if the step applies a decorator.
+		Provider string
+		// Decorator is the decorator applied to the component, empty if the step builds it.
+		Decorator string
+	}
+
+	// dryRunRecorder records the steps of a dry run, the dependencies might be resolved concurrently.
+	dryRunRecorder struct {
+		mu    sync.Mutex
+		steps []DryRunStep
+	}
+
+	// stubProvider replaces a provider during a dry run, providing zero values instead of calling it.
+	stubProvider struct {
+		Provider
+		recorder *dryRunRecorder
+	}
+
+	// stubDecorator replaces a decorator during a dry run, leaving the components untouched instead of calling it.
+	stubDecorator struct {
+		Decorator
+	}
+)
+
+// DryRun resolves all the components of the resolver, with all the providers replaced by stubs providing zero values,
+// and all the decorators by stubs returning the components untouched, so nothing is built, opened nor closed.
+//
+// It reports the order the components would be built and decorated in, along with the components failing to be
+// resolved, e.g. so a deployment pipeline validates the wiring against the production environment and configuration
+// (the registration conditions being evaluated at registration). The conditions of the decorators are evaluated
+// against the stubs. The resolver itself is left untouched.
+func (r *Resolver) DryRun() DryRunReport {
+	recorder := &dryRunRecorder{}
+	dry := &Resolver{resolverState: &resolverState{
+		providers: NewSortedCOWSlice[Provider](fn.ReverseComparator(compareProviders)),
+		store:     NewStore(),
+		lock:      NewLockManager(),
+		logger:    r.logger,
+		dryRun:    recorder,
+	}}
+	for _, p := range r.providers.All() {
+		dry.providers.Add(stubProviderOf(p, recorder))
+	}
+	r.decorators.Range(func(forName, chain any) bool {
+		stubs := NewSortedCOWSlice[Decorator](compareByPriority)
+		for _, d := range chain.(*SortedCOWSlice[Decorator]).All() {
+			stubs.Add(stubDecoratorOf(d))
+		}
+		dry.decorators.Store(forName, stubs)
+		return true
+	})
+
+	// the components are resolved within a scope, so the scoped components can be resolved too
+	scope := dry.NewScope()
+	scope.dryRun = recorder
+
+	var failures []error
+	for _, p := range dry.providers.All() {
+		for _, n := range p.ListProvidableNames() {
+			_, _, err := scope.resolve(Request{
+				unitaryTyp: n.typ,
+				query:      queryByVersion{name: n, version: attributesOf(p).version},
+				validator:  validatorUniqueMandatory{},
+				collector:  collectorUnique{},
+			})
+			if err != nil {
+				failures = append(failures, fmt.Errorf("failed to resolve component %s:\n\t%w", n, err))
+			}
+		}
+	}
+	return DryRunReport{Steps: recorder.steps, Failures: failures}
+}
+
+// Err returns the failures of the dry run joined, nil if all the components were resolved.
+func (d DryRunReport) Err() error {
+	return errors.Join(d.Failures...)
+}
+
+func (d DryRunReport) String() string {
+	var b strings.Builder
+	for i, step := range d.Steps {
+		if step.Decorator != "" {
+			b.WriteString(fmt.Sprintf("%d. decorate %s with %s\n", i+1, step.Name, step.Decorator))
+		} else {
+			b.WriteString(fmt.Sprintf("%d. build %s with %s\n", i+1, step.Name, step.Provider))
+		}
+	}
+	for _, err := range d.Failures {
+		b.WriteString(fmt.Sprintf("! %s\n", err))
+	}
+	return b.String()
+}
+
+// recordDecoration records the application of the decorator to the component, during a dry run.
+func (r *Resolver) recordDecoration(name Name, decorator Decorator) {
+	if r.dryRun != nil {
+		r.dryRun.record(DryRunStep{Name: name, Decorator: fmt.Sprint(decorator)})
+	}
+}
+
+func (d *dryRunRecorder) record(step DryRunStep) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.steps = append(d.steps, step)
+}
+
+// stubProviderOf replaces the provider by a stub, keeping its attributes but its hooks, so the zero values are
+// neither initialized nor closed.
+func stubProviderOf(p Provider, recorder *dryRunRecorder) Provider {
+	attributes := attributesOf(p)
+	attributes.onInit = func(context.Context, reflect.Value) error { return nil }
+	attributes.onClose = func(context.Context, reflect.Value) error { return nil }
+	return withAttributes(&stubProvider{Provider: unwrapped(p), recorder: recorder}, attributes)
+}
+
+func (s *stubProvider) Provide(name Name, _ []reflect.Value) (reflect.Value, error) {
+	s.recorder.record(DryRunStep{Name: name, Provider: describeProvider(s.Provider)})
+	return reflect.Zero(name.typ), nil
+}
+
+// Dependencies returns the dependencies of the provider, the ones of its factory method for the providers of
+// several results, as the factory method is not to be called.
+func (s *stubProvider) Dependencies() []Request {
+	if multi, ok := s.Provider.(*multiResultProvider); ok {
+		return multi.call.dependencies
+	}
+	return s.Provider.Dependencies()
+}
+
+func (s *stubProvider) String() string {
+	return describeProvider(s.Provider)
+}
+
+// stubDecoratorOf replaces the decorator by a stub, keeping its conditions.
+func stubDecoratorOf(d Decorator) Decorator {
+	if conditional, ok := d.(*conditionalDecorator); ok {
+		return &conditionalDecorator{Decorator: &stubDecorator{Decorator: conditional.Decorator}, conditions: conditional.conditions}
+	}
+	return &stubDecorator{Decorator: d}
+}
+
+func (s *stubDecorator) Decorate(toDecorate reflect.Value, _ []reflect.Value) (reflect.Value, error) {
+	return toDecorate, nil
+}
+
+func (s *stubDecorator) String() string {
+	return fmt.Sprint(s.Decorator)
+}
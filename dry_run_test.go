@@ -0,0 +1,106 @@
+package godi
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolver_DryRun(t *testing.T) {
+	t.Run("it should report the construction order without calling the providers", func(t *testing.T) {
+		// GIVEN
+		resolver := New()
+		called := false
+		resolver.MustRegister(func() *TestRepository { called = true; return &TestRepository{} }, Named("repository"))
+		resolver.MustRegister(func(repository *TestRepository) *TestService { called = true; return &TestService{} },
+			Named("service"),
+			Dependencies(Inject.Named("repository")),
+		)
+
+		// WHEN
+		report := resolver.DryRun()
+
+		// THEN
+		require.NoError(t, report.Err())
+		assert.False(t, called)
+		var built []string
+		for _, step := range report.Steps {
+			if step.Name.Name() == "repository" || step.Name.Name() == "service" {
+				built = append(built, step.Name.Name())
+			}
+		}
+		assert.Equal(t, []string{"repository", "service"}, built)
+		assert.Empty(t, resolver.Snapshot().Components)
+	})
+
+	t.Run("it should report the decorators applied without calling them", func(t *testing.T) {
+		// GIVEN
+		resolver := New()
+		decorated := false
+		resolver.MustRegister(func() *TestService { return &TestService{} }, Named("service"))
+		resolver.MustRegister(func(toDecorate *TestService) *TestService { decorated = true; return toDecorate }, Decorate("service"))
+
+		// WHEN
+		report := resolver.DryRun()
+
+		// THEN
+		require.NoError(t, report.Err())
+		assert.False(t, decorated)
+		var steps []DryRunStep
+		for _, step := range report.Steps {
+			if step.Name.Name() == "service" {
+				steps = append(steps, step)
+			}
+		}
+		require.Len(t, steps, 2)
+		assert.NotEmpty(t, steps[0].Provider)
+		assert.NotEmpty(t, steps[1].Decorator)
+		assert.Contains(t, report.String(), "decorate (service, *godi.TestService) with ")
+	})
+
+	t.Run("it should report the missing dependencies", func(t *testing.T) {
+		// GIVEN
+		resolver := New()
+		resolver.MustRegister(func(repository *TestRepository) *TestService { return &TestService{} },
+			Named("service"),
+			Dependencies(Inject.Named("repository")),
+		)
+
+		// WHEN
+		report := resolver.DryRun()
+
+		// THEN
+		require.Len(t, report.Failures, 1)
+		assert.ErrorContains(t, report.Err(), "repository")
+	})
+
+	t.Run("it should not call the factory method of several components", func(t *testing.T) {
+		// GIVEN
+		resolver := New()
+		called := false
+		resolver.MustRegister(func() (*TestRepository, *TestService) {
+			called = true
+			return &TestRepository{}, &TestService{}
+		})
+
+		// WHEN
+		report := resolver.DryRun()
+
+		// THEN
+		require.NoError(t, report.Err())
+		assert.False(t, called)
+	})
+
+	t.Run("it should resolve the scoped components", func(t *testing.T) {
+		// GIVEN
+		resolver := New()
+		resolver.MustRegister(func() *TestService { return &TestService{} }, Named("service"), Scoped())
+
+		// WHEN
+		report := resolver.DryRun()
+
+		// THEN
+		assert.NoError(t, report.Err())
+	})
+}
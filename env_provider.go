@@ -1,31 +1,73 @@
 package godi
 
 import (
+	"fmt"
 	"os"
 	"reflect"
+	"strconv"
 	"strings"
 	"sync"
+	"time"
+
+	"github.com/a-peyrard/godi/option"
+)
+
+// EnvSnapshotName is the name of the environment snapshot component, see WithEnvSnapshot.
+const EnvSnapshotName = "godi.env"
+
+type (
+	// EnvProvider is a provider that provides environment variables as components.
+	//
+	// The variables are provided as strings, or converted to the requested type, see parseEnvValue
+	// for the supported types. Defaults can be configured for the missing variables, see WithEnvDefault.
+	EnvProvider struct {
+		once     sync.Once
+		names    []Name
+		defaults map[string]string
+	}
+
+	// EnvOptions configures the EnvProvider.
+	EnvOptions struct {
+		defaults map[string]string
+	}
 )
 
-// EnvProvider is a provider that provides environment variables as components.
-type EnvProvider struct {
-	once  sync.Once
-	names []Name
+// NewEnvProvider creates a provider for the environment variables.
+func NewEnvProvider(opts ...option.Option[EnvOptions]) *EnvProvider {
+	options := option.Build(&EnvOptions{defaults: make(map[string]string)}, opts...)
+	return &EnvProvider{defaults: options.defaults}
 }
 
-func (e *EnvProvider) CanProvide(name Name) bool {
-	if name.typ == StringType && name.name != "" {
-		_, found := os.LookupEnv(name.name)
-		if found {
-			return true
-		}
+// WithEnvDefault provides the given raw value if the environment variable is not set.
+func WithEnvDefault(name string, value string) option.Option[EnvOptions] {
+	return func(opts *EnvOptions) {
+		opts.defaults[name] = value
 	}
+}
 
-	return false
+func (e *EnvProvider) CanProvide(name Name) bool {
+	if name.name == "" || !isEnvConvertible(name.typ) {
+		return false
+	}
+	_, found := e.lookup(name.name)
+	return found
 }
 
 func (e *EnvProvider) Provide(name Name, _ []reflect.Value) (comp reflect.Value, err error) {
-	return reflect.ValueOf(os.Getenv(name.name)), nil
+	raw, _ := e.lookup(name.name)
+	comp, err = parseEnvValue(raw, name.typ)
+	if err != nil {
+		return reflect.Zero(name.typ), fmt.Errorf("unable to convert env var %s:\n\t%w", name.name, err)
+	}
+	return comp, nil
+}
+
+func (e *EnvProvider) lookup(name string) (string, bool) {
+	if raw, found := os.LookupEnv(name); found {
+		return raw, true
+	}
+	raw, found := e.defaults[name]
+	return raw, found
 }
 
 func (e *EnvProvider) Dependencies() []Request {
@@ -52,16 +94,167 @@ func (e *EnvProvider) ListBuildableNames() []Name {
 
 func (e *EnvProvider) loadNames() {
 	props := os.Environ()
-	e.names = make([]Name, len(props))
-	for i, prop := range props {
+	e.names = make([]Name, 0, len(props)+len(e.defaults))
+	for _, prop := range props {
 		tokens := strings.SplitN(prop, "=", 2)
-		e.names[i] = Name{
+		e.names = append(e.names, Name{
 			name: tokens[0],
 			typ:  StringType,
+		})
+	}
+	for name := range e.defaults {
+		if _, found := os.LookupEnv(name); !found {
+			e.names = append(e.names, Name{
+				name: name,
+				typ:  StringType,
+			})
 		}
 	}
 }
 
 func (e *EnvProvider) Description() string {
-	return "Provides environment variables as string components"
+	if len(e.defaults) == 0 {
+		return "Provides environment variables as components, converted to the requested types"
+	}
+	return fmt.Sprintf(
+		"Provides environment variables as components, converted to the requested types, with %d defaults",
+		len(e.defaults),
+	)
+}
+
+// EnvBindingProvider is a provider binding a single environment variable to a typed component.
+//
+// The component is named after the variable (without the prefix), the raw value is converted to T,
+// see parseEnvValue for the supported types.
+type EnvBindingProvider[T any] struct {
+	Name   string
+	Prefix string
+}
+
+func (e *EnvBindingProvider[T]) CanProvide(name Name) bool {
+	if name.name != e.Name || !matchType(name.typ, TypeOf[T]()) {
+		return false
+	}
+	_, found := os.LookupEnv(e.Prefix + e.Name)
+	return found
+}
+
+func (e *EnvBindingProvider[T]) Provide(name Name, _ []reflect.Value) (comp reflect.Value, err error) {
+	comp, err = parseEnvValue(os.Getenv(e.Prefix+e.Name), TypeOf[T]())
+	if err != nil {
+		return reflect.Zero(name.typ), fmt.Errorf("unable to convert env var %s%s:\n\t%w", e.Prefix, e.Name, err)
+	}
+	return comp, nil
+}
+
+func (e *EnvBindingProvider[T]) Dependencies() []Request {
+	return nil
+}
+
+func (e *EnvBindingProvider[T]) ListProvidableNames() []Name {
+	if _, found := os.LookupEnv(e.Prefix + e.Name); !found {
+		return nil
+	}
+	return []Name{{name: e.Name, typ: TypeOf[T]()}}
+}
+
+func (e *EnvBindingProvider[T]) Priority() int {
+	return 0
+}
+
+func (e *EnvBindingProvider[T]) Description() string {
+	return fmt.Sprintf("Binds environment variable %s%s as %s", e.Prefix, e.Name, TypeOf[T]())
+}
+
+func (e *EnvBindingProvider[T]) String() string {
+	return fmt.Sprintf("EnvBindingProvider(%s%s, %s)", e.Prefix, e.Name, TypeOf[T]())
+}
+
+// isEnvConvertible checks if the raw value of an environment variable can be converted to the given type.
+func isEnvConvertible(typ reflect.Type) bool {
+	if typ == durationType {
+		return true
+	}
+	switch typ.Kind() {
+	case reflect.String, reflect.Bool,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	case reflect.Slice:
+		return typ.Elem().Kind() != reflect.Slice && isEnvConvertible(typ.Elem())
+	}
+	return false
+}
+
+// parseEnvValue converts the raw value of an environment variable to the given type,
+// slices are read from comma separated values.
+func parseEnvValue(raw string, typ reflect.Type) (reflect.Value, error) {
+	val := reflect.New(typ).Elem()
+	if typ == durationType {
+		d, err := time.ParseDuration(raw)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		val.SetInt(int64(d))
+		return val, nil
+	}
+
+	switch typ.Kind() {
+	case reflect.String:
+		val.SetString(raw)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(raw)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		val.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, err := strconv.ParseInt(raw, 10, typ.Bits())
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		val.SetInt(i)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		u, err := strconv.ParseUint(raw, 10, typ.Bits())
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		val.SetUint(u)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(raw, typ.Bits())
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		val.SetFloat(f)
+	case reflect.Slice:
+		if strings.TrimSpace(raw) == "" {
+			return reflect.MakeSlice(typ, 0, 0), nil
+		}
+		items := strings.Split(raw, ",")
+		val = reflect.MakeSlice(typ, 0, len(items))
+		for _, item := range items {
+			elem, err := parseEnvValue(strings.TrimSpace(item), typ.Elem())
+			if err != nil {
+				return reflect.Value{}, err
+			}
+			val = reflect.Append(val, elem)
+		}
+	default:
+		return reflect.Value{}, fmt.Errorf("unsupported type %s for env value", typ)
+	}
+	return val, nil
+}
+
+// snapshotEnv returns a copy of the current environment variables.
+func snapshotEnv() map[string]string {
+	props := os.Environ()
+	env := make(map[string]string, len(props))
+	for _, prop := range props {
+		tokens := strings.SplitN(prop, "=", 2)
+		if len(tokens) == 2 {
+			env[tokens[0]] = tokens[1]
+		}
+	}
+	return env
 }
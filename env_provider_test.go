@@ -0,0 +1,183 @@
+package godi
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEnvProvider(t *testing.T) {
+	t.Run("it should provide the env var converted to the requested type", func(t *testing.T) {
+		// GIVEN
+		t.Setenv("GODI_PORT", "8080")
+		t.Setenv("GODI_DEBUG", "true")
+		t.Setenv("GODI_TIMEOUT", "2s")
+		t.Setenv("GODI_HOSTS", "a.local, b.local")
+		resolver := New()
+		resolver.MustRegister(&EnvProvider{})
+
+		// WHEN
+		port, err1 := ResolveNamed[int](resolver, "GODI_PORT")
+		debug, err2 := ResolveNamed[bool](resolver, "GODI_DEBUG")
+		timeout, err3 := ResolveNamed[time.Duration](resolver, "GODI_TIMEOUT")
+		hosts, err4 := ResolveNamed[[]string](resolver, "GODI_HOSTS")
+
+		// THEN
+		require.NoError(t, errors.Join(err1, err2, err3, err4))
+		assert.Equal(t, 8080, port)
+		assert.True(t, debug)
+		assert.Equal(t, 2*time.Second, timeout)
+		assert.Equal(t, []string{"a.local", "b.local"}, hosts)
+	})
+
+	t.Run("it should provide the default of a missing env var", func(t *testing.T) {
+		// GIVEN
+		t.Setenv("GODI_SET_PORT", "9090")
+		resolver := New()
+		resolver.MustRegister(NewEnvProvider(
+			WithEnvDefault("GODI_SURELY_NOT_SET_PORT", "8080"),
+			WithEnvDefault("GODI_SET_PORT", "8080"),
+		))
+
+		// WHEN
+		missing, err1 := ResolveNamed[int](resolver, "GODI_SURELY_NOT_SET_PORT")
+		set, err2 := ResolveNamed[int](resolver, "GODI_SET_PORT")
+
+		// THEN
+		require.NoError(t, errors.Join(err1, err2))
+		assert.Equal(t, 8080, missing)
+		assert.Equal(t, 9090, set)
+	})
+
+	t.Run("it should not provide missing env vars without default", func(t *testing.T) {
+		// GIVEN
+		resolver := New()
+		resolver.MustRegister(NewEnvProvider())
+
+		// WHEN
+		_, found, err := TryResolveNamed[int](resolver, "GODI_SURELY_NOT_SET_PORT")
+
+		// THEN
+		require.NoError(t, err)
+		assert.False(t, found)
+	})
+
+	t.Run("it should fail if the env var can not be converted", func(t *testing.T) {
+		// GIVEN
+		t.Setenv("GODI_PORT", "not-a-number")
+		resolver := New()
+		resolver.MustRegister(&EnvProvider{})
+
+		// WHEN
+		_, err := ResolveNamed[int](resolver, "GODI_PORT")
+
+		// THEN
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "unable to convert env var GODI_PORT")
+	})
+}
+
+func TestEnvBindingProvider(t *testing.T) {
+	t.Run("it should provide the env var converted to the requested type", func(t *testing.T) {
+		// GIVEN
+		t.Setenv("APP_MAX_WORKERS", "12")
+		resolver := New()
+		resolver.MustRegister(&EnvBindingProvider[int]{Name: "MAX_WORKERS", Prefix: "APP_"})
+
+		// WHEN
+		workers, err := ResolveNamed[int](resolver, "MAX_WORKERS")
+
+		// THEN
+		require.NoError(t, err)
+		assert.Equal(t, 12, workers)
+	})
+
+	t.Run("it should support durations", func(t *testing.T) {
+		// GIVEN
+		t.Setenv("TIMEOUT", "1m30s")
+		resolver := New()
+		resolver.MustRegister(&EnvBindingProvider[time.Duration]{Name: "TIMEOUT"})
+
+		// WHEN
+		timeout, err := ResolveNamed[time.Duration](resolver, "TIMEOUT")
+
+		// THEN
+		require.NoError(t, err)
+		assert.Equal(t, 90*time.Second, timeout)
+	})
+
+	t.Run("it should not provide anything if the env var is not set", func(t *testing.T) {
+		// GIVEN
+		resolver := New()
+		resolver.MustRegister(&EnvBindingProvider[bool]{Name: "GODI_SURELY_NOT_SET"})
+
+		// WHEN
+		_, found, err := TryResolveNamed[bool](resolver, "GODI_SURELY_NOT_SET")
+
+		// THEN
+		require.NoError(t, err)
+		assert.False(t, found)
+	})
+
+	t.Run("it should fail if the env var can not be converted", func(t *testing.T) {
+		// GIVEN
+		t.Setenv("PORT", "not-a-number")
+		resolver := New()
+		resolver.MustRegister(&EnvBindingProvider[int]{Name: "PORT"})
+
+		// WHEN
+		_, err := ResolveNamed[int](resolver, "PORT")
+
+		// THEN
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "unable to convert env var PORT")
+	})
+}
+
+func TestResolver_WithEnvSnapshot(t *testing.T) {
+	t.Run("it should provide a snapshot of the env taken at startup", func(t *testing.T) {
+		// GIVEN
+		t.Setenv("GODI_SNAPSHOT_TEST", "before")
+		resolver := New(WithEnvSnapshot())
+		t.Setenv("GODI_SNAPSHOT_TEST", "after")
+
+		// WHEN
+		env, err := ResolveNamed[map[string]string](resolver, EnvSnapshotName)
+
+		// THEN
+		require.NoError(t, err)
+		assert.Equal(t, "before", env["GODI_SNAPSHOT_TEST"])
+	})
+
+	t.Run("it should allow to override the snapshot", func(t *testing.T) {
+		// GIVEN
+		resolver := New(WithEnvSnapshot())
+		resolver.MustRegister(
+			ToStaticProvider(map[string]string{"FOO": "bar"}),
+			Named(EnvSnapshotName),
+			Priority(10),
+		)
+
+		// WHEN
+		env, err := ResolveNamed[map[string]string](resolver, EnvSnapshotName)
+
+		// THEN
+		require.NoError(t, err)
+		assert.Equal(t, map[string]string{"FOO": "bar"}, env)
+	})
+
+	t.Run("it should not provide the snapshot by default", func(t *testing.T) {
+		// GIVEN
+		resolver := New()
+
+		// WHEN
+		_, found, err := TryResolveNamed[map[string]string](resolver, EnvSnapshotName)
+
+		// THEN
+		require.NoError(t, err)
+		assert.False(t, found)
+	})
+}
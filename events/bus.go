@@ -0,0 +1,166 @@
+package events
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"sync"
+
+	"github.com/a-peyrard/godi"
+	"github.com/a-peyrard/godi/option"
+)
+
+type (
+	// Handler handles events of type E.
+	//
+	// Handlers are contributed by registering them in the resolver, the bus collects all the
+	// handlers registered for a given event type the first time an event of this type is published.
+	Handler[E any] interface {
+		Handle(ctx context.Context, event E) error
+	}
+
+	// HandlerFunc is a helper to create Handler from a function.
+	HandlerFunc[E any] func(ctx context.Context, event E) error
+
+	// Bus dispatches events to the handlers registered in the resolver.
+	Bus struct {
+		resolver *godi.Resolver
+		options  *BusOptions
+
+		handlers sync.Map // reflect.Type -> []any
+		pending  sync.WaitGroup
+	}
+
+	BusOptions struct {
+		async   bool
+		onError func(err error)
+	}
+
+	busContextKey struct{}
+)
+
+// BusName is the name used to register the bus in the resolver.
+const BusName = "godi.events.bus"
+
+func (f HandlerFunc[E]) Handle(ctx context.Context, event E) error {
+	return f(ctx, event)
+}
+
+// WithAsync dispatches the events in background goroutines, Publish returns without waiting for the handlers.
+func WithAsync() option.Option[BusOptions] {
+	return func(opts *BusOptions) {
+		opts.async = true
+	}
+}
+
+// WithErrorHandler sets the function called with the errors of asynchronous handlers, which can not be
+// returned to the publisher. Default is to ignore them.
+func WithErrorHandler(onError func(err error)) option.Option[BusOptions] {
+	return func(opts *BusOptions) {
+		opts.onError = onError
+	}
+}
+
+// NewBus creates a bus dispatching events to the handlers registered in the resolver.
+func NewBus(resolver *godi.Resolver, opts ...option.Option[BusOptions]) *Bus {
+	return &Bus{
+		resolver: resolver,
+		options: option.Build(
+			&BusOptions{
+				onError: func(error) {},
+			},
+			opts...,
+		),
+	}
+}
+
+// Register registers a bus in the resolver, under the name BusName.
+func Register(resolver *godi.Resolver, opts ...option.Option[BusOptions]) error {
+	return resolver.Register(
+		func(resolver *godi.Resolver) *Bus {
+			return NewBus(resolver, opts...)
+		},
+		godi.Named(BusName),
+		godi.Description("in-process event bus"),
+	)
+}
+
+// ContextWithBus returns a copy of the context carrying the bus, to be used by Publish.
+func ContextWithBus(ctx context.Context, bus *Bus) context.Context {
+	return context.WithValue(ctx, busContextKey{}, bus)
+}
+
+// FromContext returns the bus carried by the context, if any.
+func FromContext(ctx context.Context) (*Bus, bool) {
+	bus, ok := ctx.Value(busContextKey{}).(*Bus)
+	return bus, ok
+}
+
+// Publish dispatches the event using the bus carried by the context (see ContextWithBus).
+func Publish[E any](ctx context.Context, event E) error {
+	bus, ok := FromContext(ctx)
+	if !ok {
+		return fmt.Errorf("no event bus found in the context to publish %T", event)
+	}
+	return PublishTo(ctx, bus, event)
+}
+
+// PublishTo dispatches the event to all the handlers of E registered in the resolver of the bus.
+//
+// In synchronous mode, all the handlers are called, and their errors are joined.
+// In asynchronous mode, errors are reported to the error handler of the bus.
+func PublishTo[E any](ctx context.Context, bus *Bus, event E) error {
+	handlers, err := handlersOf[E](bus)
+	if err != nil {
+		return err
+	}
+
+	if !bus.options.async {
+		return dispatch(ctx, handlers, event)
+	}
+
+	bus.pending.Add(1)
+	go func() {
+		defer bus.pending.Done()
+		if err := dispatch(context.WithoutCancel(ctx), handlers, event); err != nil {
+			bus.options.onError(err)
+		}
+	}()
+	return nil
+}
+
+// Wait blocks until all the asynchronous dispatches are done.
+func (b *Bus) Wait() {
+	b.pending.Wait()
+}
+
+// Close waits for the asynchronous dispatches, so the bus can be closed by the resolver.
+func (b *Bus) Close() error {
+	b.Wait()
+	return nil
+}
+
+func handlersOf[E any](bus *Bus) ([]Handler[E], error) {
+	typ := reflect.TypeOf((*E)(nil)).Elem()
+	if cached, ok := bus.handlers.Load(typ); ok {
+		return cached.([]Handler[E]), nil
+	}
+
+	handlers, err := godi.ResolveAll[Handler[E]](bus.resolver)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve handlers for event %s:\n\t%w", typ, err)
+	}
+	actual, _ := bus.handlers.LoadOrStore(typ, handlers)
+	return actual.([]Handler[E]), nil
+}
+
+func dispatch[E any](ctx context.Context, handlers []Handler[E], event E) error {
+	var errs []error
+	for _, handler := range handlers {
+		if err := handler.Handle(ctx, event); err != nil {
+			errs = append(errs, fmt.Errorf("handler %T failed to handle event %T:\n\t%w", handler, event, err))
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,103 @@
+package events
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/a-peyrard/godi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type (
+	userCreated struct {
+		Name string
+	}
+
+	recordingHandler struct {
+		mu     sync.Mutex
+		events []userCreated
+	}
+)
+
+func (h *recordingHandler) Handle(_ context.Context, event userCreated) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.events = append(h.events, event)
+	return nil
+}
+
+func TestPublish(t *testing.T) {
+	t.Run("it should dispatch the event to all the registered handlers", func(t *testing.T) {
+		// GIVEN
+		resolver := godi.New()
+		handler := &recordingHandler{}
+		resolver.MustRegister(godi.ToStaticProvider(handler), godi.Named("handler.recording"))
+		var called bool
+		resolver.MustRegister(
+			godi.ToStaticProvider[Handler[userCreated]](HandlerFunc[userCreated](func(context.Context, userCreated) error {
+				called = true
+				return nil
+			})),
+			godi.Named("handler.func"),
+		)
+		require.NoError(t, Register(resolver))
+		bus := godi.MustResolveNamed[*Bus](resolver, BusName)
+		ctx := ContextWithBus(context.Background(), bus)
+
+		// WHEN
+		err := Publish(ctx, userCreated{Name: "john"})
+
+		// THEN
+		require.NoError(t, err)
+		assert.Equal(t, []userCreated{{Name: "john"}}, handler.events)
+		assert.True(t, called)
+	})
+
+	t.Run("it should join the errors of the handlers", func(t *testing.T) {
+		// GIVEN
+		resolver := godi.New()
+		resolver.MustRegister(
+			godi.ToStaticProvider[Handler[userCreated]](HandlerFunc[userCreated](func(context.Context, userCreated) error {
+				return errors.New("boom")
+			})),
+		)
+		bus := NewBus(resolver)
+
+		// WHEN
+		err := PublishTo(context.Background(), bus, userCreated{Name: "john"})
+
+		// THEN
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "boom")
+	})
+
+	t.Run("it should dispatch asynchronously", func(t *testing.T) {
+		// GIVEN
+		resolver := godi.New()
+		handler := &recordingHandler{}
+		resolver.MustRegister(godi.ToStaticProvider(handler))
+		var reported error
+		bus := NewBus(resolver, WithAsync(), WithErrorHandler(func(err error) { reported = err }))
+
+		// WHEN
+		err := PublishTo(context.Background(), bus, userCreated{Name: "jane"})
+		bus.Wait()
+
+		// THEN
+		require.NoError(t, err)
+		require.NoError(t, reported)
+		assert.Equal(t, []userCreated{{Name: "jane"}}, handler.events)
+	})
+
+	t.Run("it should fail if no bus is in the context", func(t *testing.T) {
+		// WHEN
+		err := Publish(context.Background(), userCreated{Name: "john"})
+
+		// THEN
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "no event bus found")
+	})
+}
@@ -31,7 +31,7 @@ func NewFactoryMethodDecorator(
 		},
 		opts...,
 	)
-	if options.decorate == nil {
+	if options.decorate == nil && options.decorateType == nil {
 		return nil, errors.New("no decorate option provided")
 	}
 
@@ -54,6 +54,10 @@ func NewFactoryMethodDecorator(
 		return nil, errors.New("the first parameter of the factory method must be the same type as the return type. Or the return type must implement the first parameter type")
 	}
 
+	if options.decorateType != nil && t.In(0) != options.decorateType {
+		return nil, fmt.Errorf("the first parameter of the factory method must be the decorated type %s", options.decorateType)
+	}
+
 	fnName := runtime.FuncForPC(reflect.ValueOf(factoryMethod).Pointer()).Name()
 
 	var (
@@ -74,10 +78,7 @@ func NewFactoryMethodDecorator(
 	}
 
 	return &FactoryMethodDecorator{
-		name: Name{
-			name: *options.decorate,
-			typ:  decorates,
-		},
+		name:         decoratedName(options, decorates),
 		factory:      reflect.ValueOf(factoryMethod),
 		dependencies: paramQueries,
 		priority:     options.priority,
@@ -85,6 +86,14 @@ func NewFactoryMethodDecorator(
 	}, nil
 }
 
+// decoratedName is the name of the decorated component, or a name without name to decorate by type.
+func decoratedName(options *RegistrableOptions, decorates reflect.Type) Name {
+	if options.decorate == nil {
+		return Name{typ: decorates}
+	}
+	return Name{name: *options.decorate, typ: decorates}
+}
+
 func (f *FactoryMethodDecorator) ForName() Name {
 	return f.name
 }
@@ -329,3 +329,127 @@ func TestFactoryMethodDecorator(t *testing.T) {
 		assert.Contains(t, err.Error(), "no decorate option provided")
 	})
 }
+
+func TestResolver_DecorateType(t *testing.T) {
+	withCache := func(service DatabaseService) DatabaseService {
+		return &CachingDatabaseService{wrapped: service, cache: map[string]string{"SELECT 1": "1"}}
+	}
+
+	t.Run("it should decorate all the components implementing the type", func(t *testing.T) {
+		// GIVEN
+		resolver := New()
+		resolver.MustRegister(func() DatabaseService { return &SimpleDatabaseService{URL: "primary"} }, Named("db.primary"))
+		resolver.MustRegister(func() DatabaseService { return &SimpleDatabaseService{URL: "replica"} }, Named("db.replica"))
+		resolver.MustRegister(withCache, DecorateType[DatabaseService]())
+
+		// WHEN
+		services, err := ResolveAll[DatabaseService](resolver)
+
+		// THEN
+		require.NoError(t, err)
+		require.Len(t, services, 2)
+		for _, service := range services {
+			result, err := service.Query("SELECT 1")
+			require.NoError(t, err)
+			assert.Equal(t, "cached: 1", result)
+		}
+	})
+
+	t.Run("it should not decorate the components not implementing the type", func(t *testing.T) {
+		// GIVEN
+		resolver := New()
+		resolver.MustRegister(func() *TestLogger { return &TestLogger{Level: "INFO"} })
+		resolver.MustRegister(withCache, DecorateType[DatabaseService]())
+
+		// WHEN
+		logger, err := Resolve[*TestLogger](resolver)
+
+		// THEN
+		require.NoError(t, err)
+		assert.Equal(t, "INFO", logger.Level)
+	})
+
+	t.Run("it should skip the decoration if it can not replace the component", func(t *testing.T) {
+		// GIVEN
+		resolver := New()
+		resolver.MustRegister(func() *SimpleDatabaseService { return &SimpleDatabaseService{URL: "primary"} })
+		resolver.MustRegister(withCache, DecorateType[DatabaseService]())
+
+		// WHEN
+		service, err := Resolve[*SimpleDatabaseService](resolver)
+
+		// THEN
+		require.NoError(t, err)
+		assert.Equal(t, "primary", service.URL)
+	})
+
+	t.Run("it should apply the decorators by type after the named ones, by priority", func(t *testing.T) {
+		// GIVEN
+		var applied []string
+		resolver := New()
+		resolver.MustRegister(func() DatabaseService { return &SimpleDatabaseService{} }, Named("db"))
+		resolver.MustRegister(func(service DatabaseService) DatabaseService {
+			applied = append(applied, "by type, priority 10")
+			return service
+		}, DecorateType[DatabaseService](), Priority(10))
+		resolver.MustRegister(func(service DatabaseService) DatabaseService {
+			applied = append(applied, "by type, priority 1")
+			return service
+		}, DecorateType[DatabaseService](), Priority(1))
+		resolver.MustRegister(func(service DatabaseService) DatabaseService {
+			applied = append(applied, "by name")
+			return service
+		}, Decorate("db"))
+
+		// WHEN
+		_, err := ResolveNamed[DatabaseService](resolver, "db")
+
+		// THEN
+		require.NoError(t, err)
+		assert.Equal(t, []string{"by name", "by type, priority 1", "by type, priority 10"}, applied)
+	})
+
+	t.Run("it should fail if the first parameter is not the decorated type", func(t *testing.T) {
+		// WHEN
+		err := New().Register(func(service *SimpleDatabaseService) *SimpleDatabaseService { return service }, DecorateType[DatabaseService]())
+
+		// THEN
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "must be the decorated type")
+	})
+}
+
+func TestResolver_DecoratorsFor(t *testing.T) {
+	t.Run("it should return the decorators of the name in the order they are applied", func(t *testing.T) {
+		// GIVEN
+		resolver := New()
+		resolver.MustRegister(AddCachingDecorator, Decorate("db"), Priority(10))
+		resolver.MustRegister(AddLoggingDecorator, Decorate("db"), Priority(1))
+		resolver.MustRegister(AddCachingDecorator, Decorate("other"))
+
+		// WHEN
+		decorators := resolver.DecoratorsFor("db")
+
+		// THEN
+		require.Len(t, decorators, 2)
+		assert.Equal(t, 1, decorators[0].Priority())
+		assert.Equal(t, 10, decorators[1].Priority())
+		assert.Empty(t, resolver.DecoratorsFor("unknown"))
+	})
+
+	t.Run("it should describe the decorator chains", func(t *testing.T) {
+		// GIVEN
+		resolver := New()
+		resolver.MustRegister(AddCachingDecorator, Decorate("db"), Priority(10))
+		resolver.MustRegister(AddLoggingDecorator, Decorate("db"), Priority(1))
+		resolver.MustRegister(AddCachingDecorator, DecorateType[DatabaseService]())
+
+		// WHEN
+		description := resolver.Describe()
+
+		// THEN
+		assert.Contains(t, description, "* Decorators:\n\t- (db, godi.DatabaseService)\n\t\t1. FactoryMethodDecorator((db, godi.DatabaseService), github.com/a-peyrard/godi.AddLoggingDecorator) (priority=1)\n\t\t\tdependencies:\n")
+		assert.Contains(t, description, "\t\t2. FactoryMethodDecorator((db, godi.DatabaseService), github.com/a-peyrard/godi.AddCachingDecorator) (priority=10)\n")
+		assert.Contains(t, description, "\t- by type godi.DatabaseService\n\t\t1. ")
+	})
+}
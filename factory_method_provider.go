@@ -14,8 +14,12 @@ type (
 	FactoryMethodProvider struct {
 		name         Name
 		factory      reflect.Value
+		invoker      *invoker
 		dependencies []Request
 
+		// variadic factory methods get their last parameter as a slice, see variadicDependency
+		variadic bool
+
 		priority int
 
 		description string
@@ -30,13 +34,11 @@ func NewFactoryMethodProvider(
 	if t.Kind() != reflect.Func {
 		return nil, fmt.Errorf("factory method must be a function")
 	}
-	if t.NumOut() != 1 && t.NumOut() != 2 {
+	if t.NumOut() == 0 {
 		return nil, errors.New("factory method must either return the instance and an error, or just the instance")
 	}
-	if t.NumOut() == 2 {
-		if t.Out(1) != ErrorType {
-			return nil, errors.New("if factory method returns two elements, it must return an error as the second element")
-		}
+	if t.NumOut() > 2 || (t.NumOut() == 2 && t.Out(1) != ErrorType) || isOutStruct(t.Out(0)) {
+		return newMultiResultProvider(factoryMethod, opts...)
 	}
 
 	fnName := runtime.FuncForPC(reflect.ValueOf(factoryMethod).Pointer()).Name()
@@ -48,21 +50,14 @@ func NewFactoryMethodProvider(
 		opts...,
 	)
 
-	var (
-		provides     = t.Out(0)
-		paramQueries = make([]Request, t.NumIn())
-		err          error
-	)
-	for i := 0; i < t.NumIn(); i++ {
-		paramTyp := t.In(i)
-		depDef, found := tryGetAt(options.dependencies, i)
-		if !found {
-			depDef = defaultDependencyBuilder()
-		}
-		paramQueries[i], err = depDef.build(paramTyp)
-		if err != nil {
-			return nil, fmt.Errorf("failed to build dependency for parameter %d of factory method %s:\n\t%w", i, fnName, err)
-		}
+	if options.invoker != nil && options.invoker.typ != t {
+		return nil, fmt.Errorf("the direct call of %s does not match the factory method %s", options.invoker.typ, t)
+	}
+
+	provides := t.Out(0)
+	paramQueries, err := factoryDependencies(t, options.dependencies, fnName)
+	if err != nil {
+		return nil, err
 	}
 
 	return &FactoryMethodProvider{
@@ -71,12 +66,45 @@ func NewFactoryMethodProvider(
 			typ:  provides,
 		},
 		factory:      reflect.ValueOf(factoryMethod),
+		invoker:      options.invoker,
 		dependencies: paramQueries,
+		variadic:     t.IsVariadic(),
 		priority:     options.priority,
 		description:  options.description,
 	}, nil
 }
 
+// factoryDependencies builds the requests of the parameters of the factory method.
+func factoryDependencies(t reflect.Type, dependencies []dependency, fnName string) ([]Request, error) {
+	paramQueries := make([]Request, t.NumIn())
+	for i := 0; i < t.NumIn(); i++ {
+		paramTyp := t.In(i)
+		depDef, found := tryGetAt(dependencies, i)
+		if !found {
+			depDef = defaultDependencyBuilder()
+		}
+		if t.IsVariadic() && i == t.NumIn()-1 {
+			depDef = variadicDependency(depDef)
+		}
+		var err error
+		paramQueries[i], err = depDef.build(paramTyp)
+		if err != nil {
+			return nil, fmt.Errorf("failed to build dependency for parameter %d of factory method %s:\n\t%w", i, fnName, err)
+		}
+	}
+	return paramQueries, nil
+}
+
+// variadicDependency returns the dependency of the variadic parameter of a factory method: all the components of
+// the element type are injected (see Inject.Multiple), unless another dependency is given than Inject.Auto(),
+// so option-pattern constructors, e.g. func NewServer(opts ...ServerOption), can be registered directly.
+func variadicDependency(depDef dependency) dependency {
+	if _, auto := depDef.(*autoDependencyBuilder); auto {
+		return Inject.Multiple()
+	}
+	return depDef
+}
+
 func (f *FactoryMethodProvider) CanProvide(name Name) bool {
 	return name.name == f.name.name && matchType(name.typ, f.name.typ)
 }
@@ -92,12 +120,23 @@ func (f *FactoryMethodProvider) Provide(_ Name, dependencies []reflect.Value) (c
 				callErr = fmt.Errorf("panic calling provider for %s: %v", f.name.String(), r)
 			}
 		}()
+		if f.invoker != nil {
+			comp, err = f.invoker.call(dependencies)
+			return
+		}
+		if f.variadic {
+			results = f.factory.CallSlice(dependencies)
+			return
+		}
 		results = f.factory.Call(dependencies)
 	}()
 
 	if callErr != nil {
 		return reflect.Value{}, callErr
 	}
+	if f.invoker != nil {
+		return comp, err
+	}
 
 	if len(results) == 2 && !results[1].IsNil() {
 		return reflect.Value{}, results[1].Interface().(error)
@@ -3,6 +3,7 @@ package godi
 import (
 	"errors"
 	"reflect"
+	"strings"
 	"testing"
 
 	"fmt"
@@ -133,8 +134,8 @@ func TestFactoryMethodProvider(t *testing.T) {
 
 	t.Run("it should reject factory methods with invalid return signature", func(t *testing.T) {
 		// GIVEN
-		invalidFactory := func() (int, string, error) {
-			return 42, "invalid", nil
+		invalidFactory := func() (error, int) {
+			return nil, 42
 		}
 
 		// WHEN
@@ -143,7 +144,7 @@ func TestFactoryMethodProvider(t *testing.T) {
 		// THEN
 		require.Error(t, err)
 		assert.Nil(t, provider)
-		assert.Contains(t, err.Error(), "factory method must either return the instance and an error")
+		assert.Contains(t, err.Error(), "if factory method returns an error, it must be its last result")
 	})
 
 	t.Run("it should correctly identify what it can provide", func(t *testing.T) {
@@ -263,4 +264,59 @@ func TestFactoryMethodProvider(t *testing.T) {
 		assert.Contains(t, err.Error(), "panic calling provider")
 		assert.Contains(t, err.Error(), "something went wrong")
 	})
+
+	t.Run("it should inject all the components of the element type of a variadic parameter", func(t *testing.T) {
+		// GIVEN
+		type serverOption func(*TestService)
+		resolver := New()
+		resolver.MustRegister(ToStaticProvider(serverOption(func(s *TestService) { s.Name += "tls," })), Named("tls"), Priority(10))
+		resolver.MustRegister(ToStaticProvider(serverOption(func(s *TestService) { s.Name += "gzip," })), Named("gzip"))
+		resolver.MustRegister(func(name string, opts ...serverOption) *TestService {
+			s := &TestService{Name: name + ":"}
+			for _, opt := range opts {
+				opt(s)
+			}
+			return s
+		}, Dependencies(Inject.Named("server.name")))
+		resolver.MustRegister(ToStaticProvider("api"), Named("server.name"))
+
+		// WHEN
+		service, err := Resolve[*TestService](resolver)
+
+		// THEN
+		require.NoError(t, err)
+		assert.Equal(t, "api:tls,gzip,", service.Name)
+	})
+
+	t.Run("it should call a variadic factory method without any component of the element type", func(t *testing.T) {
+		// GIVEN
+		resolver := New()
+		resolver.MustRegister(func(opts ...func(*TestService)) *TestService {
+			return &TestService{Name: fmt.Sprintf("%d options", len(opts))}
+		})
+
+		// WHEN
+		service, err := Resolve[*TestService](resolver)
+
+		// THEN
+		require.NoError(t, err)
+		assert.Equal(t, "0 options", service.Name)
+	})
+
+	t.Run("it should use the dependency given for a variadic parameter", func(t *testing.T) {
+		// GIVEN
+		resolver := New()
+		resolver.MustRegister(ToStaticProvider([]string{"a", "b"}), Named("tokens"))
+		resolver.MustRegister(ToStaticProvider("ignored"), Named("other"))
+		resolver.MustRegister(func(tokens ...string) *TestService {
+			return &TestService{Name: strings.Join(tokens, ",")}
+		}, Dependencies(Inject.Named("tokens")))
+
+		// WHEN
+		service, err := Resolve[*TestService](resolver)
+
+		// THEN
+		require.NoError(t, err)
+		assert.Equal(t, "a,b", service.Name)
+	})
 }
@@ -0,0 +1,109 @@
+package godi
+
+import (
+	"flag"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// FlagPrefix prefixes the names of the components provided by the FlagProvider.
+const FlagPrefix = "flag."
+
+// FlagProvider is a provider that provides command-line flags as components, named "flag.<name>".
+//
+// The flags are read when the components are resolved, i.e. after the flags are parsed. The values implementing
+// flag.Getter are provided as is when they match the requested type, otherwise their string representation is
+// converted to the requested type, see parseEnvValue for the supported types.
+type FlagProvider struct {
+	visitAll func(fn func(name string, value flag.Value))
+}
+
+// NewFlagProvider creates a provider for the flags of the given flag set, or of flag.CommandLine if nil.
+func NewFlagProvider(flags *flag.FlagSet) *FlagProvider {
+	if flags == nil {
+		flags = flag.CommandLine
+	}
+	return NewFlagProviderFunc(func(fn func(name string, value flag.Value)) {
+		flags.VisitAll(func(f *flag.Flag) {
+			fn(f.Name, f.Value)
+		})
+	})
+}
+
+// NewFlagProviderFunc creates a provider for the flags visited by the given function,
+// e.g. to provide the flags of a pflag flag set:
+//
+//	godi.NewFlagProviderFunc(func(fn func(string, flag.Value)) {
+//		pflags.VisitAll(func(f *pflag.Flag) { fn(f.Name, f.Value) })
+//	})
+func NewFlagProviderFunc(visitAll func(fn func(name string, value flag.Value))) *FlagProvider {
+	return &FlagProvider{visitAll: visitAll}
+}
+
+func (f *FlagProvider) CanProvide(name Name) bool {
+	if !strings.HasPrefix(name.name, FlagPrefix) {
+		return false
+	}
+	value, found := f.lookup(strings.TrimPrefix(name.name, FlagPrefix))
+	if !found {
+		return false
+	}
+	if getter, ok := value.(flag.Getter); ok && getter.Get() != nil && reflect.TypeOf(getter.Get()).AssignableTo(name.typ) {
+		return true
+	}
+	return isEnvConvertible(name.typ)
+}
+
+func (f *FlagProvider) Provide(name Name, _ []reflect.Value) (comp reflect.Value, err error) {
+	flagName := strings.TrimPrefix(name.name, FlagPrefix)
+	value, found := f.lookup(flagName)
+	if !found {
+		return reflect.Zero(name.typ), fmt.Errorf("flag %s is not defined", flagName)
+	}
+	if getter, ok := value.(flag.Getter); ok && getter.Get() != nil && reflect.TypeOf(getter.Get()).AssignableTo(name.typ) {
+		return reflect.ValueOf(getter.Get()), nil
+	}
+	comp, err = parseEnvValue(value.String(), name.typ)
+	if err != nil {
+		return reflect.Zero(name.typ), fmt.Errorf("unable to convert flag %s:\n\t%w", flagName, err)
+	}
+	return comp, nil
+}
+
+func (f *FlagProvider) Dependencies() []Request {
+	return nil
+}
+
+func (f *FlagProvider) ListProvidableNames() []Name {
+	var names []Name
+	f.visitAll(func(name string, value flag.Value) {
+		typ := StringType
+		if getter, ok := value.(flag.Getter); ok && getter.Get() != nil {
+			typ = reflect.TypeOf(getter.Get())
+		}
+		names = append(names, Name{name: FlagPrefix + name, typ: typ})
+	})
+	sort.Slice(names, func(i, j int) bool {
+		return names[i].name < names[j].name
+	})
+	return names
+}
+
+func (f *FlagProvider) Priority() int {
+	return 0
+}
+
+func (f *FlagProvider) Description() string {
+	return "Provides command-line flags as components"
+}
+
+func (f *FlagProvider) lookup(name string) (value flag.Value, found bool) {
+	f.visitAll(func(flagName string, flagValue flag.Value) {
+		if flagName == name {
+			value, found = flagValue, true
+		}
+	})
+	return value, found
+}
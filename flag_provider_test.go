@@ -0,0 +1,86 @@
+package godi
+
+import (
+	"flag"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFlagProvider(t *testing.T) {
+	newFlags := func(args ...string) *flag.FlagSet {
+		flags := flag.NewFlagSet("test", flag.ContinueOnError)
+		flags.Bool("verbose", false, "verbose output")
+		flags.Int("port", 8080, "listen port")
+		flags.Duration("timeout", time.Second, "timeout")
+		flags.String("hosts", "", "comma separated hosts")
+		require.NoError(t, flags.Parse(args))
+		return flags
+	}
+
+	t.Run("it should provide the flags by name", func(t *testing.T) {
+		// GIVEN
+		resolver := New()
+		resolver.MustRegister(NewFlagProvider(newFlags("-verbose", "-timeout", "5s")))
+
+		// WHEN
+		verbose, err1 := ResolveNamed[bool](resolver, "flag.verbose")
+		port, err2 := ResolveNamed[int](resolver, "flag.port")
+		timeout, err3 := ResolveNamed[time.Duration](resolver, "flag.timeout")
+
+		// THEN
+		require.NoError(t, err1)
+		require.NoError(t, err2)
+		require.NoError(t, err3)
+		assert.True(t, verbose)
+		assert.Equal(t, 8080, port)
+		assert.Equal(t, 5*time.Second, timeout)
+	})
+
+	t.Run("it should convert the flags to the requested type", func(t *testing.T) {
+		// GIVEN
+		resolver := New()
+		resolver.MustRegister(NewFlagProvider(newFlags("-hosts", "a.local,b.local")))
+
+		// WHEN
+		hosts, err1 := ResolveNamed[[]string](resolver, "flag.hosts")
+		port, err2 := ResolveNamed[string](resolver, "flag.port")
+
+		// THEN
+		require.NoError(t, err1)
+		require.NoError(t, err2)
+		assert.Equal(t, []string{"a.local", "b.local"}, hosts)
+		assert.Equal(t, "8080", port)
+	})
+
+	t.Run("it should not provide undefined flags", func(t *testing.T) {
+		// GIVEN
+		resolver := New()
+		resolver.MustRegister(NewFlagProvider(newFlags()))
+
+		// WHEN
+		_, found, err := TryResolveNamed[bool](resolver, "flag.debug")
+
+		// THEN
+		require.NoError(t, err)
+		assert.False(t, found)
+	})
+
+	t.Run("it should list the flags with their types", func(t *testing.T) {
+		// GIVEN
+		provider := NewFlagProvider(newFlags())
+
+		// WHEN
+		names := provider.ListProvidableNames()
+
+		// THEN
+		assert.Equal(t, []Name{
+			{name: "flag.hosts", typ: StringType},
+			{name: "flag.port", typ: TypeOf[int]()},
+			{name: "flag.timeout", typ: durationType},
+			{name: "flag.verbose", typ: TypeOf[bool]()},
+		}, names)
+	})
+}
@@ -0,0 +1,108 @@
+package godi
+
+import "reflect"
+
+// Fork creates an independent resolver inheriting all the providers and decorators of the resolver.
+//
+// Registrations in the fork do not affect the resolver, and override the inherited providers: an inherited provider
+// is dropped when all the components it provides are also provided by a provider registered in the fork: the named
+// components by a component with the same name, the unnamed ones by a component of a matching type.
+// The fork does not share any component with the resolver, everything is built again, so the overrides are injected
+// everywhere in the fork.
+func (r *Resolver) Fork() *Resolver {
+	fork := &Resolver{resolverState: &resolverState{
+		providers: r.providers.Copy(),
+		store:     r.store.empty(),
+
+		lock: NewLockManager(),
+
+		inherited: r.providers.All(),
+
+		ctx:           r.ctx,
+		parallelSlots: r.parallelSlots,
+		tracer:        r.tracer,
+		logger:        r.logger,
+	}}
+	r.copyDecoratorsTo(fork)
+	fork.interceptors.Store(r.interceptors.Load())
+
+	fork.MustRegister(ToStaticProvider(fork), Named("godi.resolver"), Hidden())
+
+	return fork
+}
+
+func (r *Resolver) copyDecoratorsTo(other *Resolver) {
+	r.decorators.Range(func(name, decorators any) bool {
+		other.decorators.Store(name, decorators.(*SortedCOWSlice[Decorator]).Copy())
+		return true
+	})
+}
+
+// shadowInherited drops the inherited providers overridden by the given provider.
+func (r *Resolver) shadowInherited(override Provider) {
+	r.providers.RemoveIf(func(p Provider) bool {
+		return r.isInherited(p) && coversAll(override, p)
+	})
+}
+
+func (r *Resolver) isInherited(p Provider) bool {
+	for _, inherited := range r.inherited {
+		if sameProvider(inherited, p) {
+			return true
+		}
+	}
+	return false
+}
+
+func sameProvider(p1, p2 Provider) bool {
+	return reflect.TypeOf(p1) == reflect.TypeOf(p2) && reflect.TypeOf(p1).Comparable() && p1 == p2
+}
+
+// coversAll checks if the override provides all the components of the provider. The named components are only
+// covered by a component with the same name, the unnamed ones by a component of a type which can be injected
+// in their place, e.g. overriding the string named APP_ENV does not drop the string named REGION.
+func coversAll(override Provider, p Provider) bool {
+	names := p.ListProvidableNames()
+	if len(names) == 0 {
+		return false
+	}
+	overrideNames := override.ListProvidableNames()
+	for _, n := range names {
+		covered := false
+		for _, o := range overrideNames {
+			if covers(override, o, p, n) {
+				covered = true
+				break
+			}
+		}
+		if !covered {
+			return false
+		}
+	}
+	return true
+}
+
+func covers(override Provider, o Name, p Provider, n Name) bool {
+	oNamed, nNamed := isNamed(override, o), isNamed(p, n)
+	switch {
+	case oNamed && nNamed:
+		return o.name == n.name && matchType(n.typ, o.typ)
+	case !oNamed && !nNamed:
+		return matchType(n.typ, o.typ)
+	default:
+		return false
+	}
+}
+
+// isNamed checks if the component is provided with an explicit name, the functions registered without Named are
+// named after the function, and the other providers might be named after the type of the component.
+func isNamed(p Provider, n Name) bool {
+	if attributesOf(p).named {
+		return true
+	}
+	switch unwrapped(p).(type) {
+	case *FactoryMethodProvider, *multiResultProvider:
+		return false
+	}
+	return n.name != n.typ.String()
+}
@@ -0,0 +1,107 @@
+package godi
+
+import (
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolver_Fork(t *testing.T) {
+	t.Run("it should inherit the providers of the resolver", func(t *testing.T) {
+		// GIVEN
+		resolver := New()
+		resolver.MustRegister(NewTestService)
+		resolver.MustRegister(NewTestRepository)
+		resolver.MustRegister(NewTestController)
+
+		// WHEN
+		fork := resolver.Fork()
+		controller, err := Resolve[*TestController](fork)
+
+		// THEN
+		require.NoError(t, err)
+		assert.NotNil(t, controller.Service)
+	})
+
+	t.Run("it should override a provider by type without affecting the resolver", func(t *testing.T) {
+		// GIVEN
+		resolver := New()
+		resolver.MustRegister(func() *TestRepository { return &TestRepository{Data: "real"} })
+		resolver.MustRegister(NewTestService)
+		resolver.MustRegister(NewTestController)
+		fork := resolver.Fork()
+
+		// WHEN
+		fork.MustRegister(func() *TestRepository { return &TestRepository{Data: "fake"} })
+		forked := MustResolve[*TestController](fork)
+		original := MustResolve[*TestController](resolver)
+
+		// THEN
+		assert.Equal(t, "fake", forked.Repo.Data)
+		assert.Equal(t, "real", original.Repo.Data)
+	})
+
+	t.Run("it should override a provider by name", func(t *testing.T) {
+		// GIVEN
+		resolver := New()
+		resolver.MustRegister(ToStaticProvider("prod"), Named("APP_ENV"))
+		fork := resolver.Fork()
+
+		// WHEN
+		fork.MustRegister(ToStaticProvider("test"), Named("APP_ENV"))
+		env, err := ResolveNamed[string](fork, "APP_ENV")
+
+		// THEN
+		require.NoError(t, err)
+		assert.Equal(t, "test", env)
+	})
+
+	t.Run("it should keep the providers of other names with the same type", func(t *testing.T) {
+		// GIVEN
+		resolver := New()
+		resolver.MustRegister(ToStaticProvider("prod"), Named("APP_ENV"))
+		resolver.MustRegister(ToStaticProvider("eu-west-1"), Named("REGION"))
+		fork := resolver.Fork()
+
+		// WHEN
+		fork.MustRegister(ToStaticProvider("test"), Named("APP_ENV"))
+		env, envErr := ResolveNamed[string](fork, "APP_ENV")
+		region, regionErr := ResolveNamed[string](fork, "REGION")
+
+		// THEN
+		require.NoError(t, envErr)
+		assert.Equal(t, "test", env)
+		require.NoError(t, regionErr)
+		assert.Equal(t, "eu-west-1", region)
+	})
+
+	t.Run("it should not drop the implementations of an interface overridden by type", func(t *testing.T) {
+		// GIVEN
+		resolver := New()
+		resolver.MustRegister(func() *TestRepository { return &TestRepository{Data: "real"} })
+		fork := resolver.Fork()
+
+		// WHEN
+		fork.MustRegister(func() io.Closer { return closeFunc(func() error { return nil }) })
+		repo, err := Resolve[*TestRepository](fork)
+
+		// THEN
+		require.NoError(t, err)
+		assert.Equal(t, "real", repo.Data)
+	})
+
+	t.Run("it should inject the fork as the resolver", func(t *testing.T) {
+		// GIVEN
+		resolver := New()
+
+		// WHEN
+		fork := resolver.Fork()
+		injected, err := Resolve[*Resolver](fork)
+
+		// THEN
+		require.NoError(t, err)
+		assert.Same(t, fork, injected)
+	})
+}
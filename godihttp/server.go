@@ -0,0 +1,192 @@
+package godihttp
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/a-peyrard/godi"
+)
+
+type (
+	// ServerConfig configures the http server, it can be exposed as a config field (see godi.NewConfigFieldProvider),
+	// if no ServerConfig is available in the resolver, DefaultServerConfig is used.
+	ServerConfig struct {
+		Addr              string        `mapstructure:"addr"`
+		ReadTimeout       time.Duration `mapstructure:"read_timeout"`
+		ReadHeaderTimeout time.Duration `mapstructure:"read_header_timeout"`
+		WriteTimeout      time.Duration `mapstructure:"write_timeout"`
+		IdleTimeout       time.Duration `mapstructure:"idle_timeout"`
+		ShutdownTimeout   time.Duration `mapstructure:"shutdown_timeout"`
+	}
+
+	// RouteRegistrar is implemented by the components contributing routes to the mux,
+	// all the registrars available in the resolver are collected when building the mux.
+	RouteRegistrar interface {
+		RegisterRoutes(mux *http.ServeMux)
+	}
+
+	// Middleware wraps the handler served by the server, all the middlewares available in the resolver
+	// are collected, and applied in priority order, the highest priority being the outermost.
+	Middleware func(next http.Handler) http.Handler
+
+	// Server is a runnable serving the mux, the server is gracefully shut down when the context
+	// given to Run is cancelled, or when Stop is called.
+	Server struct {
+		server          *http.Server
+		shutdownTimeout time.Duration
+
+		ready     chan struct{}
+		readyOnce sync.Once
+		mu        sync.Mutex
+		listener  net.Listener
+	}
+)
+
+const (
+	// MuxName is the name used to register the mux in the resolver.
+	MuxName = "godihttp.mux"
+	// ServerName is the name used to register the server in the resolver.
+	ServerName = "godihttp.server"
+	// HTTPServerName is the name used to register the underlying http server in the resolver.
+	HTTPServerName = "godihttp.http.server"
+)
+
+// DefaultServerConfig returns the configuration used when no ServerConfig is available in the resolver.
+func DefaultServerConfig() ServerConfig {
+	return ServerConfig{
+		Addr:              ":8080",
+		ReadHeaderTimeout: 10 * time.Second,
+		IdleTimeout:       2 * time.Minute,
+		ShutdownTimeout:   30 * time.Second,
+	}
+}
+
+// NewServeMux creates a mux with the routes of all the registrars.
+func NewServeMux(registrars []RouteRegistrar) *http.ServeMux {
+	mux := http.NewServeMux()
+	for _, registrar := range registrars {
+		registrar.RegisterRoutes(mux)
+	}
+	return mux
+}
+
+// NewServer creates a server for the handler wrapped by the middlewares, the first middleware being the outermost.
+func NewServer(cfg ServerConfig, handler http.Handler, middlewares []Middleware) *Server {
+	for i := len(middlewares) - 1; i >= 0; i-- {
+		handler = middlewares[i](handler)
+	}
+	return &Server{
+		server: &http.Server{
+			Addr:              cfg.Addr,
+			Handler:           handler,
+			ReadTimeout:       cfg.ReadTimeout,
+			ReadHeaderTimeout: cfg.ReadHeaderTimeout,
+			WriteTimeout:      cfg.WriteTimeout,
+			IdleTimeout:       cfg.IdleTimeout,
+		},
+		shutdownTimeout: cfg.ShutdownTimeout,
+		ready:           make(chan struct{}),
+	}
+}
+
+// Register registers the mux, the server and the underlying http server in the resolver,
+// under the names MuxName, ServerName and HTTPServerName.
+//
+// The server is a runnable, so it is started by runner.Run along with the other runnables.
+func Register(resolver *godi.Resolver) error {
+	return errors.Join(
+		resolver.Register(
+			NewServeMux,
+			godi.Named(MuxName),
+			godi.Description("http mux with the routes of all the registrars"),
+			godi.Dependencies(godi.Inject.Multiple()),
+		),
+		resolver.Register(
+			func(cfg ServerConfig, mux *http.ServeMux, middlewares []Middleware) *Server {
+				return NewServer(cfg, mux, middlewares)
+			},
+			godi.Named(ServerName),
+			godi.Description("http server serving the mux"),
+			godi.Dependencies(
+				godi.Inject.Auto().Default(DefaultServerConfig()),
+				godi.Inject.Named(MuxName),
+				godi.Inject.Multiple(),
+			),
+		),
+		resolver.Register(
+			(*Server).HTTPServer,
+			godi.Named(HTTPServerName),
+			godi.Description("underlying http server"),
+			godi.Dependencies(godi.Inject.Named(ServerName)),
+		),
+	)
+}
+
+// Run listens on the configured address and serves until the context is cancelled, or Stop is called.
+func (s *Server) Run(ctx context.Context) error {
+	listener, err := net.Listen("tcp", s.server.Addr)
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	s.listener = listener
+	s.mu.Unlock()
+	// the server might be run again, e.g. restarted by a supervisor (see runner.Supervised)
+	s.readyOnce.Do(func() { close(s.ready) })
+
+	errs := make(chan error, 1)
+	go func() {
+		errs <- s.server.Serve(listener)
+	}()
+
+	select {
+	case err = <-errs:
+	case <-ctx.Done():
+		if err = s.shutdown(context.WithoutCancel(ctx)); err != nil {
+			return err
+		}
+		err = <-errs
+	}
+	if errors.Is(err, http.ErrServerClosed) {
+		return nil
+	}
+	return err
+}
+
+// Stop gracefully shuts down the server, waiting for the in-flight requests.
+func (s *Server) Stop(ctx context.Context) error {
+	return s.shutdown(ctx)
+}
+
+// Ready returns a channel closed once the server is listening for the first time.
+func (s *Server) Ready() <-chan struct{} {
+	return s.ready
+}
+
+// Addr returns the address the server is listening on, or nil if the server is not listening yet.
+func (s *Server) Addr() net.Addr {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.listener == nil {
+		return nil
+	}
+	return s.listener.Addr()
+}
+
+// HTTPServer returns the underlying http server.
+func (s *Server) HTTPServer() *http.Server {
+	return s.server
+}
+
+func (s *Server) shutdown(ctx context.Context) error {
+	if s.shutdownTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, s.shutdownTimeout)
+		defer cancel()
+	}
+	return s.server.Shutdown(ctx)
+}
@@ -0,0 +1,175 @@
+package godihttp
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/a-peyrard/godi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type helloRoutes struct{}
+
+func (helloRoutes) RegisterRoutes(mux *http.ServeMux) {
+	mux.HandleFunc("GET /hello", func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = io.WriteString(w, "hello")
+	})
+}
+
+func headerMiddleware(value string) Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Add("X-Trace", value)
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+func testConfig() ServerConfig {
+	cfg := DefaultServerConfig()
+	cfg.Addr = "127.0.0.1:0"
+	cfg.ShutdownTimeout = time.Second
+	return cfg
+}
+
+func TestRegister(t *testing.T) {
+	t.Run("it should collect the routes of all the registrars", func(t *testing.T) {
+		// GIVEN
+		resolver := godi.New()
+		resolver.MustRegister(godi.ToStaticProvider[RouteRegistrar](helloRoutes{}), godi.Named("routes.hello"))
+		require.NoError(t, Register(resolver))
+		mux := godi.MustResolveNamed[*http.ServeMux](resolver, MuxName)
+
+		// WHEN
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/hello", nil))
+
+		// THEN
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "hello", rec.Body.String())
+	})
+
+	t.Run("it should apply the middlewares in priority order", func(t *testing.T) {
+		// GIVEN
+		resolver := godi.New()
+		resolver.MustRegister(godi.ToStaticProvider[RouteRegistrar](helloRoutes{}), godi.Named("routes.hello"))
+		resolver.MustRegister(godi.ToStaticProvider(headerMiddleware("second")), godi.Named("middleware.second"), godi.Priority(1))
+		resolver.MustRegister(godi.ToStaticProvider(headerMiddleware("first")), godi.Named("middleware.first"), godi.Priority(2))
+		require.NoError(t, Register(resolver))
+		server := godi.MustResolveNamed[*Server](resolver, ServerName)
+
+		// WHEN
+		rec := httptest.NewRecorder()
+		server.HTTPServer().Handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/hello", nil))
+
+		// THEN
+		assert.Equal(t, []string{"first", "second"}, rec.Header().Values("X-Trace"))
+	})
+
+	t.Run("it should use the default config if none is available", func(t *testing.T) {
+		// GIVEN
+		resolver := godi.New()
+		require.NoError(t, Register(resolver))
+
+		// WHEN
+		httpServer, err := godi.ResolveNamed[*http.Server](resolver, HTTPServerName)
+
+		// THEN
+		require.NoError(t, err)
+		assert.Equal(t, DefaultServerConfig().Addr, httpServer.Addr)
+	})
+
+	t.Run("it should use the config available in the resolver", func(t *testing.T) {
+		// GIVEN
+		resolver := godi.New()
+		resolver.MustRegister(godi.ToStaticProvider(ServerConfig{Addr: ":9090"}), godi.Named("config.http"))
+		require.NoError(t, Register(resolver))
+
+		// WHEN
+		httpServer, err := godi.ResolveNamed[*http.Server](resolver, HTTPServerName)
+
+		// THEN
+		require.NoError(t, err)
+		assert.Equal(t, ":9090", httpServer.Addr)
+	})
+}
+
+func TestServer_Run(t *testing.T) {
+	t.Run("it should serve until the context is cancelled", func(t *testing.T) {
+		// GIVEN
+		server := NewServer(testConfig(), NewServeMux([]RouteRegistrar{helloRoutes{}}), nil)
+		ctx, cancel := context.WithCancel(context.Background())
+		done := make(chan error, 1)
+		go func() { done <- server.Run(ctx) }()
+		<-server.Ready()
+
+		// WHEN
+		resp, err := http.Get("http://" + server.Addr().String() + "/hello")
+		require.NoError(t, err)
+		body, _ := io.ReadAll(resp.Body)
+		_ = resp.Body.Close()
+		cancel()
+
+		// THEN
+		assert.Equal(t, "hello", string(body))
+		select {
+		case err = <-done:
+			assert.NoError(t, err)
+		case <-time.After(2 * time.Second):
+			t.Fatal("server did not shut down")
+		}
+	})
+
+	t.Run("it should return when stopped", func(t *testing.T) {
+		// GIVEN
+		server := NewServer(testConfig(), http.NewServeMux(), nil)
+		done := make(chan error, 1)
+		go func() { done <- server.Run(context.Background()) }()
+		<-server.Ready()
+
+		// WHEN
+		err := server.Stop(context.Background())
+
+		// THEN
+		require.NoError(t, err)
+		assert.NoError(t, <-done)
+	})
+
+	t.Run("it should be run again, e.g. when restarted by a supervisor", func(t *testing.T) {
+		// GIVEN
+		server := NewServer(testConfig(), http.NewServeMux(), nil)
+		done := make(chan error, 1)
+		go func() { done <- server.Run(context.Background()) }()
+		<-server.Ready()
+		require.NoError(t, server.Stop(context.Background()))
+		require.NoError(t, <-done)
+
+		// WHEN
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		err := server.Run(ctx)
+
+		// THEN
+		assert.NoError(t, err)
+	})
+
+	t.Run("it should fail if the address can not be listened", func(t *testing.T) {
+		// GIVEN
+		cfg := testConfig()
+		cfg.Addr = "invalid address"
+		server := NewServer(cfg, http.NewServeMux(), nil)
+
+		// WHEN
+		err := server.Run(context.Background())
+
+		// THEN
+		require.Error(t, err)
+		assert.True(t, strings.Contains(err.Error(), "invalid address"))
+	})
+}
@@ -0,0 +1,216 @@
+package godimetrics
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/a-peyrard/godi"
+	"github.com/a-peyrard/godi/godihttp"
+	"github.com/a-peyrard/godi/option"
+)
+
+type (
+	// Metrics collects the metrics of a resolver, and exposes them in the Prometheus text format.
+	//
+	// The resolutions are observed by an interceptor installed on the resolver (see godi.Resolver.Use), so they are
+	// recorded for the scopes and forks created afterward, while the gauges are read from godi.Resolver.Stats.
+	Metrics struct {
+		resolver *godi.Resolver
+		options  *MetricsOptions
+
+		mu          sync.Mutex
+		resolutions map[string]*resolutionMetrics
+	}
+
+	MetricsOptions struct {
+		buckets    []float64
+		path       string
+		serverAddr *string
+	}
+
+	resolutionMetrics struct {
+		count    uint64
+		failures uint64
+		sum      float64
+		buckets  []uint64
+	}
+)
+
+const (
+	// MetricsName is the name used to register the metrics in the resolver.
+	MetricsName = "godimetrics.metrics"
+	// ServerName is the name used to register the metrics server in the resolver, see WithServer.
+	ServerName = "godimetrics.server"
+
+	contentType = "text/plain; version=0.0.4; charset=utf-8"
+)
+
+// DefaultBuckets are the upper bounds (in seconds) of the buckets of the resolution duration histograms.
+var DefaultBuckets = []float64{.0001, .0005, .001, .005, .01, .05, .1, .5, 1, 5}
+
+// WithBuckets sets the upper bounds (in seconds) of the buckets of the resolution duration histograms.
+func WithBuckets(buckets ...float64) option.Option[MetricsOptions] {
+	return func(opts *MetricsOptions) {
+		opts.buckets = buckets
+	}
+}
+
+// WithPath sets the path the metrics are served on, default is /metrics.
+func WithPath(path string) option.Option[MetricsOptions] {
+	return func(opts *MetricsOptions) {
+		opts.path = path
+	}
+}
+
+// WithServer registers a runnable serving the metrics on the given address, under the name ServerName, see Register.
+func WithServer(addr string) option.Option[MetricsOptions] {
+	return func(opts *MetricsOptions) {
+		opts.serverAddr = &addr
+	}
+}
+
+// New creates the metrics of the resolver, and installs the interceptor observing its resolutions.
+func New(resolver *godi.Resolver, opts ...option.Option[MetricsOptions]) *Metrics {
+	options := option.Build(
+		&MetricsOptions{
+			buckets: DefaultBuckets,
+			path:    "/metrics",
+		},
+		opts...,
+	)
+	buckets := append([]float64(nil), options.buckets...)
+	sort.Float64s(buckets)
+	options.buckets = buckets
+
+	m := &Metrics{
+		resolver:    resolver,
+		options:     options,
+		resolutions: make(map[string]*resolutionMetrics),
+	}
+	resolver.Use(godi.Observe(m.observe))
+	return m
+}
+
+// Register creates the metrics of the resolver and registers them under the name MetricsName, along with
+// the metrics server if WithServer is given.
+//
+// The metrics are a godihttp.RouteRegistrar, so they are also served by the godihttp server if any.
+func Register(resolver *godi.Resolver, opts ...option.Option[MetricsOptions]) error {
+	m := New(resolver, opts...)
+	if err := resolver.Register(
+		godi.ToStaticProvider(m),
+		godi.Named(MetricsName),
+		godi.Description("container metrics"),
+	); err != nil {
+		return err
+	}
+	if m.options.serverAddr == nil {
+		return nil
+	}
+	return resolver.Register(
+		func() *godihttp.Server {
+			return NewServer(m, *m.options.serverAddr)
+		},
+		godi.Named(ServerName),
+		godi.Description("metrics server"),
+	)
+}
+
+// NewServer creates a runnable serving the metrics on the given address.
+func NewServer(m *Metrics, addr string) *godihttp.Server {
+	cfg := godihttp.DefaultServerConfig()
+	cfg.Addr = addr
+	return godihttp.NewServer(cfg, godihttp.NewServeMux([]godihttp.RouteRegistrar{m}), nil)
+}
+
+// RegisterRoutes serves the metrics on the configured path.
+func (m *Metrics) RegisterRoutes(mux *http.ServeMux) {
+	mux.Handle("GET "+m.options.path, m)
+}
+
+func (m *Metrics) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", contentType)
+	_, _ = m.WriteTo(w)
+}
+
+// WriteTo writes the metrics in the Prometheus text format.
+func (m *Metrics) WriteTo(w io.Writer) (int64, error) {
+	var b strings.Builder
+	stats := m.resolver.Stats()
+	writeGauge(&b, "godi_providers", "Number of registered providers.", stats.Providers)
+	writeGauge(&b, "godi_components", "Number of stored components.", stats.Components)
+	writeGauge(&b, "godi_active_scopes", "Number of scopes created and not closed yet.", stats.ActiveScopes)
+
+	m.mu.Lock()
+	names := make([]string, 0, len(m.resolutions))
+	for name := range m.resolutions {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	b.WriteString("# HELP godi_resolutions_total Number of invocations of the providers.\n")
+	b.WriteString("# TYPE godi_resolutions_total counter\n")
+	for _, name := range names {
+		fmt.Fprintf(&b, "godi_resolutions_total{name=%s} %d\n", quote(name), m.resolutions[name].count)
+	}
+
+	b.WriteString("# HELP godi_provider_failures_total Number of failed invocations of the providers.\n")
+	b.WriteString("# TYPE godi_provider_failures_total counter\n")
+	for _, name := range names {
+		fmt.Fprintf(&b, "godi_provider_failures_total{name=%s} %d\n", quote(name), m.resolutions[name].failures)
+	}
+
+	b.WriteString("# HELP godi_resolution_duration_seconds Duration of the invocations of the providers.\n")
+	b.WriteString("# TYPE godi_resolution_duration_seconds histogram\n")
+	for _, name := range names {
+		res := m.resolutions[name]
+		for i, bound := range m.options.buckets {
+			fmt.Fprintf(&b, "godi_resolution_duration_seconds_bucket{name=%s,le=\"%g\"} %d\n", quote(name), bound, res.buckets[i])
+		}
+		fmt.Fprintf(&b, "godi_resolution_duration_seconds_bucket{name=%s,le=\"+Inf\"} %d\n", quote(name), res.count)
+		fmt.Fprintf(&b, "godi_resolution_duration_seconds_sum{name=%s} %g\n", quote(name), res.sum)
+		fmt.Fprintf(&b, "godi_resolution_duration_seconds_count{name=%s} %d\n", quote(name), res.count)
+	}
+	m.mu.Unlock()
+
+	n, err := io.WriteString(w, b.String())
+	return int64(n), err
+}
+
+func (m *Metrics) observe(invocation godi.Invocation, duration time.Duration, err error) {
+	name := invocation.Name.String()
+	seconds := duration.Seconds()
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	res, found := m.resolutions[name]
+	if !found {
+		res = &resolutionMetrics{buckets: make([]uint64, len(m.options.buckets))}
+		m.resolutions[name] = res
+	}
+	res.count++
+	res.sum += seconds
+	if err != nil {
+		res.failures++
+	}
+	// the buckets are cumulative
+	for i := len(m.options.buckets) - 1; i >= 0 && seconds <= m.options.buckets[i]; i-- {
+		res.buckets[i]++
+	}
+}
+
+func writeGauge(b *strings.Builder, name, help string, value int) {
+	fmt.Fprintf(b, "# HELP %s %s\n# TYPE %s gauge\n%s %d\n", name, help, name, name, value)
+}
+
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func quote(value string) string {
+	return `"` + labelEscaper.Replace(value) + `"`
+}
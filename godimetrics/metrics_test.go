@@ -0,0 +1,95 @@
+package godimetrics
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/a-peyrard/godi"
+	"github.com/a-peyrard/godi/godihttp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type (
+	repository struct{}
+	service    struct{}
+)
+
+func TestMetrics(t *testing.T) {
+	t.Run("it should count the resolutions and failures per name", func(t *testing.T) {
+		// GIVEN
+		resolver := godi.New()
+		m := New(resolver, WithBuckets(1, 5))
+		resolver.MustRegister(func() *repository { return &repository{} }, godi.Named("repository"))
+		resolver.MustRegister(func() (*service, error) { return nil, errors.New("boom") }, godi.Named("service"))
+		godi.MustResolveNamed[*repository](resolver, "repository")
+		_, _ = godi.ResolveNamed[*service](resolver, "service")
+
+		// WHEN
+		var b strings.Builder
+		_, err := m.WriteTo(&b)
+
+		// THEN
+		require.NoError(t, err)
+		out := b.String()
+		assert.Contains(t, out, `godi_resolutions_total{name="(repository, *godimetrics.repository)"} 1`)
+		assert.Contains(t, out, `godi_provider_failures_total{name="(repository, *godimetrics.repository)"} 0`)
+		assert.Contains(t, out, `godi_provider_failures_total{name="(service, *godimetrics.service)"} 1`)
+		assert.Contains(t, out, `godi_resolution_duration_seconds_bucket{name="(repository, *godimetrics.repository)",le="1"} 1`)
+		assert.Contains(t, out, `godi_resolution_duration_seconds_bucket{name="(repository, *godimetrics.repository)",le="+Inf"} 1`)
+		assert.Contains(t, out, `godi_resolution_duration_seconds_count{name="(repository, *godimetrics.repository)"} 1`)
+	})
+
+	t.Run("it should expose the resolver gauges", func(t *testing.T) {
+		// GIVEN
+		resolver := godi.New()
+		m := New(resolver)
+		_ = resolver.NewScope()
+
+		// WHEN
+		var b strings.Builder
+		_, err := m.WriteTo(&b)
+
+		// THEN
+		require.NoError(t, err)
+		assert.Contains(t, b.String(), "# TYPE godi_active_scopes gauge\ngodi_active_scopes 1\n")
+		assert.Contains(t, b.String(), "# TYPE godi_providers gauge\n")
+	})
+}
+
+func TestRegister(t *testing.T) {
+	t.Run("it should serve the metrics with the godihttp server", func(t *testing.T) {
+		// GIVEN
+		resolver := godi.New()
+		require.NoError(t, Register(resolver))
+		require.NoError(t, godihttp.Register(resolver))
+		mux := godi.MustResolveNamed[*http.ServeMux](resolver, godihttp.MuxName)
+
+		// WHEN
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+		// THEN
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, contentType, rec.Header().Get("Content-Type"))
+		assert.Contains(t, rec.Body.String(), "godi_resolutions_total")
+	})
+
+	t.Run("it should register the metrics server", func(t *testing.T) {
+		// GIVEN
+		resolver := godi.New()
+
+		// WHEN
+		err := Register(resolver, WithServer("127.0.0.1:0"), WithPath("/internal/metrics"))
+
+		// THEN
+		require.NoError(t, err)
+		server := godi.MustResolveNamed[*godihttp.Server](resolver, ServerName)
+		rec := httptest.NewRecorder()
+		server.HTTPServer().Handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/internal/metrics", nil))
+		assert.Equal(t, http.StatusOK, rec.Code)
+	})
+}
@@ -0,0 +1,108 @@
+package godiotel
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	"github.com/a-peyrard/godi"
+	"github.com/a-peyrard/godi/option"
+	"github.com/a-peyrard/godi/runner"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type (
+	// Options configures the tracing of the resolver and the runner.
+	Options struct {
+		tracerProvider trace.TracerProvider
+	}
+)
+
+// TracerName is the name of the tracer creating the spans.
+const TracerName = "github.com/a-peyrard/godi/godiotel"
+
+// WithTracerProvider sets the provider of the tracer creating the spans, default is the global one (see otel.GetTracerProvider).
+func WithTracerProvider(tracerProvider trace.TracerProvider) option.Option[Options] {
+	return func(opts *Options) {
+		opts.tracerProvider = tracerProvider
+	}
+}
+
+// Instrument creates a span for each construction of a component by the resolver, and its scopes and forks
+// created afterward.
+//
+// The span is named after the component, and is a child of the span of the resolution context if any (see
+// godi.ResolveCtx), its attributes are the type and the priority of the component, and its decorators.
+func Instrument(resolver *godi.Resolver, opts ...option.Option[Options]) {
+	resolver.Use(NewInterceptor(resolver, opts...))
+}
+
+// NewInterceptor creates the interceptor creating a span for each construction of a component, see Instrument.
+func NewInterceptor(resolver *godi.Resolver, opts ...option.Option[Options]) godi.Interceptor {
+	tracer := tracerOf(opts...)
+	return godi.InterceptorFunc(func(invocation godi.Invocation, proceed func() (reflect.Value, error)) (reflect.Value, error) {
+		_, span := tracer.Start(
+			invocation.Context,
+			spanNameOf(invocation.Name),
+			trace.WithAttributes(
+				attribute.String("godi.name", invocation.Name.Name()),
+				attribute.String("godi.type", invocation.Name.Type().String()),
+				attribute.Int("godi.priority", invocation.Provider.Priority()),
+				attribute.StringSlice("godi.decorators", decoratorsOf(resolver, invocation.Name)),
+			),
+		)
+		defer span.End()
+
+		comp, err := proceed()
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+		}
+		return comp, err
+	})
+}
+
+// RunnerHook creates a span for the startup and the shutdown of the runner, see runner.WithPhaseHook.
+func RunnerHook(opts ...option.Option[Options]) runner.PhaseHook {
+	tracer := tracerOf(opts...)
+	return func(ctx context.Context, phase runner.Phase) (context.Context, func(error)) {
+		ctx, span := tracer.Start(ctx, "godi.runner."+string(phase))
+		return ctx, func(err error) {
+			if err != nil {
+				span.RecordError(err)
+				span.SetStatus(codes.Error, err.Error())
+			}
+			span.End()
+		}
+	}
+}
+
+func tracerOf(opts ...option.Option[Options]) trace.Tracer {
+	options := option.Build(&Options{}, opts...)
+	if options.tracerProvider == nil {
+		options.tracerProvider = otel.GetTracerProvider()
+	}
+	return options.tracerProvider.Tracer(TracerName)
+}
+
+func spanNameOf(name godi.Name) string {
+	if name.Name() != "" {
+		return name.Name()
+	}
+	return name.Type().String()
+}
+
+func decoratorsOf(resolver *godi.Resolver, name godi.Name) []string {
+	if name.Name() == "" {
+		return nil
+	}
+	decorators := resolver.DecoratorsFor(name.Name())
+	descriptions := make([]string, 0, len(decorators))
+	for _, d := range decorators {
+		descriptions = append(descriptions, fmt.Sprint(d))
+	}
+	return descriptions
+}
@@ -0,0 +1,154 @@
+package godiotel
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/a-peyrard/godi"
+	"github.com/a-peyrard/godi/runner"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+type (
+	repository struct{}
+	service    struct{}
+)
+
+func newRecorder() (*tracetest.SpanRecorder, *sdktrace.TracerProvider) {
+	recorder := tracetest.NewSpanRecorder()
+	return recorder, sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+}
+
+func attributesOf(span sdktrace.ReadOnlySpan) map[attribute.Key]attribute.Value {
+	attributes := make(map[attribute.Key]attribute.Value)
+	for _, kv := range span.Attributes() {
+		attributes[kv.Key] = kv.Value
+	}
+	return attributes
+}
+
+func TestInstrument(t *testing.T) {
+	t.Run("it should create a span for each construction of a component", func(t *testing.T) {
+		// GIVEN
+		recorder, tracerProvider := newRecorder()
+		resolver := godi.New()
+		Instrument(resolver, WithTracerProvider(tracerProvider))
+		resolver.MustRegister(func() *repository { return &repository{} }, godi.Named("repository"), godi.Priority(10))
+		resolver.MustRegister(
+			func(r *repository) *repository { return r },
+			godi.Decorate("repository"),
+			godi.Description("caching"),
+		)
+
+		// WHEN
+		godi.MustResolveNamed[*repository](resolver, "repository")
+
+		// THEN
+		spans := recorder.Ended()
+		require.Len(t, spans, 1)
+		assert.Equal(t, "repository", spans[0].Name())
+		attributes := attributesOf(spans[0])
+		assert.Equal(t, "*godiotel.repository", attributes["godi.type"].AsString())
+		assert.Equal(t, int64(10), attributes["godi.priority"].AsInt64())
+		assert.Len(t, attributes["godi.decorators"].AsStringSlice(), 1)
+	})
+
+	t.Run("it should record the failure of the provider", func(t *testing.T) {
+		// GIVEN
+		recorder, tracerProvider := newRecorder()
+		resolver := godi.New()
+		Instrument(resolver, WithTracerProvider(tracerProvider))
+		resolver.MustRegister(func() (*service, error) { return nil, errors.New("boom") })
+
+		// WHEN
+		_, err := godi.Resolve[*service](resolver)
+
+		// THEN
+		require.Error(t, err)
+		spans := recorder.Ended()
+		require.Len(t, spans, 1)
+		assert.Equal(t, "*godiotel.service", attributesOf(spans[0])["godi.type"].AsString())
+		assert.Equal(t, codes.Error, spans[0].Status().Code)
+		assert.Equal(t, "boom", spans[0].Status().Description)
+	})
+
+	t.Run("it should create the span as a child of the resolution context", func(t *testing.T) {
+		// GIVEN
+		recorder, tracerProvider := newRecorder()
+		resolver := godi.New()
+		Instrument(resolver, WithTracerProvider(tracerProvider))
+		resolver.MustRegister(func() *repository { return &repository{} }, godi.Named("repository"))
+		ctx, parent := tracerProvider.Tracer("test").Start(context.Background(), "parent")
+
+		// WHEN
+		_, err := godi.ResolveNamedCtx[*repository](ctx, resolver, "repository")
+		parent.End()
+
+		// THEN
+		require.NoError(t, err)
+		spans := recorder.Ended()
+		require.Len(t, spans, 2)
+		assert.Equal(t, parent.SpanContext().SpanID(), spans[0].Parent().SpanID())
+	})
+}
+
+func TestRunnerHook(t *testing.T) {
+	t.Run("it should create a span for the startup and the shutdown of the runner", func(t *testing.T) {
+		// GIVEN
+		recorder, tracerProvider := newRecorder()
+		ctx, cancel := context.WithCancel(context.Background())
+		resolver := godi.New()
+		resolver.MustRegister(godi.ToStaticProvider(ctx))
+		resolver.MustRegister(func() runner.Runnable {
+			return runner.RunnableFunc(func(ctx context.Context) error {
+				cancel()
+				<-ctx.Done()
+				return nil
+			})
+		})
+
+		// WHEN
+		err := runner.Run(resolver, runner.WithPhaseHook(RunnerHook(WithTracerProvider(tracerProvider))))
+
+		// THEN
+		require.NoError(t, err)
+		spans := recorder.Ended()
+		require.Len(t, spans, 2)
+		assert.Equal(t, "godi.runner.startup", spans[0].Name())
+		assert.Equal(t, "godi.runner.shutdown", spans[1].Name())
+	})
+	t.Run("it should create the spans of the components started as children of the startup span", func(t *testing.T) {
+		// GIVEN
+		recorder, tracerProvider := newRecorder()
+		ctx, cancel := context.WithCancel(context.Background())
+		resolver := godi.New()
+		Instrument(resolver, WithTracerProvider(tracerProvider))
+		resolver.MustRegister(godi.ToStaticProvider(ctx), godi.Named("context"))
+		resolver.MustRegister(func() runner.Runnable {
+			return runner.RunnableFunc(func(ctx context.Context) error {
+				cancel()
+				<-ctx.Done()
+				return nil
+			})
+		}, godi.Named("runnable"))
+
+		// WHEN
+		err := runner.Run(resolver, runner.WithPhaseHook(RunnerHook(WithTracerProvider(tracerProvider))))
+
+		// THEN
+		require.NoError(t, err)
+		spans := make(map[string]sdktrace.ReadOnlySpan)
+		for _, span := range recorder.Ended() {
+			spans[span.Name()] = span
+		}
+		require.Contains(t, spans, "runnable")
+		require.Contains(t, spans, "godi.runner.startup")
+		assert.Equal(t, spans["godi.runner.startup"].SpanContext().SpanID(), spans["runnable"].Parent().SpanID())
+	})
+}
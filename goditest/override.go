@@ -0,0 +1,37 @@
+// Package goditest provides helpers to use godi in tests.
+package goditest
+
+import (
+	"math"
+	"testing"
+
+	"github.com/a-peyrard/godi"
+	"github.com/a-peyrard/godi/option"
+)
+
+// Override replaces the providers of T with the given instance, until the end of the test.
+//
+// All the providers of components matching T are replaced, they are restored (along with the components they
+// already built) on t.Cleanup.
+func Override[T any](t testing.TB, resolver *godi.Resolver, instance T) {
+	t.Helper()
+	override(t, resolver, instance)
+}
+
+// OverrideNamed replaces the provider of the named component with the given instance, until the end of the test.
+func OverrideNamed[T any](t testing.TB, resolver *godi.Resolver, name string, instance T) {
+	t.Helper()
+	override(t, resolver, instance, godi.Named(name))
+}
+
+func override[T any](t testing.TB, resolver *godi.Resolver, instance T, opts ...option.Option[godi.RegistrableOptions]) {
+	t.Helper()
+	restore, err := resolver.Override(
+		godi.ToStaticProvider(instance),
+		append(opts, godi.Priority(math.MaxInt))...,
+	)
+	if err != nil {
+		t.Fatalf("failed to override %T:\n\t%v", instance, err)
+	}
+	t.Cleanup(restore)
+}
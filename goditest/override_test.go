@@ -0,0 +1,86 @@
+package goditest
+
+import (
+	"testing"
+
+	"github.com/a-peyrard/godi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type (
+	mailer interface {
+		Send(to string) string
+	}
+
+	smtpMailer struct{}
+
+	fakeMailer struct{}
+)
+
+func (smtpMailer) Send(to string) string { return "smtp:" + to }
+
+func (fakeMailer) Send(to string) string { return "fake:" + to }
+
+func TestOverride(t *testing.T) {
+	t.Run("it should override the providers of the type until the end of the test", func(t *testing.T) {
+		// GIVEN
+		resolver := godi.New()
+		resolver.MustRegister(func() mailer { return smtpMailer{} })
+		original := godi.MustResolve[mailer](resolver)
+
+		// WHEN
+		t.Run("overridden", func(t *testing.T) {
+			Override[mailer](t, resolver, fakeMailer{})
+
+			// THEN
+			assert.Equal(t, "fake:john", godi.MustResolve[mailer](resolver).Send("john"))
+		})
+
+		// THEN
+		restored, err := godi.Resolve[mailer](resolver)
+		require.NoError(t, err)
+		assert.Equal(t, original, restored)
+	})
+}
+
+func TestOverrideNamed(t *testing.T) {
+	t.Run("it should override the named component until the end of the test", func(t *testing.T) {
+		// GIVEN
+		resolver := godi.New()
+		resolver.MustRegister(godi.ToStaticProvider("prod"), godi.Named("APP_ENV"))
+
+		// WHEN
+		t.Run("overridden", func(t *testing.T) {
+			OverrideNamed(t, resolver, "APP_ENV", "test")
+
+			// THEN
+			assert.Equal(t, "test", godi.MustResolveNamed[string](resolver, "APP_ENV"))
+		})
+
+		// THEN
+		assert.Equal(t, "prod", godi.MustResolveNamed[string](resolver, "APP_ENV"))
+	})
+
+	t.Run("it should keep the components of other names with the same type", func(t *testing.T) {
+		// GIVEN
+		resolver := godi.New()
+		resolver.MustRegister(godi.ToStaticProvider("prod"), godi.Named("APP_ENV"))
+		resolver.MustRegister(godi.ToStaticProvider("eu-west-1"), godi.Named("REGION"))
+
+		// WHEN
+		t.Run("overridden", func(t *testing.T) {
+			OverrideNamed(t, resolver, "APP_ENV", "test")
+			region, found, err := godi.TryResolveNamed[string](resolver, "REGION")
+
+			// THEN
+			require.NoError(t, err)
+			assert.True(t, found)
+			assert.Equal(t, "eu-west-1", region)
+		})
+
+		// THEN
+		assert.Equal(t, "prod", godi.MustResolveNamed[string](resolver, "APP_ENV"))
+		assert.Equal(t, "eu-west-1", godi.MustResolveNamed[string](resolver, "REGION"))
+	})
+}
@@ -0,0 +1,179 @@
+package godi
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+type (
+	// In is embedded in the parameter structs of the factory methods, whose exported fields are all injected,
+	// by type unless their godi tag or their name tag says otherwise (see NewComponentProvider), instead of a long
+	// list of parameters:
+	//
+	//	type HandlerParams struct {
+	//		godi.In
+	//		Primary *sql.DB `godi:"name=db.primary"`
+	//		Cache   *Cache  `godi:"optional"`
+	//	}
+	//
+	//	func NewHandler(p HandlerParams) *Handler
+	In struct{}
+
+	// Out is embedded in the structs returned by the factory methods, whose exported fields are all registered
+	// as separate components, as if the factory method returned them one by one (see NewFactoryMethodProvider):
+	//
+	//	type Databases struct {
+	//		godi.Out
+	//		Primary *sql.DB `name:"db.primary"`
+	//		Replica *sql.DB `name:"db.replica"`
+	//	}
+	//
+	//	func NewDatabases(cfg Config) (Databases, error)
+	//
+	// The fields are named after their name tag (`godi:"name=db.primary"` is also accepted), or after the provider
+	// otherwise, and the fields tagged with `godi:"-"` are left out.
+	Out struct{}
+
+	// paramsProvider builds the parameter structs embedding In, it is not registered, but found for each parameter
+	// with queryParams.
+	paramsProvider struct {
+		name   Name
+		fields []injectedField
+	}
+
+	// queryParams finds the provider of a parameter struct embedding In, built on each resolution.
+	queryParams struct {
+		provider *paramsProvider
+	}
+)
+
+const nameTag = "name"
+
+var (
+	inType  = TypeOf[In]()
+	outType = TypeOf[Out]()
+)
+
+// isInStruct returns true if the type is a struct embedding In.
+func isInStruct(typ reflect.Type) bool {
+	return embeds(typ, inType)
+}
+
+// isOutStruct returns true if the type is a struct embedding Out.
+func isOutStruct(typ reflect.Type) bool {
+	return embeds(typ, outType)
+}
+
+func embeds(typ reflect.Type, marker reflect.Type) bool {
+	if typ.Kind() != reflect.Struct {
+		return false
+	}
+	for i := 0; i < typ.NumField(); i++ {
+		if field := typ.Field(i); field.Anonymous && field.Type == marker {
+			return true
+		}
+	}
+	return false
+}
+
+func isMarker(field reflect.StructField) bool {
+	return field.Anonymous && (field.Type == inType || field.Type == outType)
+}
+
+// paramsDependency returns the request of a parameter struct embedding In, whose fields are the dependencies
+// of the provider building it.
+func paramsDependency(typ reflect.Type) (Request, error) {
+	fields, err := injectedFieldsOf(typ, true)
+	if err != nil {
+		return Request{}, err
+	}
+	return Request{
+		unitaryTyp: typ,
+		query: queryParams{
+			provider: &paramsProvider{name: Name{name: typ.String(), typ: typ}, fields: fields},
+		},
+		validator: validatorUniqueMandatory{},
+		collector: collectorUnique{},
+	}, nil
+}
+
+// outFieldsOf returns the names of the components of the exported fields of a struct embedding Out, and the indexes
+// of the fields.
+func outFieldsOf(typ reflect.Type, named string) (names []Name, fields [][]int, err error) {
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		if isMarker(field) || !field.IsExported() || field.Tag.Get(injectTag) == "-" {
+			continue
+		}
+		name := named
+		if tagged, ok := field.Tag.Lookup(nameTag); ok {
+			name = tagged
+		}
+		for _, property := range strings.Split(field.Tag.Get(injectTag), ",") {
+			key, value, _ := strings.Cut(strings.TrimSpace(property), "=")
+			switch key {
+			case "":
+			case "name":
+				name = value
+			default:
+				return nil, nil, fmt.Errorf("unknown property %q in tag of field %s of %s", key, field.Name, typ)
+			}
+		}
+		names = append(names, Name{name: name, typ: field.Type})
+		fields = append(fields, field.Index)
+	}
+	if len(names) == 0 {
+		return nil, nil, fmt.Errorf("%s does not have any exported field to register", typ)
+	}
+	return names, fields, nil
+}
+
+func (p *paramsProvider) CanProvide(name Name) bool {
+	return name == p.name
+}
+
+func (p *paramsProvider) Provide(_ Name, dependencies []reflect.Value) (reflect.Value, error) {
+	params := reflect.New(p.name.typ).Elem()
+	for idx, field := range p.fields {
+		if dependencies[idx].IsValid() {
+			params.FieldByIndex(field.index).Set(dependencies[idx])
+		}
+	}
+	return params, nil
+}
+
+func (p *paramsProvider) Dependencies() []Request {
+	requests := make([]Request, len(p.fields))
+	for idx, field := range p.fields {
+		requests[idx] = field.request
+	}
+	return requests
+}
+
+func (p *paramsProvider) ListProvidableNames() []Name {
+	return []Name{p.name}
+}
+
+func (p *paramsProvider) Priority() int {
+	return 0
+}
+
+func (p *paramsProvider) Description() string {
+	return ""
+}
+
+func (p *paramsProvider) String() string {
+	return fmt.Sprintf("ParamsProvider(%s)", p.name.typ)
+}
+
+func (q queryParams) find(_ *Resolver) ([]*queryResult, error) {
+	return []*queryResult{{
+		name:     q.provider.name,
+		provider: withAttributes(q.provider, providerAttributes{transient: true, hidden: true}),
+	}}, nil
+}
+
+func (q queryParams) String() string {
+	return fmt.Sprintf("<params %s>", q.provider.name.typ)
+}
@@ -0,0 +1,210 @@
+package godi
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type (
+	database struct{ dsn string }
+
+	databases struct {
+		Out
+		Primary *database `name:"db.primary"`
+		Replica *database `godi:"name=db.replica"`
+		Pool    int
+		Ignored string `godi:"-"`
+	}
+
+	repositoryParams struct {
+		In
+		Primary *database `name:"db.primary"`
+		Replica *database `godi:"name=db.replica"`
+		Pool    int
+		Cache   *pipeReader `godi:"optional"`
+		Ignored string      `godi:"-"`
+	}
+)
+
+func TestOut(t *testing.T) {
+	newDatabases := func(calls *int) func() (databases, error) {
+		return func() (databases, error) {
+			*calls++
+			return databases{Primary: &database{dsn: "primary"}, Replica: &database{dsn: "replica"}, Pool: 8}, nil
+		}
+	}
+
+	t.Run("it should register each field as a component, calling the factory method once", func(t *testing.T) {
+		// GIVEN
+		resolver := New()
+		calls := 0
+		resolver.MustRegister(newDatabases(&calls), Named("databases"))
+
+		// WHEN
+		primary, primaryErr := ResolveNamed[*database](resolver, "db.primary")
+		replica, replicaErr := ResolveNamed[*database](resolver, "db.replica")
+		pool, poolErr := ResolveNamed[int](resolver, "databases")
+
+		// THEN
+		require.NoError(t, primaryErr)
+		require.NoError(t, replicaErr)
+		require.NoError(t, poolErr)
+		assert.Equal(t, "primary", primary.dsn)
+		assert.Equal(t, "replica", replica.dsn)
+		assert.Equal(t, 8, pool)
+		assert.Equal(t, 1, calls)
+		assert.NoError(t, resolver.Validate())
+	})
+
+	t.Run("it should not register the fields tagged with a dash", func(t *testing.T) {
+		// GIVEN
+		resolver := New()
+		calls := 0
+		resolver.MustRegister(newDatabases(&calls), Named("databases"))
+
+		// WHEN
+		_, err := Resolve[string](resolver)
+
+		// THEN
+		assert.Error(t, err)
+	})
+
+	t.Run("it should report the error of the factory method", func(t *testing.T) {
+		// GIVEN
+		resolver := New()
+		resolver.MustRegister(func() (databases, error) {
+			return databases{}, errors.New("unreachable")
+		})
+
+		// WHEN
+		_, err := ResolveNamed[*database](resolver, "db.primary")
+
+		// THEN
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "unreachable")
+	})
+
+	t.Run("it should build the fields again together once one of them is refreshed", func(t *testing.T) {
+		// GIVEN
+		resolver := New()
+		calls := 0
+		resolver.MustRegister(newDatabases(&calls), Named("databases"))
+		primary := MustResolveNamed[*database](resolver, "db.primary")
+
+		// WHEN
+		err := resolver.Refresh("db.replica")
+
+		// THEN
+		require.NoError(t, err)
+		assert.NotSame(t, primary, MustResolveNamed[*database](resolver, "db.primary"))
+		assert.Equal(t, 2, calls)
+	})
+
+	t.Run("it should fail if fields can not be told apart", func(t *testing.T) {
+		// GIVEN
+		type ambiguous struct {
+			Out
+			First  *database
+			Second *database
+		}
+		resolver := New()
+
+		// WHEN
+		err := resolver.Register(func() ambiguous { return ambiguous{} })
+
+		// THEN
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "can not be told apart")
+	})
+
+	t.Run("it should fail if the struct is returned along with other components", func(t *testing.T) {
+		// GIVEN
+		resolver := New()
+
+		// WHEN
+		err := resolver.Register(func() (databases, int) { return databases{}, 0 })
+
+		// THEN
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "must either return it and an error, or just it")
+	})
+}
+
+func TestIn(t *testing.T) {
+	registerDatabases := func(resolver *Resolver) {
+		resolver.MustRegister(func() databases {
+			return databases{Primary: &database{dsn: "primary"}, Replica: &database{dsn: "replica"}, Pool: 8}
+		})
+	}
+
+	t.Run("it should inject the fields of the parameter struct", func(t *testing.T) {
+		// GIVEN
+		resolver := New()
+		registerDatabases(resolver)
+		resolver.MustRegister(func(p repositoryParams) string {
+			return p.Primary.dsn + "/" + p.Replica.dsn
+		}, Named("repository"))
+
+		// WHEN
+		repository, err := ResolveNamed[string](resolver, "repository")
+
+		// THEN
+		require.NoError(t, err)
+		assert.Equal(t, "primary/replica", repository)
+		assert.NoError(t, resolver.Validate())
+	})
+
+	t.Run("it should leave the optional and ignored fields untouched", func(t *testing.T) {
+		// GIVEN
+		resolver := New()
+		registerDatabases(resolver)
+		var params repositoryParams
+		resolver.MustRegister(func(p repositoryParams) *pipeWriter {
+			params = p
+			return &pipeWriter{}
+		})
+
+		// WHEN
+		_, err := Resolve[*pipeWriter](resolver)
+
+		// THEN
+		require.NoError(t, err)
+		assert.Equal(t, 8, params.Pool)
+		assert.Nil(t, params.Cache)
+		assert.Empty(t, params.Ignored)
+	})
+
+	t.Run("it should report the missing fields", func(t *testing.T) {
+		// GIVEN
+		resolver := New()
+		resolver.MustRegister(func(p repositoryParams) string { return "" }, Named("repository"))
+
+		// WHEN
+		err := resolver.Validate()
+
+		// THEN
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "db.primary")
+	})
+
+	t.Run("it should describe the fields as the dependencies", func(t *testing.T) {
+		// GIVEN
+		resolver := New()
+		resolver.MustRegister(func(p repositoryParams) string { return "" }, Named("repository"))
+
+		// WHEN
+		providers := resolver.Providers()
+
+		// THEN
+		require.NotEmpty(t, providers)
+		assert.Equal(t, []DependencyInfo{
+			{Name: "db.primary", Type: TypeOf[*database]()},
+			{Name: "db.replica", Type: TypeOf[*database]()},
+			{Type: TypeOf[int]()},
+			{Type: TypeOf[*pipeReader](), Optional: true},
+		}, providers[0].Dependencies)
+	})
+}
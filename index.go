@@ -0,0 +1,179 @@
+package godi
+
+import (
+	"reflect"
+	"slices"
+	"sync"
+)
+
+type (
+	// fixedNamesProvider is implemented by the providers whose providable names might never change, and which can
+	// then only provide them, so they are indexed by name and by type. The other providers (e.g. EnvProvider)
+	// are always scanned.
+	fixedNamesProvider interface {
+		hasFixedNames() bool
+	}
+
+	// providerIndex indexes a snapshot of the providers, as the slice of the providers is copied on write,
+	// the index of a snapshot never changes, it is rebuilt for the next snapshot, see Resolver.indexOfProviders.
+	providerIndex struct {
+		snapshot *[]Provider
+
+		// byName are the positions of the fixed providers by name, and scanned are the positions of the other ones
+		byName  map[string][]int
+		scanned []int
+
+		// byType caches the positions of the fixed providers of a component matching a type,
+		// the type of keys is reflect.Type, the type of values is []int
+		byType sync.Map
+	}
+
+	// providerLookup looks up the providers of an index, either all of them, or only the fixed ones, or only the
+	// scanned ones, so the plans of the queries can be split, see Resolver.candidatesFor.
+	providerLookup struct {
+		index   *providerIndex
+		fixed   bool
+		scanned bool
+	}
+)
+
+func (f *FactoryMethodProvider) hasFixedNames() bool { return true }
+
+func (s *StructProvider[T]) hasFixedNames() bool { return true }
+
+// hasFixedNames tells if the names of the fields are static, which is not the case if the config has slices or maps,
+// the fields of their elements being provided for any index or key.
+func (c *ConfigFieldProvider[T]) hasFixedNames() bool {
+	c.loadNamesIfNeeded()
+	return len(c.patterns) == 0
+}
+
+func newProviderIndex(snapshot *[]Provider) *providerIndex {
+	index := &providerIndex{snapshot: snapshot, byName: make(map[string][]int)}
+	for position, p := range *snapshot {
+		if !hasFixedNames(p) {
+			index.scanned = append(index.scanned, position)
+			continue
+		}
+		for _, n := range p.ListProvidableNames() {
+			positions := index.byName[n.name]
+			if len(positions) == 0 || positions[len(positions)-1] != position {
+				index.byName[n.name] = append(positions, position)
+			}
+		}
+	}
+	return index
+}
+
+// indexOfProviders returns the index of the current providers, building it if the providers changed since the last
+// lookup.
+func (r *Resolver) indexOfProviders() *providerIndex {
+	snapshot := r.providers.load()
+	if index := r.index.Load(); index != nil && index.snapshot == snapshot {
+		return index
+	}
+	index := newProviderIndex(snapshot)
+	r.index.Store(index)
+	return index
+}
+
+// all looks up all the providers of the index.
+func (i *providerIndex) all() providerLookup {
+	return providerLookup{index: i, fixed: true, scanned: true}
+}
+
+// fixedOnly looks up the providers with fixed names only.
+func (i *providerIndex) fixedOnly() providerLookup {
+	return providerLookup{index: i, fixed: true}
+}
+
+// scannedOnly looks up the providers always scanned only.
+func (i *providerIndex) scannedOnly() providerLookup {
+	return providerLookup{index: i, scanned: true}
+}
+
+// providersNamed returns the providers which might provide a component with the given name, in order.
+func (i *providerIndex) providersNamed(name string) []Provider {
+	return i.all().providersNamed(name)
+}
+
+// providersOfType returns the providers which might provide a component matching the type, in order.
+func (i *providerIndex) providersOfType(typ reflect.Type) []Provider {
+	return i.all().providersOfType(typ)
+}
+
+// providersNamed returns the providers looked up which might provide a component with the given name, in order.
+func (l providerLookup) providersNamed(name string) []Provider {
+	if !l.fixed {
+		return l.index.providersAt(nil, l.index.scanned)
+	}
+	return l.index.providersAt(l.index.byName[name], l.scannedPositions())
+}
+
+// providersOfType returns the providers looked up which might provide a component matching the type, in order.
+func (l providerLookup) providersOfType(typ reflect.Type) []Provider {
+	if !l.fixed {
+		return l.index.providersAt(nil, l.index.scanned)
+	}
+	return l.index.providersAt(l.index.positionsOfType(typ), l.scannedPositions())
+}
+
+func (l providerLookup) scannedPositions() []int {
+	if !l.scanned {
+		return nil
+	}
+	return l.index.scanned
+}
+
+// positionsOfType returns the positions of the fixed providers of a component matching the type.
+func (i *providerIndex) positionsOfType(typ reflect.Type) []int {
+	if cached, found := i.byType.Load(typ); found {
+		return cached.([]int)
+	}
+	var positions []int
+	for _, indexed := range i.byName {
+		for _, position := range indexed {
+			p := (*i.snapshot)[position]
+			for _, n := range p.ListProvidableNames() {
+				if attributesOf(p).matchesQueriedType(typ, n.typ) {
+					positions = append(positions, position)
+					break
+				}
+			}
+		}
+	}
+	positions = sortedUnique(positions)
+	i.byType.Store(typ, positions)
+	return positions
+}
+
+// providersAt returns the providers at the given sorted positions, along with the scanned ones,
+// keeping the order of the snapshot.
+func (i *providerIndex) providersAt(positions []int, scanned []int) []Provider {
+	providers := make([]Provider, 0, len(positions)+len(scanned))
+	snapshot := *i.snapshot
+	p, s := 0, 0
+	for p < len(positions) || s < len(scanned) {
+		if s == len(scanned) || (p < len(positions) && positions[p] < scanned[s]) {
+			providers = append(providers, snapshot[positions[p]])
+			p++
+		} else {
+			providers = append(providers, snapshot[scanned[s]])
+			s++
+		}
+	}
+	return providers
+}
+
+func hasFixedNames(p Provider) bool {
+	if attributed, ok := p.(*attributedProvider); ok {
+		p = attributed.Provider
+	}
+	fixed, ok := p.(fixedNamesProvider)
+	return ok && fixed.hasFixedNames()
+}
+
+func sortedUnique(positions []int) []int {
+	slices.Sort(positions)
+	return slices.Compact(positions)
+}
@@ -0,0 +1,106 @@
+package godi
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolver_ProviderIndex(t *testing.T) {
+	t.Run("it should index the providers by name and by type, in the order of the providers", func(t *testing.T) {
+		// GIVEN
+		resolver := New()
+		resolver.MustRegister(ToStaticProvider(&TestService{Name: "low"}), Named("service"), Priority(-1))
+		resolver.MustRegister(ToStaticProvider(&TestService{Name: "high"}), Named("service"), Priority(10))
+		resolver.MustRegister(ToStaticProvider(&TestRepository{}), Named("repository"))
+
+		// WHEN
+		index := resolver.indexOfProviders()
+
+		// THEN
+		named := index.providersNamed("service")
+		require.Len(t, named, 2)
+		assert.Equal(t, 10, named[0].Priority())
+		assert.Equal(t, -1, named[1].Priority())
+		assert.Len(t, index.providersOfType(TypeOf[*TestService]()), 2)
+		assert.Len(t, index.providersOfType(TypeOf[Closeable]()), 3)
+		assert.Empty(t, index.providersNamed("unknown"))
+	})
+
+	t.Run("it should rebuild the index once the providers changed", func(t *testing.T) {
+		// GIVEN
+		resolver := New()
+		resolver.MustRegister(ToStaticProvider(&TestService{Name: "first"}), Named("service"))
+		before := resolver.indexOfProviders()
+		assert.Same(t, before, resolver.indexOfProviders())
+
+		// WHEN
+		resolver.MustRegister(ToStaticProvider(&TestService{Name: "second"}), Named("other"))
+
+		// THEN
+		after := resolver.indexOfProviders()
+		assert.NotSame(t, before, after)
+		assert.Len(t, after.providersOfType(TypeOf[*TestService]()), 2)
+		assert.Len(t, before.providersOfType(TypeOf[*TestService]()), 1, "the previous snapshot is left untouched")
+
+		require.NoError(t, resolver.Unregister("service"))
+		assert.Empty(t, resolver.indexOfProviders().providersNamed("service"))
+	})
+
+	t.Run("it should always scan the providers whose names are not fixed", func(t *testing.T) {
+		// GIVEN
+		t.Setenv("INDEX_GREETING", "hello")
+		resolver := New()
+		resolver.MustRegister(NewEnvProvider())
+
+		// WHEN
+		greeting, err := ResolveNamed[string](resolver, "INDEX_GREETING")
+
+		// THEN
+		require.NoError(t, err)
+		assert.Equal(t, "hello", greeting)
+	})
+
+	t.Run("it should resolve consistently while registering concurrently", func(t *testing.T) {
+		// GIVEN
+		resolver := New()
+		resolver.MustRegister(ToStaticProvider(&TestRepository{Data: "data"}))
+
+		// WHEN
+		var wg sync.WaitGroup
+		for i := 0; i < 20; i++ {
+			wg.Add(2)
+			go func() {
+				defer wg.Done()
+				resolver.MustRegister(ToStaticProvider(i), Named(fmt.Sprintf("value.%d", i)))
+			}()
+			go func() {
+				defer wg.Done()
+				repository, err := Resolve[*TestRepository](resolver)
+				assert.NoError(t, err)
+				assert.Equal(t, "data", repository.Data)
+			}()
+		}
+		wg.Wait()
+
+		// THEN
+		values, err := ResolveAllNamedAsMap[int](resolver, "value.*")
+		require.NoError(t, err)
+		assert.Len(t, values, 20)
+	})
+}
+
+func BenchmarkResolve_ManyProviders(b *testing.B) {
+	resolver := New()
+	for i := 0; i < 500; i++ {
+		resolver.MustRegister(ToStaticProvider(i), Named(fmt.Sprintf("value.%d", i)))
+	}
+	resolver.MustRegister(func() *TestService { return &TestService{} }, Transient())
+
+	for b.Loop() {
+		_, _ = Resolve[*TestService](resolver)
+	}
+}
@@ -20,6 +20,7 @@ type (
 type namedDependencyBuilder struct {
 	named    string
 	optional bool
+	fallback any
 }
 
 func (i *injectBuilder) Named(name string) *namedDependencyBuilder {
@@ -31,23 +32,40 @@ func (n *namedDependencyBuilder) Optional() *namedDependencyBuilder {
 	return n
 }
 
+// Default makes the dependency optional, and injects the given value if the dependency is not found.
+func (n *namedDependencyBuilder) Default(value any) *namedDependencyBuilder {
+	n.optional = true
+	n.fallback = value
+	return n
+}
+
 func (n *namedDependencyBuilder) build(targetTyp reflect.Type) (Request, error) {
 	var validator validator = validatorUniqueMandatory{}
 	if n.optional {
 		validator = validatorUniqueOptional{}
 	}
+	fallback, err := buildFallback(n.fallback, targetTyp)
+	if err != nil {
+		return Request{}, fmt.Errorf("invalid default value for dependency named %s:\n\t%w", n.named, err)
+	}
+	componentTyp, collector := collectorFor(targetTyp)
+	if _, ok := collector.(collectorOptional); ok {
+		validator = validatorUniqueOptional{}
+	}
 	return Request{
-		unitaryTyp: targetTyp,
+		unitaryTyp: componentTyp,
 		query: queryByName{
-			name: Name{name: n.named, typ: targetTyp},
+			name: Name{name: n.named, typ: componentTyp},
 		},
 		validator: validator,
-		collector: collectorUnique{},
+		collector: collector,
+		fallback:  fallback,
 	}, nil
 }
 
 type autoDependencyBuilder struct {
 	optional bool
+	fallback any
 }
 
 func (i *injectBuilder) Auto() *autoDependencyBuilder {
@@ -59,53 +77,188 @@ func (a *autoDependencyBuilder) Optional() *autoDependencyBuilder {
 	return a
 }
 
+// Default makes the dependency optional, and injects the given value if the dependency is not found.
+func (a *autoDependencyBuilder) Default(value any) *autoDependencyBuilder {
+	a.optional = true
+	a.fallback = value
+	return a
+}
+
 func (a *autoDependencyBuilder) build(targetTyp reflect.Type) (Request, error) {
+	if isInStruct(targetTyp) && !a.optional {
+		return paramsDependency(targetTyp)
+	}
 	var validator validator = validatorUniqueMandatory{}
 	if a.optional {
 		validator = validatorUniqueOptional{}
 	}
+	fallback, err := buildFallback(a.fallback, targetTyp)
+	if err != nil {
+		return Request{}, fmt.Errorf("invalid default value for dependency of type %s:\n\t%w", targetTyp, err)
+	}
+	componentTyp, collector := collectorFor(targetTyp)
+	if _, ok := collector.(collectorOptional); ok {
+		validator = validatorUniqueOptional{}
+	}
 	return Request{
-		unitaryTyp: targetTyp,
+		unitaryTyp: componentTyp,
 		query: queryByType{
-			typ: targetTyp,
+			typ: componentTyp,
 		},
-		validator: validator,
-		collector: collectorUnique{},
+		validator:         validator,
+		collector:         collector,
+		fallback:          fallback,
+		resolutionContext: targetTyp == ContextType,
+		injectionContext:  targetTyp == InjectionContextType,
 	}, nil
 }
 
-type multipleDependencyBuilder struct{}
+type multipleDependencyBuilder struct {
+	filter any
+	cmp    any
+}
+
+func (i *injectBuilder) Multiple() *multipleDependencyBuilder {
+	return &multipleDependencyBuilder{}
+}
+
+// Filtered only injects the components matching the predicate, of type func(T) bool, e.g. to drop the validators
+// disabled by the configuration.
+func (m *multipleDependencyBuilder) Filtered(predicate any) *multipleDependencyBuilder {
+	m.filter = predicate
+	return m
+}
 
-func (i *injectBuilder) Multiple() dependency {
-	return multipleDependencyBuilder{}
+// SortedBy sorts the injected slice with the comparison, of type func(a, b T) int (see slices.SortStableFunc),
+// instead of ordering the components by priority.
+func (m *multipleDependencyBuilder) SortedBy(cmp any) *multipleDependencyBuilder {
+	m.cmp = cmp
+	return m
 }
 
-func (m multipleDependencyBuilder) build(targetTyp reflect.Type) (r Request, err error) {
+func (m *multipleDependencyBuilder) build(targetTyp reflect.Type) (r Request, err error) {
 	if targetTyp.Kind() == reflect.Slice {
 		elemTyp := targetTyp.Elem()
+		collector, err := m.postProcessed(collectorMultipleAsSlice{}, targetTyp)
+		if err != nil {
+			return r, err
+		}
 		return Request{
 			unitaryTyp: elemTyp,
 			query: queryByType{
 				typ: elemTyp,
 			},
 			validator: validatorMultiple{},
-			collector: collectorMultipleAsSlice{},
+			collector: collector,
 		}, nil
 	}
 	if targetTyp.Kind() == reflect.Map {
+		if m.cmp != nil {
+			return r, fmt.Errorf("multiple dependencies can only be sorted with slice types, got %s", targetTyp)
+		}
 		valueTyp := targetTyp.Elem()
+		collector, err := m.postProcessed(collectorMultipleAsMap{}, targetTyp)
+		if err != nil {
+			return r, err
+		}
 		return Request{
 			unitaryTyp: valueTyp,
 			query: queryByType{
 				typ: valueTyp,
 			},
 			validator: validatorMultiple{},
-			collector: collectorMultipleAsMap{},
+			collector: collector,
 		}, nil
 	}
 	return r, fmt.Errorf("multiple dependencies can only be used with slice or map types, got %s", targetTyp)
 }
 
+// postProcessed wraps the collector to filter and sort the collected components, if requested.
+func (m *multipleDependencyBuilder) postProcessed(inner collector, targetTyp reflect.Type) (collector, error) {
+	if m.filter == nil && m.cmp == nil {
+		return inner, nil
+	}
+	processed := collectorPostProcessed{inner: inner}
+	elemTyp := targetTyp.Elem()
+	if m.filter != nil {
+		filter := reflect.ValueOf(m.filter)
+		if !isFuncOf(filter.Type(), []reflect.Type{elemTyp}, boolType) {
+			return nil, fmt.Errorf("the filter of %s must be a func(%s) bool, got %T", targetTyp, elemTyp, m.filter)
+		}
+		processed.filter = filter
+	}
+	if m.cmp != nil {
+		cmp := reflect.ValueOf(m.cmp)
+		if !isFuncOf(cmp.Type(), []reflect.Type{elemTyp, elemTyp}, intType) {
+			return nil, fmt.Errorf("the comparison of %s must be a func(a, b %s) int, got %T", targetTyp, elemTyp, m.cmp)
+		}
+		processed.cmp = cmp
+	}
+	return processed, nil
+}
+
+// isFuncOf returns whether the type is a function taking the given arguments, and returning the given result.
+func isFuncOf(fnTyp reflect.Type, args []reflect.Type, result reflect.Type) bool {
+	if fnTyp.Kind() != reflect.Func || fnTyp.IsVariadic() || fnTyp.NumIn() != len(args) || fnTyp.NumOut() != 1 {
+		return false
+	}
+	for idx, arg := range args {
+		if !arg.AssignableTo(fnTyp.In(idx)) {
+			return false
+		}
+	}
+	return fnTyp.Out(0) == result
+}
+
+type factoryDependencyBuilder struct{}
+
+// Factory injects a `func() (T, error)` building a fresh instance of T on every call, instead of a singleton.
+// The caller owns the instances, they are not closed with the resolver.
+func (i *injectBuilder) Factory() dependency {
+	return factoryDependencyBuilder{}
+}
+
+func (f factoryDependencyBuilder) build(targetTyp reflect.Type) (r Request, err error) {
+	if targetTyp.Kind() != reflect.Func ||
+		targetTyp.NumIn() != 0 ||
+		targetTyp.NumOut() != 2 ||
+		targetTyp.Out(1) != ErrorType {
+		return r, fmt.Errorf("factory dependencies can only be used with func() (T, error) types, got %s", targetTyp)
+	}
+	componentTyp := targetTyp.Out(0)
+	return Request{
+		unitaryTyp: componentTyp,
+		query: queryByType{
+			typ: componentTyp,
+		},
+		validator: validatorUniqueMandatory{},
+		collector: collectorFactory{factoryTyp: targetTyp},
+	}, nil
+}
+
 func defaultDependencyBuilder() dependency {
 	return &autoDependencyBuilder{}
 }
+
+// collectorFor returns the type of the component to inject into the target type, along with its collector:
+// an Optional is resolved as the type of its component.
+func collectorFor(targetTyp reflect.Type) (reflect.Type, collector) {
+	if componentTyp, ok := optionalComponentType(targetTyp); ok {
+		return componentTyp, collectorOptional{optionalTyp: targetTyp}
+	}
+	return targetTyp, collectorUnique{}
+}
+
+// buildFallback converts the default value of a dependency to the target type, if any.
+func buildFallback(value any, targetTyp reflect.Type) (*reflect.Value, error) {
+	if value == nil {
+		return nil, nil
+	}
+	valueOf := reflect.ValueOf(value)
+	if !valueOf.Type().AssignableTo(targetTyp) {
+		return nil, fmt.Errorf("value of type %s is not assignable to %s", valueOf.Type(), targetTyp)
+	}
+	fallback := reflect.New(targetTyp).Elem()
+	fallback.Set(valueOf)
+	return &fallback, nil
+}
@@ -0,0 +1,66 @@
+package godi
+
+import "slices"
+
+// InjectionContext describes where a component is injected. A provider taking it as a parameter builds a component
+// for each consumer, so the providers depending on it are always transient (see Transient), e.g. a logger tagged with
+// the name of the component it is injected into:
+//
+//	func NewLogger(ic godi.InjectionContext) *slog.Logger {
+//		return slog.Default().With("component", ic.Target.Name())
+//	}
+type InjectionContext struct {
+	// Name is the name of the component being built with the injection context.
+	Name Name
+	// Target is the name of the component it is injected into, zero for a top level resolution.
+	Target Name
+	// Provider is the provider of the target, nil for a top level resolution.
+	Provider Provider
+	// Path are the names of the components being built, from the root request to the target.
+	Path []Name
+}
+
+var InjectionContextType = TypeOf[InjectionContext]()
+
+// topLevelConsumer is the consumer of the components built for a top level resolution, see PerConsumer.
+const topLevelConsumer = "<top level>"
+
+// forConsumer returns the name under which the component built for the consumer is stored, see PerConsumer.
+func (n Name) forConsumer(consumer Name) Name {
+	n.consumer = topLevelConsumer
+	if consumer.typ != nil {
+		n.consumer = consumer.String()
+	}
+	return n
+}
+
+// top returns the name of the component on the top of the stack, zero if the stack is empty.
+func (tracker *Tracker) top() Name {
+	if len(tracker.stack) == 0 {
+		return Name{}
+	}
+	return tracker.stack[len(tracker.stack)-1]
+}
+
+// injectionContext returns the injection context of the component on the top of the stack.
+func (tracker *Tracker) injectionContext() InjectionContext {
+	if len(tracker.stack) == 0 {
+		return InjectionContext{}
+	}
+	ic := InjectionContext{
+		Name: tracker.stack[len(tracker.stack)-1],
+		Path: slices.Clone(tracker.stack[:len(tracker.stack)-1]),
+	}
+	if len(ic.Path) > 0 {
+		ic.Target = ic.Path[len(ic.Path)-1]
+		ic.Provider = tracker.providers[ic.Target]
+	}
+	return ic
+}
+
+// dependsOnInjectionContext checks if the provider takes an InjectionContext parameter.
+func dependsOnInjectionContext(p Provider) bool {
+	return slices.ContainsFunc(p.Dependencies(), func(req Request) bool {
+		return req.injectionContext
+	})
+}
@@ -0,0 +1,163 @@
+package godi
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type taggedLogger struct {
+	component string
+	path      []Name
+}
+
+func TestInjectionContext(t *testing.T) {
+	newLogger := func(ic InjectionContext) *taggedLogger {
+		return &taggedLogger{component: ic.Target.Name(), path: ic.Path}
+	}
+
+	t.Run("it should inject the context of the injection, with the component it is injected into", func(t *testing.T) {
+		// GIVEN
+		resolver := New()
+		resolver.MustRegister(newLogger)
+		resolver.MustRegister(func(logger *taggedLogger) *TestRepository {
+			return &TestRepository{Data: logger.component}
+		}, Named("repository"))
+		resolver.MustRegister(func(logger *taggedLogger, repository *TestRepository) *TestService {
+			return &TestService{Name: logger.component + " uses " + repository.Data}
+		}, Named("service"))
+
+		// WHEN
+		service, err := ResolveNamed[*TestService](resolver, "service")
+
+		// THEN
+		require.NoError(t, err)
+		assert.Equal(t, "service uses repository", service.Name)
+	})
+
+	t.Run("it should give the path from the root request, and the provider of the target", func(t *testing.T) {
+		// GIVEN
+		var captured InjectionContext
+		resolver := New()
+		resolver.MustRegister(func(ic InjectionContext) *taggedLogger {
+			captured = ic
+			return &taggedLogger{}
+		}, Named("logger"))
+		resolver.MustRegister(func(*taggedLogger) *TestRepository { return &TestRepository{} }, Named("repository"))
+		resolver.MustRegister(func(*TestRepository) *TestService { return &TestService{} }, Named("service"))
+
+		// WHEN
+		_, err := ResolveNamed[*TestService](resolver, "service")
+
+		// THEN
+		require.NoError(t, err)
+		assert.Equal(t, "logger", captured.Name.Name())
+		assert.Equal(t, "repository", captured.Target.Name())
+		assert.Equal(t, []string{"service", "repository"}, []string{captured.Path[0].Name(), captured.Path[1].Name()})
+		require.NotNil(t, captured.Provider)
+		assert.True(t, captured.Provider.CanProvide(captured.Target))
+	})
+
+	t.Run("it should give an empty target for a top level resolution", func(t *testing.T) {
+		// GIVEN
+		resolver := New()
+		resolver.MustRegister(newLogger)
+
+		// WHEN
+		logger, err := Resolve[*taggedLogger](resolver)
+
+		// THEN
+		require.NoError(t, err)
+		assert.Empty(t, logger.component)
+		assert.Empty(t, logger.path)
+	})
+
+	t.Run("it should build a component for each consumer", func(t *testing.T) {
+		// GIVEN
+		resolver := New()
+		resolver.MustRegister(newLogger)
+		resolver.MustRegister(func(logger *taggedLogger) *TestRepository { return &TestRepository{Data: logger.component} }, Named("repository"))
+		resolver.MustRegister(func(logger *taggedLogger) *TestService { return &TestService{Name: logger.component} }, Named("service"))
+
+		// WHEN
+		repository := MustResolveNamed[*TestRepository](resolver, "repository")
+		service := MustResolveNamed[*TestService](resolver, "service")
+
+		// THEN
+		assert.Equal(t, "repository", repository.Data)
+		assert.Equal(t, "service", service.Name)
+		for _, p := range resolver.Providers() {
+			if p.Names[0].Type() == TypeOf[*taggedLogger]() {
+				assert.Equal(t, LifetimeTransient, p.Lifetime)
+			}
+		}
+		assert.NoError(t, resolver.Validate())
+	})
+}
+
+func TestPerConsumer(t *testing.T) {
+	t.Run("it should build a component for each consumer, and reuse it", func(t *testing.T) {
+		// GIVEN
+		var built int
+		resolver := New()
+		resolver.MustRegister(func(ic InjectionContext) *taggedLogger {
+			built++
+			return &taggedLogger{component: ic.Target.Name()}
+		}, PerConsumer())
+		resolver.MustRegister(func(logger *taggedLogger) *TestRepository { return &TestRepository{Data: logger.component} }, Named("repository"), Transient())
+		resolver.MustRegister(func(logger *taggedLogger, _ *TestRepository) *TestService {
+			return &TestService{Name: logger.component}
+		}, Named("service"))
+
+		// WHEN
+		service := MustResolveNamed[*TestService](resolver, "service")
+		repository1 := MustResolveNamed[*TestRepository](resolver, "repository")
+		repository2 := MustResolveNamed[*TestRepository](resolver, "repository")
+
+		// THEN
+		assert.Equal(t, "service", service.Name)
+		assert.Equal(t, "repository", repository1.Data)
+		assert.NotSame(t, repository1, repository2, "the repository is transient")
+		assert.Equal(t, 2, built, "the logger of the repository is reused")
+	})
+
+	t.Run("it should share the component between the top level resolutions", func(t *testing.T) {
+		// GIVEN
+		resolver := New()
+		resolver.MustRegister(func() *taggedLogger { return &taggedLogger{} }, PerConsumer())
+		resolver.MustRegister(func(logger *taggedLogger) *TestService { return &TestService{} }, Named("service"))
+
+		// WHEN
+		logger1 := MustResolve[*taggedLogger](resolver)
+		_ = MustResolveNamed[*TestService](resolver, "service")
+		logger2 := MustResolve[*taggedLogger](resolver)
+
+		// THEN
+		assert.Same(t, logger1, logger2)
+		assert.Len(t, resolver.store.ListNames(), 3, "the logger of the top level resolutions, the one of the service, and the service")
+	})
+
+	t.Run("it should close the components built for the consumers once unregistered", func(t *testing.T) {
+		// GIVEN
+		var closed int
+		resolver := New()
+		resolver.MustRegister(func() closeFunc {
+			return func() error {
+				closed++
+				return nil
+			}
+		}, Named("closer"), PerConsumer())
+		resolver.MustRegister(func(closeFunc) *TestService { return &TestService{} }, Named("service"))
+		resolver.MustRegister(func(closeFunc) *TestRepository { return &TestRepository{} }, Named("repository"))
+		_ = MustResolveNamed[*TestService](resolver, "service")
+		_ = MustResolveNamed[*TestRepository](resolver, "repository")
+
+		// WHEN
+		err := resolver.Unregister("closer")
+
+		// THEN
+		require.NoError(t, err)
+		assert.Equal(t, 2, closed)
+	})
+}
@@ -0,0 +1,226 @@
+package godi
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+const (
+	LifetimeSingleton = "singleton"
+	LifetimeTransient = "transient"
+	LifetimeScoped    = "scoped"
+	// LifetimePerConsumer is the lifetime of the components built for each consumer, see PerConsumer.
+	LifetimePerConsumer = "per-consumer"
+)
+
+type (
+	// ProviderInfo describes a registered provider, see Resolver.Providers.
+	ProviderInfo struct {
+		// Source is the function of the provider with its location, or the description of the provider.
+		Source      string
+		Names       []Name
+		Priority    int
+		Version     string
+		Description string
+		// Lifetime is one of LifetimeSingleton, LifetimeTransient, LifetimeScoped or LifetimePerConsumer.
+		Lifetime     string
+		Eager        bool
+		Hidden       bool
+		Tags         []string
+		ExposedAs    []reflect.Type
+		Dependencies []DependencyInfo
+		// Conditions describe the registration conditions, they all held.
+		Conditions []string
+	}
+
+	// DependencyInfo describes a dependency of a provider.
+	DependencyInfo struct {
+		// Name is empty for the dependencies injected by type.
+		Name     string
+		Type     reflect.Type
+		Optional bool
+		Multiple bool
+	}
+
+	// Explanation describes how the components with a given name are resolved, see Resolver.Explain.
+	Explanation struct {
+		Name string
+		// Candidates are the providers of the name, the first one providing a given Name wins.
+		Candidates []ExplainedCandidate
+		// Skipped are the providers of the name whose registration was skipped, as a condition did not hold.
+		Skipped []SkippedProvider
+	}
+
+	// ExplainedCandidate is a provider of a name, along with the reason it wins or not.
+	ExplainedCandidate struct {
+		Name     Name
+		Provider ProviderInfo
+		Selected bool
+		Reason   string
+	}
+
+	// SkippedProvider is a provider whose registration was skipped, along with the condition which did not hold.
+	SkippedProvider struct {
+		Provider  ProviderInfo
+		Condition string
+	}
+
+	skippedRegistration struct {
+		provider  Provider
+		condition Condition
+	}
+)
+
+// Providers returns the description of the registered providers, from the highest to the lowest precedence,
+// the hidden ones included.
+func (r *Resolver) Providers() []ProviderInfo {
+	providers := r.providers.All()
+	infos := make([]ProviderInfo, 0, len(providers))
+	for _, p := range providers {
+		infos = append(infos, providerInfoOf(p))
+	}
+	return infos
+}
+
+// Explain describes which provider provides the components with the given name, and why the others do not:
+// the providers are ordered by priority, then by version, the last registered winning for the same priority.
+// The providers whose registration was skipped as a condition did not hold are listed too.
+func (r *Resolver) Explain(name string) Explanation {
+	explanation := Explanation{Name: name}
+
+	winners := make(map[Name]Provider)
+	for _, p := range r.providers.All() {
+		for _, n := range p.ListProvidableNames() {
+			if n.name != name {
+				continue
+			}
+			candidate := ExplainedCandidate{Name: n, Provider: providerInfoOf(p)}
+			key := Name{name: n.name, typ: n.typ}
+			if winner, found := winners[key]; found {
+				candidate.Reason = shadowingReason(winner, p)
+			} else {
+				winners[key] = p
+				candidate.Selected = true
+				candidate.Reason = fmt.Sprintf("provides %s with the highest priority (%d)", n, p.Priority())
+			}
+			explanation.Candidates = append(explanation.Candidates, candidate)
+		}
+	}
+
+	if skipped := r.skipped.Load(); skipped != nil {
+		for _, s := range *skipped {
+			for _, n := range s.provider.ListProvidableNames() {
+				if n.name == name {
+					explanation.Skipped = append(explanation.Skipped, SkippedProvider{
+						Provider:  providerInfoOf(s.provider),
+						Condition: describeCondition(s.condition),
+					})
+					break
+				}
+			}
+		}
+	}
+	return explanation
+}
+
+func (e Explanation) String() string {
+	var b strings.Builder
+	b.WriteString(fmt.Sprintf("* %s:\n", e.Name))
+	if len(e.Candidates) == 0 {
+		b.WriteString("\tno provider registered\n")
+	}
+	for _, c := range e.Candidates {
+		marker := "-"
+		if c.Selected {
+			marker = "+"
+		}
+		b.WriteString(fmt.Sprintf("\t%s %s: %s\n", marker, c.Provider.Source, c.Reason))
+	}
+	for _, s := range e.Skipped {
+		b.WriteString(fmt.Sprintf("\t- %s: skipped, the condition %s did not hold\n", s.Provider.Source, s.Condition))
+	}
+	return b.String()
+}
+
+// shadowingReason explains why the provider is shadowed by the winner, which has the precedence over it.
+func shadowingReason(winner Provider, p Provider) string {
+	switch {
+	case winner.Priority() != p.Priority():
+		return fmt.Sprintf("shadowed by %s, with a higher priority (%d > %d)", describeProvider(winner), winner.Priority(), p.Priority())
+	case attributesOf(winner).version != attributesOf(p).version:
+		return fmt.Sprintf("shadowed by %s, with a higher version (%s > %s)", describeProvider(winner), attributesOf(winner).version, attributesOf(p).version)
+	default:
+		return fmt.Sprintf("shadowed by %s, registered later with the same priority (%d)", describeProvider(winner), p.Priority())
+	}
+}
+
+func (r *Resolver) recordSkipped(skipped skippedRegistration) {
+	for {
+		current := r.skipped.Load()
+		var updated []skippedRegistration
+		if current != nil {
+			updated = append(updated, *current...)
+		}
+		updated = append(updated, skipped)
+		if r.skipped.CompareAndSwap(current, &updated) {
+			return
+		}
+	}
+}
+
+func providerInfoOf(p Provider) ProviderInfo {
+	attributes := attributesOf(p)
+	info := ProviderInfo{
+		Source:      describeProvider(p),
+		Names:       p.ListProvidableNames(),
+		Priority:    p.Priority(),
+		Version:     attributes.version,
+		Description: p.Description(),
+		Lifetime:    LifetimeSingleton,
+		Eager:       attributes.eager,
+		Hidden:      attributes.hidden,
+		Tags:        attributes.tags,
+		ExposedAs:   attributes.exposedAs,
+	}
+	switch {
+	case attributes.transient:
+		info.Lifetime = LifetimeTransient
+	case attributes.perConsumer:
+		info.Lifetime = LifetimePerConsumer
+	case attributes.scoped:
+		info.Lifetime = LifetimeScoped
+	}
+	dependencies := p.Dependencies()
+	if multi, ok := unwrapped(p).(*multiResultProvider); ok {
+		dependencies = multi.call.Dependencies() // rather than the results of the factory method
+	}
+	for _, request := range dependencies {
+		if params, ok := request.query.(queryParams); ok {
+			// the fields of the parameter struct are the actual dependencies, see In
+			for _, field := range params.provider.Dependencies() {
+				info.Dependencies = append(info.Dependencies, dependencyInfoOf(field))
+			}
+			continue
+		}
+		info.Dependencies = append(info.Dependencies, dependencyInfoOf(request))
+	}
+	for _, cond := range attributes.conditions {
+		info.Conditions = append(info.Conditions, describeCondition(cond))
+	}
+	return info
+}
+
+func dependencyInfoOf(request Request) DependencyInfo {
+	info := DependencyInfo{Type: request.unitaryTyp}
+	if byName, ok := request.query.(queryByName); ok {
+		info.Name = byName.name.name
+	}
+	switch request.validator.(type) {
+	case validatorUniqueOptional:
+		info.Optional = true
+	case validatorMultiple:
+		info.Multiple = true
+	}
+	return info
+}
@@ -0,0 +1,116 @@
+package godi
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolver_Providers(t *testing.T) {
+	t.Run("it should describe the registered providers", func(t *testing.T) {
+		// GIVEN
+		t.Setenv("GODI_INSPECT", "on")
+		resolver := New()
+		resolver.MustRegister(
+			func(prefix string, numbers []int) fmt.Stringer { return nil },
+			Named("greeter"),
+			Priority(10),
+			Description("greets the users"),
+			Dependencies(Inject.Named("prefix").Optional(), Inject.Multiple()),
+			Transient(),
+			Tagged("greeting"),
+			WhenEnv("GODI_INSPECT").Equals("on"),
+		)
+
+		// WHEN
+		providers := resolver.Providers()
+
+		// THEN
+		require.Len(t, providers, 2, "the resolver registers itself")
+		greeter := providers[0]
+		assert.Contains(t, greeter.Source, "TestResolver_Providers")
+		assert.Equal(t, []Name{{name: "greeter", typ: reflect.TypeOf((*fmt.Stringer)(nil)).Elem()}}, greeter.Names)
+		assert.Equal(t, 10, greeter.Priority)
+		assert.Equal(t, "greets the users", greeter.Description)
+		assert.Equal(t, LifetimeTransient, greeter.Lifetime)
+		assert.Equal(t, []string{"greeting"}, greeter.Tags)
+		assert.Equal(t, []DependencyInfo{
+			{Name: "prefix", Type: StringType, Optional: true},
+			{Type: reflect.TypeOf(0), Multiple: true},
+		}, greeter.Dependencies)
+		assert.Equal(t, []string{`env GODI_INSPECT equals "on"`}, greeter.Conditions)
+		assert.True(t, providers[1].Hidden)
+	})
+}
+
+func TestResolver_Explain(t *testing.T) {
+	t.Run("it should explain which provider wins for a name", func(t *testing.T) {
+		// GIVEN
+		resolver := New()
+		resolver.MustRegister(func() string { return "low" }, Named("greeting"))
+		resolver.MustRegister(func() string { return "high" }, Named("greeting"), Priority(10))
+		resolver.MustRegister(func() string { return "last" }, Named("greeting"))
+		resolver.MustRegister(func() string { return "other" }, Named("other"))
+
+		// WHEN
+		explanation := resolver.Explain("greeting")
+
+		// THEN
+		require.Len(t, explanation.Candidates, 3)
+		assert.True(t, explanation.Candidates[0].Selected)
+		assert.Equal(t, 10, explanation.Candidates[0].Provider.Priority)
+		assert.Equal(t, "provides (greeting, string) with the highest priority (10)", explanation.Candidates[0].Reason)
+		assert.False(t, explanation.Candidates[1].Selected)
+		assert.Contains(t, explanation.Candidates[1].Reason, "with a higher priority (10 > 0)")
+		assert.Contains(t, explanation.Candidates[2].Reason, "with a higher priority (10 > 0)")
+		assert.Equal(t, "high", MustResolveNamed[string](resolver, "greeting"))
+	})
+
+	t.Run("it should explain the providers shadowed by a provider registered later", func(t *testing.T) {
+		// GIVEN
+		resolver := New()
+		resolver.MustRegister(func() string { return "first" }, Named("greeting"))
+		resolver.MustRegister(func() string { return "last" }, Named("greeting"))
+
+		// WHEN
+		explanation := resolver.Explain("greeting")
+
+		// THEN
+		require.Len(t, explanation.Candidates, 2)
+		assert.True(t, explanation.Candidates[0].Selected)
+		assert.Contains(t, explanation.Candidates[1].Reason, "registered later with the same priority (0)")
+		assert.Equal(t, "last", MustResolveNamed[string](resolver, "greeting"))
+	})
+
+	t.Run("it should list the providers skipped by a condition", func(t *testing.T) {
+		// GIVEN
+		resolver := New()
+		resolver.MustRegister(func() string { return "default" }, Named("greeting"))
+		resolver.MustRegister(func() string { return "linux" }, Named("greeting"), Priority(10), WhenOS("plan9"))
+
+		// WHEN
+		explanation := resolver.Explain("greeting")
+
+		// THEN
+		require.Len(t, explanation.Candidates, 1)
+		require.Len(t, explanation.Skipped, 1)
+		assert.Equal(t, `os in ["plan9"]`, explanation.Skipped[0].Condition)
+		assert.Equal(t, 10, explanation.Skipped[0].Provider.Priority)
+		assert.Contains(t, explanation.String(), `skipped, the condition os in ["plan9"] did not hold`)
+	})
+
+	t.Run("it should explain that nothing provides an unknown name", func(t *testing.T) {
+		// GIVEN
+		resolver := New()
+
+		// WHEN
+		explanation := resolver.Explain("unknown")
+
+		// THEN
+		assert.Empty(t, explanation.Candidates)
+		assert.Equal(t, "* unknown:\n\tno provider registered\n", explanation.String())
+	})
+}
@@ -0,0 +1,78 @@
+package godi
+
+import (
+	"context"
+	"reflect"
+	"time"
+)
+
+type (
+	// Interceptor wraps the invocations of the providers, e.g. for tracing, metrics or logging.
+	//
+	// The interceptor must call proceed to invoke the provider (or the next interceptor), it can then inspect,
+	// or even replace, the provided component and the error.
+	Interceptor interface {
+		Intercept(invocation Invocation, proceed func() (reflect.Value, error)) (reflect.Value, error)
+	}
+
+	// InterceptorFunc is a helper to create Interceptor from a function.
+	InterceptorFunc func(invocation Invocation, proceed func() (reflect.Value, error)) (reflect.Value, error)
+
+	// Invocation describes the invocation of a provider.
+	Invocation struct {
+		Name         Name
+		Provider     Provider
+		Dependencies []reflect.Value
+		// Context is the context of the resolution (see ResolveCtx), context.Background() if it has none.
+		Context context.Context
+	}
+)
+
+// Use adds an interceptor wrapping the invocations of the providers, the first added interceptor is the outermost.
+//
+// Scopes and forks inherit the interceptors of the resolver at their creation.
+func (r *Resolver) Use(interceptor Interceptor) *Resolver {
+	for {
+		current := r.interceptors.Load()
+		var updated []Interceptor
+		if current != nil {
+			updated = append(updated, *current...)
+		}
+		updated = append(updated, interceptor)
+		if r.interceptors.CompareAndSwap(current, &updated) {
+			return r
+		}
+	}
+}
+
+// Observe creates an interceptor calling the given function after every invocation of a provider,
+// with the duration of the invocation, and its error if any.
+func Observe(observer func(invocation Invocation, duration time.Duration, err error)) Interceptor {
+	return InterceptorFunc(func(invocation Invocation, proceed func() (reflect.Value, error)) (reflect.Value, error) {
+		start := time.Now()
+		comp, err := proceed()
+		observer(invocation, time.Since(start), err)
+		return comp, err
+	})
+}
+
+func (f InterceptorFunc) Intercept(invocation Invocation, proceed func() (reflect.Value, error)) (reflect.Value, error) {
+	return f(invocation, proceed)
+}
+
+// intercept invokes the provider through the interceptors of the resolver, if any.
+func (r *Resolver) intercept(invocation Invocation, provide func() (reflect.Value, error)) (reflect.Value, error) {
+	interceptors := r.interceptors.Load()
+	if interceptors == nil {
+		return provide()
+	}
+
+	proceed := provide
+	for i := len(*interceptors) - 1; i >= 0; i-- {
+		interceptor, next := (*interceptors)[i], proceed
+		proceed = func() (reflect.Value, error) {
+			return interceptor.Intercept(invocation, next)
+		}
+	}
+	return proceed()
+}
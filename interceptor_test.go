@@ -0,0 +1,142 @@
+package godi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolver_Use(t *testing.T) {
+	t.Run("it should wrap the invocations of the providers", func(t *testing.T) {
+		// GIVEN
+		resolver := New()
+		resolver.MustRegister(NewTestService)
+		resolver.MustRegister(NewTestRepository)
+		resolver.MustRegister(NewTestController)
+		var invocations []string
+		resolver.Use(InterceptorFunc(func(invocation Invocation, proceed func() (reflect.Value, error)) (reflect.Value, error) {
+			invocations = append(invocations, fmt.Sprintf("%s with %d dependencies", invocation.Name, len(invocation.Dependencies)))
+			return proceed()
+		}))
+
+		// WHEN
+		_, err := Resolve[*TestController](resolver)
+
+		// THEN
+		require.NoError(t, err)
+		assert.Equal(t, []string{
+			"(godi.NewTestService, *godi.TestService) with 0 dependencies",
+			"(godi.NewTestRepository, *godi.TestRepository) with 0 dependencies",
+			"(godi.NewTestController, *godi.TestController) with 2 dependencies",
+		}, invocations)
+	})
+
+	t.Run("it should apply the first interceptor as the outermost", func(t *testing.T) {
+		// GIVEN
+		resolver := New()
+		resolver.MustRegister(NewTestService)
+		var calls []string
+		tracing := func(label string) Interceptor {
+			return InterceptorFunc(func(_ Invocation, proceed func() (reflect.Value, error)) (reflect.Value, error) {
+				calls = append(calls, "before "+label)
+				defer func() { calls = append(calls, "after "+label) }()
+				return proceed()
+			})
+		}
+		resolver.Use(tracing("outer")).Use(tracing("inner"))
+
+		// WHEN
+		_, err := Resolve[*TestService](resolver)
+
+		// THEN
+		require.NoError(t, err)
+		assert.Equal(t, []string{"before outer", "before inner", "after inner", "after outer"}, calls)
+	})
+
+	t.Run("it should observe the duration and the error of the invocations", func(t *testing.T) {
+		// GIVEN
+		resolver := New()
+		resolver.MustRegister(func() (*TestService, error) {
+			time.Sleep(10 * time.Millisecond)
+			return nil, errors.New("boom")
+		})
+		var (
+			observedDuration time.Duration
+			observedErr      error
+		)
+		resolver.Use(Observe(func(_ Invocation, duration time.Duration, err error) {
+			observedDuration = duration
+			observedErr = err
+		}))
+
+		// WHEN
+		_, err := Resolve[*TestService](resolver)
+
+		// THEN
+		require.Error(t, err)
+		assert.GreaterOrEqual(t, observedDuration, 10*time.Millisecond)
+		assert.EqualError(t, observedErr, "boom")
+	})
+
+	t.Run("it should allow to replace the provided component", func(t *testing.T) {
+		// GIVEN
+		resolver := New()
+		resolver.MustRegister(NewTestService)
+		resolver.Use(InterceptorFunc(func(_ Invocation, proceed func() (reflect.Value, error)) (reflect.Value, error) {
+			comp, err := proceed()
+			if service, ok := comp.Interface().(*TestService); ok {
+				service.Name = "intercepted"
+			}
+			return comp, err
+		}))
+
+		// WHEN
+		service, err := Resolve[*TestService](resolver)
+
+		// THEN
+		require.NoError(t, err)
+		assert.Equal(t, "intercepted", service.Name)
+	})
+
+	t.Run("it should intercept the invocations of a scope", func(t *testing.T) {
+		// GIVEN
+		resolver := New()
+		resolver.MustRegister(NewTestService, Scoped())
+		intercepted := 0
+		resolver.Use(Observe(func(Invocation, time.Duration, error) { intercepted++ }))
+		scope := resolver.NewScope()
+
+		// WHEN
+		_, err := Resolve[*TestService](scope)
+
+		// THEN
+		require.NoError(t, err)
+		assert.Equal(t, 1, intercepted)
+	})
+
+	t.Run("it should give the context of the resolution to the interceptors", func(t *testing.T) {
+		// GIVEN
+		type ctxKey struct{}
+		resolver := New()
+		resolver.MustRegister(NewTestService)
+		var value any
+		resolver.Use(InterceptorFunc(func(invocation Invocation, proceed func() (reflect.Value, error)) (reflect.Value, error) {
+			value = invocation.Context.Value(ctxKey{})
+			return proceed()
+		}))
+		ctx := context.WithValue(context.Background(), ctxKey{}, "traced")
+
+		// WHEN
+		_, err := ResolveCtx[*TestService](ctx, resolver)
+
+		// THEN
+		require.NoError(t, err)
+		assert.Equal(t, "traced", value)
+	})
+}
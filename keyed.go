@@ -0,0 +1,109 @@
+package godi
+
+import (
+	"fmt"
+	"reflect"
+)
+
+type (
+	// Keyed is implemented by the components keyed by a domain key (e.g. a topic, a currency code) in the maps
+	// injected with Inject.MultipleKeyed, instead of their name.
+	Keyed interface {
+		Key() string
+	}
+
+	// collectorMultipleAsKeyedMap collects the components in a map keyed by a function of the components,
+	// see Inject.MultipleKeyed.
+	collectorMultipleAsKeyedMap struct {
+		mapTyp reflect.Type
+		keyOf  reflect.Value
+	}
+)
+
+// ResolveAllKeyed resolves all the components of type T, keyed by the given function, e.g. the currency code
+// of the payment providers. Two components with the same key are reported as an error.
+func ResolveAllKeyed[K comparable, T any](resolver *Resolver, keyOf func(T) K) (map[K]T, error) {
+	lookFor := reflect.TypeOf((*T)(nil)).Elem()
+
+	val, _, err := resolveTyped[map[K]T](
+		resolver,
+		Request{
+			unitaryTyp: lookFor,
+			query:      queryByType{typ: lookFor},
+			validator:  validatorMultiple{},
+			collector:  collectorMultipleAsKeyedMap{mapTyp: TypeOf[map[K]T](), keyOf: reflect.ValueOf(keyOf)},
+		},
+	)
+	return val, err
+}
+
+type keyedDependencyBuilder struct {
+	keyOf any
+}
+
+// MultipleKeyed injects all the components of the value type of a map, keyed by the given function of type
+// func(T) K instead of their name, e.g. func(c Consumer) string { return c.Topic() }. The components implementing
+// Keyed are keyed by their Key method when the function is nil. Two components with the same key are reported
+// as an error.
+func (i *injectBuilder) MultipleKeyed(keyOf any) dependency {
+	return keyedDependencyBuilder{keyOf: keyOf}
+}
+
+func (k keyedDependencyBuilder) build(targetTyp reflect.Type) (r Request, err error) {
+	if targetTyp.Kind() != reflect.Map {
+		return r, fmt.Errorf("keyed dependencies can only be used with map types, got %s", targetTyp)
+	}
+	keyOf := reflect.ValueOf(k.keyOf)
+	if k.keyOf == nil {
+		keyOf = reflect.ValueOf(Keyed.Key)
+	}
+	fnTyp := keyOf.Type()
+	if fnTyp.Kind() != reflect.Func || fnTyp.NumIn() != 1 || fnTyp.NumOut() != 1 || fnTyp.IsVariadic() {
+		return r, fmt.Errorf("the key of the components must be given by a func(T) K, got %s", fnTyp)
+	}
+	if fnTyp.Out(0) != targetTyp.Key() {
+		return r, fmt.Errorf("the key function %s does not return the keys of %s", fnTyp, targetTyp)
+	}
+	valueTyp := targetTyp.Elem()
+	if !valueTyp.AssignableTo(fnTyp.In(0)) && fnTyp.In(0).Kind() != reflect.Interface {
+		return r, fmt.Errorf("the key function %s does not take the values of %s", fnTyp, targetTyp)
+	}
+	return Request{
+		unitaryTyp: valueTyp,
+		query: queryByType{
+			typ: valueTyp,
+		},
+		validator: validatorMultiple{},
+		collector: collectorMultipleAsKeyedMap{mapTyp: targetTyp, keyOf: keyOf},
+	}, nil
+}
+
+func (c collectorMultipleAsKeyedMap) collect(_ reflect.Type, r *Resolver, results []*queryResult, tracker *Tracker) (val reflect.Value, found bool, err error) {
+	mapValue := reflect.MakeMapWithSize(c.mapTyp, len(results))
+	keyedBy := make(map[any]Name, len(results))
+	argTyp := c.keyOf.Type().In(0)
+	for _, result := range results {
+		comp, _, err := extractComponentFromResult(r, result, tracker)
+		if err != nil {
+			return reflect.Value{}, false, err
+		}
+
+		// the components of an interface are only known to implement the argument of the function once built
+		if !comp.Type().AssignableTo(argTyp) {
+			return reflect.Value{}, false, fmt.Errorf("component %s of type %s can not be keyed by %s", result.name, comp.Type(), c.keyOf.Type())
+		}
+		key := c.keyOf.Call([]reflect.Value{comp})[0]
+		if other, duplicated := keyedBy[key.Interface()]; duplicated {
+			return reflect.Value{}, false, fmt.Errorf("components %s and %s have the same key %v", other, result.name, key)
+		}
+		keyedBy[key.Interface()] = result.name
+
+		mapValue.SetMapIndex(key, comp)
+	}
+
+	return mapValue, true, nil
+}
+
+func (c collectorMultipleAsKeyedMap) String() string {
+	return fmt.Sprintf("<📦 multiple as map keyed by %s>", c.keyOf.Type())
+}
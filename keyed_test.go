@@ -0,0 +1,100 @@
+package godi
+
+import (
+	"maps"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type currencyProvider struct {
+	currency string
+}
+
+func (c *currencyProvider) Key() string {
+	return c.currency
+}
+
+func TestMultipleKeyed(t *testing.T) {
+	t.Run("it should inject the components keyed by the given function", func(t *testing.T) {
+		// GIVEN
+		resolver := New()
+		resolver.MustRegister(func() *TestRepository { return &TestRepository{Data: "orders"} }, Named("repository.orders"))
+		resolver.MustRegister(func() *TestRepository { return &TestRepository{Data: "payments"} }, Named("repository.payments"))
+		var injected map[string]*TestRepository
+		resolver.MustRegister(func(repositories map[string]*TestRepository) *TestService {
+			injected = repositories
+			return &TestService{}
+		}, Dependencies(Inject.MultipleKeyed(func(r *TestRepository) string { return r.Data })))
+
+		// WHEN
+		_, err := Resolve[*TestService](resolver)
+
+		// THEN
+		require.NoError(t, err)
+		assert.ElementsMatch(t, []string{"orders", "payments"}, slices.Collect(maps.Keys(injected)))
+		assert.Equal(t, "payments", injected["payments"].Data)
+	})
+
+	t.Run("it should key the components by their Key method", func(t *testing.T) {
+		// GIVEN
+		resolver := New()
+		resolver.MustRegister(func() *currencyProvider { return &currencyProvider{currency: "EUR"} }, Named("stripe"))
+		resolver.MustRegister(func() *currencyProvider { return &currencyProvider{currency: "USD"} }, Named("paypal"))
+		var injected map[string]*currencyProvider
+		resolver.MustRegister(func(providers map[string]*currencyProvider) *TestService {
+			injected = providers
+			return &TestService{}
+		}, Dependencies(Inject.MultipleKeyed(nil)))
+
+		// WHEN
+		_, err := Resolve[*TestService](resolver)
+
+		// THEN
+		require.NoError(t, err)
+		assert.ElementsMatch(t, []string{"EUR", "USD"}, slices.Collect(maps.Keys(injected)))
+	})
+
+	t.Run("it should fail if two components have the same key", func(t *testing.T) {
+		// GIVEN
+		resolver := New()
+		resolver.MustRegister(func() *currencyProvider { return &currencyProvider{currency: "EUR"} }, Named("stripe"))
+		resolver.MustRegister(func() *currencyProvider { return &currencyProvider{currency: "EUR"} }, Named("adyen"))
+
+		// WHEN
+		_, err := ResolveAllKeyed(resolver, (*currencyProvider).Key)
+
+		// THEN
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "have the same key EUR")
+	})
+
+	t.Run("it should reject a key function not matching the map", func(t *testing.T) {
+		// GIVEN
+		resolver := New()
+
+		// WHEN
+		err := resolver.Register(func(map[int]*TestRepository) *TestService {
+			return &TestService{}
+		}, Dependencies(Inject.MultipleKeyed(func(r *TestRepository) string { return r.Data })))
+
+		// THEN
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "does not return the keys of map[int]*godi.TestRepository")
+	})
+
+	t.Run("it should resolve all the components keyed by the given function", func(t *testing.T) {
+		// GIVEN
+		resolver := New()
+		resolver.MustRegister(func() *currencyProvider { return &currencyProvider{currency: "EUR"} }, Named("stripe"))
+
+		// WHEN
+		providers, err := ResolveAllKeyed(resolver, (*currencyProvider).Key)
+
+		// THEN
+		require.NoError(t, err)
+		assert.Equal(t, map[string]*currencyProvider{"EUR": {currency: "EUR"}}, providers)
+	})
+}
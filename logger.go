@@ -0,0 +1,82 @@
+package godi
+
+import (
+	"fmt"
+	"log/slog"
+
+	"github.com/a-peyrard/godi/option"
+	"github.com/rs/zerolog"
+)
+
+type (
+	// Logger is used by the resolver to report what it does, the args are alternating keys and values,
+	// like for slog, so a *slog.Logger is a Logger.
+	Logger interface {
+		Debug(msg string, args ...any)
+		Info(msg string, args ...any)
+		Warn(msg string, args ...any)
+		Error(msg string, args ...any)
+	}
+
+	nopLogger struct{}
+
+	zerologLogger struct {
+		logger zerolog.Logger
+	}
+)
+
+// WithLogger sets the logger of the resolver, and of its scopes and forks, nothing is logged by default.
+func WithLogger(logger Logger) option.Option[ResolverOptions] {
+	return func(opts *ResolverOptions) {
+		opts.logger = logger
+	}
+}
+
+// NewSlogLogger adapts a slog logger, note that a *slog.Logger can also be used directly.
+func NewSlogLogger(logger *slog.Logger) Logger {
+	return logger
+}
+
+// NewZerologLogger adapts a zerolog logger.
+func NewZerologLogger(logger zerolog.Logger) Logger {
+	return zerologLogger{logger: logger}
+}
+
+func (nopLogger) Debug(string, ...any) {}
+func (nopLogger) Info(string, ...any)  {}
+func (nopLogger) Warn(string, ...any)  {}
+func (nopLogger) Error(string, ...any) {}
+
+func (z zerologLogger) Debug(msg string, args ...any) {
+	z.logger.Debug().Fields(args).Msg(msg)
+}
+
+func (z zerologLogger) Info(msg string, args ...any) {
+	z.logger.Info().Fields(args).Msg(msg)
+}
+
+func (z zerologLogger) Warn(msg string, args ...any) {
+	z.logger.Warn().Fields(args).Msg(msg)
+}
+
+func (z zerologLogger) Error(msg string, args ...any) {
+	z.logger.Error().Fields(args).Msg(msg)
+}
+
+// loggerOrNop returns the given logger, or a logger discarding everything if nil.
+func loggerOrNop(logger Logger) Logger {
+	if logger == nil {
+		return nopLogger{}
+	}
+	return logger
+}
+
+// mustSucceed logs and panics if the error is not nil, for the Must* functions.
+func mustSucceed(logger Logger, err error, format string, args ...any) {
+	if err == nil {
+		return
+	}
+	msg := fmt.Sprintf(format, args...)
+	logger.Error(msg, "error", err)
+	panic(fmt.Sprintf("%s:\n\t%v", msg, err))
+}
@@ -0,0 +1,86 @@
+package godi
+
+import (
+	"bytes"
+	"log/slog"
+	"testing"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolver_WithLogger(t *testing.T) {
+	t.Run("it should log the registrations and the resolutions with slog", func(t *testing.T) {
+		// GIVEN
+		var out bytes.Buffer
+		logger := slog.New(slog.NewTextHandler(&out, &slog.HandlerOptions{Level: slog.LevelDebug}))
+		resolver := New(WithLogger(logger))
+		resolver.MustRegister(NewTestService)
+
+		// WHEN
+		_, err := Resolve[*TestService](resolver)
+
+		// THEN
+		require.NoError(t, err)
+		assert.Contains(t, out.String(), `msg="provider registered"`)
+		assert.Contains(t, out.String(), `msg="request resolved" request="{q=<type~=*godi.TestService>`)
+	})
+
+	t.Run("it should log the resolutions of the stored components", func(t *testing.T) {
+		// GIVEN
+		var out bytes.Buffer
+		logger := slog.New(slog.NewTextHandler(&out, &slog.HandlerOptions{Level: slog.LevelDebug}))
+		resolver := New(WithLogger(logger))
+		resolver.MustRegister(NewTestService, Named("service"))
+		MustResolveNamed[*TestService](resolver, "service")
+
+		// WHEN
+		_, err := ResolveNamed[*TestService](resolver, "service")
+
+		// THEN
+		require.NoError(t, err)
+		assert.Contains(t, out.String(), `msg="request resolved" request="{q=<type~=*godi.TestService & name=service>`)
+		assert.Contains(t, out.String(), "depth=0 stored=true")
+	})
+
+	t.Run("it should warn when a provider shadows another one with zerolog", func(t *testing.T) {
+		// GIVEN
+		var out bytes.Buffer
+		resolver := New(WithLogger(NewZerologLogger(zerolog.New(&out).Level(zerolog.WarnLevel))))
+		resolver.MustRegister(ToStaticProvider("first"), Named("greeting"))
+
+		// WHEN
+		resolver.MustRegister(ToStaticProvider("second"), Named("greeting"))
+
+		// THEN
+		assert.Contains(t, out.String(), `"level":"warn"`)
+		assert.Contains(t, out.String(), `"message":"provider shadows a provider registered with the same priority"`)
+	})
+
+	t.Run("it should not warn when a provider has a higher priority", func(t *testing.T) {
+		// GIVEN
+		var out bytes.Buffer
+		resolver := New(WithLogger(NewZerologLogger(zerolog.New(&out).Level(zerolog.WarnLevel))))
+		resolver.MustRegister(ToStaticProvider("first"), Named("greeting"))
+
+		// WHEN
+		resolver.MustRegister(ToStaticProvider("second"), Named("greeting"), Priority(10))
+
+		// THEN
+		assert.Empty(t, out.String())
+	})
+
+	t.Run("it should log and panic when a Must function fails", func(t *testing.T) {
+		// GIVEN
+		var out bytes.Buffer
+		resolver := New(WithLogger(slog.New(slog.NewTextHandler(&out, nil))))
+
+		// WHEN
+		resolve := func() { MustResolve[*TestService](resolver) }
+
+		// THEN
+		assert.Panics(t, resolve)
+		assert.Contains(t, out.String(), `level=ERROR msg="failed to resolve type *godi.TestService"`)
+	})
+}
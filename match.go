@@ -0,0 +1,112 @@
+package godi
+
+import (
+	"fmt"
+	"path"
+	"reflect"
+)
+
+// queryByPattern finds the components of a type whose name matches a glob pattern, see path.Match.
+type queryByPattern struct {
+	typ     reflect.Type
+	pattern string
+}
+
+// ResolveAllNamed resolves the components of type T whose name matches the glob pattern, e.g. "kafka.consumer.*",
+// ordered by priority. The pattern follows path.Match, with a `*` matching any sequence of characters but `/`.
+func ResolveAllNamed[T any](resolver *Resolver, pattern string) ([]T, error) {
+	return resolveMatching[[]T, T](resolver, pattern, collectorMultipleAsSlice{})
+}
+
+// ResolveAllNamedAsMap resolves the components of type T whose name matches the glob pattern, keyed by name,
+// see ResolveAllNamed.
+func ResolveAllNamedAsMap[T any](resolver *Resolver, pattern string) (map[string]T, error) {
+	return resolveMatching[map[string]T, T](resolver, pattern, collectorMultipleAsMap{})
+}
+
+func resolveMatching[C any, T any](resolver *Resolver, pattern string, collector collector) (C, error) {
+	var zero C
+	if err := validatePattern(pattern); err != nil {
+		return zero, err
+	}
+	lookFor := reflect.TypeOf((*T)(nil)).Elem()
+
+	val, _, err := resolveTyped[C](
+		resolver,
+		Request{
+			unitaryTyp: lookFor,
+			query:      queryByPattern{typ: lookFor, pattern: pattern},
+			validator:  validatorMultiple{},
+			collector:  collector,
+		},
+	)
+	return val, err
+}
+
+type matchingDependencyBuilder struct {
+	pattern string
+}
+
+// Matching injects the components whose name matches the glob pattern, e.g. "kafka.consumer.*", as a slice ordered
+// by priority, or as a map by name, see ResolveAllNamed.
+func (i *injectBuilder) Matching(pattern string) dependency {
+	return matchingDependencyBuilder{pattern: pattern}
+}
+
+func (m matchingDependencyBuilder) build(targetTyp reflect.Type) (r Request, err error) {
+	if err := validatePattern(m.pattern); err != nil {
+		return r, err
+	}
+	var collector collector
+	switch targetTyp.Kind() {
+	case reflect.Slice:
+		collector = collectorMultipleAsSlice{}
+	case reflect.Map:
+		collector = collectorMultipleAsMap{}
+	default:
+		return r, fmt.Errorf("matching dependencies can only be used with slice or map types, got %s", targetTyp)
+	}
+	elemTyp := targetTyp.Elem()
+	return Request{
+		unitaryTyp: elemTyp,
+		query: queryByPattern{
+			typ:     elemTyp,
+			pattern: m.pattern,
+		},
+		validator: validatorMultiple{},
+		collector: collector,
+	}, nil
+}
+
+func validatePattern(pattern string) error {
+	if _, err := path.Match(pattern, ""); err != nil {
+		return fmt.Errorf("invalid name pattern %q:\n\t%w", pattern, err)
+	}
+	return nil
+}
+
+func (q queryByPattern) find(r *Resolver) ([]*queryResult, error) {
+	results := resultsOf(r.candidatesFor(q))
+	for _, result := range results {
+		if storedComp, found := r.store.Get(result.name); found {
+			result.component = &storedComp
+		}
+	}
+	return results, nil
+}
+
+func (q queryByPattern) plan(lookup providerLookup) []candidate {
+	// the first provider (in priority order) of a name wins, as for the queries by type
+	byType := queryByType{typ: q.typ}
+	var candidates []candidate
+	for _, c := range byType.plan(lookup) {
+		if matched, _ := path.Match(q.pattern, c.name.name); matched {
+			candidates = append(candidates, c)
+		}
+	}
+	return candidates
+}
+
+func (q queryByPattern) String() string {
+	return fmt.Sprintf("<type~=%s & name~=%s>", q.typ.String(), q.pattern)
+}
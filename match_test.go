@@ -0,0 +1,79 @@
+package godi
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolver_Matching(t *testing.T) {
+	t.Run("it should resolve only the components whose name matches the pattern", func(t *testing.T) {
+		// GIVEN
+		resolver := New()
+		resolver.MustRegister(ToStaticProvider(&TestService{Name: "orders"}), Named("kafka.consumer.orders"))
+		resolver.MustRegister(ToStaticProvider(&TestService{Name: "payments"}), Named("kafka.consumer.payments"))
+		resolver.MustRegister(ToStaticProvider(&TestService{Name: "producer"}), Named("kafka.producer.orders"))
+
+		// WHEN
+		consumers, err := ResolveAllNamed[*TestService](resolver, "kafka.consumer.*")
+
+		// THEN
+		require.NoError(t, err)
+		assert.ElementsMatch(t, []string{"orders", "payments"}, namesOf(consumers))
+	})
+
+	t.Run("it should resolve the matching components keyed by name", func(t *testing.T) {
+		// GIVEN
+		resolver := New()
+		resolver.MustRegister(ToStaticProvider(&TestService{Name: "users"}), Named("route.users"))
+		resolver.MustRegister(ToStaticProvider(&TestService{Name: "orders"}), Named("route.orders"))
+		resolver.MustRegister(ToStaticProvider("not a service"), Named("route.health"))
+
+		// WHEN
+		routes, err := ResolveAllNamedAsMap[*TestService](resolver, "route.*")
+
+		// THEN
+		require.NoError(t, err)
+		assert.Len(t, routes, 2)
+		assert.Equal(t, "users", routes["route.users"].Name)
+		assert.Equal(t, "orders", routes["route.orders"].Name)
+	})
+
+	t.Run("it should inject the matching components", func(t *testing.T) {
+		// GIVEN
+		resolver := New()
+		resolver.MustRegister(ToStaticProvider(&TestService{Name: "orders"}), Named("kafka.consumer.orders"))
+		resolver.MustRegister(ToStaticProvider(&TestService{Name: "payments"}), Named("kafka.consumer.payments"))
+		resolver.MustRegister(ToStaticProvider(&TestService{Name: "cache"}), Named("cache"))
+		resolver.MustRegister(
+			func(consumers map[string]*TestService) int { return len(consumers) },
+			Named("count"),
+			Dependencies(Inject.Matching("kafka.consumer.*")),
+		)
+
+		// WHEN
+		count, err := ResolveNamed[int](resolver, "count")
+
+		// THEN
+		require.NoError(t, err)
+		assert.Equal(t, 2, count)
+	})
+
+	t.Run("it should fail if the pattern is malformed", func(t *testing.T) {
+		// GIVEN
+		resolver := New()
+
+		// WHEN
+		_, err := ResolveAllNamed[*TestService](resolver, "kafka.[")
+		registerErr := resolver.Register(
+			func([]*TestService) int { return 0 },
+			Dependencies(Inject.Matching("kafka.[")),
+		)
+
+		// THEN
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), `invalid name pattern "kafka.["`)
+		require.Error(t, registerErr)
+	})
+}
@@ -0,0 +1,168 @@
+package godi
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+type (
+	// NamespacedRegistry registers the components of a registry under a prefix, see Namespaced.
+	NamespacedRegistry struct {
+		prefix   string
+		registry Registry
+	}
+
+	// namespacedProvider provides the components of a provider registered by a namespaced registry,
+	// with the prefix prepended to their names.
+	namespacedProvider struct {
+		Provider
+		prefix       string
+		dependencies []Request
+	}
+)
+
+// Namespaced returns a registry registering the components of the registry with the prefix prepended to their names,
+// e.g. "payments.client" for the client of the registry namespaced "payments", so the components of a registry
+// loaded from a plugin can not clash with the ones of the application.
+//
+// The components of the registry are isolated: they are only provided by name, so they are neither injected by type
+// nor collected in the components of the application. The dependencies of the components on the components of the
+// registry follow the prefix, the ones by type being injected with the single component of the registry matching the
+// type, if any, the other dependencies are looked up in the whole resolver. The registry is not allowed to register
+// decorators.
+func Namespaced(prefix string, registry Registry) NamespacedRegistry {
+	return NamespacedRegistry{prefix: prefix, registry: registry}
+}
+
+// Register registers the components of the registry under the prefix, see Install.
+//
+// It panics if the components can not be registered.
+func (n NamespacedRegistry) Register(resolver *Resolver) {
+	mustSucceed(resolver.logger, n.Install(resolver), "failed to register the registry namespaced %s", n.prefix)
+}
+
+// Install registers the components of the registry under the prefix, see Namespaced.
+//
+// The registry registers its components in a fork of the resolver first (see Fork), so its registration conditions
+// see the components of the resolver, then they are added to the resolver under the prefix.
+func (n NamespacedRegistry) Install(resolver *Resolver) error {
+	if n.prefix == "" {
+		return errors.New("the prefix of a namespaced registry can not be empty")
+	}
+	if resolver.compiled.Load() {
+		return fmt.Errorf("unable to register the registry namespaced %s, the resolver is compiled and can not be modified anymore", n.prefix)
+	}
+
+	staging := resolver.Fork()
+	decorators := countDecorators(staging)
+	registered := recordRegistrations(staging, n.registry)
+	if countDecorators(staging) != decorators {
+		return fmt.Errorf("the registry namespaced %s registers decorators, they can not be namespaced", n.prefix)
+	}
+
+	var errs []error
+	for _, p := range registered {
+		attributes := attributesOf(p)
+		attributes.namespace = n.prefix
+		namespaced := &namespacedProvider{
+			Provider:     unwrapped(p),
+			prefix:       n.prefix + ".",
+			dependencies: n.prefixed(unwrapped(p).Dependencies(), registered),
+		}
+		if err := resolver.addProvider(withAttributes(namespaced, attributes)); err != nil {
+			errs = append(errs, fmt.Errorf("failed to register provider %s:\n\t%w", namespaced, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// prefixed returns the requests, the names of the components of the registry being prefixed. As its components are
+// only provided by name, a request by type matching a single component of the registry is made by its name.
+func (n NamespacedRegistry) prefixed(requests []Request, registered []Provider) []Request {
+	prefixed := make([]Request, len(requests))
+	for i, req := range requests {
+		switch q := req.query.(type) {
+		case queryByName:
+			if _, matches := localComponents(registered, func(p Provider, name Name) bool {
+				return name.name == q.name.name
+			}); matches > 0 {
+				q.name.name = n.prefix + "." + q.name.name
+				req.query = q
+			}
+		case queryByType:
+			if local, matches := localComponents(registered, func(p Provider, name Name) bool {
+				return attributesOf(p).matches(q.typ, name.typ)
+			}); matches == 1 {
+				req.query = queryByName{name: Name{name: n.prefix + "." + local.name, typ: q.typ}}
+			}
+		}
+		prefixed[i] = req
+	}
+	return prefixed
+}
+
+// localComponents counts the components of the registry matching the predicate, returning the last one.
+func localComponents(registered []Provider, predicate func(p Provider, name Name) bool) (Name, int) {
+	var (
+		found   Name
+		matches int
+	)
+	for _, p := range registered {
+		for _, name := range p.ListProvidableNames() {
+			if predicate(p, name) {
+				found = name
+				matches++
+			}
+		}
+	}
+	return found, matches
+}
+
+func countDecorators(r *Resolver) int {
+	count := 0
+	r.decorators.Range(func(_, decorators any) bool {
+		count += len(decorators.(*SortedCOWSlice[Decorator]).All())
+		return true
+	})
+	return count
+}
+
+func (p *namespacedProvider) CanProvide(name Name) bool {
+	inner, ok := p.inner(name)
+	return ok && p.Provider.CanProvide(inner)
+}
+
+func (p *namespacedProvider) Provide(name Name, dependencies []reflect.Value) (reflect.Value, error) {
+	inner, ok := p.inner(name)
+	if !ok {
+		return reflect.Value{}, fmt.Errorf("%s does not provide %s", p, name)
+	}
+	return p.Provider.Provide(inner, dependencies)
+}
+
+func (p *namespacedProvider) Dependencies() []Request {
+	return p.dependencies
+}
+
+func (p *namespacedProvider) ListProvidableNames() []Name {
+	names := p.Provider.ListProvidableNames()
+	prefixed := make([]Name, len(names))
+	for i, name := range names {
+		prefixed[i] = name
+		prefixed[i].name = p.prefix + name.name
+	}
+	return prefixed
+}
+
+func (p *namespacedProvider) String() string {
+	return fmt.Sprintf("%s in namespace %s", describeProvider(p.Provider), strings.TrimSuffix(p.prefix, "."))
+}
+
+// inner returns the name of the component for the namespaced provider, without the prefix.
+func (p *namespacedProvider) inner(name Name) (Name, bool) {
+	unprefixed, ok := strings.CutPrefix(name.name, p.prefix)
+	name.name = unprefixed
+	return name, ok
+}
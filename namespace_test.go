@@ -0,0 +1,102 @@
+package godi
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type paymentsRegistry struct{}
+
+func (paymentsRegistry) Register(resolver *Resolver) {
+	resolver.MustRegister(func() string { return "stripe" }, Named("client"))
+	resolver.MustRegister(
+		func(client string, repository *TestRepository) *TestService {
+			return &TestService{Name: client + "/" + repository.Data}
+		},
+		Named("service"),
+		Dependencies(Inject.Named("client"), Inject.Auto()),
+	)
+}
+
+func TestNamespaced(t *testing.T) {
+	t.Run("it should register the components under the prefix", func(t *testing.T) {
+		// GIVEN
+		resolver := New()
+		resolver.MustRegister(func() string { return "paypal" }, Named("client"))
+		resolver.MustRegister(func() *TestRepository { return &TestRepository{Data: "repository"} })
+
+		// WHEN
+		err := Namespaced("payments", paymentsRegistry{}).Install(resolver)
+
+		// THEN
+		require.NoError(t, err)
+		assert.Equal(t, "paypal", MustResolveNamed[string](resolver, "client"))
+		assert.Equal(t, "stripe", MustResolveNamed[string](resolver, "payments.client"))
+		assert.Equal(t, "stripe/repository", MustResolveNamed[*TestService](resolver, "payments.service").Name)
+		assert.NoError(t, resolver.Validate())
+	})
+
+	t.Run("it should not expose the components without the prefix", func(t *testing.T) {
+		// GIVEN
+		resolver := New()
+		resolver.MustRegister(func() *TestRepository { return &TestRepository{} })
+
+		// WHEN
+		Namespaced("payments", paymentsRegistry{}).Register(resolver)
+
+		// THEN
+		_, err := ResolveNamed[string](resolver, "client")
+		assert.Error(t, err)
+	})
+
+	t.Run("it should only provide the components by name, injecting them by type within the registry", func(t *testing.T) {
+		// GIVEN
+		resolver := New()
+		resolver.MustRegister(func() *TestRepository { return &TestRepository{Data: "app"} })
+		registry := registryFunc(func(r *Resolver) {
+			r.MustRegister(func() *TestRepository { return &TestRepository{Data: "plugin"} }, Named("repository"), Priority(100))
+			r.MustRegister(func(repository *TestRepository) *TestService {
+				return &TestService{Name: repository.Data}
+			}, Named("service"))
+		})
+
+		// WHEN
+		err := Namespaced("plugin", registry).Install(resolver)
+
+		// THEN
+		require.NoError(t, err)
+		assert.Equal(t, "app", MustResolve[*TestRepository](resolver).Data)
+		assert.Len(t, MustResolveAll[*TestRepository](resolver), 1)
+		assert.Equal(t, "plugin", MustResolveNamed[*TestService](resolver, "plugin.service").Name)
+		_, err = Resolve[*TestService](resolver)
+		assert.Error(t, err)
+	})
+
+	t.Run("it should reject the registries registering decorators", func(t *testing.T) {
+		// GIVEN
+		resolver := New()
+		registry := registryFunc(func(r *Resolver) {
+			r.MustRegister(func(s string) string { return s + "!" }, Decorate("client"))
+		})
+
+		// WHEN
+		err := Namespaced("payments", registry).Install(resolver)
+
+		// THEN
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "registers decorators")
+	})
+
+	t.Run("it should reject an empty prefix", func(t *testing.T) {
+		// GIVEN
+		resolver := New()
+
+		// WHEN
+		err := Namespaced("", paymentsRegistry{}).Install(resolver)
+
+		// THEN
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,52 @@
+package godi
+
+import (
+	"reflect"
+)
+
+type (
+	// Optional is an optional dependency carrying whether it was found, to distinguish a missing dependency
+	// from a component being the zero value of its type, e.g. an empty string.
+	//
+	// A parameter or a field of type Optional[T] is always optional, it is resolved as T, by type or by name:
+	//
+	//	func NewServer(port godi.Optional[int]) *Server {
+	//		if p, found := port.Get(); found { ... }
+	//	}
+	Optional[T any] struct {
+		Value T
+		Found bool
+	}
+
+	// optionalValue is implemented by Optional, to find the type of the optional component.
+	optionalValue interface {
+		optionalOf() reflect.Type
+	}
+)
+
+var optionalValueType = TypeOf[optionalValue]()
+
+// Get returns the value, and whether it was found.
+func (o Optional[T]) Get() (T, bool) {
+	return o.Value, o.Found
+}
+
+// OrElse returns the value if it was found, the given fallback otherwise.
+func (o Optional[T]) OrElse(fallback T) T {
+	if o.Found {
+		return o.Value
+	}
+	return fallback
+}
+
+func (o Optional[T]) optionalOf() reflect.Type {
+	return TypeOf[T]()
+}
+
+// optionalComponentType returns the type of the component of an Optional, false if the type is not an Optional.
+func optionalComponentType(typ reflect.Type) (reflect.Type, bool) {
+	if typ.Kind() != reflect.Struct || typ.PkgPath() != optionalValueType.PkgPath() || !typ.Implements(optionalValueType) {
+		return nil, false
+	}
+	return reflect.Zero(typ).Interface().(optionalValue).optionalOf(), true
+}
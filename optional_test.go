@@ -0,0 +1,85 @@
+package godi
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOptional(t *testing.T) {
+	type server struct {
+		port  Optional[int]
+		label Optional[string]
+	}
+
+	t.Run("it should inject a found zero value, telling it apart from a missing one", func(t *testing.T) {
+		// GIVEN
+		resolver := New()
+		resolver.MustRegister(func() int { return 0 }, Named("port"))
+		resolver.MustRegister(func(port Optional[int], label Optional[string]) *server {
+			return &server{port: port, label: label}
+		}, Named("server"), Dependencies(Inject.Named("port"), Inject.Named("label")))
+
+		// WHEN
+		s, err := ResolveNamed[*server](resolver, "server")
+
+		// THEN
+		require.NoError(t, err)
+		port, found := s.port.Get()
+		assert.True(t, found)
+		assert.Equal(t, 0, port)
+		assert.False(t, s.label.Found)
+		assert.Equal(t, "none", s.label.OrElse("none"))
+	})
+
+	t.Run("it should resolve an optional dependency by type", func(t *testing.T) {
+		// GIVEN
+		resolver := New()
+		resolver.MustRegister(func() *TestRepository { return &TestRepository{Data: "data"} })
+		resolver.MustRegister(func(repository Optional[*TestRepository], config Optional[*TestConfig]) *TestService {
+			name := repository.Value.Data
+			if !config.Found {
+				name += " without config"
+			}
+			return &TestService{Name: name}
+		})
+
+		// WHEN
+		service, err := Resolve[*TestService](resolver)
+
+		// THEN
+		require.NoError(t, err)
+		assert.Equal(t, "data without config", service.Name)
+	})
+
+	t.Run("it should inject an optional field of a struct", func(t *testing.T) {
+		// GIVEN
+		type handler struct {
+			Port Optional[int] `godi:"name=port"`
+		}
+		resolver := New()
+		resolver.MustRegister(func() int { return 8080 }, Named("port"))
+		var h handler
+
+		// WHEN
+		err := ResolveInto(resolver, &h)
+
+		// THEN
+		require.NoError(t, err)
+		assert.Equal(t, Optional[int]{Value: 8080, Found: true}, h.Port)
+	})
+
+	t.Run("it should describe the dependency as optional", func(t *testing.T) {
+		// GIVEN
+		resolver := New()
+		resolver.MustRegister(func(Optional[int]) *TestService { return &TestService{} })
+
+		// WHEN
+		providers := resolver.Providers()
+
+		// THEN
+		require.NotEmpty(t, providers)
+		assert.Equal(t, []DependencyInfo{{Type: TypeOf[int](), Optional: true}}, providers[0].Dependencies)
+	})
+}
@@ -0,0 +1,181 @@
+package godi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/a-peyrard/godi/option"
+)
+
+// Override registers a provider replacing the providers of the same components (by name or by type, see Fork),
+// and returns a function restoring the previous state. It is meant for tests, see the goditest package.
+//
+// The components already built for the replaced providers are put aside, and restored along with their providers.
+// Note that the components already built with the replaced components are not rebuilt. The wired components
+// can not be overridden, see Wired.
+func (r *Resolver) Override(reg Registrable, opts ...option.Option[RegistrableOptions]) (restore func(), err error) {
+	before := r.providers.All()
+	if err := r.Register(reg, opts...); err != nil {
+		return nil, err
+	}
+
+	var override Provider
+	for _, p := range r.providers.All() {
+		if !containsProvider(before, p) {
+			override = p
+			break
+		}
+	}
+	if override == nil {
+		return nil, fmt.Errorf("nothing to override with %T, only providers can be overridden", reg)
+	}
+
+	overrideNames := override.ListProvidableNames()
+	var replaced []Provider
+	r.providers.RemoveIf(func(p Provider) bool {
+		if !sameProvider(p, override) && coversAll(override, p) {
+			replaced = append(replaced, p)
+			return true
+		}
+		return false
+	})
+
+	var putAside []*storedComponent
+	for _, p := range replaced {
+		for _, n := range p.ListProvidableNames() {
+			putAside = append(putAside, r.store.removeAll(versionedName(n, p))...)
+		}
+	}
+	r.resetPlans()
+
+	return func() {
+		r.providers.RemoveIf(func(p Provider) bool {
+			return sameProvider(p, override)
+		})
+		for _, n := range overrideNames {
+			r.store.removeAll(versionedName(n, override))
+		}
+		for _, p := range replaced {
+			r.providers.Add(p)
+		}
+		for _, stored := range putAside {
+			r.store.restore(stored)
+		}
+		r.resetPlans()
+	}, nil
+}
+
+// Unregister removes the providers of the components with the given name, e.g. to unload a plugin. The components
+// they already built are removed from the resolver and closed, the errors of their closing are returned.
+//
+// A provider providing several components is removed with all its components. Note that the components
+// already built with the removed components are not rebuilt, they keep a reference to them.
+func (r *Resolver) Unregister(name string) error {
+	if r.compiled.Load() {
+		return fmt.Errorf("unable to unregister %s, the resolver is compiled and can not be modified anymore", name)
+	}
+	if err := r.checkNotWired("unregister", name); err != nil {
+		return err
+	}
+
+	removed := r.removeProvidersOf(name)
+	if len(removed) == 0 {
+		return fmt.Errorf("unable to unregister %s, no provider is registered for it", name)
+	}
+	return r.closeComponentsOf(removed)
+}
+
+// Replace registers a provider for the components with the given name, replacing the providers registered for
+// them, e.g. to switch an implementation behind a feature flag. The components built by the replaced providers are
+// removed from the resolver and closed, the errors of their closing are returned.
+//
+// Functions are registered with the given name (see Named), Provider implementations must provide it.
+// As for Unregister, the components already built with the replaced components are not rebuilt.
+func (r *Resolver) Replace(name string, reg Registrable, opts ...option.Option[RegistrableOptions]) error {
+	if r.compiled.Load() {
+		return fmt.Errorf("unable to replace %s, the resolver is compiled and can not be modified anymore", name)
+	}
+	if err := r.checkNotWired("replace", name); err != nil {
+		return err
+	}
+
+	// the replaced providers are removed first, so the replacement does not shadow them, see checkShadowed
+	replaced := r.removeProvidersOf(name)
+	restore := func() {
+		for _, p := range replaced {
+			r.providers.Add(p)
+		}
+		r.resetPlans()
+	}
+
+	before := r.providers.All()
+	if err := r.Register(reg, append([]option.Option[RegistrableOptions]{Named(name)}, opts...)...); err != nil {
+		restore()
+		return fmt.Errorf("failed to replace %s:\n\t%w", name, err)
+	}
+	for _, p := range r.providers.All() {
+		if !containsProvider(before, p) && providesName(p, name) {
+			return r.closeComponentsOf(replaced)
+		}
+	}
+
+	// the registered providers do not provide the name, so they can not be found by it
+	r.providers.RemoveIf(func(p Provider) bool {
+		return !containsProvider(before, p)
+	})
+	restore()
+	return fmt.Errorf("unable to replace %s with %T, it does not provide %s, or a condition does not hold", name, reg, name)
+}
+
+// removeProvidersOf removes the providers of the components with the given name, returning them.
+func (r *Resolver) removeProvidersOf(name string) []Provider {
+	var removed []Provider
+	r.providers.RemoveIf(func(p Provider) bool {
+		if providesName(p, name) {
+			removed = append(removed, p)
+			return true
+		}
+		return false
+	})
+	r.resetPlans()
+	return removed
+}
+
+// closeComponentsOf removes the components built by the providers from the store, and closes them.
+func (r *Resolver) closeComponentsOf(providers []Provider) error {
+	var errs []error
+	for _, p := range providers {
+		for _, n := range p.ListProvidableNames() {
+			for _, stored := range r.store.removeAll(versionedName(n, p)) {
+				if err := stored.close(context.Background()); err != nil {
+					errs = append(errs, err)
+				}
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func providesName(p Provider, name string) bool {
+	for _, n := range p.ListProvidableNames() {
+		if n.name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// resetPlans drops the cached query plans, once the providers changed.
+func (r *Resolver) resetPlans() {
+	r.plans.Clear()
+}
+
+func containsProvider(providers []Provider, p Provider) bool {
+	for _, candidate := range providers {
+		if sameProvider(candidate, p) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,119 @@
+package godi
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolver_Unregister(t *testing.T) {
+	t.Run("it should remove the providers of the name, and close the components they built", func(t *testing.T) {
+		// GIVEN
+		resolver := New()
+		resolver.MustRegister(func() *TestService { return &TestService{Name: "plugin"} }, Named("plugin"))
+		resolver.MustRegister(func() *TestService { return &TestService{Name: "other"} }, Named("other"))
+		plugin := MustResolveNamed[*TestService](resolver, "plugin")
+
+		// WHEN
+		err := resolver.Unregister("plugin")
+
+		// THEN
+		require.NoError(t, err)
+		assert.True(t, plugin.closed)
+		_, err = ResolveNamed[*TestService](resolver, "plugin")
+		assert.Error(t, err)
+		assert.Equal(t, "other", MustResolveNamed[*TestService](resolver, "other").Name)
+	})
+
+	t.Run("it should fail if nothing provides the name", func(t *testing.T) {
+		// GIVEN
+		resolver := New()
+
+		// WHEN
+		err := resolver.Unregister("plugin")
+
+		// THEN
+		assert.EqualError(t, err, "unable to unregister plugin, no provider is registered for it")
+	})
+
+	t.Run("it should return the errors of the closing", func(t *testing.T) {
+		// GIVEN
+		resolver := New()
+		resolver.MustRegister(func() closeFunc { return func() error { return errors.New("boom") } }, Named("plugin"))
+		_ = MustResolveNamed[closeFunc](resolver, "plugin")
+
+		// WHEN
+		err := resolver.Unregister("plugin")
+
+		// THEN
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "boom")
+	})
+}
+
+func TestResolver_Replace(t *testing.T) {
+	t.Run("it should replace the provider, and close the component it built", func(t *testing.T) {
+		// GIVEN
+		resolver := New()
+		resolver.MustRegister(func() *TestService { return &TestService{Name: "v1"} }, Named("service"), Priority(10))
+		v1 := MustResolveNamed[*TestService](resolver, "service")
+
+		// WHEN
+		err := resolver.Replace("service", func() *TestService { return &TestService{Name: "v2"} })
+
+		// THEN
+		require.NoError(t, err)
+		assert.True(t, v1.closed)
+		assert.Equal(t, "v2", MustResolveNamed[*TestService](resolver, "service").Name)
+		assert.Len(t, resolver.Explain("service").Candidates, 1)
+	})
+
+	t.Run("it should keep the replaced provider if the replacement is not registered", func(t *testing.T) {
+		// GIVEN
+		resolver := New()
+		resolver.MustRegister(func() *TestService { return &TestService{Name: "v1"} }, Named("service"))
+		v1 := MustResolveNamed[*TestService](resolver, "service")
+
+		// WHEN
+		err := resolver.Replace("service", func() *TestService { return &TestService{Name: "v2"} }, WhenOS("plan9"))
+
+		// THEN
+		require.Error(t, err)
+		assert.False(t, v1.closed)
+		assert.Same(t, v1, MustResolveNamed[*TestService](resolver, "service"))
+	})
+
+	t.Run("it should remove the replacement if it does not provide the name", func(t *testing.T) {
+		// GIVEN
+		resolver := New()
+		resolver.MustRegister(func() *TestService { return &TestService{Name: "v1"} }, Named("service"))
+		other, err := NewStructProvider[TestRepository](Named("other"))
+		require.NoError(t, err)
+
+		// WHEN
+		err = resolver.Replace("service", other)
+
+		// THEN
+		require.Error(t, err)
+		assert.Equal(t, "v1", MustResolveNamed[*TestService](resolver, "service").Name)
+		_, found, resolveErr := TryResolveNamed[*TestRepository](resolver, "other")
+		require.NoError(t, resolveErr)
+		assert.False(t, found)
+	})
+
+	t.Run("it should not replace anything on a compiled resolver", func(t *testing.T) {
+		// GIVEN
+		resolver := NewBuilder().
+			Register(func() *TestService { return &TestService{Name: "v1"} }, Named("service")).
+			MustCompile()
+
+		// WHEN
+		err := resolver.Replace("service", func() *TestService { return &TestService{Name: "v2"} })
+
+		// THEN
+		require.Error(t, err)
+		assert.Equal(t, "v1", MustResolveNamed[*TestService](resolver, "service").Name)
+	})
+}
@@ -56,3 +56,8 @@ func (Registry) Register(resolver *godi.Resolver) {
 		),
 	)
 }
+
+// With composes the registry with the registries of other modules, see godi.Compose.
+func (r Registry) With(others ...godi.Registry) godi.CompositeRegistry {
+	return godi.Compose(r).With(others...)
+}
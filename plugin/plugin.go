@@ -0,0 +1,98 @@
+// Package plugin loads the components of Go plugins (see https://pkg.go.dev/plugin) in a resolver, so applications
+// built on godi can be extended without being rebuilt.
+//
+// A plugin is a main package built with -buildmode=plugin, exporting its registry as Registry, e.g. the registry
+// generated from its annotations:
+//
+//	var Registry = registry.Registry{}
+package plugin
+
+import (
+	"fmt"
+	"path/filepath"
+	goplugin "plugin"
+	"strings"
+
+	"github.com/a-peyrard/godi"
+	"github.com/a-peyrard/godi/option"
+)
+
+// RegistrySymbol is the name of the symbol exported by the plugins, either a godi.Registry or a func() godi.Registry.
+const RegistrySymbol = "Registry"
+
+type (
+	// LoadOptions are the options of Load.
+	LoadOptions struct {
+		prefix *string
+	}
+
+	// lookupFunc looks up a symbol exported by a plugin, see plugin.Plugin.Lookup.
+	lookupFunc func(symName string) (goplugin.Symbol, error)
+)
+
+// WithPrefix replaces the name of the plugin file by the given prefix, in the names of the components of the plugin.
+func WithPrefix(prefix string) option.Option[LoadOptions] {
+	return func(opts *LoadOptions) {
+		opts.prefix = &prefix
+	}
+}
+
+// Open opens the Go plugin, and returns the registry it exports, see RegistrySymbol.
+func Open(path string) (godi.Registry, error) {
+	p, err := goplugin.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open plugin %s:\n\t%w", path, err)
+	}
+	return registryOf(path, p.Lookup)
+}
+
+// Load opens the Go plugin, and registers its components in the resolver, isolated under a prefix (see
+// godi.Namespaced), e.g. "payments.client" for the client of the plugin payments.so. The prefix is the name of
+// the plugin file without its extension, unless WithPrefix is given. The components of the plugin are only provided
+// by name, so the components of the application resolved by type are not affected by the plugin.
+func Load(resolver *godi.Resolver, path string, opts ...option.Option[LoadOptions]) error {
+	p, err := goplugin.Open(path)
+	if err != nil {
+		return fmt.Errorf("failed to open plugin %s:\n\t%w", path, err)
+	}
+	return load(resolver, path, p.Lookup, opts...)
+}
+
+// load registers the components of the registry exported by the plugin in the resolver, see Load.
+func load(resolver *godi.Resolver, path string, lookup lookupFunc, opts ...option.Option[LoadOptions]) error {
+	options := option.Build(&LoadOptions{}, opts...)
+	prefix := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	if options.prefix != nil {
+		prefix = *options.prefix
+	}
+
+	registry, err := registryOf(path, lookup)
+	if err != nil {
+		return err
+	}
+	if err := godi.Namespaced(prefix, registry).Install(resolver); err != nil {
+		return fmt.Errorf("failed to register the components of plugin %s:\n\t%w", path, err)
+	}
+	return nil
+}
+
+// registryOf returns the registry exported by the plugin, a plugin exporting a variable gives a pointer to it.
+func registryOf(path string, lookup lookupFunc) (godi.Registry, error) {
+	symbol, err := lookup(RegistrySymbol)
+	if err != nil {
+		return nil, fmt.Errorf("plugin %s does not export a registry:\n\t%w", path, err)
+	}
+	switch registry := symbol.(type) {
+	case *godi.Registry:
+		if *registry == nil {
+			return nil, fmt.Errorf("the registry exported by plugin %s is nil", path)
+		}
+		return *registry, nil
+	case func() godi.Registry:
+		return registry(), nil
+	case godi.Registry:
+		return registry, nil
+	default:
+		return nil, fmt.Errorf("symbol %s of plugin %s is a %T, not a godi.Registry", RegistrySymbol, path, symbol)
+	}
+}
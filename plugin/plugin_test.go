@@ -0,0 +1,136 @@
+package plugin
+
+import (
+	"errors"
+	goplugin "plugin"
+	"testing"
+
+	"github.com/a-peyrard/godi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type (
+	greeterRegistry struct{}
+
+	// client is provided by both the application and the plugin
+	client struct {
+		name string
+	}
+
+	clientRegistry struct{}
+)
+
+func (clientRegistry) Register(resolver *godi.Resolver) {
+	resolver.MustRegister(func() *client { return &client{name: "plugin"} }, godi.Priority(100))
+	resolver.MustRegister(func(c *client) string { return "hello from " + c.name }, godi.Named("greeting"))
+}
+
+func (greeterRegistry) Register(resolver *godi.Resolver) {
+	resolver.MustRegister(func() string { return "hello" }, godi.Named("greeting"))
+}
+
+func exporting(symbol goplugin.Symbol) lookupFunc {
+	return func(symName string) (goplugin.Symbol, error) {
+		if symName != RegistrySymbol {
+			return nil, errors.New("symbol not found")
+		}
+		return symbol, nil
+	}
+}
+
+func TestRegistryOf(t *testing.T) {
+	t.Run("it should get the registry exported as a variable", func(t *testing.T) {
+		// GIVEN
+		var registry godi.Registry = greeterRegistry{}
+
+		// WHEN
+		found, err := registryOf("greeter.so", exporting(&registry))
+
+		// THEN
+		require.NoError(t, err)
+		assert.Equal(t, greeterRegistry{}, found)
+	})
+
+	t.Run("it should get the registry of a variable of a registry type", func(t *testing.T) {
+		// GIVEN
+		registry := greeterRegistry{}
+
+		// WHEN
+		found, err := registryOf("greeter.so", exporting(&registry))
+
+		// THEN
+		require.NoError(t, err)
+		resolver := godi.New()
+		godi.Namespaced("greeter", found).Register(resolver)
+		assert.Equal(t, "hello", godi.MustResolveNamed[string](resolver, "greeter.greeting"))
+	})
+
+	t.Run("it should get the registry exported as a function", func(t *testing.T) {
+		// GIVEN
+		newRegistry := func() godi.Registry { return greeterRegistry{} }
+
+		// WHEN
+		found, err := registryOf("greeter.so", exporting(newRegistry))
+
+		// THEN
+		require.NoError(t, err)
+		assert.Equal(t, greeterRegistry{}, found)
+	})
+
+	t.Run("it should fail if the plugin does not export a registry", func(t *testing.T) {
+		// GIVEN
+		answer := 42
+
+		// WHEN
+		_, err := registryOf("greeter.so", exporting(&answer))
+
+		// THEN
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "is a *int, not a godi.Registry")
+	})
+
+	t.Run("it should fail if the registry is missing", func(t *testing.T) {
+		// GIVEN
+		lookup := func(string) (goplugin.Symbol, error) { return nil, errors.New("symbol Registry not found") }
+
+		// WHEN
+		_, err := registryOf("greeter.so", lookup)
+
+		// THEN
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "does not export a registry")
+	})
+}
+
+func TestLoad(t *testing.T) {
+	t.Run("it should fail if the plugin can not be opened", func(t *testing.T) {
+		// GIVEN
+		resolver := godi.New()
+
+		// WHEN
+		err := Load(resolver, "testdata/missing.so")
+
+		// THEN
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "failed to open plugin testdata/missing.so")
+	})
+	t.Run("it should not change the components of the application resolved by type", func(t *testing.T) {
+		// GIVEN
+		resolver := godi.New()
+		resolver.MustRegister(func() *client { return &client{name: "app"} })
+
+		// WHEN
+		err := load(resolver, "clients.so", exporting(func() godi.Registry { return clientRegistry{} }))
+
+		// THEN
+		require.NoError(t, err)
+		appClient, resolveErr := godi.Resolve[*client](resolver)
+		require.NoError(t, resolveErr)
+		assert.Equal(t, "app", appClient.name)
+		clients, resolveAllErr := godi.ResolveAll[*client](resolver)
+		require.NoError(t, resolveAllErr)
+		assert.Len(t, clients, 1)
+		assert.Equal(t, "hello from plugin", godi.MustResolveNamed[string](resolver, "clients.greeting"))
+	})
+}
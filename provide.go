@@ -1,11 +1,17 @@
 package godi
 
 import (
+	"context"
 	"fmt"
 	"reflect"
+
+	"golang.org/x/sync/errgroup"
 )
 
 type (
+	// retention tells how a built component is kept by the resolver.
+	retention int
+
 	dependencyVertex struct {
 		name         Name
 		resolved     *reflect.Value
@@ -14,22 +20,64 @@ type (
 	}
 )
 
+const (
+	// retainStored stores the component, it is built only once.
+	retainStored retention = iota
+	// retainTracked builds the component even if already stored, it is not stored but closed with the resolver.
+	retainTracked
+	// retainNone builds the component even if already stored, the caller owns it, see Inject.Factory.
+	retainNone
+)
+
 func (r *Resolver) provideUsing(p Provider, name Name, tracker *Tracker) (reflect.Value, error) {
-	err := tracker.Push(name)
+	if attributesOf(p).transient {
+		return r.provide(p, name, tracker, retainTracked)
+	}
+	return r.provide(p, name, tracker, retainStored)
+}
+
+// provide builds the component using the provider, a transient component is built even if already stored,
+// and it is not stored.
+func (r *Resolver) provide(p Provider, name Name, tracker *Tracker, retained retention) (reflect.Value, error) {
+	owner, err := r.ownerOf(p, name)
 	if err != nil {
-		return reflect.Value{}, fmt.Errorf("dependency cycle detected when trying to provide component %s using provider %s:\n\t%w", name, p, err)
+		return reflect.Value{}, err
+	}
+	if owner != r {
+		return owner.provide(p, name, tracker, retained)
+	}
+
+	// the components built for each consumer are stored under the name of their consumer
+	key := name
+	if attributesOf(p).perConsumer {
+		key = name.forConsumer(tracker.top())
 	}
 
-	lock := r.lock.GetLockFor(name)
-	lock.Lock()
+	err = tracker.pushProvided(name, p)
+	if err != nil {
+		return reflect.Value{}, fmt.Errorf("dependency cycle detected when trying to provide component %s using provider %s:\n\t%w", name, p, err)
+	}
+	bound, outer := bind(tracker), tracker.bound
+	tracker.bound = bound
 	defer func() {
-		lock.Unlock()
-		r.lock.ReleaseLock(name) // no need to store the lock anymore, we won't build the same component again
+		bound.release()
+		tracker.bound = outer
 	}()
 
-	// now that we have the lock, check if the component was built while we were waiting
-	if storedComp, found := r.store.Get(name); found {
-		return storedComp, nil
+	// transient components are built on every resolution, there is no need to synchronize their creation
+	if retained == retainStored {
+		lock := r.lock.GetLockFor(key)
+		lock.Lock()
+		defer func() {
+			lock.Unlock()
+			r.lock.ReleaseLock(key) // no need to store the lock anymore, we won't build the same component again
+		}()
+
+		// now that we have the lock, check if the component was built while we were waiting
+		if storedComp, found := r.store.Get(key); found {
+			outer.dependsOn(key)
+			return storedComp, nil
+		}
 	}
 
 	dependencies, err := r.resolveDependencies(p.Dependencies(), tracker)
@@ -37,15 +85,29 @@ func (r *Resolver) provideUsing(p Provider, name Name, tracker *Tracker) (reflec
 		return reflect.Value{}, fmt.Errorf("failed to resolve dependencies for provider %s to provide component %s:\n\t%w", p, name, err)
 	}
 
-	comp, err := p.Provide(name, dependencies)
+	invocationCtx := tracker.ctx
+	if invocationCtx == nil {
+		invocationCtx = context.Background()
+	}
+	invocation := Invocation{Name: name, Provider: p, Dependencies: dependencies, Context: invocationCtx}
+	comp, err := r.intercept(invocation, func() (reflect.Value, error) {
+		return r.provideWithContext(p, name, dependencies, tracker)
+	})
 	if err != nil {
 		return reflect.Value{}, fmt.Errorf("failed to provide component %s using provider %s:\n\t%w", name, p, err)
 	}
 
 	// check if we have decorators to apply
-	decoratorsForName, found := r.decorators.Load(name)
+	decoratorsForName, found := r.decorators.Load(name.unversioned())
 	if found {
 		for _, decorator := range decoratorsForName.(*SortedCOWSlice[Decorator]).All() {
+			applies, err := r.applies(decorator)
+			if err != nil {
+				return reflect.Value{}, err
+			}
+			if !applies {
+				continue
+			}
 			dependencies, err := r.resolveDependencies(decorator.Dependencies(), tracker)
 			if err != nil {
 				return reflect.Value{}, fmt.Errorf("failed to resolve dependencies for decorator %s:\n\t%w", decorator, err)
@@ -54,28 +116,158 @@ func (r *Resolver) provideUsing(p Provider, name Name, tracker *Tracker) (reflec
 			if err != nil {
 				return reflect.Value{}, fmt.Errorf("failed to apply decorator %s to component %s:\n\t%w", decorator, name, err)
 			}
+			r.recordDecoration(name, decorator)
 		}
 	}
 
-	// unstack the current component from the tracker
+	comp, err = r.decorateByType(p, name, comp, tracker)
+	if err != nil {
+		return reflect.Value{}, err
+	}
+
+	if err := r.postConstruct(p, name, comp, tracker); err != nil {
+		return reflect.Value{}, err
+	}
+
+	// unstack the current component from the tracker, once its construction is released
+	bound.release()
 	tracker.Pop()
 
-	// store the component in the store for future use
-	r.store.Put(name, comp)
+	switch retained {
+	case retainStored:
+		// store the component in the store for future use, it is retired along with its dependencies
+		r.store.put(key, comp, attributesOf(p).ttl, attributesOf(p).onClose, bound.builtWith())
+		outer.dependsOn(key)
+	case retainTracked:
+		// transient components are not stored, but they still need to be closed with the resolver,
+		// the component built with it is built with its dependencies
+		r.store.track(name, comp, attributesOf(p).onClose)
+		for _, dependency := range bound.builtWith() {
+			outer.dependsOn(dependency)
+		}
+	case retainNone:
+		// the caller owns the component, keeping a reference to it would leak it
+	}
 
 	return comp, nil
 }
 
 func (r *Resolver) resolveDependencies(requests []Request, tracker *Tracker) ([]reflect.Value, error) {
 	dependencies := make([]reflect.Value, len(requests))
-	for idx, req := range requests {
+	resolveAt := func(idx int, req Request) error {
+		if req.resolutionContext && tracker.ctx != nil {
+			dependencies[idx] = reflect.ValueOf(contextBoundTo(tracker.ctx, tracker.bound))
+			return nil
+		}
+		if req.injectionContext {
+			dependencies[idx] = reflect.ValueOf(tracker.injectionContext())
+			return nil
+		}
 		req.tracker = NewTrackerFrom(tracker)
 		val, _, err := r.resolve(req)
 		if err != nil {
-			return nil, fmt.Errorf("failed to resolve dependency %v:\n\t%w", req, err)
+			return fmt.Errorf("failed to resolve dependency %v:\n\t%w", req, err)
 		}
-		dependencies[idx] = val
+		dependencies[idx] = r.bindDependency(val, tracker)
+		return nil
 	}
 
+	if r.parallelSlots == nil || len(requests) <= 1 {
+		for idx, req := range requests {
+			if err := resolveAt(idx, req); err != nil {
+				return nil, err
+			}
+		}
+		return dependencies, nil
+	}
+
+	// the dependencies are independent, each of them gets its own tracker, so they can be resolved concurrently,
+	// the slots being shared by all the resolutions, a dependency is resolved in place if none is free, as waiting
+	// for a slot could deadlock when the slots are held by the resolutions of the dependencies of this one
+	var (
+		group   errgroup.Group
+		inPlace error
+	)
+	for idx, req := range requests {
+		select {
+		case r.parallelSlots <- struct{}{}:
+			group.Go(func() error {
+				defer func() { <-r.parallelSlots }()
+				return resolveAt(idx, req)
+			})
+		default:
+			if err := resolveAt(idx, req); err != nil && inPlace == nil {
+				inPlace = err
+			}
+		}
+	}
+	if err := group.Wait(); err != nil {
+		return nil, err
+	}
+	if inPlace != nil {
+		return nil, inPlace
+	}
 	return dependencies, nil
 }
+
+// provideWithContext calls the provider, giving up if the context of the resolution is done before the provider returns.
+//
+// The provider can not be interrupted, if it eventually returns a component, it is closed.
+func (r *Resolver) provideWithContext(p Provider, name Name, dependencies []reflect.Value, tracker *Tracker) (reflect.Value, error) {
+	if err := tracker.contextErr(); err != nil {
+		return reflect.Value{}, err
+	}
+	if tracker.ctx == nil || tracker.ctx.Done() == nil {
+		return p.Provide(name.unversioned(), dependencies)
+	}
+
+	type provided struct {
+		comp reflect.Value
+		err  error
+	}
+	done := make(chan provided, 1)
+	go func() {
+		comp, err := p.Provide(name.unversioned(), dependencies)
+		done <- provided{comp: comp, err: err}
+	}()
+
+	select {
+	case res := <-done:
+		return res.comp, res.err
+	case <-tracker.ctx.Done():
+		go func() {
+			if res := <-done; res.err == nil {
+				_ = closeComponent(context.Background(), name, res.comp, attributesOf(p).onClose)
+			}
+		}()
+		return reflect.Value{}, context.Cause(tracker.ctx)
+	}
+}
+
+// postConstruct finishes the setup of the component, with its OnInit hook if any, or its PostConstruct method.
+// The component is closed if its setup fails, as it will not be stored.
+func (r *Resolver) postConstruct(p Provider, name Name, comp reflect.Value, tracker *Tracker) error {
+	if !comp.IsValid() || ((comp.Kind() == reflect.Pointer || comp.Kind() == reflect.Interface) && comp.IsNil()) {
+		return nil
+	}
+	ctx := tracker.ctx
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	var err error
+	if onInit := attributesOf(p).onInit; onInit != nil {
+		err = onInit(ctx, comp)
+	} else if postConstructor, ok := comp.Interface().(PostConstructor); ok {
+		err = postConstructor.PostConstruct(ctx)
+	} else {
+		return nil
+	}
+	if err != nil {
+		if closeErr := closeComponent(ctx, name, comp, attributesOf(p).onClose); closeErr != nil {
+			r.logger.Warn("failed to close component after its initialization failure", "component", name, "error", closeErr)
+		}
+		return fmt.Errorf("failed to initialize component %s:\n\t%w", name, err)
+	}
+	return nil
+}
@@ -1,6 +1,12 @@
 package godi
 
-import "reflect"
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"slices"
+	"time"
+)
 
 type (
 	Provider interface {
@@ -11,4 +17,154 @@ type (
 		Priority() int
 		Description() string
 	}
+
+	// providerAttributes are the registration attributes of a provider, they are not part of the Provider contract,
+	// as they are handled by the resolver itself.
+	providerAttributes struct {
+		ttl         time.Duration
+		version     string
+		scoped      bool
+		transient   bool
+		perConsumer bool
+		eager       bool
+		onClose     closeHook
+		onInit      initHook
+		tags        []string
+		exposedAs   []reflect.Type
+		hidden      bool
+		// named is set for the functions registered with an explicit name, see Named
+		named bool
+		// namespace is the prefix of the namespaced registry registering the provider, its components are only
+		// provided by name, see Namespaced
+		namespace string
+		// conditions are the registration conditions, they all held, see Resolver.Providers
+		conditions []Condition
+		// wiredBy is the name of the wiring the components are taken from, see Wired
+		wiredBy string
+	}
+
+	// initHook finishes the setup of a component, replacing its PostConstruct method, see OnInit.
+	initHook func(ctx context.Context, comp reflect.Value) error
+
+	// attributedProvider wraps a provider registered with some attributes.
+	attributedProvider struct {
+		Provider
+		attributes providerAttributes
+	}
 )
+
+func (o *RegistrableOptions) attributes() providerAttributes {
+	return providerAttributes{
+		ttl:         o.ttl,
+		version:     o.version,
+		scoped:      o.scoped,
+		transient:   o.transient,
+		perConsumer: o.perConsumer,
+		eager:       o.eager,
+		onClose:     o.closeHook(),
+		onInit:      o.onInit,
+		tags:        o.tags,
+		exposedAs:   o.exposedAs,
+		hidden:      o.hidden,
+		named:       o.named != "",
+		conditions:  o.conditions,
+		wiredBy:     o.wiredBy,
+	}
+}
+
+// closeHook returns the hook closing the components, which leaves them open if the resolver does not own them,
+// see OwnedByContainer.
+func (o *RegistrableOptions) closeHook() closeHook {
+	if o.notOwned {
+		return func(context.Context, reflect.Value) error { return nil }
+	}
+	return o.onClose
+}
+
+func (a providerAttributes) isZero() bool {
+	return a.ttl == 0 && a.version == "" &&
+		!a.scoped && !a.transient && !a.perConsumer && !a.eager &&
+		a.onClose == nil && a.onInit == nil &&
+		len(a.tags) == 0 && len(a.exposedAs) == 0 && !a.hidden && !a.named && a.namespace == "" &&
+		len(a.conditions) == 0 && a.wiredBy == ""
+}
+
+// matches checks if a component of the provider, of the given type, matches the queried type,
+// taking into account the interfaces the provider is explicitly exposed as (see As), and if it is hidden (see Hidden).
+func (a providerAttributes) matches(queryType, providedType reflect.Type) bool {
+	if a.hidden {
+		return queryType == providedType
+	}
+	if len(a.exposedAs) == 0 {
+		return matchType(queryType, providedType)
+	}
+	return queryType == providedType || slices.Contains(a.exposedAs, queryType)
+}
+
+// matchesQueriedType checks if a component of the provider, of the given type, matches a query by type, the
+// components of the namespaced registries are only provided by name, see Namespaced.
+func (a providerAttributes) matchesQueriedType(queryType, providedType reflect.Type) bool {
+	return a.namespace == "" && a.matches(queryType, providedType)
+}
+
+// exposes checks if the provider exposes the named component with the type of the name, see As.
+func exposes(p Provider, n Name) bool {
+	attributes := attributesOf(p)
+	if len(attributes.exposedAs) == 0 {
+		return true
+	}
+	for _, provided := range p.ListProvidableNames() {
+		if provided.name == n.name && attributes.matches(n.typ, provided.typ) {
+			return true
+		}
+	}
+	return false
+}
+
+func validateExposedAs(p Provider, exposedAs []reflect.Type) error {
+	for _, iface := range exposedAs {
+		if iface.Kind() != reflect.Interface {
+			return fmt.Errorf("components can only be exposed as interfaces, got %s", iface)
+		}
+		for _, n := range p.ListProvidableNames() {
+			if !n.typ.Implements(iface) {
+				return fmt.Errorf("component %s can not be exposed as %s, it does not implement it", n, iface)
+			}
+		}
+	}
+	return nil
+}
+
+// withAttributes attaches the attributes to the provider, if any.
+func withAttributes(p Provider, attributes providerAttributes) Provider {
+	if multi, ok := p.(*multiResultProvider); ok {
+		multi.bindAttributes(attributes)
+	}
+	if attributes.isZero() {
+		return p
+	}
+	return &attributedProvider{Provider: p, attributes: attributes}
+}
+
+// unwrapped returns the provider without its attributes.
+func unwrapped(p Provider) Provider {
+	if attributed, ok := p.(*attributedProvider); ok {
+		return attributed.Provider
+	}
+	return p
+}
+
+// attributesOf returns the registration attributes of the provider.
+func attributesOf(p Provider) providerAttributes {
+	if attributed, ok := p.(*attributedProvider); ok {
+		return attributed.attributes
+	}
+	return providerAttributes{}
+}
+
+func (a *attributedProvider) String() string {
+	if stringer, ok := a.Provider.(fmt.Stringer); ok {
+		return stringer.String()
+	}
+	return fmt.Sprintf("%T", a.Provider)
+}
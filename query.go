@@ -12,12 +12,26 @@ type (
 		fmt.Stringer
 	}
 
+	// plannedQuery is a query whose providers lookup can be computed once for all on a compiled resolver.
+	plannedQuery interface {
+		query
+
+		// plan returns the candidates among the providers looked up, see Resolver.candidatesFor
+		plan(lookup providerLookup) []candidate
+	}
+
 	queryResult struct {
 		name      Name
 		component *reflect.Value
 		provider  Provider
 	}
 
+	// candidate is a provider able to provide the named component.
+	candidate struct {
+		name     Name
+		provider Provider
+	}
+
 	queryByType struct {
 		typ reflect.Type
 	}
@@ -27,31 +41,65 @@ type (
 	}
 )
 
+// candidatesFor returns the providers planned by the query. If the resolver is compiled, no provider can be
+// registered anymore, so the plan of the providers with fixed names is cached (see Builder.Compile). The dynamic
+// providers (e.g. EnvProvider) are planned again on every query, as their answers change over time, e.g. once an env
+// var is set, and only if they answer the query, it is planned with all the providers, so they are ranked with them.
+func (r *Resolver) candidatesFor(q plannedQuery) []candidate {
+	index := r.indexOfProviders()
+	if !r.compiled.Load() {
+		return q.plan(index.all())
+	}
+	if len(index.scanned) > 0 && len(q.plan(index.scannedOnly())) > 0 {
+		return q.plan(index.all())
+	}
+	return r.fixedCandidatesFor(q, index)
+}
+
+// fixedCandidatesFor returns the providers with fixed names planned by the query, the plan being cached.
+func (r *Resolver) fixedCandidatesFor(q plannedQuery, index *providerIndex) []candidate {
+	if cached, found := r.plans.Load(q); found {
+		return cached.([]candidate)
+	}
+	candidates := q.plan(index.fixedOnly())
+	r.plans.Store(q, candidates)
+	return candidates
+}
+
 func (q queryByType) find(r *Resolver) ([]*queryResult, error) {
-	// find all the providable names that match the type
-	nameWithProviderMap := make(map[Name]*queryResult)
-	for _, provider := range r.providers.All() {
+	candidates := r.candidatesFor(q)
+	values := make([]*queryResult, 0, len(candidates))
+	for _, c := range candidates {
+		var comp *reflect.Value = nil
+		if storedComp, found := r.store.Get(c.name); found {
+			comp = &storedComp
+		}
+		values = append(values, &queryResult{
+			name:      c.name,
+			component: comp,
+			provider:  c.provider,
+		})
+	}
+	return values, nil
+}
+
+func (q queryByType) plan(lookup providerLookup) []candidate {
+	// find all the providable names that match the type, keeping the providers order (by priority)
+	seen := make(map[Name]struct{})
+	var candidates []candidate
+	for _, provider := range lookup.providersOfType(q.typ) {
 		namesForProvider := provider.ListProvidableNames()
 		for _, n := range namesForProvider {
-			if _, exists := nameWithProviderMap[n]; !exists && matchType(q.typ, n.typ) {
-				var comp *reflect.Value = nil
-				if storedComp, found := r.store.Get(n); found {
-					comp = &storedComp
-				}
-				nameWithProviderMap[n] = &queryResult{
-					name:      n,
-					component: comp,
-					provider:  provider,
-				}
+			if _, exists := seen[n]; !exists && attributesOf(provider).matchesQueriedType(q.typ, n.typ) {
+				seen[n] = struct{}{}
+				candidates = append(candidates, candidate{
+					name:     versionedName(n, provider),
+					provider: provider,
+				})
 			}
 		}
 	}
-
-	values := make([]*queryResult, 0, len(nameWithProviderMap))
-	for _, v := range nameWithProviderMap {
-		values = append(values, v)
-	}
-	return values, nil
+	return candidates
 }
 
 func (q queryByType) String() string {
@@ -70,21 +118,36 @@ func (q queryByName) find(r *Resolver) ([]*queryResult, error) {
 		}, nil
 	}
 
-	for _, provider := range r.providers.All() {
-		if provider.CanProvide(q.name) {
-			return []*queryResult{
+	return resultsOf(r.candidatesFor(q)), nil
+}
+
+func (q queryByName) plan(lookup providerLookup) []candidate {
+	for _, provider := range lookup.providersNamed(q.name.name) {
+		if provider.CanProvide(q.name) && exposes(provider, q.name) {
+			return []candidate{
 				{
-					name:      q.name,
-					component: nil,
-					provider:  provider,
+					name:     versionedName(q.name, provider),
+					provider: provider,
 				},
-			}, nil
+			}
 		}
 	}
 
-	return []*queryResult{}, nil
+	return []candidate{}
 }
 
 func (q queryByName) String() string {
 	return fmt.Sprintf("<type~=%s & name=%s>", q.name.typ.String(), q.name.name)
 }
+
+func resultsOf(candidates []candidate) []*queryResult {
+	results := make([]*queryResult, 0, len(candidates))
+	for _, c := range candidates {
+		results = append(results, &queryResult{
+			name:      c.name,
+			component: nil,
+			provider:  c.provider,
+		})
+	}
+	return results
+}
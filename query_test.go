@@ -0,0 +1,28 @@
+package godi
+
+import (
+	"testing"
+
+	"github.com/a-peyrard/godi/slices"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestQueryByType(t *testing.T) {
+	t.Run("it should find the components of a type in priority order", func(t *testing.T) {
+		// GIVEN
+		resolver := New()
+		resolver.MustRegister(func() *TestService { return &TestService{Name: "low"} }, Named("low"), Priority(1))
+		resolver.MustRegister(func() *TestService { return &TestService{Name: "high"} }, Named("high"), Priority(10))
+		resolver.MustRegister(func() *TestService { return &TestService{Name: "medium"} }, Named("medium"), Priority(5))
+
+		// WHEN
+		resolved, err := ResolveAll[*TestService](resolver)
+
+		// THEN
+		require.NoError(t, err)
+		assert.Equal(t, []string{"high", "medium", "low"}, slices.Map(resolved, func(s *TestService) string {
+			return s.Name
+		}))
+	})
+}
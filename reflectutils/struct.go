@@ -1,41 +1,107 @@
 package reflectutils
 
 import (
+	"fmt"
 	"github.com/a-peyrard/godi/fn"
+	"github.com/a-peyrard/godi/option"
 	"reflect"
+	"slices"
+	"sort"
+	"strconv"
 )
 
+type (
+	// WalkOptions configures how WalkStruct walks the nested fields.
+	WalkOptions struct {
+		maxDepth    int
+		unexported  bool
+		collections bool
+	}
+
+	walker struct {
+		consumer fn.TriConsumer[reflect.Value, reflect.Type, []string]
+		options  *WalkOptions
+	}
+)
+
+// WithMaxDepth stops walking the fields nested deeper than the given depth, the fields of the element being at
+// depth 1. A depth of 0, the default, walks all the fields.
+func WithMaxDepth(depth int) option.Option[WalkOptions] {
+	return func(opts *WalkOptions) {
+		opts.maxDepth = depth
+	}
+}
+
+// WithUnexportedFields walks the unexported fields too, they are skipped by default. Their values can neither be set
+// nor interfaced.
+func WithUnexportedFields() option.Option[WalkOptions] {
+	return func(opts *WalkOptions) {
+		opts.unexported = true
+	}
+}
+
+// WithCollections walks the elements of the slices, arrays and maps, named after their index or key in the path,
+// the maps being walked in the order of their keys. The elements of the maps can not be set.
+func WithCollections() option.Option[WalkOptions] {
+	return func(opts *WalkOptions) {
+		opts.collections = true
+	}
+}
+
 // WalkStruct applies a bi-consumer on all fields and nested fields of a given object.
-func WalkStruct[T any](element T, consumer fn.TriConsumer[reflect.Value, reflect.Type, []string]) {
-	walkStructInternal(reflect.ValueOf(element), []string{}, consumer)
+//
+// A struct type is not walked again within itself, so the self-referencing types, e.g. type Node struct { Next *Node },
+// are walked once, even if the consumer creates the nil structs.
+func WalkStruct[T any](element T, consumer fn.TriConsumer[reflect.Value, reflect.Type, []string], opts ...option.Option[WalkOptions]) {
+	w := walker{consumer: consumer, options: option.Build(&WalkOptions{}, opts...)}
+	w.walk(reflect.ValueOf(element), []string{}, nil)
 }
 
-func walkStructInternal(val reflect.Value, path []string, consumer fn.TriConsumer[reflect.Value, reflect.Type, []string]) {
-	var (
-		nestedVal   reflect.Value
-		structField reflect.StructField
-	)
+// walk applies the consumer on the value and its nested fields, the ancestors being the struct types walked to reach it.
+func (w walker) walk(val reflect.Value, path []string, ancestors []reflect.Type) {
 	// apply the consumer
-	consumer(val, val.Type(), path)
+	w.consumer(val, val.Type(), path)
 
 	// dereference the value
 	val = Deref(val)
 
-	if !val.IsValid() {
+	if !val.IsValid() || (w.options.maxDepth > 0 && len(path) >= w.options.maxDepth) {
 		return
 	}
 
-	// loop on fields
-	if val.Kind() == reflect.Struct {
+	switch val.Kind() {
+	case reflect.Struct:
 		typ := val.Type()
+		if slices.Contains(ancestors, typ) {
+			return
+		}
+		ancestors = append(slices.Clip(ancestors), typ)
 		for i := 0; i < typ.NumField(); i++ {
-			structField = typ.Field(i)
-			if !structField.IsExported() {
+			structField := typ.Field(i)
+			if !structField.IsExported() && !w.options.unexported {
 				continue
 			}
-			nestedVal = val.Field(i)
+			w.walk(val.Field(i), append(slices.Clip(path), structField.Name), ancestors)
+		}
 
-			walkStructInternal(nestedVal, append(path, structField.Name), consumer)
+	case reflect.Slice, reflect.Array:
+		if !w.options.collections {
+			return
+		}
+		for i := 0; i < val.Len(); i++ {
+			w.walk(val.Index(i), append(slices.Clip(path), strconv.Itoa(i)), ancestors)
+		}
+
+	case reflect.Map:
+		if !w.options.collections {
+			return
+		}
+		keys := val.MapKeys()
+		sort.Slice(keys, func(i, j int) bool {
+			return fmt.Sprint(keys[i]) < fmt.Sprint(keys[j])
+		})
+		for _, key := range keys {
+			w.walk(val.MapIndex(key), append(slices.Clip(path), fmt.Sprint(key)), ancestors)
 		}
 	}
 }
@@ -48,9 +114,10 @@ func Deref(value reflect.Value) reflect.Value {
 	return value
 }
 
-// CreateNilStructs creates new struct instances for nil struct pointers
+// CreateNilStructs creates new struct instances for nil struct pointers, the ones which can be set
 func CreateNilStructs(val reflect.Value, typ reflect.Type, _ []string) {
 	if typ.Kind() == reflect.Pointer &&
+		val.CanSet() &&
 		val.IsNil() &&
 		typ.Elem().Kind() == reflect.Struct {
 
@@ -59,7 +126,7 @@ func CreateNilStructs(val reflect.Value, typ reflect.Type, _ []string) {
 }
 
 func CreateEmptyArrays(val reflect.Value, typ reflect.Type, _ []string) {
-	if typ.Kind() == reflect.Slice && val.IsNil() {
+	if typ.Kind() == reflect.Slice && val.CanSet() && val.IsNil() {
 		val.Set(reflect.MakeSlice(typ, 0, 0))
 	}
 }
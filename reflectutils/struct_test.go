@@ -38,6 +38,14 @@ type (
 	TestConfigWithArray struct {
 		SomeValue []string
 	}
+	TestNode struct {
+		Name string
+		Next *TestNode
+	}
+	TestConfigWithCollections struct {
+		Foos   []FooTestConfig
+		Labels map[string]string
+	}
 )
 
 func (c *TestConfig) ApplyDefault() {
@@ -209,4 +217,78 @@ func TestWalkStruct(t *testing.T) {
 		expectedPaths := []string{"Foo", "Foo.Hello", "Foo.World", "Bar", "Bar.First", "Bar.Second", "SomeValue"}
 		assert.ElementsMatch(t, expectedPaths, capturedPaths)
 	})
+
+	t.Run("it should not walk forever self-referencing types", func(t *testing.T) {
+		// GIVEN
+		var capturedPaths []string
+		pathCapture := func(val reflect.Value, typ reflect.Type, path []string) {
+			capturedPaths = append(capturedPaths, strings.Join(path, "."))
+		}
+
+		// WHEN
+		element := &TestNode{}
+		WalkStruct(element, fn.AllTriConsumer(CreateNilStructs, pathCapture))
+
+		// THEN
+		assert.Equal(t, []string{"", "Name", "Next"}, capturedPaths)
+		require.NotNil(t, element.Next)
+		assert.Nil(t, element.Next.Next)
+	})
+
+	t.Run("it should not walk the fields deeper than the max depth", func(t *testing.T) {
+		// GIVEN
+		var capturedPaths []string
+		pathCapture := func(val reflect.Value, typ reflect.Type, path []string) {
+			if len(path) > 0 {
+				capturedPaths = append(capturedPaths, strings.Join(path, "."))
+			}
+		}
+
+		// WHEN
+		element := &TestConfig{Foo: &FooTestConfig{}}
+		WalkStruct(element, pathCapture, WithMaxDepth(1))
+
+		// THEN
+		assert.ElementsMatch(t, []string{"Foo", "Bar", "SomeValue"}, capturedPaths)
+	})
+
+	t.Run("it should walk the unexported fields if asked to", func(t *testing.T) {
+		// GIVEN
+		var capturedPaths []string
+		pathCapture := func(val reflect.Value, typ reflect.Type, path []string) {
+			if len(path) > 0 {
+				capturedPaths = append(capturedPaths, strings.Join(path, "."))
+			}
+		}
+
+		// WHEN
+		element := &TestConfigWithPrivate{foobar: &FooBarTestConfig{}}
+		WalkStruct(element, fn.AllTriConsumer(CreateNilStructs, pathCapture), WithUnexportedFields())
+
+		// THEN
+		assert.Contains(t, capturedPaths, "foobar.waldo")
+	})
+
+	t.Run("it should walk the elements of slices and maps if asked to", func(t *testing.T) {
+		// GIVEN
+		var capturedPaths []string
+		pathCapture := func(val reflect.Value, typ reflect.Type, path []string) {
+			if len(path) > 0 {
+				capturedPaths = append(capturedPaths, strings.Join(path, "."))
+			}
+		}
+
+		// WHEN
+		element := &TestConfigWithCollections{
+			Foos:   []FooTestConfig{{}, {}},
+			Labels: map[string]string{"b": "2", "a": "1"},
+		}
+		WalkStruct(element, pathCapture, WithCollections())
+
+		// THEN
+		assert.Equal(t, []string{
+			"Foos", "Foos.0", "Foos.0.Hello", "Foos.0.World", "Foos.1", "Foos.1.Hello", "Foos.1.World",
+			"Labels", "Labels.a", "Labels.b",
+		}, capturedPaths)
+	})
 }
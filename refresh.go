@@ -0,0 +1,166 @@
+package godi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// Evict removes the components with the given name from the resolver, and closes them, so the next resolution
+// builds them again, e.g. to reconnect a client. The errors of their closing are returned.
+//
+// Nothing is evicted if the components were not built yet. Note that the components already built with the evicted
+// components are not rebuilt, they keep a reference to them. The wired components can not be evicted, see Wired.
+func (r *Resolver) Evict(name string) error {
+	if !r.isProvided(name) {
+		return fmt.Errorf("unable to evict %s, no provider is registered for it", name)
+	}
+	if err := r.checkNotWired("evict", name); err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, n := range r.storedNamesOf(name) {
+		stored, found := r.evict(n)
+		if !found {
+			continue
+		}
+		if err := stored.close(context.Background()); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Refresh builds again the components with the given name, and replaces the ones stored in the resolver,
+// e.g. to rotate the credentials of a client. The replaced components are closed once the new ones are built,
+// and are kept if the build fails. The resolutions concurrent to the refresh get the new components.
+//
+// As for Evict, the components already built with the refreshed components are not rebuilt, and the components
+// built for each consumer (see PerConsumer) are only evicted, they are built again on their next resolution.
+// The wired components can not be refreshed, see Wired.
+func (r *Resolver) Refresh(name string) error {
+	if !r.isProvided(name) {
+		return fmt.Errorf("unable to refresh %s, no provider is registered for it", name)
+	}
+	if err := r.checkNotWired("refresh", name); err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, n := range r.storedNamesOf(name) {
+		var p Provider
+		if n.consumer == "" {
+			p = r.storedBy(n)
+		}
+		stored, found := r.evict(n)
+		if !found {
+			continue
+		}
+		if p != nil {
+			tracker := NewTracker()
+			tracker.ctx = r.ctx
+			if _, err := r.provide(p, n, tracker, retainStored); err != nil {
+				// the previous component is restored, unless a concurrent resolution built a new one meanwhile
+				if _, rebuilt := r.store.inner.LoadOrStore(n, stored); !rebuilt {
+					errs = append(errs, fmt.Errorf("failed to refresh %s, the previous component is kept:\n\t%w", n, err))
+					continue
+				}
+				errs = append(errs, fmt.Errorf("failed to refresh %s:\n\t%w", n, err))
+			}
+		}
+		if err := stored.close(context.Background()); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// RefreshableProvider is implemented by the dynamic providers whose providable names change over time, e.g. the
+// plugins configured in a directory, so they can list their names again, see Resolver.RefreshProviders.
+type RefreshableProvider interface {
+	Provider
+
+	// RefreshNames updates the names listed by ListProvidableNames, it might be called concurrently
+	// to the resolutions.
+	RefreshNames() error
+}
+
+// RefreshProviders asks the providers implementing RefreshableProvider to list their names again, then drops the
+// index of the providers and the query plans (see Builder), so the next resolutions see the new names, even
+// on a compiled resolver. The errors of the providers are returned, the other providers are refreshed anyway.
+//
+// The components already built are kept, even the ones whose name disappeared.
+func (r *Resolver) RefreshProviders() error {
+	var errs []error
+	for _, p := range r.providers.All() {
+		refreshable, ok := unwrapped(p).(RefreshableProvider)
+		if !ok {
+			continue
+		}
+		if err := refreshable.RefreshNames(); err != nil {
+			errs = append(errs, fmt.Errorf("failed to refresh the names of provider %s:\n\t%w", p, err))
+		}
+	}
+	r.index.Store(nil)
+	r.resetPlans()
+	return errors.Join(errs...)
+}
+
+// evict removes the stored component, waiting for its construction if it is in progress.
+func (r *Resolver) evict(n Name) (*storedComponent, bool) {
+	lock := r.lock.GetLockFor(n)
+	lock.Lock()
+	defer func() {
+		lock.Unlock()
+		r.lock.ReleaseLock(n)
+	}()
+	return r.store.remove(n)
+}
+
+// storedNamesOf returns the names under which the components with the given name are stored, starting with
+// the results of the factory method returning them along with other components, if any, so they are all built again
+// together, see multiResultProvider.
+func (r *Resolver) storedNamesOf(name string) []Name {
+	resultsOf := map[string]bool{name + resultsSuffix: true}
+	together := map[string]bool{name: true}
+	for _, p := range r.providers.All() {
+		if multi, ok := unwrapped(p).(*multiResultProvider); ok && providesName(multi, name) {
+			// e.g. the fields of a godi.Out struct, which are not named after the factory method
+			resultsOf[multi.call.name.name] = true
+			for _, n := range multi.names {
+				together[n.name] = true
+			}
+		}
+	}
+
+	var results, names []Name
+	for _, n := range r.store.ListNames() {
+		switch {
+		case resultsOf[n.name]:
+			results = append(results, n)
+		case together[n.name]:
+			names = append(names, n)
+		}
+	}
+	return append(results, names...)
+}
+
+// storedBy returns the provider of the stored component, nil if it is not registered anymore.
+func (r *Resolver) storedBy(n Name) Provider {
+	for _, p := range r.providers.All() {
+		if attributesOf(p).version == n.version && p.CanProvide(n.unversioned()) {
+			return p
+		}
+	}
+	return nil
+}
+
+func (r *Resolver) isProvided(name string) bool {
+	for _, p := range r.providers.All() {
+		if providesName(p, name) {
+			return true
+		}
+	}
+	return false
+}
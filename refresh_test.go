@@ -0,0 +1,228 @@
+package godi
+
+import (
+	"errors"
+	"reflect"
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolver_Evict(t *testing.T) {
+	t.Run("it should close the stored component, and build it again on the next resolution", func(t *testing.T) {
+		// GIVEN
+		builds := 0
+		resolver := New()
+		resolver.MustRegister(func() *TestService {
+			builds++
+			return &TestService{Name: "client-" + strconv.Itoa(builds)}
+		}, Named("client"))
+		client := MustResolveNamed[*TestService](resolver, "client")
+
+		// WHEN
+		err := resolver.Evict("client")
+
+		// THEN
+		require.NoError(t, err)
+		assert.True(t, client.closed)
+		assert.Equal(t, "client-2", MustResolveNamed[*TestService](resolver, "client").Name)
+	})
+
+	t.Run("it should do nothing if the component is not built yet", func(t *testing.T) {
+		// GIVEN
+		resolver := New()
+		resolver.MustRegister(func() *TestService { return &TestService{Name: "client"} }, Named("client"))
+
+		// WHEN
+		err := resolver.Evict("client")
+
+		// THEN
+		require.NoError(t, err)
+		assert.Equal(t, "client", MustResolveNamed[*TestService](resolver, "client").Name)
+	})
+
+	t.Run("it should fail if nothing provides the name", func(t *testing.T) {
+		// GIVEN
+		resolver := New()
+
+		// WHEN
+		err := resolver.Evict("unknown")
+
+		// THEN
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "no provider is registered for it")
+	})
+}
+
+func TestResolver_Refresh(t *testing.T) {
+	t.Run("it should replace the stored component by a new one, and close the previous one", func(t *testing.T) {
+		// GIVEN
+		builds := 0
+		resolver := New()
+		resolver.MustRegister(func() *TestService {
+			builds++
+			return &TestService{Name: "client-" + strconv.Itoa(builds)}
+		}, Named("client"))
+		client := MustResolveNamed[*TestService](resolver, "client")
+
+		// WHEN
+		err := resolver.Refresh("client")
+
+		// THEN
+		require.NoError(t, err)
+		assert.True(t, client.closed)
+		assert.Equal(t, 2, builds)
+		assert.Equal(t, "client-2", MustResolveNamed[*TestService](resolver, "client").Name)
+		assert.Equal(t, 2, builds)
+	})
+
+	t.Run("it should keep the previous component if the new one can not be built", func(t *testing.T) {
+		// GIVEN
+		fail := false
+		resolver := New()
+		resolver.MustRegister(func() (*TestService, error) {
+			if fail {
+				return nil, errors.New("credentials expired")
+			}
+			return &TestService{Name: "client"}, nil
+		}, Named("client"))
+		client := MustResolveNamed[*TestService](resolver, "client")
+		fail = true
+
+		// WHEN
+		err := resolver.Refresh("client")
+
+		// THEN
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "credentials expired")
+		assert.False(t, client.closed)
+		assert.Same(t, client, MustResolveNamed[*TestService](resolver, "client"))
+	})
+
+	t.Run("it should be safe to refresh while resolving", func(t *testing.T) {
+		// GIVEN
+		resolver := New()
+		resolver.MustRegister(func() *TestService { return &TestService{Name: "client"} }, Named("client"))
+		MustResolveNamed[*TestService](resolver, "client")
+
+		// WHEN
+		var wg sync.WaitGroup
+		for i := 0; i < 10; i++ {
+			wg.Add(2)
+			go func() {
+				defer wg.Done()
+				assert.NoError(t, resolver.Refresh("client"))
+			}()
+			go func() {
+				defer wg.Done()
+				_, err := ResolveNamed[*TestService](resolver, "client")
+				assert.NoError(t, err)
+			}()
+		}
+		wg.Wait()
+
+		// THEN
+		assert.Equal(t, "client", MustResolveNamed[*TestService](resolver, "client").Name)
+	})
+}
+
+// pluginsProvider provides a string per plugin, the plugins being discovered on refresh.
+type pluginsProvider struct {
+	mu        sync.Mutex
+	names     []Name
+	discover  func() ([]string, error)
+	refreshes int
+}
+
+func (p *pluginsProvider) CanProvide(name Name) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for _, n := range p.names {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
+
+func (p *pluginsProvider) Provide(name Name, _ []reflect.Value) (reflect.Value, error) {
+	return reflect.ValueOf("plugin " + name.name), nil
+}
+
+func (p *pluginsProvider) Dependencies() []Request {
+	return nil
+}
+
+func (p *pluginsProvider) ListProvidableNames() []Name {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.names
+}
+
+func (p *pluginsProvider) Priority() int {
+	return 0
+}
+
+func (p *pluginsProvider) Description() string {
+	return ""
+}
+
+func (p *pluginsProvider) RefreshNames() error {
+	plugins, err := p.discover()
+	if err != nil {
+		return err
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.refreshes++
+	p.names = nil
+	for _, plugin := range plugins {
+		p.names = append(p.names, Name{name: plugin, typ: StringType})
+	}
+	return nil
+}
+
+func TestResolver_RefreshProviders(t *testing.T) {
+	t.Run("it should surface the new names of a compiled resolver once refreshed", func(t *testing.T) {
+		// GIVEN
+		plugins := []string{"a"}
+		provider := &pluginsProvider{discover: func() ([]string, error) { return plugins, nil }}
+		require.NoError(t, provider.RefreshNames())
+		resolver := NewBuilder().Providers(provider).MustCompile()
+		before, err := ResolveAll[string](resolver)
+		require.NoError(t, err)
+		plugins = []string{"a", "b"}
+
+		// WHEN
+		err = resolver.RefreshProviders()
+
+		// THEN
+		require.NoError(t, err)
+		after, err := ResolveAll[string](resolver)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"plugin a"}, before)
+		assert.ElementsMatch(t, []string{"plugin a", "plugin b"}, after)
+		assert.Equal(t, "plugin b", MustResolveNamed[string](resolver, "b"))
+	})
+
+	t.Run("it should report the errors of the providers, and refresh the other ones", func(t *testing.T) {
+		// GIVEN
+		broken := &pluginsProvider{discover: func() ([]string, error) { return nil, errors.New("unreadable directory") }}
+		working := &pluginsProvider{discover: func() ([]string, error) { return []string{"a"}, nil }}
+		resolver := New()
+		resolver.MustRegister(broken, Named("broken"))
+		resolver.MustRegister(working, Named("working"))
+
+		// WHEN
+		err := resolver.RefreshProviders()
+
+		// THEN
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "unreadable directory")
+		assert.Equal(t, 1, working.refreshes)
+		assert.Equal(t, "plugin a", MustResolveNamed[string](resolver, "a"))
+	})
+}
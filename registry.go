@@ -1,8 +1,133 @@
 package godi
 
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+)
+
+type (
+	// Registry registers components in a resolver, the registries generated from the annotations implement it.
+	Registry interface {
+		Register(resolver *Resolver)
+	}
+
+	// CompositeRegistry registers the components of several registries, e.g. the ones of the modules
+	// of an application, see Compose.
+	CompositeRegistry struct {
+		registries []Registry
+	}
+)
+
 type EmptyRegistry struct {
 }
 
 func (e EmptyRegistry) Register(*Resolver) {
 
 }
+
+// Compose creates a registry registering the components of the registries, in order.
+//
+// The composite registries are flattened, and a registry composed several times (e.g. the registry of a module
+// shared by other modules) is only registered once.
+func Compose(registries ...Registry) CompositeRegistry {
+	return CompositeRegistry{}.With(registries...)
+}
+
+// With returns a registry registering the components of the registry, then the ones of the others.
+func (c CompositeRegistry) With(others ...Registry) CompositeRegistry {
+	composed := CompositeRegistry{registries: slices.Clone(c.registries)}
+	for _, other := range others {
+		if nested, ok := other.(CompositeRegistry); ok {
+			composed = composed.With(nested.registries...)
+			continue
+		}
+		if other != nil && !composed.contains(other) {
+			composed.registries = append(composed.registries, other)
+		}
+	}
+	return composed
+}
+
+func (c CompositeRegistry) contains(registry Registry) bool {
+	if !reflect.TypeOf(registry).Comparable() {
+		return false
+	}
+	return slices.Contains(c.registries, registry)
+}
+
+// Registries returns the registries composed, flattened.
+func (c CompositeRegistry) Registries() []Registry {
+	return slices.Clone(c.registries)
+}
+
+// Install registers the components of the registries, and fails if several registries register a component
+// with the same name at the same priority, as the last one registered would silently win.
+//
+// The components are registered even if there are conflicts.
+func (c CompositeRegistry) Install(resolver *Resolver) error {
+	registered := make([][]Provider, len(c.registries))
+	for i, registry := range c.registries {
+		registered[i] = recordRegistrations(resolver, registry)
+	}
+
+	var errs []error
+	for i := range c.registries {
+		for j := i + 1; j < len(c.registries); j++ {
+			for _, conflict := range conflictingNames(registered[i], registered[j]) {
+				errs = append(errs, fmt.Errorf(
+					"%s is registered by %T and %T with the same priority %d", conflict.name, c.registries[i], c.registries[j], conflict.priority,
+				))
+			}
+		}
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("the registries register the same components:\n\t%w", errors.Join(errs...))
+	}
+	return nil
+}
+
+// Register registers the components of the registries, see Install.
+//
+// It panics if several registries register a component with the same name at the same priority.
+func (c CompositeRegistry) Register(resolver *Resolver) {
+	mustSucceed(resolver.logger, c.Install(resolver), "failed to register the composed registries")
+}
+
+// recordRegistrations registers the components of the registry, returning the providers it registered.
+func recordRegistrations(resolver *Resolver, registry Registry) []Provider {
+	var providers []Provider
+	record := func(provider Provider) {
+		providers = append(providers, provider)
+	}
+	previous := resolver.onRegister.Swap(&record)
+	defer resolver.onRegister.Store(previous)
+
+	registry.Register(resolver)
+	return providers
+}
+
+type nameConflict struct {
+	name     Name
+	priority int
+}
+
+// conflictingNames returns the names provided by both sets of providers with the same priority and version.
+func conflictingNames(providers []Provider, others []Provider) []nameConflict {
+	var conflicts []nameConflict
+	for _, other := range others {
+		for _, provider := range providers {
+			if provider.Priority() != other.Priority() || attributesOf(provider).version != attributesOf(other).version {
+				continue
+			}
+			for _, n := range other.ListProvidableNames() {
+				if provider.CanProvide(n) {
+					conflicts = append(conflicts, nameConflict{name: n, priority: other.Priority()})
+					break
+				}
+			}
+		}
+	}
+	return conflicts
+}
@@ -0,0 +1,97 @@
+package godi
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type greetingRegistry struct {
+	greeting string
+	priority int
+}
+
+func (g greetingRegistry) Register(resolver *Resolver) {
+	resolver.MustRegister(func() string { return g.greeting }, Named("greeting"), Priority(g.priority))
+}
+
+type registryFunc func(resolver *Resolver)
+
+func (f registryFunc) Register(resolver *Resolver) {
+	f(resolver)
+}
+
+func TestCompositeRegistry(t *testing.T) {
+	t.Run("it should register the components of all the registries", func(t *testing.T) {
+		// GIVEN
+		resolver := New()
+		registry := Compose(greetingRegistry{greeting: "hello"}).With(registryFunc(func(resolver *Resolver) {
+			resolver.MustRegister(func() int { return 42 }, Named("answer"))
+		}))
+
+		// WHEN
+		err := registry.Install(resolver)
+
+		// THEN
+		require.NoError(t, err)
+		assert.Equal(t, "hello", MustResolveNamed[string](resolver, "greeting"))
+		assert.Equal(t, 42, MustResolveNamed[int](resolver, "answer"))
+	})
+
+	t.Run("it should flatten the composite registries, and only keep the first occurrence of a registry", func(t *testing.T) {
+		// GIVEN
+		common := greetingRegistry{greeting: "hello"}
+		module := Compose(common, greetingRegistry{greeting: "bonjour", priority: 10})
+
+		// WHEN
+		registry := Compose(common).With(module, EmptyRegistry{})
+
+		// THEN
+		assert.Equal(t, []Registry{
+			common,
+			greetingRegistry{greeting: "bonjour", priority: 10},
+			EmptyRegistry{},
+		}, registry.Registries())
+		assert.NoError(t, registry.Install(New()))
+	})
+
+	t.Run("it should report the components registered by several registries with the same priority", func(t *testing.T) {
+		// GIVEN
+		resolver := New()
+		registry := Compose(greetingRegistry{greeting: "hello"}, greetingRegistry{greeting: "bonjour"})
+
+		// WHEN
+		err := registry.Install(resolver)
+
+		// THEN
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "(greeting, string) is registered by godi.greetingRegistry and godi.greetingRegistry with the same priority 0")
+		assert.Equal(t, "bonjour", MustResolveNamed[string](resolver, "greeting"), "the components are registered anyway")
+	})
+
+	t.Run("it should not report the components registered with different priorities", func(t *testing.T) {
+		// GIVEN
+		resolver := New()
+		registry := Compose(greetingRegistry{greeting: "hello", priority: 10}, greetingRegistry{greeting: "bonjour"})
+
+		// WHEN
+		err := registry.Install(resolver)
+
+		// THEN
+		require.NoError(t, err)
+		assert.Equal(t, "hello", MustResolveNamed[string](resolver, "greeting"))
+	})
+
+	t.Run("it should panic on conflicts when used as a registry", func(t *testing.T) {
+		// GIVEN
+		resolver := New()
+		var registry Registry = Compose(greetingRegistry{greeting: "hello"}, greetingRegistry{greeting: "bonjour"})
+
+		// WHEN
+		register := func() { registry.Register(resolver) }
+
+		// THEN
+		assert.Panics(t, register)
+	})
+}
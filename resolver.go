@@ -1,18 +1,19 @@
 package godi
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"github.com/a-peyrard/godi/fn"
 	"github.com/a-peyrard/godi/option"
-	"log"
 	"reflect"
+	"slices"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 )
 
-const perfOutput = true
-
 type (
 	Query interface {
 	}
@@ -20,6 +21,12 @@ type (
 	Name struct {
 		name string
 		typ  reflect.Type
+
+		// version is only set for components provided by a versioned provider, see Version
+		version string
+
+		// consumer is only set for the components built for a given consumer, see PerConsumer
+		consumer string
 	}
 
 	Request struct {
@@ -28,14 +35,91 @@ type (
 		validator  validator
 		collector  collector
 		tracker    *Tracker
+
+		// ctx is the context of a top level resolution, it is then carried by the tracker
+		ctx context.Context
+
+		// resolutionContext requests the context of the resolution, if any, instead of a context component
+		resolutionContext bool
+
+		// injectionContext requests the InjectionContext of the component being built
+		injectionContext bool
+
+		// fallback is the value used when nothing is found for the request, if any
+		fallback *reflect.Value
 	}
 
 	Resolver struct {
+		*resolverState
+
+		// bound is the construction the resolver was injected into, if any, so the resolutions made by its provider
+		// are tracked along the construction, see boundResolution
+		bound *boundResolution
+	}
+
+	// resolverState is the state of a resolver, shared with the resolvers bound to its constructions.
+	resolverState struct {
 		providers  *SortedCOWSlice[Provider]
 		decorators sync.Map // type of keys is Name, type of values is *SortedCOWSlice[Decorator]
 		store      *Store
 
 		lock *LockManager
+
+		// inflight counts the top level resolutions in progress
+		inflight atomic.Int64
+
+		// index is the index of the current snapshot of the providers, see indexOfProviders
+		index atomic.Pointer[providerIndex]
+
+		// compiled resolvers do not accept registrations anymore, their query plans are cached, see Builder
+		compiled atomic.Bool
+		plans    sync.Map // type of keys is plannedQuery, type of values is []candidate
+
+		// parent is the resolver the scope was created from, if any, see NewScope
+		parent  *Resolver
+		closing atomic.Bool
+		// scopes counts the scopes created from the resolver and not closed yet, see NewScope
+		scopes atomic.Int64
+
+		// inherited are the providers a fork inherited from its origin, they can be overridden, see Fork
+		inherited []Provider
+
+		// ctx is the context of the resolutions without their own context, see WithContext
+		ctx context.Context
+
+		// parallelSlots bounds the number of dependencies resolved concurrently by all the resolutions,
+		// nil if the dependencies are resolved sequentially, see WithParallelResolution
+		parallelSlots chan struct{}
+
+		// strictPriorities rejects the providers tied with a registered one, see WithStrictPriorities
+		strictPriorities bool
+
+		// interceptors wrap the invocations of the providers, see Use
+		interceptors atomic.Pointer[[]Interceptor]
+
+		// skipped are the providers whose registration was skipped as a condition did not hold, see Explain
+		skipped atomic.Pointer[[]skippedRegistration]
+
+		// onRegister is notified of the providers registered, to detect the conflicts between registries,
+		// see CompositeRegistry
+		onRegister atomic.Pointer[func(Provider)]
+
+		// dryRun records the steps of the resolutions of a dry run, see DryRun
+		dryRun *dryRunRecorder
+
+		// tracer is notified of the resolutions, see WithTracer
+		tracer Tracer
+		logger Logger
+	}
+
+	// Stats is a snapshot of the content of a resolver, see Resolver.Stats.
+	Stats struct {
+		// Providers is the number of registered providers.
+		Providers int
+		// Components is the number of stored components.
+		Components int
+		// ActiveScopes is the number of scopes created from the resolver and not closed yet.
+		ActiveScopes int
 	}
 
 	// Closeable is an interface that can be used to close resources.
@@ -43,23 +127,119 @@ type (
 		Close() error
 	}
 
+	// Stoppable is an interface for components needing a graceful shutdown, they are stopped with the context
+	// given to Resolver.CloseWithContext, so they can honor its deadline.
+	Stoppable interface {
+		Stop(ctx context.Context) error
+	}
+
+	// PostConstructor is an interface for components needing to finish their setup once all their dependencies
+	// are injected, PostConstruct is called right after the component is provided and decorated, before it is stored.
+	PostConstructor interface {
+		PostConstruct(ctx context.Context) error
+	}
+
 	Registrable = any
 
 	RegistrableOptions struct {
 		named        string
 		priority     int
 		dependencies []dependency
-		conditions   []condition
+		conditions   []Condition
+		// appliedConditions are evaluated when a decorator is applied, and at registration for a provider,
+		// see WhenProvided
+		appliedConditions []Condition
 
-		decorate *string
+		decorate     *string
+		decorateType reflect.Type
 
 		description string
+
+		ttl time.Duration
+
+		version string
+
+		scoped      bool
+		transient   bool
+		perConsumer bool
+		eager       bool
+
+		onClose closeHook
+		onInit  initHook
+		// notOwned components are left open by the resolver, see OwnedByContainer
+		notOwned bool
+
+		tags []string
+
+		exposedAs []reflect.Type
+		hidden    bool
+		// wiredBy is the name of the wiring the component is taken from, see Wired
+		wiredBy string
+
+		// invoker calls the factory method without reflection, see Call0
+		invoker *invoker
+	}
+
+	// ResolverOptions are the options used to build a Resolver.
+	ResolverOptions struct {
+		envSnapshot      bool
+		parallelism      int
+		strictPriorities bool
+		storeLimit       int
+		onEvict          func(name Name, comp any)
+		tracer           Tracer
+		logger           Logger
 	}
 
 	UnsafeInitializer = func() error
 	Initializer       = func()
 )
 
+// WithParallelResolution resolves up to n dependencies concurrently, so the construction time of
+// independent dependencies (e.g. doing network calls) is not summed anymore.
+// The limit is shared by all the resolutions of the resolver (and of its scopes and forks), once reached,
+// the dependencies are resolved sequentially.
+//
+// Note that the providers must then be safe to be called concurrently with other providers.
+func WithParallelResolution(n int) option.Option[ResolverOptions] {
+	return func(opts *ResolverOptions) {
+		opts.parallelism = n
+	}
+}
+
+// WithStrictPriorities rejects the registration of a provider of a name already provided by a provider with the same
+// priority and version. Otherwise, the last registered provider wins, and a warning is logged.
+func WithStrictPriorities() option.Option[ResolverOptions] {
+	return func(opts *ResolverOptions) {
+		opts.strictPriorities = true
+	}
+}
+
+// WithStoreLimit bounds the number of components stored by the resolver, e.g. for the clients created per tenant,
+// the least recently used components are evicted beyond the limit, and closed once no resolution is in flight.
+// An evicted component is built again on its next resolution, so the components must not rely on being singletons,
+// the stored components built with it are evicted along with it, so none of them keeps serving a closed component.
+func WithStoreLimit(n int) option.Option[ResolverOptions] {
+	return func(opts *ResolverOptions) {
+		opts.storeLimit = n
+	}
+}
+
+// WithEvictionCallback notifies the callback of the components evicted by the store, see WithStoreLimit.
+func WithEvictionCallback(onEvict func(name Name, comp any)) option.Option[ResolverOptions] {
+	return func(opts *ResolverOptions) {
+		opts.onEvict = onEvict
+	}
+}
+
+// WithEnvSnapshot registers a map[string]string component named EnvSnapshotName,
+// containing the environment variables at the time the resolver is created.
+func WithEnvSnapshot() option.Option[ResolverOptions] {
+	return func(opts *ResolverOptions) {
+		opts.envSnapshot = true
+	}
+}
+
 func Named(name string) option.Option[RegistrableOptions] {
 	return func(opts *RegistrableOptions) {
 		opts.named = name
@@ -84,38 +264,229 @@ func Description(description string) option.Option[RegistrableOptions] {
 	}
 }
 
+// TTL makes the provided component expire once it is older than the given duration, it is then rebuilt on the next resolution.
+//
+// The expired instance is closed (if closeable) once no resolution is in flight, the stored components built with it
+// expire (and are closed) along with it, so they are rebuilt with the new instance. Note that the components not
+// stored (e.g. transient, or built by a factory) keep the closed instance they were built with.
+func TTL(ttl time.Duration) option.Option[RegistrableOptions] {
+	return func(opts *RegistrableOptions) {
+		opts.ttl = ttl
+	}
+}
+
+// Version registers the provider as a given version of its components.
+//
+// Multiple versions of a named component can coexist, the resolution defaults to the highest version
+// (comparing dot separated numbers, ignoring a leading "v"), a given version can be resolved using ResolveVersion.
+// Note that the priority still prevails over the version.
+func Version(version string) option.Option[RegistrableOptions] {
+	return func(opts *RegistrableOptions) {
+		opts.version = version
+	}
+}
+
+// Tagged adds the components of the provider to the given groups, a group can be injected using Inject.Tagged,
+// or resolved using ResolveTagged.
+func Tagged(tags ...string) option.Option[RegistrableOptions] {
+	return func(opts *RegistrableOptions) {
+		opts.tags = append(opts.tags, tags...)
+	}
+}
+
+// As exposes the components of the provider under the interface I, the components are then only matched
+// by the queries for their own type and for the interfaces they are exposed as, instead of every interface
+// they implement, which avoids accidental ambiguities.
+//
+// The option can be given several times to expose the components under several interfaces.
+func As[I any]() option.Option[RegistrableOptions] {
+	return func(opts *RegistrableOptions) {
+		opts.exposedAs = append(opts.exposedAs, TypeOf[I]())
+	}
+}
+
+// Hidden hides the components of the provider from the queries by interface (e.g. ResolveAll[io.Closer]),
+// they can still be resolved by name, or by their own type.
+func Hidden() option.Option[RegistrableOptions] {
+	return func(opts *RegistrableOptions) {
+		opts.hidden = true
+	}
+}
+
+// Wired marks the component as taken from the given wiring, a component generated with the -wire flag of
+// the generator, building the static part of the graph by calling the providers directly. As its components
+// do not go through the resolver, the providers they would be replaced by are rejected, along with the refresh
+// or the removal of the wired components, see Resolver.Override.
+func Wired(wiring string) option.Option[RegistrableOptions] {
+	return func(opts *RegistrableOptions) {
+		opts.wiredBy = wiring
+	}
+}
+
+// Transient makes the provider invoked on every resolution, its components are not stored as singletons.
+//
+// The closeable components are still tracked, to be closed with the resolver.
+func Transient() option.Option[RegistrableOptions] {
+	return func(opts *RegistrableOptions) {
+		opts.transient = true
+	}
+}
+
+// PerConsumer makes the provider build a component for each component it is injected into, e.g. a logger tagged
+// with the name of its consumer (see InjectionContext). The component built for a consumer is stored, and injected
+// again if the consumer is built again, the top level resolutions share the same component.
+func PerConsumer() option.Option[RegistrableOptions] {
+	return func(opts *RegistrableOptions) {
+		opts.perConsumer = true
+	}
+}
+
+// OnInit registers a function called right after the components of the provider are provided and decorated,
+// before they are stored, instead of their PostConstruct method, so components not implementing PostConstructor
+// can finish their setup.
+func OnInit[T any](onInit func(T) error) option.Option[RegistrableOptions] {
+	return func(opts *RegistrableOptions) {
+		opts.onInit = func(_ context.Context, comp reflect.Value) error {
+			typed, ok := comp.Interface().(T)
+			if !ok {
+				return fmt.Errorf("component of type %s is not a %s", comp.Type(), TypeOf[T]())
+			}
+			return onInit(typed)
+		}
+	}
+}
+
+// Eager makes the components of the provider instantiated by Resolver.Warmup, so misconfigurations fail fast at startup.
+func Eager() option.Option[RegistrableOptions] {
+	return func(opts *RegistrableOptions) {
+		opts.eager = true
+	}
+}
+
+// OnClose registers a function called to release the components of the provider when the resolver is closed,
+// instead of their Close or Stop method, so components not implementing Closeable or Stoppable can participate
+// in the shutdown.
+func OnClose[T any](onClose func(T) error) option.Option[RegistrableOptions] {
+	return func(opts *RegistrableOptions) {
+		opts.onClose = func(_ context.Context, comp reflect.Value) error {
+			typed, ok := comp.Interface().(T)
+			if !ok {
+				return fmt.Errorf("component of type %s is not a %s", comp.Type(), TypeOf[T]())
+			}
+			return onClose(typed)
+		}
+	}
+}
+
+// OwnedByContainer tells whether the resolver owns the components of the provider, and closes them (see OnClose)
+// when it is closed, or when they are evicted or refreshed. The components are owned by default, even the static
+// values (see ToStaticProvider and Bind), so OwnedByContainer(false) must be given for the values whose lifecycle
+// is handled by the caller, e.g. a connection pool shared with another part of the application.
+func OwnedByContainer(owned bool) option.Option[RegistrableOptions] {
+	return func(opts *RegistrableOptions) {
+		opts.notOwned = !owned
+	}
+}
+
 func Decorate(named string) option.Option[RegistrableOptions] {
 	return func(opts *RegistrableOptions) {
 		opts.decorate = &named
 	}
 }
 
+// DecorateType registers a decorator for every component implementing T, whatever its name,
+// e.g. to wrap all the runner.Runnable with a panic recovery.
+//
+// The components are checked when they are provided. As the decorated component replaces the original one,
+// the decoration is skipped if it is not assignable to the type the component is provided as.
+func DecorateType[T any]() option.Option[RegistrableOptions] {
+	return func(opts *RegistrableOptions) {
+		opts.decorateType = TypeOf[T]()
+	}
+}
+
+// Name returns the name of the component, empty for the components only identified by their type.
+func (n Name) Name() string {
+	return n.name
+}
+
+// Type returns the type the component is provided as.
+func (n Name) Type() reflect.Type {
+	return n.typ
+}
+
 func (n Name) String() string {
-	return fmt.Sprintf("(%s, %s)", n.name, n.typ.String())
+	var consumer string
+	if n.consumer != "" {
+		consumer = " for " + n.consumer
+	}
+	if n.version != "" {
+		return fmt.Sprintf("(%s@%s, %s)%s", n.name, n.version, n.typ.String(), consumer)
+	}
+	return fmt.Sprintf("(%s, %s)%s", n.name, n.typ.String(), consumer)
 }
 
 func (r Request) String() string {
 	return fmt.Sprintf("{q=%s v=%s c=%s}", r.query, r.validator, r.collector)
 }
 
-func New() *Resolver {
+func New(opts ...option.Option[ResolverOptions]) *Resolver {
+	options := option.Build(&ResolverOptions{}, opts...)
 
-	r := &Resolver{
-		providers: NewSortedCOWSlice[Provider](fn.ReverseComparator(compareByPriority[Provider])),
-		store:     NewStore(),
+	r := &Resolver{resolverState: &resolverState{
+		providers: NewSortedCOWSlice[Provider](fn.ReverseComparator(compareProviders)),
+		store:     newStoreFor(options),
 
 		lock: NewLockManager(),
-	}
+
+		parallelSlots:    newParallelSlots(options.parallelism),
+		strictPriorities: options.strictPriorities,
+		tracer:           options.tracer,
+		logger:           loggerOrNop(options.logger),
+	}}
 
 	// Register itself as a static provider.
 	//
 	// If providers want to resolve the resolver to be able to dynamically resolve dependencies
-	r.MustRegister(ToStaticProvider(r), Named("godi.resolver"))
+	r.MustRegister(ToStaticProvider(r), Named("godi.resolver"), Hidden())
+
+	if options.envSnapshot {
+		r.MustRegister(
+			ToStaticProvider(snapshotEnv()),
+			Named(EnvSnapshotName),
+			Description("Snapshot of the environment variables at startup"),
+		)
+	}
 
 	return r
 }
 
+// newParallelSlots returns the slots of the dependencies resolved concurrently, nil if they are resolved sequentially.
+func newParallelSlots(parallelism int) chan struct{} {
+	if parallelism <= 1 {
+		return nil
+	}
+	return make(chan struct{}, parallelism)
+}
+
+func newStoreFor(options *ResolverOptions) *Store {
+	if options.storeLimit <= 0 {
+		return NewStore()
+	}
+	var onEvict func(Name, reflect.Value)
+	if options.onEvict != nil {
+		onEvict = func(name Name, comp reflect.Value) {
+			options.onEvict(name, comp.Interface())
+		}
+	}
+	return NewBoundedStore(options.storeLimit, onEvict)
+}
+
 func (r *Resolver) Register(reg Registrable, opts ...option.Option[RegistrableOptions]) error {
+	if r.compiled.Load() {
+		return fmt.Errorf("unable to register %T, the resolver is compiled and can not be modified anymore", reg)
+	}
+
 	var (
 		t         = reflect.TypeOf(reg)
 		provider  Provider
@@ -127,7 +498,7 @@ func (r *Resolver) Register(reg Registrable, opts ...option.Option[RegistrableOp
 		)
 	)
 	if t.Kind() == reflect.Func {
-		if options.decorate == nil {
+		if options.decorate == nil && options.decorateType == nil {
 			provider, err = NewFactoryMethodProvider(reg, opts...)
 			if err != nil {
 				return fmt.Errorf("failed to create factory method provider for %T:\n\t%w", reg, err)
@@ -146,15 +517,47 @@ func (r *Resolver) Register(reg Registrable, opts ...option.Option[RegistrableOp
 		return fmt.Errorf("we can register provider as function or as Provider implementation, or decorators as Decorator implementation or function, unsupported type %T", reg)
 	}
 
+	// the conditions of the decorators on the content of the resolver are evaluated when they are applied
+	conditions := options.conditions
+	if decorator != nil && len(options.appliedConditions) > 0 {
+		decorator = &conditionalDecorator{Decorator: decorator, conditions: options.appliedConditions}
+	} else {
+		conditions = append(slices.Clone(conditions), options.appliedConditions...)
+	}
+
 	// validate the conditions if any, they might prevent the registration
-	for _, cond := range options.conditions {
-		if !r.validateCondition(cond) {
+	for _, cond := range conditions {
+		holds, err := cond.Evaluate(r)
+		if err != nil {
+			return fmt.Errorf("failed to evaluate registration condition for %T:\n\t%w", reg, err)
+		}
+		if !holds {
+			r.logger.Debug("registration skipped, a condition does not hold", "registrable", fmt.Sprintf("%T", reg))
+			if provider != nil {
+				r.recordSkipped(skippedRegistration{provider: withAttributes(provider, options.attributes()), condition: cond})
+			}
 			return nil
 		}
 	}
 
+	// a component depending on where it is injected can not be shared, unless it is built for each consumer
+	if provider != nil && !options.perConsumer && dependsOnInjectionContext(provider) {
+		options.transient = true
+	}
+
+	// everything registered in a scope only lives in the scope
+	options.scoped = options.scoped || r.parent != nil
+
 	if provider != nil {
-		r.providers.Add(provider)
+		if err := validateExposedAs(provider, options.exposedAs); err != nil {
+			return fmt.Errorf("failed to register provider %T:\n\t%w", reg, err)
+		}
+	}
+
+	if provider != nil {
+		if err := r.addProvider(withAttributes(provider, options.attributes())); err != nil {
+			return fmt.Errorf("failed to register provider %T:\n\t%w", reg, err)
+		}
 	}
 	if decorator != nil {
 		decoratedName := decorator.ForName()
@@ -170,23 +573,54 @@ func (r *Resolver) Register(reg Registrable, opts ...option.Option[RegistrableOp
 	return nil
 }
 
-func (r *Resolver) validateCondition(cond condition) bool {
-	val, found, err := r.resolve(Request{
-		unitaryTyp: StringType,
-		query: queryByName{
-			name: Name{
-				name: cond.namedStringComponent,
-				typ:  StringType,
-			},
-		},
-		validator: validatorUniqueOptional{},
-		collector: collectorUnique{},
-	})
-	if err != nil || !found {
-		return false
+// addProvider adds the provider, along with its attributes, dropping the inherited providers it overrides.
+func (r *Resolver) addProvider(provider Provider) error {
+	if err := r.checkWiring(provider); err != nil {
+		return err
+	}
+	if len(r.inherited) > 0 {
+		r.shadowInherited(provider)
+	} else if r.parent == nil {
+		if err := r.checkShadowed(provider, attributesOf(provider).version); err != nil {
+			return err
+		}
+	}
+	r.providers.Add(provider)
+	if onRegister := r.onRegister.Load(); onRegister != nil {
+		(*onRegister)(provider)
 	}
+	r.logger.Debug("provider registered", "provider", provider)
+	return nil
+}
 
-	return cond.operator(val.String(), cond.value)
+// checkShadowed warns if the provider shadows a provider registered with the same priority and version,
+// which is likely a mistake, as the last registered provider silently wins. With strict priorities,
+// it is an error instead, see WithStrictPriorities.
+func (r *Resolver) checkShadowed(provider Provider, version string) error {
+	for _, existing := range r.providers.All() {
+		if existing.Priority() != provider.Priority() || attributesOf(existing).version != version {
+			continue
+		}
+		for _, n := range provider.ListProvidableNames() {
+			if !existing.CanProvide(n) {
+				continue
+			}
+			if r.strictPriorities {
+				return fmt.Errorf(
+					"%s provides %s with the same priority (%d) as %s, set a different priority to break the tie",
+					describeProvider(provider), n, provider.Priority(), describeProvider(existing),
+				)
+			}
+			r.logger.Warn(
+				"provider shadows a provider registered with the same priority",
+				"name", n,
+				"provider", provider,
+				"shadowed", existing,
+			)
+			return nil
+		}
+	}
+	return nil
 }
 
 func tryGetAt[T any](slice []T, index int) (val T, found bool) {
@@ -198,15 +632,26 @@ func tryGetAt[T any](slice []T, index int) (val T, found bool) {
 
 func (r *Resolver) MustRegister(reg Registrable, opts ...option.Option[RegistrableOptions]) *Resolver {
 	err := r.Register(reg, opts...)
-	if err != nil {
-		panic(fmt.Sprintf("failed to register provider %T:\n\t%v", reg, err))
-	}
+	mustSucceed(r.logger, err, "failed to register provider %T", reg)
 	return r
 }
 
 func (r *Resolver) Close() error {
+	return r.CloseWithContext(context.Background())
+}
+
+// CloseWithContext closes the stored components in the reverse order of their instantiation, so the consumers
+// are closed before the components they depend on. Closing stops once the context is done.
+func (r *Resolver) CloseWithContext(ctx context.Context) error {
+	if !r.closing.CompareAndSwap(false, true) {
+		return nil // the resolver might be stored as a component, do not close it twice
+	}
+	if r.parent != nil {
+		r.parent.scopes.Add(-1)
+	}
+
 	// close all the stored components
-	return r.store.Close()
+	return r.store.CloseWithContext(ctx)
 }
 
 // Resolve attempts to resolve a component of type T from the resolver.
@@ -251,6 +696,46 @@ func ResolveNamed[T any](resolver *Resolver, name string) (T, error) {
 	return val, err
 }
 
+// ResolveCtx attempts to resolve a component of type T from the resolver, aborting the resolution once the context is done.
+//
+// The context is injected in the providers taking a context.Context parameter built during this resolution.
+func ResolveCtx[T any](ctx context.Context, resolver *Resolver) (T, error) {
+	lookFor := reflect.TypeOf((*T)(nil)).Elem()
+
+	val, _, err := resolveTyped[T](
+		resolver,
+		Request{
+			unitaryTyp: lookFor,
+			query:      queryByType{typ: lookFor},
+			validator:  validatorUniqueMandatory{},
+			collector:  collectorUnique{},
+			ctx:        ctx,
+		},
+	)
+	return val, err
+}
+
+// ResolveNamedCtx attempts to resolve a named component of type T from the resolver, aborting the resolution once the context is done.
+//
+// The context is injected in the providers taking a context.Context parameter built during this resolution.
+func ResolveNamedCtx[T any](ctx context.Context, resolver *Resolver, name string) (T, error) {
+	lookFor := reflect.TypeOf((*T)(nil)).Elem()
+
+	val, _, err := resolveTyped[T](
+		resolver,
+		Request{
+			unitaryTyp: lookFor,
+			query: queryByName{
+				name: Name{name: name, typ: lookFor},
+			},
+			validator: validatorUniqueMandatory{},
+			collector: collectorUnique{},
+			ctx:       ctx,
+		},
+	)
+	return val, err
+}
+
 // ResolveAll attempts to resolve all components of type T from the resolver.
 func ResolveAll[T any](resolver *Resolver) ([]T, error) {
 	lookFor := reflect.TypeOf((*T)(nil)).Elem()
@@ -267,6 +752,58 @@ func ResolveAll[T any](resolver *Resolver) ([]T, error) {
 	return val, err
 }
 
+// ResolveAllAsMap attempts to resolve all components of type T from the resolver, keyed by component name.
+func ResolveAllAsMap[T any](resolver *Resolver) (map[string]T, error) {
+	lookFor := reflect.TypeOf((*T)(nil)).Elem()
+
+	val, _, err := resolveTyped[map[string]T](
+		resolver,
+		Request{
+			unitaryTyp: lookFor,
+			query:      queryByType{typ: lookFor},
+			validator:  validatorMultiple{},
+			collector:  collectorMultipleAsMap{},
+		},
+	)
+	return val, err
+}
+
+// ResolveAllCtx attempts to resolve all components of type T from the resolver, aborting the resolution once the
+// context is done, see ResolveCtx.
+func ResolveAllCtx[T any](ctx context.Context, resolver *Resolver) ([]T, error) {
+	lookFor := reflect.TypeOf((*T)(nil)).Elem()
+
+	val, _, err := resolveTyped[[]T](
+		resolver,
+		Request{
+			unitaryTyp: lookFor,
+			query:      queryByType{typ: lookFor},
+			validator:  validatorMultiple{},
+			collector:  collectorMultipleAsSlice{},
+			ctx:        ctx,
+		},
+	)
+	return val, err
+}
+
+// ResolveAllAsMapCtx attempts to resolve all components of type T from the resolver, keyed by component name,
+// aborting the resolution once the context is done, see ResolveCtx.
+func ResolveAllAsMapCtx[T any](ctx context.Context, resolver *Resolver) (map[string]T, error) {
+	lookFor := reflect.TypeOf((*T)(nil)).Elem()
+
+	val, _, err := resolveTyped[map[string]T](
+		resolver,
+		Request{
+			unitaryTyp: lookFor,
+			query:      queryByType{typ: lookFor},
+			validator:  validatorMultiple{},
+			collector:  collectorMultipleAsMap{},
+			ctx:        ctx,
+		},
+	)
+	return val, err
+}
+
 // TryResolve attempts to resolve a component of type T from the resolver.
 //
 // It returns the resolved value, a boolean indicating if it was found, and an error if any occurred during resolution.
@@ -311,14 +848,30 @@ func TryResolveNamed[T any](resolver *Resolver, name string) (value T, found boo
 	)
 }
 
+// ResolveOr attempts to resolve a component of type T from the resolver, returning the fallback if none is found.
+func ResolveOr[T any](resolver *Resolver, fallback T) (T, error) {
+	val, found, err := TryResolve[T](resolver)
+	if err != nil || !found {
+		return fallback, err
+	}
+	return val, nil
+}
+
+// ResolveNamedOr attempts to resolve a named component of type T from the resolver, returning the fallback if none is found.
+func ResolveNamedOr[T any](resolver *Resolver, name string, fallback T) (T, error) {
+	val, found, err := TryResolveNamed[T](resolver, name)
+	if err != nil || !found {
+		return fallback, err
+	}
+	return val, nil
+}
+
 // MustResolve attempts to resolve a component of type T from the resolver.
 //
 // It panics if the resolution fails.
 func MustResolve[T any](resolver *Resolver) T {
 	res, err := Resolve[T](resolver)
-	if err != nil {
-		log.Fatalf("failed to resolve type %T:\n\t%v", res, err)
-	}
+	mustSucceed(resolver.logger, err, "failed to resolve type %T", res)
 	return res
 }
 
@@ -327,9 +880,7 @@ func MustResolve[T any](resolver *Resolver) T {
 // It panics if the resolution fails.
 func MustResolveNamed[T any](resolver *Resolver, name string) T {
 	res, err := ResolveNamed[T](resolver, name)
-	if err != nil {
-		log.Fatalf("failed to resolve named component %s of type %T:\n\t%v", name, res, err)
-	}
+	mustSucceed(resolver.logger, err, "failed to resolve named component %s of type %T", name, res)
 	return res
 }
 
@@ -338,9 +889,16 @@ func MustResolveNamed[T any](resolver *Resolver, name string) T {
 // It panics if the resolution fails.
 func MustResolveAll[T any](resolver *Resolver) []T {
 	res, err := ResolveAll[T](resolver)
-	if err != nil {
-		log.Fatalf("failed to resolve all components of type %T:\n\t%v", res, err)
-	}
+	mustSucceed(resolver.logger, err, "failed to resolve all components of type %T", res)
+	return res
+}
+
+// MustResolveAllAsMap attempts to resolve all components of type T from the resolver, keyed by component name.
+//
+// It panics if the resolution fails.
+func MustResolveAllAsMap[T any](resolver *Resolver) map[string]T {
+	res, err := ResolveAllAsMap[T](resolver)
+	mustSucceed(resolver.logger, err, "failed to resolve all components of type %T", res)
 	return res
 }
 
@@ -357,15 +915,27 @@ func resolveTyped[T any](resolver *Resolver, req Request) (val T, found bool, er
 }
 
 func (r *Resolver) resolve(req Request) (val reflect.Value, found bool, err error) {
-	if perfOutput {
-		start := time.Now()
+	if req.tracker == nil {
+		if stored, found := r.resolveStored(req); found {
+			return stored, true, nil
+		}
+
+		req.tracker = r.newTracker(req.ctx)
+
+		// expired components are only closed when no resolution is in flight, as one might still be using them
+		r.inflight.Add(1)
 		defer func() {
-			fmt.Printf("resolved %s in %s\n", req, time.Since(start))
+			if r.inflight.Add(-1) == 0 {
+				r.store.CloseRetired()
+			}
 		}()
 	}
 
-	if req.tracker == nil {
-		req.tracker = NewTracker()
+	end := r.trace(req, len(req.tracker.stack))
+	defer func() { end(err) }()
+
+	if err := req.tracker.contextErr(); err != nil {
+		return reflect.Value{}, false, fmt.Errorf("resolution of request %v aborted:\n\t%w", req, err)
 	}
 
 	results, err := req.query.find(r)
@@ -374,9 +944,56 @@ func (r *Resolver) resolve(req Request) (val reflect.Value, found bool, err erro
 	}
 	err = req.validator.validate(results)
 	if err != nil {
-		return reflect.Value{}, false, fmt.Errorf("failed to validate results for request %v:\n\t%w", req, err)
+		return reflect.Value{}, false, fmt.Errorf("failed to validate results for request %v:\n\t%w", req, r.withSuggestions(err, req.query, results))
 	}
-	return req.collector.collect(req.unitaryTyp, r, results, req.tracker)
+	val, found, err = req.collector.collect(req.unitaryTyp, r, results, req.tracker)
+	if err == nil && !found && req.fallback != nil {
+		return *req.fallback, false, nil
+	}
+	return val, found, err
+}
+
+// resolveStored is the fast path of the resolution of a component already stored, skipping the query machinery
+// and the tracking of the resolution. It only applies to the requests of a unique component, by name, or by type
+// once the resolver is compiled (as the lookup of the providers is cached), when nothing traces the resolutions
+// (the resolutions are still logged),
+// and outside of a construction (whose dependencies are recorded).
+func (r *Resolver) resolveStored(req Request) (reflect.Value, bool) {
+	if _, unique := req.collector.(collectorUnique); !unique || r.tracer != nil {
+		return reflect.Value{}, false
+	}
+	if r.bound.active() || boundResolutionOf(req.ctx).active() {
+		return reflect.Value{}, false // the component is a dependency of the construction, see Store.put
+	}
+	ctx := req.ctx
+	if ctx == nil {
+		ctx = r.ctx
+	}
+	if ctx != nil && context.Cause(ctx) != nil {
+		return reflect.Value{}, false // the resolution fails
+	}
+
+	var name Name
+	switch q := req.query.(type) {
+	case queryByName:
+		name = q.name
+	case queryByType:
+		if !r.compiled.Load() {
+			return reflect.Value{}, false
+		}
+		candidates := r.candidatesFor(q)
+		if len(candidates) != 1 {
+			return reflect.Value{}, false
+		}
+		name = candidates[0].name
+	default:
+		return reflect.Value{}, false
+	}
+	comp, found := r.store.Get(name)
+	if _, nop := r.logger.(nopLogger); found && !nop {
+		r.logger.Debug("request resolved", "request", req, "depth", 0, "stored", true)
+	}
+	return comp, found
 }
 
 type WithPriority interface {
@@ -401,6 +1018,8 @@ func unReflect[T any](v reflect.Value) (res T, err error) {
 	return res, nil
 }
 
+// Describe returns a human-readable description of the providers, decorators and stored components,
+// see Providers and Explain for a structured description.
 func (r *Resolver) Describe() string {
 	var b strings.Builder
 	b.WriteString("* Providers:\n")
@@ -412,7 +1031,11 @@ func (r *Resolver) Describe() string {
 			providerStr = fmt.Sprintf("%T", p)
 		}
 
-		b.WriteString(fmt.Sprintf("\t- %s (priority=%d)\n", providerStr, p.Priority()))
+		if version := attributesOf(p).version; version != "" {
+			b.WriteString(fmt.Sprintf("\t- %s (priority=%d, version=%s)\n", providerStr, p.Priority(), version))
+		} else {
+			b.WriteString(fmt.Sprintf("\t- %s (priority=%d)\n", providerStr, p.Priority()))
+		}
 		if desc := p.Description(); desc != "" {
 			b.WriteString(fmt.Sprintf("\t\tdescription: %s\n", desc))
 		}
@@ -425,14 +1048,29 @@ func (r *Resolver) Describe() string {
 			b.WriteString(fmt.Sprintf("\t\t\t- %s\n", d))
 		}
 	}
+	r.describeDecorators(&b)
 	b.WriteString("* Stored components:\n")
+	providers := r.providers.All()
 	for _, n := range r.store.ListNames() {
+		if isRedacted(providers, n) {
+			b.WriteString(fmt.Sprintf("\t- %s: %s\n", n, redactedValue))
+			continue
+		}
 		comp, _ := r.store.Get(n)
 		b.WriteString(fmt.Sprintf("\t- %s: %v\n", n, comp))
 	}
 	return b.String()
 }
 
+// Stats returns a snapshot of the content of the resolver, e.g. to expose it as metrics.
+func (r *Resolver) Stats() Stats {
+	return Stats{
+		Providers:    len(r.providers.All()),
+		Components:   len(r.store.ListNames()),
+		ActiveScopes: int(r.scopes.Load()),
+	}
+}
+
 func (r *Resolver) Initialize() error {
 	// find all initializers
 	initializers, err := ResolveAll[Initializer](r)
@@ -457,9 +1095,41 @@ func (r *Resolver) Initialize() error {
 	return nil
 }
 
+// WithContext sets the context of the resolver, it is meant to be called at startup, before any resolution.
+//
+// The context is used by all the resolutions without their own context (see ResolveCtx): it is injected in the
+// providers taking a context.Context parameter, and the resolutions are aborted once it is done.
+func (r *Resolver) WithContext(ctx context.Context) *Resolver {
+	r.ctx = ctx
+	return r
+}
+
+// Warmup instantiates the components of all the eager providers (see Eager), along with their dependencies.
+//
+// All the failures are reported, and the warmup is aborted once the context is done.
+func (r *Resolver) Warmup(ctx context.Context) error {
+	var errs []error
+	for _, p := range r.providers.All() {
+		if !attributesOf(p).eager {
+			continue
+		}
+		for _, n := range p.ListProvidableNames() {
+			_, _, err := r.resolve(Request{
+				unitaryTyp: n.typ,
+				query:      queryByVersion{name: n, version: attributesOf(p).version},
+				validator:  validatorUniqueMandatory{},
+				collector:  collectorUnique{},
+				ctx:        ctx,
+			})
+			if err != nil {
+				errs = append(errs, fmt.Errorf("failed to warm up component %s:\n\t%w", n, err))
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
 func (r *Resolver) MustInitialize() {
 	err := r.Initialize()
-	if err != nil {
-		log.Fatalf("failed to initialize resolver:\n\t%v", err)
-	}
+	mustSucceed(r.logger, err, "failed to initialize resolver")
 }
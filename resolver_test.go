@@ -6,6 +6,7 @@ import (
 	"github.com/a-peyrard/godi/concurrent"
 	"github.com/a-peyrard/godi/slices"
 	"io"
+	"log/slog"
 	"reflect"
 	"sync"
 	"sync/atomic"
@@ -16,6 +17,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"strconv"
+	"strings"
 )
 
 var closeCounter atomic.Int32
@@ -185,7 +187,7 @@ func TestResolver(t *testing.T) {
 
 		// THEN
 		require.NoError(t, err)
-		assert.Len(t, resolved, 3) // our 2 services, and the resolver itself!
+		assert.Len(t, resolved, 2) // our 2 services, the resolver itself is hidden
 		types := slices.Map(resolved, func(c io.Closer) string {
 			return fmt.Sprintf("%T", c)
 		})
@@ -303,6 +305,114 @@ func TestResolver_Close(t *testing.T) {
 		// THEN
 		assert.Equal(t, int32(1), after-before)
 	})
+
+	t.Run("it should close the consumers before their dependencies", func(t *testing.T) {
+		// GIVEN
+		resolver := New()
+		var closed []string
+		recorder := func(name string) Closeable {
+			return closeFunc(func() error {
+				closed = append(closed, name)
+				return nil
+			})
+		}
+		resolver.MustRegister(
+			func(Closeable, Closeable) Closeable { return recorder("service") },
+			Named("service"),
+			Dependencies(Inject.Named("cache"), Inject.Named("pool")),
+		)
+		resolver.MustRegister(
+			func(Closeable) Closeable { return recorder("cache") },
+			Named("cache"),
+			Dependencies(Inject.Named("pool")),
+		)
+		resolver.MustRegister(func() Closeable { return recorder("pool") }, Named("pool"))
+
+		_, err := ResolveNamed[Closeable](resolver, "service")
+		require.NoError(t, err)
+
+		// WHEN
+		err = resolver.Close()
+
+		// THEN
+		require.NoError(t, err)
+		assert.Equal(t, []string{"service", "cache", "pool"}, closed)
+	})
+
+	t.Run("it should stop closing once the context is done", func(t *testing.T) {
+		// GIVEN
+		resolver := New()
+		_, err := Resolve[*TestService](resolver.MustRegister(NewTestService))
+		require.NoError(t, err)
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		// WHEN
+		err = resolver.CloseWithContext(ctx)
+
+		// THEN
+		require.Error(t, err)
+		assert.ErrorIs(t, err, context.Canceled)
+		assert.Contains(t, err.Error(), "1 components were not closed")
+	})
+
+	t.Run("it should stop the stoppable components with the closing context", func(t *testing.T) {
+		// GIVEN
+		resolver := New()
+		worker := &testWorker{}
+		resolver.MustRegister(ToStaticProvider(worker))
+		_, err := Resolve[*testWorker](resolver)
+		require.NoError(t, err)
+		ctx := context.WithValue(context.Background(), shutdownKey{}, "shutdown")
+
+		// WHEN
+		err = resolver.CloseWithContext(ctx)
+
+		// THEN
+		require.NoError(t, err)
+		assert.Equal(t, "shutdown", worker.stoppedWith)
+	})
+
+	t.Run("it should release the components using the OnClose hook", func(t *testing.T) {
+		// GIVEN
+		resolver := New()
+		var released []string
+		resolver.MustRegister(
+			func() map[string]string { return map[string]string{"name": "cache"} },
+			OnClose(func(m map[string]string) error {
+				released = append(released, m["name"])
+				return nil
+			}),
+		)
+		_, err := Resolve[map[string]string](resolver)
+		require.NoError(t, err)
+
+		// WHEN
+		err = resolver.Close()
+
+		// THEN
+		require.NoError(t, err)
+		assert.Equal(t, []string{"cache"}, released)
+	})
+
+	t.Run("it should report the OnClose hook failures", func(t *testing.T) {
+		// GIVEN
+		resolver := New()
+		resolver.MustRegister(
+			NewTestService,
+			OnClose(func(*TestService) error { return errors.New("boom") }),
+		)
+		service, err := Resolve[*TestService](resolver)
+		require.NoError(t, err)
+
+		// WHEN
+		err = resolver.Close()
+
+		// THEN
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "boom")
+		assert.False(t, service.closed, "the hook replaces the Close method")
+	})
 }
 
 func TestResolver_TryResolve(t *testing.T) {
@@ -553,21 +663,7 @@ func TestResolver_Register(t *testing.T) {
 		assert.Contains(t, err.Error(), "must either return the instance and an error")
 	})
 
-	t.Run("it should fail if function does not return an error as second element", func(t *testing.T) {
-		// GIVEN
-		resolver := New()
-
-		// WHEN
-		err := resolver.Register(func() (string, string) {
-			return "really", "not a valid provider"
-		})
-
-		// THEN
-		require.Error(t, err)
-		assert.Contains(t, err.Error(), "returns two elements, it must return an error")
-	})
-
-	t.Run("it should fail if function does return more than two elements", func(t *testing.T) {
+	t.Run("it should fail if function returns several components of the same type", func(t *testing.T) {
 		// GIVEN
 		resolver := New()
 
@@ -578,7 +674,7 @@ func TestResolver_Register(t *testing.T) {
 
 		// THEN
 		require.Error(t, err)
-		assert.Contains(t, err.Error(), "must either return the instance and an error")
+		assert.Contains(t, err.Error(), "returns several components of type string named")
 	})
 
 	t.Run("it should allows to register with custom name", func(t *testing.T) {
@@ -1475,3 +1571,1095 @@ func TestResolver_Decorator(t *testing.T) {
 		assert.Equal(t, "test-service", service.Name)
 	})
 }
+
+func TestResolver_TTL(t *testing.T) {
+	t.Run("it should rebuild the component once its ttl is expired", func(t *testing.T) {
+		// GIVEN
+		resolver := New()
+		var built atomic.Int32
+		resolver.MustRegister(func() *TestService {
+			return &TestService{Name: "service-" + strconv.Itoa(int(built.Add(1)))}
+		}, TTL(20*time.Millisecond))
+
+		// WHEN
+		first := MustResolve[*TestService](resolver)
+		cached := MustResolve[*TestService](resolver)
+		time.Sleep(30 * time.Millisecond)
+		rebuilt := MustResolve[*TestService](resolver)
+
+		// THEN
+		assert.Same(t, first, cached)
+		assert.NotSame(t, first, rebuilt)
+		assert.Equal(t, "service-2", rebuilt.Name)
+	})
+
+	t.Run("it should close the expired component", func(t *testing.T) {
+		// GIVEN
+		resolver := New()
+		resolver.MustRegister(NewTestService, TTL(10*time.Millisecond))
+		first := MustResolve[*TestService](resolver)
+
+		// WHEN
+		time.Sleep(20 * time.Millisecond)
+		_ = MustResolve[*TestService](resolver)
+
+		// THEN
+		assert.True(t, first.closed)
+	})
+
+	t.Run("it should expire the components built with the expired component", func(t *testing.T) {
+		// GIVEN
+		resolver := New()
+		resolver.MustRegister(NewTestRepository, TTL(10*time.Millisecond))
+		resolver.MustRegister(func(repo *TestRepository) *TestController { return &TestController{Repo: repo} })
+		first := MustResolve[*TestController](resolver)
+
+		// WHEN
+		time.Sleep(20 * time.Millisecond)
+		_ = MustResolve[*TestRepository](resolver)
+		rebuilt := MustResolve[*TestController](resolver)
+
+		// THEN
+		assert.True(t, first.Repo.closed)
+		assert.NotSame(t, first, rebuilt)
+		assert.False(t, rebuilt.Repo.closed)
+	})
+
+	t.Run("it should not expire components registered without ttl", func(t *testing.T) {
+		// GIVEN
+		resolver := New()
+		resolver.MustRegister(NewTestService)
+		first := MustResolve[*TestService](resolver)
+
+		// WHEN
+		time.Sleep(10 * time.Millisecond)
+		second := MustResolve[*TestService](resolver)
+
+		// THEN
+		assert.Same(t, first, second)
+	})
+}
+
+func TestResolver_ResolveOr(t *testing.T) {
+	t.Run("it should return the resolved component if found", func(t *testing.T) {
+		// GIVEN
+		resolver := New()
+		resolver.MustRegister(ToStaticProvider("from-container"), Named("greeting"))
+
+		// WHEN
+		greeting, err := ResolveNamedOr(resolver, "greeting", "fallback")
+
+		// THEN
+		require.NoError(t, err)
+		assert.Equal(t, "from-container", greeting)
+	})
+
+	t.Run("it should return the fallback if nothing is found", func(t *testing.T) {
+		// GIVEN
+		resolver := New()
+		fallback := &TestService{Name: "fallback"}
+
+		// WHEN
+		service, err := ResolveOr(resolver, fallback)
+
+		// THEN
+		require.NoError(t, err)
+		assert.Same(t, fallback, service)
+	})
+
+	t.Run("it should inject the default value of missing dependencies", func(t *testing.T) {
+		// GIVEN
+		resolver := New()
+		resolver.MustRegister(
+			func(greeting string, port int) string {
+				return greeting + ":" + strconv.Itoa(port)
+			},
+			Named("address"),
+			Dependencies(
+				Inject.Named("greeting").Default("localhost"),
+				Inject.Auto().Default(8080),
+			),
+		)
+
+		// WHEN
+		address, err := ResolveNamed[string](resolver, "address")
+
+		// THEN
+		require.NoError(t, err)
+		assert.Equal(t, "localhost:8080", address)
+	})
+
+	t.Run("it should only inject the default value if the dependency is missing", func(t *testing.T) {
+		// GIVEN
+		resolver := New()
+		resolver.MustRegister(ToStaticProvider("hello"), Named("hello.greeting"))
+		resolver.MustRegister(
+			func(greeting string, name string) string {
+				return greeting + " " + name
+			},
+			Named("message"),
+			Dependencies(
+				Inject.Named("hello.greeting").Default("hi"),
+				Inject.Named("hello.name").Default("world"),
+			),
+		)
+
+		// WHEN
+		message, err := ResolveNamed[string](resolver, "message")
+
+		// THEN
+		require.NoError(t, err)
+		assert.Equal(t, "hello world", message)
+	})
+
+	t.Run("it should fail to register if the default value has the wrong type", func(t *testing.T) {
+		// GIVEN
+		resolver := New()
+
+		// WHEN
+		err := resolver.Register(
+			func(port int) string { return strconv.Itoa(port) },
+			Dependencies(Inject.Named("port").Default("not-an-int")),
+		)
+
+		// THEN
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "invalid default value for dependency named port")
+	})
+}
+
+func TestResolver_ResolveAllAsMap(t *testing.T) {
+	t.Run("it should resolve all components keyed by name", func(t *testing.T) {
+		// GIVEN
+		resolver := New()
+		resolver.MustRegister(ToStaticProvider(&TestService{Name: "first"}), Named("service.first"))
+		resolver.MustRegister(ToStaticProvider(&TestService{Name: "second"}), Named("service.second"))
+
+		// WHEN
+		services, err := ResolveAllAsMap[*TestService](resolver)
+
+		// THEN
+		require.NoError(t, err)
+		require.Len(t, services, 2)
+		assert.Equal(t, "first", services["service.first"].Name)
+		assert.Equal(t, "second", services["service.second"].Name)
+	})
+
+	t.Run("it should return an empty map if nothing is registered", func(t *testing.T) {
+		// GIVEN
+		resolver := New()
+
+		// WHEN
+		services, err := ResolveAllAsMap[*TestService](resolver)
+
+		// THEN
+		require.NoError(t, err)
+		assert.Empty(t, services)
+	})
+}
+
+func TestResolver_MultiplePostProcessed(t *testing.T) {
+	registerWords := func(resolver *Resolver) {
+		for _, word := range []string{"banana", "kiwi", "apple"} {
+			resolver.MustRegister(ToStaticProvider(word), Named("word."+word))
+		}
+	}
+
+	t.Run("it should filter and sort the injected slice", func(t *testing.T) {
+		// GIVEN
+		resolver := New()
+		registerWords(resolver)
+		resolver.MustRegister(
+			func(words []string) *ComplexComponent { return &ComplexComponent{tokens: words} },
+			Dependencies(Inject.Multiple().
+				Filtered(func(word string) bool { return word != "kiwi" }).
+				SortedBy(strings.Compare),
+			),
+		)
+
+		// WHEN
+		comp, err := Resolve[*ComplexComponent](resolver)
+
+		// THEN
+		require.NoError(t, err)
+		assert.Equal(t, []string{"apple", "banana"}, comp.tokens)
+	})
+
+	t.Run("it should filter the injected map", func(t *testing.T) {
+		// GIVEN
+		resolver := New()
+		registerWords(resolver)
+		var injected map[string]string
+		resolver.MustRegister(
+			func(words map[string]string) *TestService {
+				injected = words
+				return &TestService{}
+			},
+			Dependencies(Inject.Multiple().Filtered(func(word string) bool { return len(word) > 4 })),
+		)
+
+		// WHEN
+		_, err := Resolve[*TestService](resolver)
+
+		// THEN
+		require.NoError(t, err)
+		assert.Equal(t, map[string]string{"word.banana": "banana", "word.apple": "apple"}, injected)
+	})
+
+	t.Run("it should reject a filter not taking the injected components", func(t *testing.T) {
+		// GIVEN
+		resolver := New()
+
+		// WHEN
+		err := resolver.Register(
+			func([]string) *TestService { return &TestService{} },
+			Dependencies(Inject.Multiple().Filtered(func(int) bool { return true })),
+		)
+
+		// THEN
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "the filter of []string must be a func(string) bool, got func(int) bool")
+	})
+
+	t.Run("it should reject sorting a map", func(t *testing.T) {
+		// GIVEN
+		resolver := New()
+
+		// WHEN
+		err := resolver.Register(
+			func(map[string]string) *TestService { return &TestService{} },
+			Dependencies(Inject.Multiple().SortedBy(strings.Compare)),
+		)
+
+		// THEN
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "multiple dependencies can only be sorted with slice types")
+	})
+}
+
+func TestResolver_ResolveCtx(t *testing.T) {
+	type ctxKey struct{}
+
+	t.Run("it should inject the resolution context in the providers", func(t *testing.T) {
+		// GIVEN
+		resolver := New()
+		resolver.MustRegister(func(ctx context.Context) *TestService {
+			return &TestService{Name: ctx.Value(ctxKey{}).(string)}
+		})
+		ctx := context.WithValue(context.Background(), ctxKey{}, "from-ctx")
+
+		// WHEN
+		service, err := ResolveCtx[*TestService](ctx, resolver)
+
+		// THEN
+		require.NoError(t, err)
+		assert.Equal(t, "from-ctx", service.Name)
+	})
+
+	t.Run("it should abort a slow provider once the context is done", func(t *testing.T) {
+		// GIVEN
+		resolver := New()
+		release := make(chan struct{})
+		defer close(release)
+		resolver.MustRegister(func() *TestService {
+			<-release
+			return &TestService{Name: "slow"}
+		}, Named("slow"))
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+
+		// WHEN
+		_, err := ResolveNamedCtx[*TestService](ctx, resolver, "slow")
+
+		// THEN
+		require.Error(t, err)
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+	})
+
+	t.Run("it should not resolve anything if the context is already canceled", func(t *testing.T) {
+		// GIVEN
+		resolver := New()
+		built := false
+		resolver.MustRegister(func() *TestService {
+			built = true
+			return &TestService{}
+		})
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		// WHEN
+		_, err := ResolveCtx[*TestService](ctx, resolver)
+
+		// THEN
+		require.ErrorIs(t, err, context.Canceled)
+		assert.False(t, built)
+	})
+
+	t.Run("it should abort the rest of the chain once the caller gives up, within a scope", func(t *testing.T) {
+		// GIVEN
+		resolver := New()
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		resolver.MustRegister(func() *TestRepository {
+			cancel() // the caller gives up while the chain is being built
+			return &TestRepository{}
+		}, Scoped())
+		built := false
+		resolver.MustRegister(func(*TestRepository) *TestService {
+			built = true
+			return &TestService{}
+		}, Scoped())
+		scope := resolver.NewScope()
+		defer func() { _ = scope.Close() }()
+
+		// WHEN
+		_, err := ResolveAllCtx[*TestService](ctx, scope)
+
+		// THEN
+		require.ErrorIs(t, err, context.Canceled)
+		assert.False(t, built)
+	})
+
+	t.Run("it should resolve all the components with the context", func(t *testing.T) {
+		// GIVEN
+		resolver := New()
+		resolver.MustRegister(func(ctx context.Context) *TestService {
+			return &TestService{Name: ctx.Value(ctxKey{}).(string)}
+		}, Named("service"))
+		ctx := context.WithValue(context.Background(), ctxKey{}, "from-ctx")
+
+		// WHEN
+		services, err := ResolveAllAsMapCtx[*TestService](ctx, resolver)
+
+		// THEN
+		require.NoError(t, err)
+		assert.Equal(t, map[string]*TestService{"service": {Name: "from-ctx"}}, services)
+	})
+
+	t.Run("it should not fill the fields of a struct if the context is already canceled", func(t *testing.T) {
+		// GIVEN
+		type handler struct {
+			Service *TestService `godi:""`
+		}
+		resolver := New()
+		resolver.MustRegister(func() *TestService { return &TestService{} })
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		var h handler
+
+		// WHEN
+		err := ResolveIntoCtx(ctx, resolver, &h)
+
+		// THEN
+		require.ErrorIs(t, err, context.Canceled)
+		assert.Nil(t, h.Service)
+	})
+}
+
+func TestResolver_Transient(t *testing.T) {
+	t.Run("it should invoke the provider on every resolution", func(t *testing.T) {
+		// GIVEN
+		resolver := New()
+		counter := 0
+		resolver.MustRegister(func() *TestService {
+			counter++
+			return &TestService{Name: strconv.Itoa(counter)}
+		}, Transient())
+
+		// WHEN
+		first := MustResolve[*TestService](resolver)
+		second := MustResolve[*TestService](resolver)
+
+		// THEN
+		assert.NotSame(t, first, second)
+		assert.Equal(t, "1", first.Name)
+		assert.Equal(t, "2", second.Name)
+	})
+
+	t.Run("it should close all the produced instances with the resolver", func(t *testing.T) {
+		// GIVEN
+		resolver := New()
+		resolver.MustRegister(func() *TestService { return &TestService{} }, Transient())
+		first := MustResolve[*TestService](resolver)
+		second := MustResolve[*TestService](resolver)
+
+		// WHEN
+		err := resolver.Close()
+
+		// THEN
+		require.NoError(t, err)
+		assert.True(t, first.closed)
+		assert.True(t, second.closed)
+	})
+}
+
+func TestResolver_Warmup(t *testing.T) {
+	t.Run("it should instantiate the eager components and their dependencies", func(t *testing.T) {
+		// GIVEN
+		resolver := New()
+		var built []string
+		resolver.MustRegister(func() *TestService {
+			built = append(built, "service")
+			return &TestService{}
+		})
+		resolver.MustRegister(func(*TestService) *TestController {
+			built = append(built, "controller")
+			return &TestController{}
+		}, Eager())
+		resolver.MustRegister(func() *TestRepository {
+			built = append(built, "repository")
+			return &TestRepository{}
+		})
+
+		// WHEN
+		err := resolver.Warmup(context.Background())
+
+		// THEN
+		require.NoError(t, err)
+		assert.Equal(t, []string{"service", "controller"}, built)
+	})
+
+	t.Run("it should report the failures of the eager components", func(t *testing.T) {
+		// GIVEN
+		resolver := New()
+		resolver.MustRegister(NewFailingProvider, Eager())
+
+		// WHEN
+		err := resolver.Warmup(context.Background())
+
+		// THEN
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "failed to warm up component")
+	})
+}
+
+func TestResolver_WithContext(t *testing.T) {
+	type ctxKey struct{}
+
+	t.Run("it should inject the resolver context in the providers", func(t *testing.T) {
+		// GIVEN
+		ctx := context.WithValue(context.Background(), ctxKey{}, "from-resolver")
+		resolver := New().WithContext(ctx)
+		resolver.MustRegister(func(ctx context.Context) *TestService {
+			return &TestService{Name: ctx.Value(ctxKey{}).(string)}
+		})
+
+		// WHEN
+		service, err := Resolve[*TestService](resolver)
+
+		// THEN
+		require.NoError(t, err)
+		assert.Equal(t, "from-resolver", service.Name)
+	})
+
+	t.Run("it should prefer the context of the resolution", func(t *testing.T) {
+		// GIVEN
+		resolver := New().WithContext(context.WithValue(context.Background(), ctxKey{}, "from-resolver"))
+		resolver.MustRegister(func(ctx context.Context) *TestService {
+			return &TestService{Name: ctx.Value(ctxKey{}).(string)}
+		})
+
+		// WHEN
+		service, err := ResolveCtx[*TestService](context.WithValue(context.Background(), ctxKey{}, "from-resolution"), resolver)
+
+		// THEN
+		require.NoError(t, err)
+		assert.Equal(t, "from-resolution", service.Name)
+	})
+
+	t.Run("it should abort the resolutions once the resolver context is done", func(t *testing.T) {
+		// GIVEN
+		ctx, cancel := context.WithCancel(context.Background())
+		resolver := New().WithContext(ctx)
+		resolver.MustRegister(NewTestService)
+		cancel()
+
+		// WHEN
+		_, err := Resolve[*TestService](resolver)
+
+		// THEN
+		require.ErrorIs(t, err, context.Canceled)
+	})
+}
+
+func TestResolver_WithParallelResolution(t *testing.T) {
+	t.Run("it should resolve the independent dependencies concurrently", func(t *testing.T) {
+		// GIVEN
+		resolver := New(WithParallelResolution(2))
+		resolver.MustRegister(func() *TestService {
+			time.Sleep(100 * time.Millisecond)
+			return &TestService{}
+		})
+		resolver.MustRegister(func() *TestRepository {
+			time.Sleep(100 * time.Millisecond)
+			return &TestRepository{}
+		})
+		resolver.MustRegister(NewTestController)
+
+		// WHEN
+		start := time.Now()
+		controller, err := Resolve[*TestController](resolver)
+		elapsed := time.Since(start)
+
+		// THEN
+		require.NoError(t, err)
+		assert.NotNil(t, controller.Service)
+		assert.NotNil(t, controller.Repo)
+		assert.Less(t, elapsed, 180*time.Millisecond)
+	})
+
+	t.Run("it should report the failure of a dependency", func(t *testing.T) {
+		// GIVEN
+		resolver := New(WithParallelResolution(4))
+		resolver.MustRegister(NewFailingProvider)
+		resolver.MustRegister(NewTestRepository)
+		resolver.MustRegister(NewTestController)
+
+		// WHEN
+		_, err := Resolve[*TestController](resolver)
+
+		// THEN
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "failed to resolve dependency")
+	})
+
+	t.Run("it should share the limit between the resolutions", func(t *testing.T) {
+		// GIVEN
+		resolver := New(WithParallelResolution(2))
+		var running, maxRunning atomic.Int64
+		leaf := func() *TestService {
+			current := running.Add(1)
+			defer running.Add(-1)
+			for {
+				previous := maxRunning.Load()
+				if current <= previous || maxRunning.CompareAndSwap(previous, current) {
+					break
+				}
+			}
+			time.Sleep(20 * time.Millisecond)
+			return &TestService{}
+		}
+		for _, name := range []string{"a", "b", "c", "d", "e", "f", "g", "h"} {
+			resolver.MustRegister(leaf, Named(name), Transient())
+		}
+		resolver.MustRegister(func(a, b, c, d *TestService) *TestController {
+			return &TestController{Service: a}
+		}, Named("left"), Transient(), Dependencies(Inject.Named("a"), Inject.Named("b"), Inject.Named("c"), Inject.Named("d")))
+		resolver.MustRegister(func(e, f, g, h *TestService) *TestController {
+			return &TestController{Service: e}
+		}, Named("right"), Transient(), Dependencies(Inject.Named("e"), Inject.Named("f"), Inject.Named("g"), Inject.Named("h")))
+		resolver.MustRegister(func(left, right *TestController) *TestRepository {
+			return &TestRepository{}
+		}, Dependencies(Inject.Named("left"), Inject.Named("right")))
+
+		// WHEN
+		_, err := Resolve[*TestRepository](resolver)
+
+		// THEN
+		require.NoError(t, err)
+		// the two slots, plus the goroutine of the resolution resolving in place once they are taken
+		assert.LessOrEqual(t, maxRunning.Load(), int64(3))
+	})
+}
+
+type closeFunc func() error
+
+func (f closeFunc) Close() error {
+	return f()
+}
+
+type shutdownKey struct{}
+
+type testWorker struct {
+	stoppedWith any
+}
+
+func (w *testWorker) Stop(ctx context.Context) error {
+	w.stoppedWith = ctx.Value(shutdownKey{})
+	return nil
+}
+
+func TestResolver_WithStrictPriorities(t *testing.T) {
+	t.Run("it should fail to register a provider tied with a registered one", func(t *testing.T) {
+		// GIVEN
+		resolver := New(WithStrictPriorities())
+		resolver.MustRegister(ToStaticProvider("first"), Named("greeting"))
+
+		// WHEN
+		err := resolver.Register(ToStaticProvider("second"), Named("greeting"))
+
+		// THEN
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "provides (greeting, string) with the same priority (0)")
+		greeting, err := ResolveNamed[string](resolver, "greeting")
+		require.NoError(t, err)
+		assert.Equal(t, "first", greeting)
+	})
+
+	t.Run("it should register the providers with different priorities or versions", func(t *testing.T) {
+		// GIVEN
+		resolver := New(WithStrictPriorities())
+		resolver.MustRegister(ToStaticProvider("first"), Named("greeting"))
+
+		// WHEN
+		errPriority := resolver.Register(ToStaticProvider("second"), Named("greeting"), Priority(10))
+		errVersion := resolver.Register(ToStaticProvider("third"), Named("greeting"), Version("2"))
+
+		// THEN
+		require.NoError(t, errPriority)
+		require.NoError(t, errVersion)
+	})
+
+	t.Run("it should let the last registered provider win without strict priorities", func(t *testing.T) {
+		// GIVEN
+		resolver := New()
+		resolver.MustRegister(ToStaticProvider("first"), Named("greeting"))
+		resolver.MustRegister(ToStaticProvider("second"), Named("greeting"))
+
+		// WHEN
+		greeting, err := ResolveNamed[string](resolver, "greeting")
+
+		// THEN
+		require.NoError(t, err)
+		assert.Equal(t, "second", greeting)
+	})
+}
+
+func TestResolver_WithStoreLimit(t *testing.T) {
+	newTenantClient := func(tenant string) func() *TestService {
+		return func() *TestService { return &TestService{Name: tenant} }
+	}
+
+	t.Run("it should evict and close the least recently used components beyond the limit", func(t *testing.T) {
+		// GIVEN
+		var evicted []string
+		resolver := New(WithStoreLimit(2), WithEvictionCallback(func(name Name, _ any) {
+			evicted = append(evicted, name.Name())
+		}))
+		resolver.MustRegister(newTenantClient("acme"), Named("client.acme"))
+		resolver.MustRegister(newTenantClient("globex"), Named("client.globex"))
+		resolver.MustRegister(newTenantClient("initech"), Named("client.initech"))
+		acme := MustResolveNamed[*TestService](resolver, "client.acme")
+		globex := MustResolveNamed[*TestService](resolver, "client.globex")
+		MustResolveNamed[*TestService](resolver, "client.acme")
+
+		// WHEN
+		MustResolveNamed[*TestService](resolver, "client.initech")
+
+		// THEN
+		assert.Equal(t, []string{"client.globex"}, evicted)
+		assert.True(t, globex.closed)
+		assert.False(t, acme.closed)
+		assert.Same(t, acme, MustResolveNamed[*TestService](resolver, "client.acme"))
+		assert.NotSame(t, globex, MustResolveNamed[*TestService](resolver, "client.globex"))
+	})
+
+	t.Run("it should evict the components built with an evicted component", func(t *testing.T) {
+		// GIVEN
+		resolver := New(WithStoreLimit(3))
+		resolver.MustRegister(NewTestRepository)
+		resolver.MustRegister(func(repo *TestRepository) *TestController { return &TestController{Repo: repo} })
+		for _, tenant := range []string{"acme", "globex", "initech"} {
+			resolver.MustRegister(newTenantClient(tenant), Named("client."+tenant))
+		}
+
+		for _, tenant := range []string{"acme", "globex", "initech", "acme", "globex"} {
+			// WHEN
+			MustResolve[*TestController](resolver)
+			MustResolveNamed[*TestService](resolver, "client."+tenant)
+
+			// THEN
+			assert.False(t, MustResolve[*TestController](resolver).Repo.closed)
+		}
+	})
+
+	t.Run("it should never evict the resolver", func(t *testing.T) {
+		// GIVEN
+		resolver := New(WithStoreLimit(1))
+		MustResolve[*Resolver](resolver)
+		resolver.MustRegister(newTenantClient("acme"), Named("client.acme"))
+		resolver.MustRegister(newTenantClient("globex"), Named("client.globex"))
+
+		// WHEN
+		MustResolveNamed[*TestService](resolver, "client.acme")
+		MustResolveNamed[*TestService](resolver, "client.globex")
+
+		// THEN
+		assert.Same(t, resolver, MustResolve[*Resolver](resolver))
+		assert.Equal(t, "globex", MustResolveNamed[*TestService](resolver, "client.globex").Name)
+	})
+}
+
+type (
+	factoryCycleA struct{}
+	factoryCycleB struct {
+		factory func() (*factoryCycleA, error)
+	}
+	factoryCycleC struct{}
+)
+
+func TestResolver_Factory(t *testing.T) {
+	t.Run("it should inject a factory building a fresh component on every call", func(t *testing.T) {
+		// GIVEN
+		resolver := New()
+		resolver.MustRegister(NewTestService)
+		resolver.MustRegister(
+			func(factory func() (*TestService, error)) func() (*TestService, error) {
+				return factory
+			},
+			Named("serviceFactory"),
+			Dependencies(Inject.Factory()),
+		)
+		singleton := MustResolve[*TestService](resolver)
+
+		// WHEN
+		factory, err := ResolveNamed[func() (*TestService, error)](resolver, "serviceFactory")
+
+		// THEN
+		require.NoError(t, err)
+		first, err := factory()
+		require.NoError(t, err)
+		second, err := factory()
+		require.NoError(t, err)
+		assert.NotSame(t, first, second)
+		assert.NotSame(t, singleton, first)
+	})
+
+	t.Run("it should leave the components built by the factory to the caller", func(t *testing.T) {
+		// GIVEN
+		resolver := New()
+		resolver.MustRegister(NewTestService)
+		resolver.MustRegister(
+			func(factory func() (*TestService, error)) func() (*TestService, error) {
+				return factory
+			},
+			Dependencies(Inject.Factory()),
+		)
+		factory := MustResolve[func() (*TestService, error)](resolver)
+		service, err := factory()
+		require.NoError(t, err)
+
+		// WHEN
+		err = resolver.Close()
+
+		// THEN
+		require.NoError(t, err)
+		assert.False(t, service.closed)
+	})
+
+	t.Run("it should not keep a reference to the components built by the factory", func(t *testing.T) {
+		// GIVEN
+		resolver := New()
+		resolver.MustRegister(NewTestService)
+		resolver.MustRegister(
+			func(factory func() (*TestService, error)) func() (*TestService, error) {
+				return factory
+			},
+			Dependencies(Inject.Factory()),
+		)
+		factory := MustResolve[func() (*TestService, error)](resolver)
+
+		// WHEN
+		for range 100 {
+			service, err := factory()
+			require.NoError(t, err)
+			require.NoError(t, service.Close())
+		}
+
+		// THEN
+		assert.Empty(t, resolver.store.tracked)
+	})
+
+	t.Run("it should return the provider error from the factory", func(t *testing.T) {
+		// GIVEN
+		resolver := New()
+		resolver.MustRegister(NewFailingProvider)
+		resolver.MustRegister(
+			func(factory func() (*TestService, error)) func() (*TestService, error) {
+				return factory
+			},
+			Dependencies(Inject.Factory()),
+		)
+		factory := MustResolve[func() (*TestService, error)](resolver)
+
+		// WHEN
+		service, err := factory()
+
+		// THEN
+		require.Error(t, err)
+		assert.Nil(t, service)
+	})
+
+	t.Run("it should detect the cycle of a factory called by the construction it is injected into", func(t *testing.T) {
+		// GIVEN
+		resolver := New()
+		resolver.MustRegister(func(*factoryCycleB) *factoryCycleA { return &factoryCycleA{} })
+		resolver.MustRegister(func(*factoryCycleC) *factoryCycleB { return &factoryCycleB{} })
+		resolver.MustRegister(func(factory func() (*factoryCycleA, error)) (*factoryCycleC, error) {
+			_, err := factory()
+			return &factoryCycleC{}, err
+		}, Dependencies(Inject.Factory()))
+
+		// WHEN
+		done := make(chan error, 1)
+		go func() {
+			_, err := Resolve[*factoryCycleA](resolver)
+			done <- err
+		}()
+
+		// THEN
+		select {
+		case err := <-done:
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), "cycle found")
+		case <-time.After(time.Second):
+			t.Fatal("the resolution did not return")
+		}
+	})
+
+	t.Run("it should not continue the construction once done", func(t *testing.T) {
+		// GIVEN
+		resolver := New()
+		resolver.MustRegister(func(*factoryCycleB) *factoryCycleA { return &factoryCycleA{} })
+		resolver.MustRegister(func(factory func() (*factoryCycleA, error)) *factoryCycleB {
+			return &factoryCycleB{factory: factory}
+		}, Dependencies(Inject.Factory()))
+		b := MustResolve[*factoryCycleB](resolver)
+
+		// WHEN
+		a, err := b.factory()
+
+		// THEN
+		require.NoError(t, err)
+		assert.NotNil(t, a)
+	})
+
+	t.Run("it should reject a dependency not shaped as a factory", func(t *testing.T) {
+		// GIVEN
+		resolver := New()
+
+		// WHEN
+		err := resolver.Register(
+			func(factory func() *TestService) string { return "" },
+			Dependencies(Inject.Factory()),
+		)
+
+		// THEN
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "factory dependencies can only be used with func() (T, error) types")
+	})
+}
+
+func TestResolver_CycleDiagnostics(t *testing.T) {
+	t.Run("it should describe the chain, the providers and how to break the cycle", func(t *testing.T) {
+		// GIVEN
+		resolver := New()
+		resolver.MustRegister(func(string) int { return 0 }, Named("a"), Dependencies(Inject.Named("b")))
+		resolver.MustRegister(func(bool) string { return "" }, Named("b"), Dependencies(Inject.Named("c")))
+		resolver.MustRegister(func(int) bool { return false }, Named("c"), Dependencies(Inject.Named("a")))
+
+		// WHEN
+		_, err := ResolveNamed[int](resolver, "a")
+
+		// THEN
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "cycle found: (a, int) -> (b, string) -> (c, bool) -> (a, int)\n")
+		assert.Regexp(t, `\t\(b, string\) is provided by github.com/a-peyrard/godi.TestResolver_CycleDiagnostics.func1.\d+ \(.*/resolver_test.go:\d+\)`, err.Error())
+		assert.Contains(t, err.Error(), "hint: break the cycle by injecting the *godi.Resolver in (c, bool) instead of (a, int), and resolving it once (c, bool) is built")
+	})
+
+	t.Run("it should describe the cycles found by the validation", func(t *testing.T) {
+		// GIVEN
+		resolver := New()
+		resolver.MustRegister(func(string) int { return 0 }, Named("a"), Dependencies(Inject.Named("b")))
+		resolver.MustRegister(func(int) string { return "" }, Named("b"), Dependencies(Inject.Named("a")))
+
+		// WHEN
+		err := resolver.Validate()
+
+		// THEN
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), " -> ")
+		assert.Contains(t, err.Error(), "resolver_test.go:")
+		assert.Contains(t, err.Error(), "hint: break the cycle")
+	})
+}
+
+func TestResolver_Hidden(t *testing.T) {
+	t.Run("it should skip the hidden components in the interface queries", func(t *testing.T) {
+		// GIVEN
+		resolver := New()
+		resolver.MustRegister(NewTestService)
+		resolver.MustRegister(NewTestRepository, Hidden())
+
+		// WHEN
+		closers, err := ResolveAll[io.Closer](resolver)
+
+		// THEN
+		require.NoError(t, err)
+		require.Len(t, closers, 1)
+		assert.IsType(t, &TestService{}, closers[0])
+	})
+
+	t.Run("it should still resolve the hidden components by name or by their own type", func(t *testing.T) {
+		// GIVEN
+		resolver := New()
+		resolver.MustRegister(NewTestRepository, Named("repository"), Hidden())
+
+		// WHEN
+		byName, err1 := ResolveNamed[*TestRepository](resolver, "repository")
+		byType, err2 := Resolve[*TestRepository](resolver)
+		self, err3 := Resolve[*Resolver](resolver)
+
+		// THEN
+		require.NoError(t, err1)
+		require.NoError(t, err2)
+		require.NoError(t, err3)
+		assert.Same(t, byType, byName)
+		assert.Same(t, resolver, self)
+	})
+}
+
+type initializedWorker struct {
+	repository  *TestRepository
+	initialized bool
+	closed      bool
+	initErr     error
+}
+
+func (w *initializedWorker) PostConstruct(_ context.Context) error {
+	w.initialized = w.repository != nil
+	return w.initErr
+}
+
+func (w *initializedWorker) Close() error {
+	w.closed = true
+	return nil
+}
+
+func TestResolver_PostConstruct(t *testing.T) {
+	t.Run("it should call PostConstruct once the dependencies are injected", func(t *testing.T) {
+		// GIVEN
+		resolver := New()
+		resolver.MustRegister(NewTestRepository)
+		resolver.MustRegister(func(repository *TestRepository) *initializedWorker {
+			return &initializedWorker{repository: repository}
+		})
+
+		// WHEN
+		worker, err := Resolve[*initializedWorker](resolver)
+
+		// THEN
+		require.NoError(t, err)
+		assert.True(t, worker.initialized)
+	})
+
+	t.Run("it should call the OnInit hook instead of PostConstruct", func(t *testing.T) {
+		// GIVEN
+		var initialized []string
+		resolver := New()
+		resolver.MustRegister(NewTestRepository, OnInit(func(repository *TestRepository) error {
+			initialized = append(initialized, "repository")
+			return nil
+		}))
+		resolver.MustRegister(func(repository *TestRepository) *initializedWorker {
+			return &initializedWorker{repository: repository}
+		}, OnInit(func(worker *initializedWorker) error {
+			initialized = append(initialized, "worker")
+			return nil
+		}))
+
+		// WHEN
+		worker, err := Resolve[*initializedWorker](resolver)
+
+		// THEN
+		require.NoError(t, err)
+		assert.False(t, worker.initialized)
+		assert.Equal(t, []string{"repository", "worker"}, initialized)
+	})
+
+	t.Run("it should close the component and not store it if its initialization fails", func(t *testing.T) {
+		// GIVEN
+		var workers []*initializedWorker
+		resolver := New()
+		resolver.MustRegister(func() *initializedWorker {
+			worker := &initializedWorker{initErr: errors.New("unable to connect")}
+			workers = append(workers, worker)
+			return worker
+		})
+
+		// WHEN
+		_, err1 := Resolve[*initializedWorker](resolver)
+		_, err2 := Resolve[*initializedWorker](resolver)
+
+		// THEN
+		require.Error(t, err1)
+		require.Error(t, err2)
+		assert.Contains(t, err1.Error(), "unable to connect")
+		require.Len(t, workers, 2)
+		assert.True(t, workers[0].closed)
+	})
+}
+
+func BenchmarkResolveNamed_Stored(b *testing.B) {
+	resolver := New()
+	resolver.MustRegister(NewTestService, Named("service"))
+	MustResolveNamed[*TestService](resolver, "service")
+
+	for b.Loop() {
+		_, _ = ResolveNamed[*TestService](resolver, "service")
+	}
+}
+
+func BenchmarkResolve_Stored(b *testing.B) {
+	resolver := New()
+	resolver.MustRegister(NewTestService)
+	MustResolve[*TestService](resolver)
+
+	for b.Loop() {
+		_, _ = Resolve[*TestService](resolver)
+	}
+}
+
+func BenchmarkResolve_StoredCompiled(b *testing.B) {
+	resolver := NewBuilder().Providers(NewTestService).MustCompile()
+	MustResolve[*TestService](resolver)
+
+	for b.Loop() {
+		_, _ = Resolve[*TestService](resolver)
+	}
+}
+
+func BenchmarkResolveNamed_StoredLogged(b *testing.B) {
+	resolver := New(WithLogger(slog.New(slog.NewTextHandler(io.Discard, &slog.HandlerOptions{Level: slog.LevelInfo}))))
+	resolver.MustRegister(NewTestService, Named("service"))
+	MustResolveNamed[*TestService](resolver, "service")
+
+	for b.Loop() {
+		_, _ = ResolveNamed[*TestService](resolver, "service")
+	}
+}
+
+func BenchmarkResolve_Transient(b *testing.B) {
+	resolver := New()
+	resolver.MustRegister(func(repository *TestRepository) *TestService {
+		return &TestService{Name: repository.Data}
+	}, Transient())
+	resolver.MustRegister(func() *TestRepository { return &TestRepository{} })
+
+	for b.Loop() {
+		_, _ = Resolve[*TestService](resolver)
+	}
+}
+
+func BenchmarkResolve_Parallel(b *testing.B) {
+	resolver := New()
+	resolver.MustRegister(NewTestService, Named("service"))
+	MustResolveNamed[*TestService](resolver, "service")
+
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			_, _ = ResolveNamed[*TestService](resolver, "service")
+		}
+	})
+}
@@ -0,0 +1,252 @@
+package godi
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"slices"
+
+	"github.com/a-peyrard/godi/option"
+)
+
+// resultsSuffix is the suffix of the name of the results of a factory method returning several components.
+const resultsSuffix = "#results"
+
+type (
+	// multiResultProvider provides the components returned by a factory method returning several components,
+	// e.g. func NewPipe() (*Reader, *Writer, error), each of them under its own Name, with the name of the provider
+	// and its type, or the fields of a godi.Out struct (see Out). The factory method is called once for all of them:
+	// its results are provided as a whole by a hidden provider, and the components are extracted from them.
+	multiResultProvider struct {
+		names []Name
+		call  *factoryResultsProvider
+		// results is the provider calling the factory method, with the lifetime of the components,
+		// see bindAttributes
+		results Provider
+
+		priority    int
+		description string
+	}
+
+	// factoryResultsProvider calls the factory method of a multiResultProvider, and provides its results.
+	factoryResultsProvider struct {
+		name         Name
+		factory      reflect.Value
+		dependencies []Request
+		variadic     bool
+		withError    bool
+		// fields are the indexes of the components in the godi.Out struct returned, if any, see Out
+		fields [][]int
+	}
+
+	// factoryResults are the results of a factory method returning several components, the error excluded.
+	factoryResults []reflect.Value
+
+	// queryResults finds the results of the factory method of a multiResultProvider.
+	queryResults struct {
+		provider *multiResultProvider
+	}
+)
+
+var factoryResultsType = TypeOf[factoryResults]()
+
+func newMultiResultProvider(factoryMethod any, opts ...option.Option[RegistrableOptions]) (Provider, error) {
+	t := reflect.TypeOf(factoryMethod)
+	fnName := runtime.FuncForPC(reflect.ValueOf(factoryMethod).Pointer()).Name()
+	options := option.Build(
+		&RegistrableOptions{
+			named: filepath.Base(fnName),
+		},
+		opts...,
+	)
+	if options.perConsumer {
+		return nil, errors.New("the components of a factory method returning several components can not be built per consumer")
+	}
+
+	withError := t.Out(t.NumOut()-1) == ErrorType
+	count := t.NumOut()
+	if withError {
+		count--
+	}
+	var (
+		names  []Name
+		fields [][]int
+	)
+	if isOutStruct(t.Out(0)) {
+		if count != 1 {
+			return nil, fmt.Errorf("factory method %s returning a godi.Out struct must either return it and an error, or just it", fnName)
+		}
+		var err error
+		if names, fields, err = outFieldsOf(t.Out(0), options.named); err != nil {
+			return nil, err
+		}
+	} else {
+		for i := range count {
+			names = append(names, Name{name: options.named, typ: t.Out(i)})
+		}
+	}
+	for i, n := range names {
+		if n.typ == ErrorType {
+			return nil, errors.New("if factory method returns an error, it must be its last result")
+		}
+		if slices.Contains(names[:i], n) {
+			return nil, fmt.Errorf("factory method %s returns several components of type %s named %s, they can not be told apart", fnName, n.typ, n.name)
+		}
+	}
+
+	dependencies, err := factoryDependencies(t, options.dependencies, fnName)
+	if err != nil {
+		return nil, err
+	}
+	if slices.ContainsFunc(dependencies, func(req Request) bool { return req.injectionContext }) {
+		return nil, errors.New("the components of a factory method returning several components can not depend on the InjectionContext")
+	}
+
+	call := &factoryResultsProvider{
+		name:         Name{name: options.named + resultsSuffix, typ: factoryResultsType},
+		factory:      reflect.ValueOf(factoryMethod),
+		dependencies: dependencies,
+		variadic:     t.IsVariadic(),
+		withError:    withError,
+		fields:       fields,
+	}
+	return &multiResultProvider{
+		names:       names,
+		call:        call,
+		results:     call,
+		priority:    options.priority,
+		description: options.description,
+	}, nil
+}
+
+// bindAttributes gives the results of the factory method the lifetime of the components, so they are built again
+// along with them, e.g. in each scope.
+func (m *multiResultProvider) bindAttributes(attributes providerAttributes) {
+	m.results = withAttributes(m.call, providerAttributes{
+		ttl:       attributes.ttl,
+		version:   attributes.version,
+		scoped:    attributes.scoped,
+		transient: attributes.transient,
+		hidden:    true,
+	})
+}
+
+func (m *multiResultProvider) hasFixedNames() bool { return true }
+
+func (m *multiResultProvider) CanProvide(name Name) bool {
+	for _, n := range m.names {
+		if name.name == n.name && matchType(name.typ, n.typ) {
+			return true
+		}
+	}
+	return false
+}
+
+func (m *multiResultProvider) Provide(name Name, dependencies []reflect.Value) (reflect.Value, error) {
+	results := dependencies[0].Interface().(factoryResults)
+	for i, n := range m.names {
+		if n.name == name.name && n.typ == name.typ {
+			return results[i], nil
+		}
+	}
+	return reflect.Value{}, fmt.Errorf("%s does not provide %s", m, name)
+}
+
+func (m *multiResultProvider) Dependencies() []Request {
+	return []Request{{
+		unitaryTyp: factoryResultsType,
+		query:      queryResults{provider: m},
+		validator:  validatorUniqueMandatory{},
+		collector:  collectorUnique{},
+	}}
+}
+
+func (m *multiResultProvider) ListProvidableNames() []Name {
+	return m.names
+}
+
+func (m *multiResultProvider) Priority() int {
+	return m.priority
+}
+
+func (m *multiResultProvider) Description() string {
+	return m.description
+}
+
+func (m *multiResultProvider) String() string {
+	return fmt.Sprintf("MultiResultProvider(%s, %s)", m.names[0].name, runtime.FuncForPC(m.call.factory.Pointer()).Name())
+}
+
+func (f *factoryResultsProvider) CanProvide(name Name) bool {
+	return name == f.name
+}
+
+func (f *factoryResultsProvider) Provide(_ Name, dependencies []reflect.Value) (comp reflect.Value, err error) {
+	var results []reflect.Value
+	func() {
+		defer func() {
+			if r := recover(); r != nil {
+				err = fmt.Errorf("panic calling provider for %s: %v", f.name.String(), r)
+			}
+		}()
+		if f.variadic {
+			results = f.factory.CallSlice(dependencies)
+			return
+		}
+		results = f.factory.Call(dependencies)
+	}()
+	if err != nil {
+		return reflect.Value{}, err
+	}
+
+	if f.withError {
+		if errValue := results[len(results)-1]; !errValue.IsNil() {
+			return reflect.Value{}, errValue.Interface().(error)
+		}
+		results = results[:len(results)-1]
+	}
+	if f.fields != nil {
+		out := results[0]
+		results = make([]reflect.Value, len(f.fields))
+		for i, index := range f.fields {
+			results[i] = out.FieldByIndex(index)
+		}
+	}
+	return reflect.ValueOf(factoryResults(results)), nil
+}
+
+func (f *factoryResultsProvider) Dependencies() []Request {
+	return f.dependencies
+}
+
+func (f *factoryResultsProvider) ListProvidableNames() []Name {
+	return []Name{f.name}
+}
+
+func (f *factoryResultsProvider) Priority() int {
+	return 0
+}
+
+func (f *factoryResultsProvider) Description() string {
+	return ""
+}
+
+func (f *factoryResultsProvider) String() string {
+	return fmt.Sprintf("FactoryMethodProvider(%s, %s)", f.name.String(), runtime.FuncForPC(f.factory.Pointer()).Name())
+}
+
+func (q queryResults) find(r *Resolver) ([]*queryResult, error) {
+	results := q.provider.results
+	name := versionedName(q.provider.call.name, results)
+	result := &queryResult{name: name, provider: results}
+	if comp, found := r.store.Get(name); found {
+		result.component = &comp
+	}
+	return []*queryResult{result}, nil
+}
+
+func (q queryResults) String() string {
+	return fmt.Sprintf("<results of %s>", q.provider)
+}
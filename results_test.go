@@ -0,0 +1,113 @@
+package godi
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type (
+	pipeReader struct{ pipe *int }
+	pipeWriter struct{ pipe *int }
+)
+
+func TestMultiResultProvider(t *testing.T) {
+	newPipe := func(calls *int) func() (*pipeReader, *pipeWriter, error) {
+		return func() (*pipeReader, *pipeWriter, error) {
+			*calls++
+			pipe := new(int)
+			return &pipeReader{pipe: pipe}, &pipeWriter{pipe: pipe}, nil
+		}
+	}
+
+	t.Run("it should provide each result as a component, calling the factory method once", func(t *testing.T) {
+		// GIVEN
+		resolver := New()
+		calls := 0
+		resolver.MustRegister(newPipe(&calls), Named("pipe"))
+
+		// WHEN
+		reader, readerErr := Resolve[*pipeReader](resolver)
+		writer, writerErr := ResolveNamed[*pipeWriter](resolver, "pipe")
+
+		// THEN
+		require.NoError(t, readerErr)
+		require.NoError(t, writerErr)
+		assert.Same(t, reader.pipe, writer.pipe)
+		assert.Equal(t, 1, calls)
+		assert.NoError(t, resolver.Validate())
+	})
+
+	t.Run("it should call the factory method in each scope for scoped components", func(t *testing.T) {
+		// GIVEN
+		resolver := New()
+		calls := 0
+		resolver.MustRegister(newPipe(&calls), Named("pipe"), Scoped())
+		first, second := resolver.NewScope(), resolver.NewScope()
+
+		// WHEN
+		firstReader, _ := Resolve[*pipeReader](first)
+		firstWriter, _ := Resolve[*pipeWriter](first)
+		secondReader, err := Resolve[*pipeReader](second)
+
+		// THEN
+		require.NoError(t, err)
+		assert.Same(t, firstReader.pipe, firstWriter.pipe)
+		assert.NotSame(t, firstReader.pipe, secondReader.pipe)
+		assert.Equal(t, 2, calls)
+	})
+
+	t.Run("it should inject the dependencies of the factory method, and report its error", func(t *testing.T) {
+		// GIVEN
+		resolver := New()
+		resolver.MustRegister(func() string { return "broken" }, Named("pipe.state"))
+		resolver.MustRegister(func(state string) (*pipeReader, *pipeWriter, error) {
+			return nil, nil, errors.New(state)
+		}, Named("pipe"), Dependencies(Inject.Named("pipe.state")))
+
+		// WHEN
+		_, err := Resolve[*pipeWriter](resolver)
+
+		// THEN
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "broken")
+	})
+
+	t.Run("it should build the components again together once refreshed", func(t *testing.T) {
+		// GIVEN
+		resolver := New()
+		calls := 0
+		resolver.MustRegister(newPipe(&calls), Named("pipe"))
+		reader := MustResolve[*pipeReader](resolver)
+		_ = MustResolve[*pipeWriter](resolver)
+
+		// WHEN
+		err := resolver.Refresh("pipe")
+
+		// THEN
+		require.NoError(t, err)
+		refreshedReader := MustResolve[*pipeReader](resolver)
+		refreshedWriter := MustResolve[*pipeWriter](resolver)
+		assert.NotSame(t, reader, refreshedReader)
+		assert.Same(t, refreshedReader.pipe, refreshedWriter.pipe)
+		assert.Equal(t, 2, calls)
+	})
+
+	t.Run("it should describe the dependencies of the factory method", func(t *testing.T) {
+		// GIVEN
+		resolver := New()
+		resolver.MustRegister(func(string) (*pipeReader, *pipeWriter) {
+			return &pipeReader{}, &pipeWriter{}
+		}, Named("pipe"), Dependencies(Inject.Named("pipe.state")))
+
+		// WHEN
+		providers := resolver.Providers()
+
+		// THEN
+		require.NotEmpty(t, providers)
+		assert.Equal(t, []Name{{name: "pipe", typ: TypeOf[*pipeReader]()}, {name: "pipe", typ: TypeOf[*pipeWriter]()}}, providers[0].Names)
+		assert.Equal(t, []DependencyInfo{{Name: "pipe.state", Type: StringType}}, providers[0].Dependencies)
+	})
+}
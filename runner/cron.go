@@ -0,0 +1,129 @@
+package runner
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// cronSchedule is a parsed cron expression, each field being the set of the matching values.
+type cronSchedule struct {
+	minutes     [60]bool
+	hours       [24]bool
+	daysOfMonth [32]bool
+	months      [13]bool
+	daysOfWeek  [7]bool
+
+	// restricted days of month and week are combined with a OR, as cron does
+	anyDayOfMonth bool
+	anyDayOfWeek  bool
+}
+
+// cronSearchLimit bounds the search of the next run, for expressions never matching (e.g. "0 0 31 2 *").
+const cronSearchLimit = 5 * 366 * 24 * time.Hour
+
+// parseCron parses a standard cron expression: minute, hour, day of month, month and day of week.
+//
+// Each field supports *, values, ranges (1-5), steps (*/15, 1-30/5) and lists (1,15,30).
+// Sunday is both 0 and 7 for the day of week.
+func parseCron(expr string) (*cronSchedule, error) {
+	fields := strings.Fields(expr)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("invalid cron expression %q: expected 5 fields, got %d", expr, len(fields))
+	}
+
+	var (
+		schedule = &cronSchedule{}
+		err      error
+	)
+	parse := func(field string, minValue int, maxValue int, set func(int)) {
+		if err == nil {
+			err = parseCronField(field, minValue, maxValue, set)
+		}
+	}
+	parse(fields[0], 0, 59, func(v int) { schedule.minutes[v] = true })
+	parse(fields[1], 0, 23, func(v int) { schedule.hours[v] = true })
+	parse(fields[2], 1, 31, func(v int) { schedule.daysOfMonth[v] = true })
+	parse(fields[3], 1, 12, func(v int) { schedule.months[v] = true })
+	parse(fields[4], 0, 7, func(v int) { schedule.daysOfWeek[v%7] = true })
+	if err != nil {
+		return nil, fmt.Errorf("invalid cron expression %q: %w", expr, err)
+	}
+	schedule.anyDayOfMonth = fields[2] == "*"
+	schedule.anyDayOfWeek = fields[4] == "*"
+	return schedule, nil
+}
+
+func parseCronField(field string, minValue int, maxValue int, set func(int)) error {
+	for _, part := range strings.Split(field, ",") {
+		valueRange, stepStr, hasStep := strings.Cut(part, "/")
+		step := 1
+		if hasStep {
+			var err error
+			if step, err = strconv.Atoi(stepStr); err != nil || step <= 0 {
+				return fmt.Errorf("invalid step %q", stepStr)
+			}
+		}
+
+		from, to := minValue, maxValue
+		if valueRange != "*" {
+			fromStr, toStr, isRange := strings.Cut(valueRange, "-")
+			var err error
+			if from, err = strconv.Atoi(fromStr); err != nil {
+				return fmt.Errorf("invalid value %q", fromStr)
+			}
+			to = from
+			if isRange {
+				if to, err = strconv.Atoi(toStr); err != nil {
+					return fmt.Errorf("invalid value %q", toStr)
+				}
+			} else if hasStep {
+				to = maxValue
+			}
+		}
+		if from < minValue || to > maxValue || from > to {
+			return fmt.Errorf("%q is out of the range %d-%d", part, minValue, maxValue)
+		}
+		for v := from; v <= to; v += step {
+			set(v)
+		}
+	}
+	return nil
+}
+
+// next returns the first time matching the schedule strictly after the given time,
+// or the zero time if none is found within the search limit.
+func (c *cronSchedule) next(after time.Time) time.Time {
+	limit := after.Add(cronSearchLimit)
+	t := after.Truncate(time.Minute).Add(time.Minute)
+	for t.Before(limit) {
+		switch {
+		case !c.months[t.Month()]:
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, t.Location())
+		case !c.matchesDay(t):
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, t.Location())
+		case !c.hours[t.Hour()]:
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, t.Location())
+		case !c.minutes[t.Minute()]:
+			t = t.Add(time.Minute)
+		default:
+			return t
+		}
+	}
+	return time.Time{}
+}
+
+func (c *cronSchedule) matchesDay(t time.Time) bool {
+	dayOfMonth := c.daysOfMonth[t.Day()]
+	dayOfWeek := c.daysOfWeek[t.Weekday()]
+	switch {
+	case c.anyDayOfMonth && c.anyDayOfWeek:
+		return true
+	case c.anyDayOfMonth:
+		return dayOfWeek
+	case c.anyDayOfWeek:
+		return dayOfMonth
+	}
+	return dayOfMonth || dayOfWeek
+}
@@ -0,0 +1,65 @@
+package runner
+
+import (
+	"math/rand/v2"
+	"time"
+
+	"github.com/a-peyrard/godi/option"
+)
+
+type (
+	// Backoff computes the delay before a restart, the attempts start at 1.
+	Backoff func(attempt int) time.Duration
+
+	// RestartHook is notified before each restart, with the failure causing it and the delay before the restart.
+	RestartHook func(runnable Runnable, attempt int, err error, delay time.Duration)
+)
+
+// Supervised wraps the runnable so it is restarted when it fails, see RestartOnError. It is a Supervisor of the
+// runnable alone, so it takes the same options, and without any option, the runnable is restarted up to 3 times
+// in a row (see WithStablePeriod), with an exponential backoff starting at 100ms.
+//
+// The supervised runnable is stopped like the wrapped one (see RunAllWithOptions), and it is not restarted anymore
+// once stopped.
+func Supervised(runnable Runnable, opts ...option.Option[SupervisorOptions]) *Supervisor {
+	return NewSupervisor(nil, []Runnable{runnable}, opts...)
+}
+
+// RestartOnError restarts the runnable up to maxRetries times in a row (see WithStablePeriod), waiting for the backoff
+// before each restart.
+func RestartOnError(maxRetries int, backoff Backoff) option.Option[SupervisorOptions] {
+	return func(opts *SupervisorOptions) {
+		opts.maxRestarts = maxRetries
+		opts.backoff = backoff
+	}
+}
+
+// OnRestart registers a hook notified before each restart, e.g. to log or count the failures.
+func OnRestart(hook RestartHook) option.Option[SupervisorOptions] {
+	return func(opts *SupervisorOptions) {
+		opts.hooks = append(opts.hooks, hook)
+	}
+}
+
+// ExponentialBackoff doubles the delay after each attempt, from initial up to maxDelay,
+// the delay is randomized between half and the full computed delay to avoid synchronized restarts.
+func ExponentialBackoff(initial time.Duration, maxDelay time.Duration) Backoff {
+	return func(attempt int) time.Duration {
+		delay := initial
+		for i := 1; i < attempt && delay < maxDelay; i++ {
+			delay *= 2
+		}
+		delay = min(delay, maxDelay)
+		if delay < 2 {
+			return delay
+		}
+		return delay/2 + rand.N(delay/2)
+	}
+}
+
+// ConstantBackoff waits the same delay before each restart.
+func ConstantBackoff(delay time.Duration) Backoff {
+	return func(int) time.Duration {
+		return delay
+	}
+}
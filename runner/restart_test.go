@@ -0,0 +1,123 @@
+package runner
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSupervised(t *testing.T) {
+	t.Run("it should restart the runnable until it succeeds", func(t *testing.T) {
+		// GIVEN
+		var (
+			runs     int
+			restarts []int
+		)
+		runnable := RunnableFunc(func(ctx context.Context) error {
+			runs++
+			if runs < 3 {
+				return errors.New("crashed")
+			}
+			return nil
+		})
+		supervised := Supervised(
+			runnable,
+			RestartOnError(5, ConstantBackoff(time.Millisecond)),
+			OnRestart(func(_ Runnable, attempt int, err error, _ time.Duration) {
+				restarts = append(restarts, attempt)
+			}),
+		)
+
+		// WHEN
+		err := supervised.Run(context.Background())
+
+		// THEN
+		require.NoError(t, err)
+		assert.Equal(t, 3, runs)
+		assert.Equal(t, []int{1, 2}, restarts)
+	})
+
+	t.Run("it should give up after the max retries", func(t *testing.T) {
+		// GIVEN
+		var runs int
+		supervised := Supervised(
+			RunnableFunc(func(ctx context.Context) error {
+				runs++
+				return errors.New("crashed")
+			}),
+			RestartOnError(2, ConstantBackoff(time.Millisecond)),
+		)
+
+		// WHEN
+		err := supervised.Run(context.Background())
+
+		// THEN
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "gave up after 2 restarts")
+		assert.Contains(t, err.Error(), "crashed")
+		assert.Equal(t, 3, runs)
+	})
+
+	t.Run("it should stop waiting for the restart when the context is canceled", func(t *testing.T) {
+		// GIVEN
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+		supervised := Supervised(
+			RunnableFunc(func(ctx context.Context) error {
+				return errors.New("crashed")
+			}),
+			RestartOnError(5, ConstantBackoff(time.Hour)),
+		)
+
+		// WHEN
+		err := supervised.Run(ctx)
+
+		// THEN
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+	})
+}
+
+func TestSupervised_Stop(t *testing.T) {
+	t.Run("it should not restart the runnable once stopped", func(t *testing.T) {
+		// GIVEN
+		stop := make(chan struct{})
+		runs := 0
+		supervised := Supervised(
+			RunnableFunc(func(ctx context.Context) error {
+				runs++
+				<-stop
+				return errors.New("stopped")
+			}),
+			RestartOnError(5, ConstantBackoff(time.Millisecond)),
+		)
+		done := make(chan error, 1)
+		go func() { done <- supervised.Run(context.Background()) }()
+
+		// WHEN
+		err := supervised.Stop(context.Background())
+		close(stop)
+
+		// THEN
+		require.NoError(t, err)
+		assert.EqualError(t, <-done, "stopped")
+		assert.Equal(t, 1, runs)
+	})
+}
+
+func TestExponentialBackoff(t *testing.T) {
+	t.Run("it should double the delay up to the max, with jitter", func(t *testing.T) {
+		// GIVEN
+		backoff := ExponentialBackoff(100*time.Millisecond, time.Second)
+
+		// WHEN / THEN
+		for attempt, expected := range map[int]time.Duration{1: 100 * time.Millisecond, 2: 200 * time.Millisecond, 4: 800 * time.Millisecond, 10: time.Second} {
+			delay := backoff(attempt)
+			assert.GreaterOrEqual(t, delay, expected/2, "attempt %d", attempt)
+			assert.LessOrEqual(t, delay, expected, "attempt %d", attempt)
+		}
+	})
+}
@@ -4,10 +4,12 @@ import (
 	"context"
 	"fmt"
 	"github.com/a-peyrard/godi"
+	"github.com/a-peyrard/godi/option"
 	"github.com/rs/zerolog"
 	"golang.org/x/sync/errgroup"
 	"os"
 	"os/signal"
+	"sort"
 	"syscall"
 )
 
@@ -19,6 +21,18 @@ type (
 
 	// RunnableFunc is a helper to create Runnable from a function.
 	RunnableFunc func(ctx context.Context) error
+
+	// Starter represents a component which must be started before the runnables are launched,
+	// e.g. to run migrations or to open a listener.
+	Starter interface {
+		Start(ctx context.Context) error
+	}
+
+	// Ordered gives the startup phase of a Starter, the phases are started in ascending order,
+	// and the starters not implementing Ordered are in the phase 0.
+	Ordered interface {
+		Order() int
+	}
 )
 
 func (f RunnableFunc) Run(ctx context.Context) error {
@@ -26,7 +40,7 @@ func (f RunnableFunc) Run(ctx context.Context) error {
 }
 
 // Run starts all runnables registered in the resolver with proper context handling
-func Run(resolver *godi.Resolver) error {
+func Run(resolver *godi.Resolver, opts ...option.Option[Options]) error {
 	ctx, found, err := godi.TryResolve[context.Context](resolver)
 	if err != nil {
 		return fmt.Errorf("failed to resolve context: %w", err)
@@ -35,31 +49,80 @@ func Run(resolver *godi.Resolver) error {
 		ctx = context.Background()
 	}
 
-	runnables, err := godi.ResolveAll[Runnable](resolver)
+	runnables, err := start(ctx, resolver, buildOptions(opts...))
 	if err != nil {
-		return fmt.Errorf("failed to resolve runnables: %w", err)
+		return err
 	}
 	if len(runnables) == 0 {
 		return nil // nothing to run
 	}
 
-	return RunAll(ctx, runnables...)
+	return RunAllWithOptions(ctx, runnables, opts...)
+}
+
+// start resolves the runnables and the starters, and starts the starters.
+func start(ctx context.Context, resolver *godi.Resolver, options *Options) (runnables []Runnable, err error) {
+	ctx, end := options.phaseHook(ctx, PhaseStartup)
+	defer func() { end(err) }()
+
+	starters, err := godi.ResolveAllCtx[Starter](ctx, resolver)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve starters: %w", err)
+	}
+	runnables, err = godi.ResolveAllCtx[Runnable](ctx, resolver)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve runnables: %w", err)
+	}
+
+	// the runnables are only launched once all the starters succeeded
+	if err = StartAll(ctx, starters...); err != nil {
+		return nil, err
+	}
+	return runnables, nil
+}
+
+// StartAll starts the starters phase by phase (see Ordered), the starters of a phase are started concurrently,
+// and the next phase is only started once they all succeeded.
+func StartAll(ctx context.Context, starters ...Starter) error {
+	phases := make(map[int][]Starter)
+	for _, starter := range starters {
+		order := 0
+		if ordered, ok := starter.(Ordered); ok {
+			order = ordered.Order()
+		}
+		phases[order] = append(phases[order], starter)
+	}
+	orders := make([]int, 0, len(phases))
+	for order := range phases {
+		orders = append(orders, order)
+	}
+	sort.Ints(orders)
+
+	for _, order := range orders {
+		// the context is given as is, as the starters might keep it for the lifetime of what they started
+		var group errgroup.Group
+		for _, starter := range phases[order] {
+			group.Go(func() error {
+				if err := starter.Start(ctx); err != nil {
+					return fmt.Errorf("failed to start %T (phase %d): %w", starter, order, err)
+				}
+				return nil
+			})
+		}
+		if err := group.Wait(); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
 // RunAll runs all the provided runnables concurrently and waits for all of them to finish.
 //
 // This method is blocking and will return an error if any of the runnables returns an error.
+// Runnables declaring children (see Parent) are supervised along with their children.
+// See RunAllWithOptions to configure the failure policy and the shutdown.
 func RunAll(parentCtx context.Context, runnables ...Runnable) error {
-	group, ctx := errgroup.WithContext(parentCtx)
-
-	for _, runnable := range runnables {
-		innerRunnable := runnable // capture loop variable
-		group.Go(func() error {
-			return innerRunnable.Run(ctx)
-		})
-	}
-
-	return group.Wait()
+	return RunAllWithOptions(parentCtx, runnables)
 }
 
 // WithSyscallKillableContext wraps a context, and return a new context that can be canceled by system signals (SIGINT, SIGTERM, SIGKILL).
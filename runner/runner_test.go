@@ -3,11 +3,14 @@ package runner
 import (
 	"context"
 	"errors"
+	"sync"
 	"sync/atomic"
 	"testing"
 	"time"
 
+	"github.com/a-peyrard/godi"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 // mockRunnable is a test implementation of Runnable
@@ -118,3 +121,74 @@ func TestRunAll(t *testing.T) {
 		assert.Less(t, elapsed, 100*time.Millisecond, "Runnables should run concurrently")
 	})
 }
+
+type recordingStarter struct {
+	name    string
+	order   int
+	started *[]string
+	lock    *sync.Mutex
+	err     error
+}
+
+func (s recordingStarter) Start(_ context.Context) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	*s.started = append(*s.started, s.name)
+	return s.err
+}
+
+func (s recordingStarter) Order() int {
+	return s.order
+}
+
+func TestRun(t *testing.T) {
+	t.Run("it should start the starters phase by phase before launching the runnables", func(t *testing.T) {
+		// GIVEN
+		var (
+			started []string
+			lock    sync.Mutex
+		)
+		resolver := godi.New()
+		resolver.MustRegister(godi.ToStaticProvider[Starter](recordingStarter{name: "server", order: 10, started: &started, lock: &lock}), godi.Named("server"))
+		resolver.MustRegister(godi.ToStaticProvider[Starter](recordingStarter{name: "migrations", order: -10, started: &started, lock: &lock}), godi.Named("migrations"))
+		resolver.MustRegister(godi.ToStaticProvider[Starter](recordingStarter{name: "cache", started: &started, lock: &lock}), godi.Named("cache"))
+		resolver.MustRegister(godi.ToStaticProvider[Runnable](RunnableFunc(func(ctx context.Context) error {
+			lock.Lock()
+			defer lock.Unlock()
+			started = append(started, "runnable")
+			return nil
+		})))
+
+		// WHEN
+		err := Run(resolver)
+
+		// THEN
+		require.NoError(t, err)
+		assert.Equal(t, []string{"migrations", "cache", "server", "runnable"}, started)
+	})
+
+	t.Run("it should not launch the runnables if a starter fails", func(t *testing.T) {
+		// GIVEN
+		var (
+			started []string
+			lock    sync.Mutex
+		)
+		resolver := godi.New()
+		resolver.MustRegister(godi.ToStaticProvider[Starter](recordingStarter{name: "migrations", started: &started, lock: &lock, err: errors.New("database unreachable")}), godi.Named("migrations"))
+		resolver.MustRegister(godi.ToStaticProvider[Starter](recordingStarter{name: "server", order: 1, started: &started, lock: &lock}), godi.Named("server"))
+		resolver.MustRegister(godi.ToStaticProvider[Runnable](RunnableFunc(func(ctx context.Context) error {
+			lock.Lock()
+			defer lock.Unlock()
+			started = append(started, "runnable")
+			return nil
+		})))
+
+		// WHEN
+		err := Run(resolver)
+
+		// THEN
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "database unreachable")
+		assert.Equal(t, []string{"migrations"}, started)
+	})
+}
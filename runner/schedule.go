@@ -0,0 +1,115 @@
+package runner
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/a-peyrard/godi/option"
+)
+
+type (
+	// Job is a function run on a schedule, see Every and Cron.
+	Job func(ctx context.Context) error
+
+	// ScheduleOptions configures a scheduled runnable.
+	ScheduleOptions struct {
+		skipOverlapping bool
+	}
+
+	// scheduledRunnable runs a job each time the schedule fires.
+	scheduledRunnable struct {
+		next    func(after time.Time) time.Time
+		job     Job
+		options *ScheduleOptions
+		err     error
+	}
+)
+
+// SkipOverlapping skips a run if the previous one is still running, by default the runs can overlap.
+func SkipOverlapping() option.Option[ScheduleOptions] {
+	return func(opts *ScheduleOptions) {
+		opts.skipOverlapping = true
+	}
+}
+
+// Every creates a runnable running the job every interval, the first run being one interval after the start.
+//
+// The runnable runs until its context is done, or until the job fails, the running jobs are awaited in both cases.
+func Every(interval time.Duration, job Job, opts ...option.Option[ScheduleOptions]) Runnable {
+	return &scheduledRunnable{
+		next: func(after time.Time) time.Time {
+			return after.Add(interval)
+		},
+		job:     job,
+		options: option.Build(&ScheduleOptions{}, opts...),
+	}
+}
+
+// Cron creates a runnable running the job on the schedule of a standard cron expression, e.g. "*/5 * * * *",
+// in the local time zone. The runnable fails at once if the expression is invalid.
+//
+// The runnable runs until its context is done, or until the job fails, the running jobs are awaited in both cases.
+func Cron(expr string, job Job, opts ...option.Option[ScheduleOptions]) Runnable {
+	runnable := &scheduledRunnable{
+		job:     job,
+		options: option.Build(&ScheduleOptions{}, opts...),
+	}
+	schedule, err := parseCron(expr)
+	if err != nil {
+		runnable.err = err
+	} else {
+		runnable.next = schedule.next
+	}
+	return runnable
+}
+
+func (s *scheduledRunnable) Run(parentCtx context.Context) error {
+	if s.err != nil {
+		return s.err
+	}
+
+	ctx, cancel := context.WithCancelCause(parentCtx)
+	defer cancel(nil)
+
+	var (
+		wg      sync.WaitGroup
+		running atomic.Int32
+	)
+	defer wg.Wait()
+
+	next := s.next(time.Now())
+	for {
+		if next.IsZero() {
+			return errors.New("the schedule never fires")
+		}
+		timer := time.NewTimer(time.Until(next))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			wg.Wait()
+			return context.Cause(ctx)
+		case <-timer.C:
+		}
+
+		if !s.options.skipOverlapping || running.Load() == 0 {
+			running.Add(1)
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				defer running.Add(-1)
+				if err := s.job(ctx); err != nil {
+					cancel(err)
+				}
+			}()
+		}
+
+		// a late schedule does not try to catch up with the missed runs
+		next = s.next(next)
+		if now := time.Now(); next.Before(now) {
+			next = s.next(now)
+		}
+	}
+}
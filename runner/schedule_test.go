@@ -0,0 +1,127 @@
+package runner
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEvery(t *testing.T) {
+	t.Run("it should run the job every interval until the context is canceled", func(t *testing.T) {
+		// GIVEN
+		var runs atomic.Int32
+		ctx, cancel := context.WithTimeout(context.Background(), 55*time.Millisecond)
+		defer cancel()
+		runnable := Every(10*time.Millisecond, func(ctx context.Context) error {
+			runs.Add(1)
+			return nil
+		})
+
+		// WHEN
+		err := runnable.Run(ctx)
+
+		// THEN
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+		assert.InDelta(t, 5, runs.Load(), 2)
+	})
+
+	t.Run("it should stop when the job fails", func(t *testing.T) {
+		// GIVEN
+		runnable := Every(time.Millisecond, func(ctx context.Context) error {
+			return errors.New("job failed")
+		})
+
+		// WHEN
+		err := runnable.Run(context.Background())
+
+		// THEN
+		require.Error(t, err)
+		assert.Equal(t, "job failed", err.Error())
+	})
+
+	t.Run("it should skip the overlapping runs", func(t *testing.T) {
+		// GIVEN
+		var (
+			runs       atomic.Int32
+			concurrent atomic.Int32
+			overlapped atomic.Bool
+		)
+		ctx, cancel := context.WithTimeout(context.Background(), 60*time.Millisecond)
+		defer cancel()
+		runnable := Every(5*time.Millisecond, func(ctx context.Context) error {
+			runs.Add(1)
+			if concurrent.Add(1) > 1 {
+				overlapped.Store(true)
+			}
+			defer concurrent.Add(-1)
+			time.Sleep(20 * time.Millisecond)
+			return nil
+		}, SkipOverlapping())
+
+		// WHEN
+		_ = runnable.Run(ctx)
+
+		// THEN
+		assert.False(t, overlapped.Load())
+		assert.Less(t, runs.Load(), int32(6))
+	})
+}
+
+func TestCron(t *testing.T) {
+	t.Run("it should fail at once with an invalid expression", func(t *testing.T) {
+		// WHEN
+		err := Cron("*/5 * * *", func(ctx context.Context) error { return nil }).Run(context.Background())
+
+		// THEN
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "expected 5 fields")
+	})
+
+	t.Run("it should compute the next runs of the expression", func(t *testing.T) {
+		// GIVEN
+		from := time.Date(2024, time.January, 31, 23, 58, 30, 0, time.UTC) // a wednesday
+
+		for expr, expected := range map[string]time.Time{
+			"*/5 * * * *":    time.Date(2024, time.February, 1, 0, 0, 0, 0, time.UTC),
+			"30 9 * * 1-5":   time.Date(2024, time.February, 1, 9, 30, 0, 0, time.UTC),
+			"0 0 * * 0":      time.Date(2024, time.February, 4, 0, 0, 0, 0, time.UTC),
+			"0 12 29 2 *":    time.Date(2024, time.February, 29, 12, 0, 0, 0, time.UTC),
+			"0 0 15 * 6":     time.Date(2024, time.February, 3, 0, 0, 0, 0, time.UTC),
+			"0,59 23 * * *":  time.Date(2024, time.January, 31, 23, 59, 0, 0, time.UTC),
+			"0 8-18/5 * 3 *": time.Date(2024, time.March, 1, 8, 0, 0, 0, time.UTC),
+		} {
+			// WHEN
+			schedule, err := parseCron(expr)
+
+			// THEN
+			require.NoError(t, err, expr)
+			assert.Equal(t, expected, schedule.next(from), expr)
+		}
+	})
+
+	t.Run("it should reject out of range values", func(t *testing.T) {
+		// WHEN
+		_, err := parseCron("60 * * * *")
+
+		// THEN
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "out of the range 0-59")
+	})
+
+	t.Run("it should not find a next run for expressions never matching", func(t *testing.T) {
+		// GIVEN
+		schedule, err := parseCron("0 0 31 2 *")
+		require.NoError(t, err)
+
+		// WHEN
+		next := schedule.next(time.Now())
+
+		// THEN
+		assert.True(t, next.IsZero())
+	})
+}
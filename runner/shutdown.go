@@ -0,0 +1,190 @@
+package runner
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/a-peyrard/godi"
+	"github.com/a-peyrard/godi/option"
+)
+
+type (
+	// FailurePolicy defines how the runner reacts when one of the runnables fails.
+	FailurePolicy int
+
+	// Options configures how the runnables are run and shut down.
+	Options struct {
+		failurePolicy   FailurePolicy
+		shutdownTimeout time.Duration
+		phaseHook       PhaseHook
+	}
+
+	// Phase is a phase of the runner, see PhaseHook.
+	Phase string
+
+	// PhaseHook is called when a phase of the runner begins, e.g. to trace the startup and the shutdown.
+	// It returns the context of the phase, and a function called with the error of the phase when it ends.
+	PhaseHook func(ctx context.Context, phase Phase) (context.Context, func(err error))
+)
+
+const (
+	// FailFast shuts down all the runnables as soon as one of them fails, this is the default.
+	FailFast FailurePolicy = iota
+	// ContinueOnError keeps the other runnables running when one of them fails,
+	// all the failures are reported once every runnable returned.
+	ContinueOnError
+)
+
+const (
+	// PhaseStartup covers the resolution of the runnables and the starters, and the start of the starters.
+	PhaseStartup Phase = "startup"
+	// PhaseShutdown covers the drain of the runnables, see RunAllWithOptions.
+	PhaseShutdown Phase = "shutdown"
+)
+
+// WithPhaseHook sets the hook called when a phase of the runner begins, see PhaseHook.
+func WithPhaseHook(hook PhaseHook) option.Option[Options] {
+	return func(opts *Options) {
+		opts.phaseHook = hook
+	}
+}
+
+// WithFailurePolicy sets how the runner reacts when one of the runnables fails, default is FailFast.
+func WithFailurePolicy(policy FailurePolicy) option.Option[Options] {
+	return func(opts *Options) {
+		opts.failurePolicy = policy
+	}
+}
+
+// WithShutdownTimeout bounds the steps of the shutdown: each call to a Stop method, through the deadline of its
+// context, then, once the context of the runnables is canceled, the wait for all the Run methods to return.
+// As the runnables are stopped one after the other, the shutdown of N runnables lasts up to (N+1) times the timeout,
+// the Close methods, which do not take a context, are not bounded. By default, the runner waits as long as needed.
+func WithShutdownTimeout(timeout time.Duration) option.Option[Options] {
+	return func(opts *Options) {
+		opts.shutdownTimeout = timeout
+	}
+}
+
+// RunAllWithOptions runs all the provided runnables concurrently and waits for all of them to finish.
+//
+// On shutdown, i.e. when the context is done or a runnable fails (see FailFast), the runnables still running
+// are drained in the reverse order: the ones implementing godi.Stoppable or godi.Closeable are stopped one after
+// the other, then the context shared by the runnables is canceled.
+func RunAllWithOptions(parentCtx context.Context, runnables []Runnable, opts ...option.Option[Options]) error {
+	options := buildOptions(opts...)
+
+	// the runnables get their own context, only canceled once they are drained
+	ctx, cancel := context.WithCancel(context.WithoutCancel(parentCtx))
+	defer cancel()
+
+	type result struct {
+		idx int
+		err error
+	}
+	results := make(chan result, len(runnables))
+	// the parents are run and stopped through their supervisor, so it does not restart them once stopped
+	supervised := make([]Runnable, len(runnables))
+	for idx, runnable := range runnables {
+		supervised[idx] = supervise(runnable)
+		go func() {
+			results <- result{idx: idx, err: supervised[idx].Run(ctx)}
+		}()
+	}
+
+	var (
+		errs     []error
+		returned = make([]bool, len(runnables))
+		running  = len(runnables)
+		shutdown = false
+	)
+	collect := func(res result) {
+		running--
+		returned[res.idx] = true
+		if res.err != nil {
+			errs = append(errs, res.err)
+		}
+	}
+	for running > 0 && !shutdown {
+		select {
+		case <-parentCtx.Done():
+			shutdown = true
+		case res := <-results:
+			collect(res)
+			shutdown = res.err != nil && options.failurePolicy == FailFast
+		}
+	}
+
+	if running > 0 {
+		shutdownCtx, end := options.phaseHook(parentCtx, PhaseShutdown)
+		shutdownErrs := len(errs)
+		for idx := len(runnables) - 1; idx >= 0; idx-- {
+			if !returned[idx] {
+				if err := stopRunnable(shutdownCtx, supervised[idx], options.shutdownTimeout); err != nil {
+					errs = append(errs, err)
+				}
+			}
+		}
+		cancel()
+
+		var timeout <-chan time.Time
+		if options.shutdownTimeout > 0 {
+			timer := time.NewTimer(options.shutdownTimeout)
+			defer timer.Stop()
+			timeout = timer.C
+		}
+		for running > 0 {
+			select {
+			case res := <-results:
+				collect(res)
+			case <-timeout:
+				errs = append(errs, fmt.Errorf("%d runnables did not stop within %s", running, options.shutdownTimeout))
+				running = 0
+			}
+		}
+		end(errors.Join(errs[shutdownErrs:]...))
+	}
+
+	if len(errs) == 0 {
+		return nil
+	}
+	if options.failurePolicy == FailFast {
+		return errs[0]
+	}
+	return errors.Join(errs...)
+}
+
+func buildOptions(opts ...option.Option[Options]) *Options {
+	return option.Build(
+		&Options{
+			phaseHook: func(ctx context.Context, _ Phase) (context.Context, func(error)) {
+				return ctx, func(error) {}
+			},
+		},
+		opts...,
+	)
+}
+
+// stopRunnable stops the runnable if it is godi.Stoppable or godi.Closeable.
+func stopRunnable(parentCtx context.Context, runnable Runnable, timeout time.Duration) error {
+	ctx := context.WithoutCancel(parentCtx)
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	var err error
+	switch stoppable := runnable.(type) {
+	case godi.Stoppable:
+		err = stoppable.Stop(ctx)
+	case godi.Closeable:
+		err = stoppable.Close()
+	}
+	if err != nil {
+		return fmt.Errorf("failed to stop %T: %w", runnable, err)
+	}
+	return nil
+}
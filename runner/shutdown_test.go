@@ -0,0 +1,171 @@
+package runner
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/a-peyrard/godi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// stoppableRunnable runs until it is stopped, or until its context is canceled.
+type stoppableRunnable struct {
+	name     string
+	stopped  chan struct{}
+	events   *[]string
+	lock     *sync.Mutex
+	stopOnce sync.Once
+}
+
+func newStoppableRunnable(name string, events *[]string, lock *sync.Mutex) *stoppableRunnable {
+	return &stoppableRunnable{name: name, stopped: make(chan struct{}), events: events, lock: lock}
+}
+
+func (s *stoppableRunnable) Run(ctx context.Context) error {
+	select {
+	case <-s.stopped:
+		s.record("returned " + s.name)
+		return nil
+	case <-ctx.Done():
+		s.record("canceled " + s.name)
+		return ctx.Err()
+	}
+}
+
+func (s *stoppableRunnable) Stop(_ context.Context) error {
+	s.record("stopped " + s.name)
+	s.stopOnce.Do(func() { close(s.stopped) })
+	return nil
+}
+
+func (s *stoppableRunnable) record(event string) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	*s.events = append(*s.events, event)
+}
+
+func TestRunAllWithOptions(t *testing.T) {
+	t.Run("it should stop the runnables in the reverse order before canceling the context", func(t *testing.T) {
+		// GIVEN
+		var (
+			events []string
+			lock   sync.Mutex
+		)
+		ctx, cancel := context.WithCancel(context.Background())
+		first := newStoppableRunnable("first", &events, &lock)
+		second := newStoppableRunnable("second", &events, &lock)
+
+		// WHEN
+		go func() {
+			time.Sleep(20 * time.Millisecond)
+			cancel()
+		}()
+		err := RunAllWithOptions(ctx, []Runnable{first, second})
+
+		// THEN
+		require.NoError(t, err)
+		var stops []string
+		for _, event := range events {
+			if strings.HasPrefix(event, "stopped") {
+				stops = append(stops, event)
+			}
+		}
+		assert.Equal(t, []string{"stopped second", "stopped first"}, stops)
+		assert.ElementsMatch(t, []string{"stopped second", "stopped first", "returned second", "returned first"}, events)
+	})
+
+	t.Run("it should keep running the other runnables when one fails with ContinueOnError", func(t *testing.T) {
+		// GIVEN
+		var counter int32
+		failing := &mockRunnable{err: errors.New("something went wrong")}
+		slow := &mockRunnable{counter: &counter, value: 1, delay: 50 * time.Millisecond}
+
+		// WHEN
+		err := RunAllWithOptions(context.Background(), []Runnable{failing, slow}, WithFailurePolicy(ContinueOnError))
+
+		// THEN
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "something went wrong")
+		assert.NotContains(t, err.Error(), "context canceled", "the slow runnable should have completed")
+	})
+
+	t.Run("it should cancel the other runnables when one fails with FailFast", func(t *testing.T) {
+		// GIVEN
+		failing := &mockRunnable{err: errors.New("something went wrong")}
+		slow := &mockRunnable{delay: time.Second}
+
+		// WHEN
+		start := time.Now()
+		err := RunAllWithOptions(context.Background(), []Runnable{failing, slow})
+
+		// THEN
+		require.Error(t, err)
+		assert.Equal(t, "something went wrong", err.Error())
+		assert.Less(t, time.Since(start), 500*time.Millisecond)
+	})
+
+	t.Run("it should give up on the runnables not stopping within the shutdown timeout", func(t *testing.T) {
+		// GIVEN
+		ctx, cancel := context.WithCancel(context.Background())
+		stubborn := RunnableFunc(func(ctx context.Context) error {
+			time.Sleep(time.Second)
+			return nil
+		})
+
+		// WHEN
+		go func() {
+			time.Sleep(10 * time.Millisecond)
+			cancel()
+		}()
+		start := time.Now()
+		err := RunAllWithOptions(ctx, []Runnable{stubborn}, WithShutdownTimeout(20*time.Millisecond))
+
+		// THEN
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "1 runnables did not stop within 20ms")
+		assert.Less(t, time.Since(start), 500*time.Millisecond)
+	})
+}
+
+func TestWithPhaseHook(t *testing.T) {
+	t.Run("it should notify the startup and the shutdown phases", func(t *testing.T) {
+		// GIVEN
+		var (
+			events []string
+			lock   sync.Mutex
+		)
+		hook := func(ctx context.Context, phase Phase) (context.Context, func(error)) {
+			lock.Lock()
+			defer lock.Unlock()
+			events = append(events, "begin "+string(phase))
+			return ctx, func(err error) {
+				lock.Lock()
+				defer lock.Unlock()
+				events = append(events, fmt.Sprintf("end %s (%v)", phase, err))
+			}
+		}
+		ctx, cancel := context.WithCancel(context.Background())
+		resolver := godi.New()
+		resolver.MustRegister(godi.ToStaticProvider(ctx))
+		resolver.MustRegister(func() Runnable {
+			return RunnableFunc(func(ctx context.Context) error {
+				cancel()
+				<-ctx.Done()
+				return nil
+			})
+		})
+
+		// WHEN
+		err := Run(resolver, WithPhaseHook(hook))
+
+		// THEN
+		require.NoError(t, err)
+		assert.Equal(t, []string{"begin startup", "end startup (<nil>)", "begin shutdown", "end shutdown (<nil>)"}, events)
+	})
+}
@@ -0,0 +1,296 @@
+package runner
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/a-peyrard/godi"
+	"github.com/a-peyrard/godi/option"
+)
+
+type (
+	// Escalation defines how a supervisor reacts when one of its children fails.
+	Escalation int
+
+	// Parent is implemented by runnables declaring child runnables.
+	//
+	// The runner supervises the parent and its children as a tree, Erlang supervisor style:
+	// when a child fails, the escalation policy of the parent decides what to do.
+	// If the parent itself fails, the failure is escalated to the supervisor of the parent (if any).
+	Parent interface {
+		Runnable
+		Children() []Runnable
+		Escalation() Escalation
+	}
+
+	// SupervisedParent is implemented by the parents configuring their supervisor beyond the escalation policy,
+	// e.g. WithMaxRestarts or WithBackoff, the options being applied after the escalation policy.
+	SupervisedParent interface {
+		Parent
+		Supervision() []option.Option[SupervisorOptions]
+	}
+
+	// Supervisor is a Runnable running a parent runnable along with its children,
+	// applying an escalation policy when a child fails.
+	Supervisor struct {
+		parent   Runnable
+		children []Runnable
+		options  *SupervisorOptions
+
+		mu       sync.Mutex
+		restarts int
+		stopped  atomic.Bool
+	}
+
+	SupervisorOptions struct {
+		escalation   Escalation
+		maxRestarts  int
+		stablePeriod time.Duration
+		backoff      Backoff
+		hooks        []RestartHook
+	}
+
+	// subtreeRestart is returned by a child failing in a subtree restarted on failure, see RestartSubtree.
+	subtreeRestart struct {
+		child Runnable
+		err   error
+	}
+)
+
+const (
+	// RestartChild restarts only the failing child.
+	RestartChild Escalation = iota
+	// RestartSubtree restarts the parent and all its children.
+	RestartSubtree
+	// GiveUp stops the parent and all its children, and escalates the failure.
+	GiveUp
+)
+
+const (
+	defaultMaxRestarts  = 3
+	defaultStablePeriod = time.Minute
+)
+
+var errMaxRestarts = errors.New("max restarts reached")
+
+// WithEscalation sets the escalation policy of the supervisor, default is RestartChild.
+func WithEscalation(escalation Escalation) option.Option[SupervisorOptions] {
+	return func(opts *SupervisorOptions) {
+		opts.escalation = escalation
+	}
+}
+
+// WithMaxRestarts sets the number of restarts allowed before giving up, default is 3. The restarts are counted
+// again from zero once a runnable ran for the stable period before failing, see WithStablePeriod.
+func WithMaxRestarts(maxRestarts int) option.Option[SupervisorOptions] {
+	return func(opts *SupervisorOptions) {
+		opts.maxRestarts = maxRestarts
+	}
+}
+
+// WithStablePeriod sets how long a runnable must run before failing for its failure to be considered isolated,
+// default is 1 minute: the restarts and the attempts of the backoff are then counted again from zero, so a service
+// failing once in a while is always restarted.
+func WithStablePeriod(period time.Duration) option.Option[SupervisorOptions] {
+	return func(opts *SupervisorOptions) {
+		opts.stablePeriod = period
+	}
+}
+
+// WithBackoff sets the delay before each restart, default is an exponential backoff from 100ms up to 30s
+// (see ExponentialBackoff). The attempts are counted over all the restarts of the supervisor, since the last
+// failure after a stable run, see WithStablePeriod.
+func WithBackoff(backoff Backoff) option.Option[SupervisorOptions] {
+	return func(opts *SupervisorOptions) {
+		opts.backoff = backoff
+	}
+}
+
+// NewSupervisor creates a supervisor for the given parent (which can be nil) and children.
+//
+// Children implementing Parent are supervised as subtrees, by nested supervisors. The supervisor is stopped like
+// its parent and children (see RunAllWithOptions), the nested supervisors along with it, and it does not restart
+// them anymore once stopped.
+func NewSupervisor(parent Runnable, children []Runnable, opts ...option.Option[SupervisorOptions]) *Supervisor {
+	supervised := make([]Runnable, len(children))
+	for i, child := range children {
+		supervised[i] = supervise(child)
+	}
+	return &Supervisor{
+		parent:   parent,
+		children: supervised,
+		options: option.Build(
+			&SupervisorOptions{
+				escalation:   RestartChild,
+				maxRestarts:  defaultMaxRestarts,
+				stablePeriod: defaultStablePeriod,
+				backoff:      ExponentialBackoff(100*time.Millisecond, 30*time.Second),
+			},
+			opts...,
+		),
+	}
+}
+
+// supervise wraps the runnable in a supervisor if it declares children.
+func supervise(runnable Runnable) Runnable {
+	parent, ok := runnable.(Parent)
+	if !ok {
+		return runnable
+	}
+	opts := []option.Option[SupervisorOptions]{WithEscalation(parent.Escalation())}
+	if supervised, ok := parent.(SupervisedParent); ok {
+		opts = append(opts, supervised.Supervision()...)
+	}
+	return NewSupervisor(parent, parent.Children(), opts...)
+}
+
+// Run runs the parent and the children until they all return, restarting them according to the escalation policy.
+// A supervisor run again, e.g. restarted by its own supervisor, counts its restarts from zero.
+func (s *Supervisor) Run(ctx context.Context) error {
+	s.mu.Lock()
+	s.restarts = 0
+	s.mu.Unlock()
+	for {
+		started := time.Now()
+		err := s.runOnce(ctx)
+		var restart *subtreeRestart
+		if !errors.As(err, &restart) {
+			return err
+		}
+		if s.stopped.Load() {
+			return restart.err
+		}
+		if err := s.restart(ctx, restart.child, restart.err, time.Since(started)); errors.Is(err, errMaxRestarts) {
+			return fmt.Errorf("supervisor gave up after %d restarts of the subtree, last failure:\n\t%w", s.options.maxRestarts, restart.err)
+		} else if err != nil {
+			return err
+		}
+	}
+}
+
+// Stop stops the parent and the children, the ones implementing godi.Stoppable or godi.Closeable,
+// they are not restarted anymore.
+func (s *Supervisor) Stop(ctx context.Context) error {
+	s.stopped.Store(true)
+	var errs []error
+	for _, runnable := range append([]Runnable{s.parent}, s.children...) {
+		switch stoppable := runnable.(type) {
+		case godi.Stoppable:
+			errs = append(errs, stoppable.Stop(ctx))
+		case godi.Closeable:
+			errs = append(errs, stoppable.Close())
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (s *Supervisor) runOnce(parentCtx context.Context) error {
+	ctx, cancel := context.WithCancel(parentCtx)
+	defer cancel()
+
+	var (
+		wg       sync.WaitGroup
+		failOnce sync.Once
+		failure  error
+	)
+	fail := func(err error) {
+		failOnce.Do(func() {
+			failure = err
+			cancel()
+		})
+	}
+
+	if s.parent != nil {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := s.parent.Run(ctx); err != nil && ctx.Err() == nil {
+				fail(err)
+			}
+		}()
+	}
+	for _, child := range s.children {
+		wg.Add(1)
+		go func(child Runnable) {
+			defer wg.Done()
+			if err := s.runChild(ctx, child); err != nil {
+				fail(err)
+			}
+		}(child)
+	}
+	wg.Wait()
+
+	if failure != nil {
+		return failure
+	}
+	return parentCtx.Err()
+}
+
+// runChild runs the child until it succeeds, or until the escalation policy decides to stop it.
+func (s *Supervisor) runChild(ctx context.Context, child Runnable) error {
+	for {
+		started := time.Now()
+		err := child.Run(ctx)
+		if err == nil || ctx.Err() != nil {
+			return nil
+		}
+		if s.stopped.Load() {
+			return err
+		}
+
+		switch s.options.escalation {
+		case RestartChild:
+			if restartErr := s.restart(ctx, child, err, time.Since(started)); errors.Is(restartErr, errMaxRestarts) {
+				return fmt.Errorf("supervisor gave up after %d restarts, last failure:\n\t%w", s.options.maxRestarts, err)
+			} else if restartErr != nil {
+				return nil
+			}
+		case RestartSubtree:
+			return &subtreeRestart{child: child, err: err}
+		default:
+			return err
+		}
+	}
+}
+
+// restart counts a restart of the failed runnable, unless the max restarts is reached, then notifies the hooks
+// and waits for the backoff, or for the context to be done. The restarts are counted from zero again if the runnable
+// ran for the stable period before failing.
+func (s *Supervisor) restart(ctx context.Context, failed Runnable, err error, ranFor time.Duration) error {
+	s.mu.Lock()
+	if ranFor >= s.options.stablePeriod {
+		s.restarts = 0
+	}
+	if s.restarts >= s.options.maxRestarts {
+		s.mu.Unlock()
+		return errMaxRestarts
+	}
+	s.restarts++
+	attempt := s.restarts
+	s.mu.Unlock()
+
+	delay := s.options.backoff(attempt)
+	for _, hook := range s.options.hooks {
+		hook(failed, attempt, err, delay)
+	}
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+func (r *subtreeRestart) Error() string {
+	return fmt.Sprintf("subtree restart requested by the failure of %T: %v", r.child, r.err)
+}
+
+func (r *subtreeRestart) Unwrap() error {
+	return r.err
+}
@@ -0,0 +1,285 @@
+package runner
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/a-peyrard/godi/option"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// flakyRunnable fails the given number of times before succeeding
+type flakyRunnable struct {
+	runs     atomic.Int32
+	failures int32
+}
+
+func (f *flakyRunnable) Run(_ context.Context) error {
+	if f.runs.Add(1) <= f.failures {
+		return errors.New("flaky failure")
+	}
+	return nil
+}
+
+// blockingRunnable runs until its context is canceled
+type blockingRunnable struct {
+	runs atomic.Int32
+}
+
+func (b *blockingRunnable) Run(ctx context.Context) error {
+	b.runs.Add(1)
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+// crashingRunnable runs for a while before failing, the given number of times
+type crashingRunnable struct {
+	flakyRunnable
+	runFor time.Duration
+}
+
+func (c *crashingRunnable) Run(ctx context.Context) error {
+	time.Sleep(c.runFor)
+	return c.flakyRunnable.Run(ctx)
+}
+
+type parentRunnable struct {
+	flakyRunnable
+	children   []Runnable
+	escalation Escalation
+}
+
+func (p *parentRunnable) Children() []Runnable {
+	return p.children
+}
+
+func (p *parentRunnable) Escalation() Escalation {
+	return p.escalation
+}
+
+// supervisedParent configures its supervisor
+type supervisedParent struct {
+	parentRunnable
+	supervision []option.Option[SupervisorOptions]
+}
+
+func (p *supervisedParent) Supervision() []option.Option[SupervisorOptions] {
+	return p.supervision
+}
+
+func TestSupervisor(t *testing.T) {
+	t.Run("it should restart only the failing child", func(t *testing.T) {
+		// GIVEN
+		flaky := &flakyRunnable{failures: 2}
+		stable := &flakyRunnable{}
+		supervisor := NewSupervisor(nil, []Runnable{flaky, stable}, WithEscalation(RestartChild))
+
+		// WHEN
+		err := supervisor.Run(context.Background())
+
+		// THEN
+		require.NoError(t, err)
+		assert.Equal(t, int32(3), flaky.runs.Load())
+		assert.Equal(t, int32(1), stable.runs.Load())
+	})
+
+	t.Run("it should give up when the child keeps failing", func(t *testing.T) {
+		// GIVEN
+		flaky := &flakyRunnable{failures: 100}
+		supervisor := NewSupervisor(nil, []Runnable{flaky}, WithMaxRestarts(2))
+
+		// WHEN
+		err := supervisor.Run(context.Background())
+
+		// THEN
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "supervisor gave up after 2 restarts")
+		assert.Contains(t, err.Error(), "flaky failure")
+		assert.Equal(t, int32(3), flaky.runs.Load())
+	})
+
+	t.Run("it should wait for the backoff before each restart", func(t *testing.T) {
+		// GIVEN
+		flaky := &flakyRunnable{failures: 2}
+		var attempts []int
+		supervisor := NewSupervisor(nil, []Runnable{flaky}, WithBackoff(func(attempt int) time.Duration {
+			attempts = append(attempts, attempt)
+			return 20 * time.Millisecond
+		}))
+
+		// WHEN
+		start := time.Now()
+		err := supervisor.Run(context.Background())
+
+		// THEN
+		require.NoError(t, err)
+		assert.Equal(t, []int{1, 2}, attempts)
+		assert.GreaterOrEqual(t, time.Since(start), 40*time.Millisecond)
+	})
+
+	t.Run("it should count the restarts from zero again after a stable run", func(t *testing.T) {
+		// GIVEN
+		crashing := &crashingRunnable{flakyRunnable: flakyRunnable{failures: 4}, runFor: 20 * time.Millisecond}
+		var attempts []int
+		supervisor := NewSupervisor(
+			nil,
+			[]Runnable{crashing},
+			WithMaxRestarts(1),
+			WithStablePeriod(10*time.Millisecond),
+			WithBackoff(func(attempt int) time.Duration {
+				attempts = append(attempts, attempt)
+				return time.Millisecond
+			}),
+		)
+
+		// WHEN
+		err := supervisor.Run(context.Background())
+
+		// THEN
+		require.NoError(t, err)
+		assert.Equal(t, int32(5), crashing.runs.Load())
+		assert.Equal(t, []int{1, 1, 1, 1}, attempts)
+	})
+
+	t.Run("it should stop waiting for the restart when the context is canceled", func(t *testing.T) {
+		// GIVEN
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+		flaky := &flakyRunnable{failures: 100}
+		supervisor := NewSupervisor(nil, []Runnable{flaky}, WithBackoff(ConstantBackoff(time.Hour)))
+
+		// WHEN
+		err := supervisor.Run(ctx)
+
+		// THEN
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+		assert.Equal(t, int32(1), flaky.runs.Load())
+	})
+
+	t.Run("it should restart the whole subtree", func(t *testing.T) {
+		// GIVEN
+		flaky := &flakyRunnable{failures: 1}
+		sibling := &flakyRunnable{}
+		parent := &flakyRunnable{}
+		supervisor := NewSupervisor(parent, []Runnable{flaky, sibling}, WithEscalation(RestartSubtree))
+
+		// WHEN
+		err := supervisor.Run(context.Background())
+
+		// THEN
+		require.NoError(t, err)
+		assert.Equal(t, int32(2), flaky.runs.Load())
+		assert.Equal(t, int32(2), sibling.runs.Load())
+		assert.Equal(t, int32(2), parent.runs.Load())
+	})
+
+	t.Run("it should stop the subtree and escalate when giving up", func(t *testing.T) {
+		// GIVEN
+		flaky := &flakyRunnable{failures: 1}
+		sibling := &blockingRunnable{}
+		supervisor := NewSupervisor(nil, []Runnable{flaky, sibling}, WithEscalation(GiveUp))
+
+		// WHEN
+		err := supervisor.Run(context.Background())
+
+		// THEN
+		require.Error(t, err)
+		assert.Equal(t, "flaky failure", err.Error())
+		assert.Equal(t, int32(1), sibling.runs.Load())
+	})
+
+	t.Run("it should escalate failures of nested subtrees to their parent supervisor", func(t *testing.T) {
+		// GIVEN
+		flaky := &flakyRunnable{failures: 1}
+		subtree := &parentRunnable{children: []Runnable{flaky}, escalation: GiveUp}
+		supervisor := NewSupervisor(nil, []Runnable{subtree}, WithEscalation(RestartChild))
+
+		// WHEN
+		err := supervisor.Run(context.Background())
+
+		// THEN
+		require.NoError(t, err)
+		assert.Equal(t, int32(2), subtree.runs.Load())
+		assert.Equal(t, int32(2), flaky.runs.Load())
+	})
+
+	t.Run("it should not restart the grandchildren once stopped", func(t *testing.T) {
+		// GIVEN
+		grandchild := &crashingRunnable{flakyRunnable: flakyRunnable{failures: 1000}, runFor: time.Millisecond}
+		subtree := &supervisedParent{
+			parentRunnable: parentRunnable{children: []Runnable{grandchild}, escalation: RestartChild},
+			supervision:    []option.Option[SupervisorOptions]{WithMaxRestarts(1000), WithBackoff(ConstantBackoff(time.Millisecond))},
+		}
+		supervisor := NewSupervisor(nil, []Runnable{subtree})
+		done := make(chan error, 1)
+		go func() {
+			done <- supervisor.Run(context.Background())
+		}()
+		require.Eventually(t, func() bool { return grandchild.runs.Load() > 2 }, time.Second, time.Millisecond)
+
+		// WHEN
+		err := supervisor.Stop(context.Background())
+
+		// THEN
+		require.NoError(t, err)
+		select {
+		case <-done:
+		case <-time.After(time.Second):
+			t.Fatal("the supervisor kept restarting the grandchild after being stopped")
+		}
+		runs := grandchild.runs.Load()
+		time.Sleep(20 * time.Millisecond)
+		assert.Equal(t, runs, grandchild.runs.Load())
+	})
+
+	t.Run("it should stop everything when the context is canceled", func(t *testing.T) {
+		// GIVEN
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+		child := &blockingRunnable{}
+		supervisor := NewSupervisor(&blockingRunnable{}, []Runnable{child})
+
+		// WHEN
+		err := supervisor.Run(ctx)
+
+		// THEN
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+		assert.Equal(t, int32(1), child.runs.Load())
+	})
+}
+
+func TestRunAll_Parent(t *testing.T) {
+	t.Run("it should supervise runnables declaring children", func(t *testing.T) {
+		// GIVEN
+		flaky := &flakyRunnable{failures: 1}
+		parent := &parentRunnable{children: []Runnable{flaky}, escalation: RestartChild}
+
+		// WHEN
+		err := RunAll(context.Background(), parent)
+
+		// THEN
+		require.NoError(t, err)
+		assert.Equal(t, int32(2), flaky.runs.Load())
+		assert.Equal(t, int32(1), parent.runs.Load())
+	})
+	t.Run("it should apply the options of the supervised parents", func(t *testing.T) {
+		// GIVEN
+		flaky := &flakyRunnable{failures: 100}
+		parent := &supervisedParent{
+			parentRunnable: parentRunnable{children: []Runnable{flaky}, escalation: RestartChild},
+			supervision:    []option.Option[SupervisorOptions]{WithMaxRestarts(1), WithBackoff(ConstantBackoff(time.Millisecond))},
+		}
+
+		// WHEN
+		err := RunAll(context.Background(), parent)
+
+		// THEN
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "supervisor gave up after 1 restarts")
+		assert.Equal(t, int32(2), flaky.runs.Load())
+	})
+}
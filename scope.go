@@ -0,0 +1,59 @@
+package godi
+
+import (
+	"fmt"
+
+	"github.com/a-peyrard/godi/option"
+)
+
+// Scoped makes the component instantiated once per scope (see Resolver.NewScope), and closed with the scope.
+//
+// Scoped components can only be resolved within a scope, and can not be injected in singletons.
+func Scoped() option.Option[RegistrableOptions] {
+	return func(opts *RegistrableOptions) {
+		opts.scoped = true
+	}
+}
+
+// NewScope creates a child resolver, instantiating the scoped components once for the scope, while the singletons
+// are still resolved (and stored) by the parent.
+//
+// The components registered in the scope are only visible from the scope, and are always scoped.
+// Closing the scope closes the scoped components only.
+func (r *Resolver) NewScope() *Resolver {
+	scope := &Resolver{resolverState: &resolverState{
+		parent:    r,
+		providers: r.providers.Copy(),
+		store:     r.store.empty(),
+
+		lock: NewLockManager(),
+
+		ctx:           r.ctx,
+		parallelSlots: r.parallelSlots,
+		tracer:        r.tracer,
+		logger:        r.logger,
+	}}
+	r.copyDecoratorsTo(scope)
+	scope.interceptors.Store(r.interceptors.Load())
+
+	// the scope shadows the parent, so the providers resolving the resolver get the scope
+	scope.MustRegister(ToStaticProvider(scope), Named("godi.resolver"), Hidden())
+	r.scopes.Add(1)
+
+	return scope
+}
+
+// ownerOf returns the resolver responsible for building and storing the components of the provider,
+// i.e. the resolver itself for scoped components, and the root resolver for singletons.
+func (r *Resolver) ownerOf(p Provider, name Name) (*Resolver, error) {
+	if attributesOf(p).scoped {
+		if r.parent == nil {
+			return nil, fmt.Errorf("component %s is scoped, it can only be resolved within a scope, and can not be injected in singletons", name)
+		}
+		return r, nil
+	}
+	if r.parent != nil {
+		return r.parent.ownerOf(p, name)
+	}
+	return r, nil
+}
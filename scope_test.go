@@ -0,0 +1,134 @@
+package godi
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type requestContext struct {
+	ID     string
+	closed bool
+}
+
+func (c *requestContext) Close() error {
+	c.closed = true
+	return nil
+}
+
+func TestResolver_NewScope(t *testing.T) {
+	t.Run("it should instantiate scoped components once per scope", func(t *testing.T) {
+		// GIVEN
+		resolver := New()
+		counter := 0
+		resolver.MustRegister(func() *requestContext {
+			counter++
+			return &requestContext{ID: "request"}
+		}, Scoped())
+		scope1 := resolver.NewScope()
+		scope2 := resolver.NewScope()
+
+		// WHEN
+		first1 := MustResolve[*requestContext](scope1)
+		second1 := MustResolve[*requestContext](scope1)
+		first2 := MustResolve[*requestContext](scope2)
+
+		// THEN
+		assert.Same(t, first1, second1)
+		assert.NotSame(t, first1, first2)
+		assert.Equal(t, 2, counter)
+	})
+
+	t.Run("it should share the singletons with the parent", func(t *testing.T) {
+		// GIVEN
+		resolver := New()
+		resolver.MustRegister(NewTestService)
+		scope := resolver.NewScope()
+
+		// WHEN
+		fromScope := MustResolve[*TestService](scope)
+		fromParent := MustResolve[*TestService](resolver)
+
+		// THEN
+		assert.Same(t, fromParent, fromScope)
+	})
+
+	t.Run("it should close the scoped components with the scope", func(t *testing.T) {
+		// GIVEN
+		resolver := New()
+		resolver.MustRegister(func() *requestContext { return &requestContext{} }, Scoped())
+		resolver.MustRegister(NewTestService)
+		scope := resolver.NewScope()
+		reqCtx := MustResolve[*requestContext](scope)
+		service := MustResolve[*TestService](scope)
+
+		// WHEN
+		err := scope.Close()
+
+		// THEN
+		require.NoError(t, err)
+		assert.True(t, reqCtx.closed)
+		assert.False(t, service.closed)
+	})
+
+	t.Run("it should keep the registrations of the scope in the scope", func(t *testing.T) {
+		// GIVEN
+		resolver := New()
+		scope := resolver.NewScope()
+		scope.MustRegister(ToStaticProvider(&requestContext{ID: "42"}))
+
+		// WHEN
+		fromScope, err := Resolve[*requestContext](scope)
+		_, foundInParent, errParent := TryResolve[*requestContext](resolver)
+
+		// THEN
+		require.NoError(t, err)
+		require.NoError(t, errParent)
+		assert.Equal(t, "42", fromScope.ID)
+		assert.False(t, foundInParent)
+	})
+
+	t.Run("it should inject the scope as the resolver", func(t *testing.T) {
+		// GIVEN
+		resolver := New()
+		scope := resolver.NewScope()
+
+		// WHEN
+		injected, err := ResolveNamed[*Resolver](scope, "godi.resolver")
+
+		// THEN
+		require.NoError(t, err)
+		assert.Same(t, scope, injected)
+	})
+
+	t.Run("it should fail to resolve scoped components outside of a scope", func(t *testing.T) {
+		// GIVEN
+		resolver := New()
+		resolver.MustRegister(func() *requestContext { return &requestContext{} }, Scoped())
+
+		// WHEN
+		_, err := Resolve[*requestContext](resolver)
+
+		// THEN
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "can only be resolved within a scope")
+	})
+
+	t.Run("it should count the active scopes", func(t *testing.T) {
+		// GIVEN
+		resolver := New()
+		resolver.MustRegister(NewTestService)
+		MustResolve[*TestService](resolver)
+		scope1 := resolver.NewScope()
+		_ = resolver.NewScope()
+
+		// WHEN
+		require.NoError(t, scope1.Close())
+		stats := resolver.Stats()
+
+		// THEN
+		assert.Equal(t, 1, stats.ActiveScopes)
+		assert.Equal(t, 1, stats.Components)
+	})
+}
@@ -0,0 +1,239 @@
+package godi
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/a-peyrard/godi/option"
+	"github.com/a-peyrard/godi/secrets"
+	"github.com/a-peyrard/godi/slices"
+)
+
+// SecretPrefix prefixes the names of the components provided by the SecretProvider.
+const SecretPrefix = "secret."
+
+// redactedValue replaces the value of the sensitive components in Describe.
+const redactedValue = "******"
+
+// DefaultSecretLookupTimeout bounds the look up of a secret in the backends, unless WithSecretLookupTimeout is given.
+const DefaultSecretLookupTimeout = 10 * time.Second
+
+// DefaultSecretMissTTL is how long the secrets not found are cached, unless WithSecretMissTTL is given.
+const DefaultSecretMissTTL = 5 * time.Second
+
+type (
+	// SecretProvider is a provider that provides secrets as string components, named "secret.<key>".
+	//
+	// The secrets are looked up in the backends, in order, the first backend knowing the key wins.
+	// The looked up secrets are cached, the unknown ones for a short time, and their values are redacted by
+	// Resolver.Describe.
+	// The backends are called without holding any lock, within a timeout, as the secrets are looked up while
+	// planning the resolutions.
+	SecretProvider struct {
+		backends []secrets.Backend
+		ttl      time.Duration
+		missTTL  time.Duration
+		timeout  time.Duration
+
+		lock  sync.Mutex
+		cache map[string]cachedSecret
+	}
+
+	// SecretOptions configures the SecretProvider.
+	SecretOptions struct {
+		backends []secrets.Backend
+		ttl      time.Duration
+		missTTL  time.Duration
+		timeout  time.Duration
+	}
+
+	cachedSecret struct {
+		value     string
+		found     bool
+		expiresAt time.Time
+	}
+
+	// redactor is implemented by the providers of sensitive components, which are redacted by Describe.
+	redactor interface {
+		redacts(name Name) bool
+	}
+)
+
+// NewSecretProvider creates a provider looking up the secrets in the configured backends.
+func NewSecretProvider(opts ...option.Option[SecretOptions]) *SecretProvider {
+	options := option.Build(&SecretOptions{missTTL: DefaultSecretMissTTL, timeout: DefaultSecretLookupTimeout}, opts...)
+	return &SecretProvider{
+		backends: options.backends,
+		ttl:      options.ttl,
+		missTTL:  options.missTTL,
+		timeout:  options.timeout,
+		cache:    make(map[string]cachedSecret),
+	}
+}
+
+// WithSecretBackends adds backends to look up the secrets from, see the secrets package.
+func WithSecretBackends(backends ...secrets.Backend) option.Option[SecretOptions] {
+	return func(opts *SecretOptions) {
+		opts.backends = append(opts.backends, backends...)
+	}
+}
+
+// WithSecretCacheTTL sets how long the looked up secrets are cached, by default they are cached forever.
+// The secrets not found are not cached longer, see WithSecretMissTTL.
+func WithSecretCacheTTL(ttl time.Duration) option.Option[SecretOptions] {
+	return func(opts *SecretOptions) {
+		opts.ttl = ttl
+	}
+}
+
+// WithSecretMissTTL sets how long the secrets not found are cached, see DefaultSecretMissTTL, so a secret added to
+// a backend is found once it expired. A TTL of zero disables the caching of the secrets not found.
+func WithSecretMissTTL(ttl time.Duration) option.Option[SecretOptions] {
+	return func(opts *SecretOptions) {
+		opts.missTTL = ttl
+	}
+}
+
+// WithSecretLookupTimeout sets how long the backends are given to look up a secret, see DefaultSecretLookupTimeout.
+func WithSecretLookupTimeout(timeout time.Duration) option.Option[SecretOptions] {
+	return func(opts *SecretOptions) {
+		opts.timeout = timeout
+	}
+}
+
+func (s *SecretProvider) CanProvide(name Name) bool {
+	if !strings.HasPrefix(name.name, SecretPrefix) || !matchType(name.typ, StringType) {
+		return false
+	}
+	_, found, err := s.lookup(context.Background(), strings.TrimPrefix(name.name, SecretPrefix))
+	// a failing backend is reported by Provide
+	return found || err != nil
+}
+
+func (s *SecretProvider) Provide(name Name, _ []reflect.Value) (comp reflect.Value, err error) {
+	key := strings.TrimPrefix(name.name, SecretPrefix)
+	value, found, err := s.lookup(context.Background(), key)
+	if err != nil {
+		return reflect.Zero(name.typ), fmt.Errorf("unable to look up secret %s:\n\t%w", key, err)
+	}
+	if !found {
+		return reflect.Zero(name.typ), fmt.Errorf("secret %s not found", key)
+	}
+	return reflect.ValueOf(value), nil
+}
+
+func (s *SecretProvider) Dependencies() []Request {
+	return nil
+}
+
+// ListProvidableNames lists the secrets looked up so far, as the backends cannot be listed.
+func (s *SecretProvider) ListProvidableNames() []Name {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	names := make([]Name, 0, len(s.cache))
+	for key, cached := range s.cache {
+		if cached.found {
+			names = append(names, Name{name: SecretPrefix + key, typ: StringType})
+		}
+	}
+	sort.Slice(names, func(i, j int) bool {
+		return names[i].name < names[j].name
+	})
+	return names
+}
+
+func (s *SecretProvider) Priority() int {
+	return 0
+}
+
+func (s *SecretProvider) Description() string {
+	return fmt.Sprintf("Provides secrets from %s", strings.Join(s.backendNames(), ", "))
+}
+
+func (s *SecretProvider) String() string {
+	return fmt.Sprintf("SecretProvider(%s)", strings.Join(s.backendNames(), ", "))
+}
+
+func (s *SecretProvider) redacts(name Name) bool {
+	return strings.HasPrefix(name.name, SecretPrefix)
+}
+
+func (s *SecretProvider) backendNames() []string {
+	return slices.Map(s.backends, func(backend secrets.Backend) string { return backend.Name() })
+}
+
+func (s *SecretProvider) lookup(ctx context.Context, key string) (string, bool, error) {
+	if cached, found := s.cached(key); found {
+		return cached.value, cached.found, nil
+	}
+
+	// the backends are called without the lock, so a slow backend does not block the other secrets
+	if s.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, s.timeout)
+		defer cancel()
+	}
+	var looked cachedSecret
+	for _, backend := range s.backends {
+		value, found, err := backend.Lookup(ctx, key)
+		if err != nil {
+			return "", false, fmt.Errorf("backend %s failed:\n\t%w", backend.Name(), err)
+		}
+		if found {
+			looked = cachedSecret{value: value, found: true}
+			break
+		}
+	}
+	ttl := s.ttl
+	if !looked.found {
+		if s.missTTL <= 0 {
+			return "", false, nil
+		}
+		if ttl <= 0 || s.missTTL < ttl {
+			ttl = s.missTTL
+		}
+	}
+	if ttl > 0 {
+		looked.expiresAt = time.Now().Add(ttl)
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.cache[key] = looked
+	return looked.value, looked.found, nil
+}
+
+// cached returns the cached look up of the secret, if it did not expire.
+func (s *SecretProvider) cached(key string) (cachedSecret, bool) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	cached, found := s.cache[key]
+	if !found {
+		return cachedSecret{}, false
+	}
+	if cached.expiresAt.IsZero() || time.Now().Before(cached.expiresAt) {
+		return cached, true
+	}
+	delete(s.cache, key)
+	return cachedSecret{}, false
+}
+
+// isRedacted checks if the component is provided by a provider of sensitive components.
+func isRedacted(providers []Provider, name Name) bool {
+	for _, p := range providers {
+		if attributed, ok := p.(*attributedProvider); ok {
+			p = attributed.Provider
+		}
+		if r, ok := p.(redactor); ok && r.redacts(name) {
+			return true
+		}
+	}
+	return false
+}
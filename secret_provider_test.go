@@ -0,0 +1,214 @@
+package godi
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/a-peyrard/godi/secrets"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type countingBackend struct {
+	secrets map[string]string
+	lookups int
+	err     error
+}
+
+func (c *countingBackend) Lookup(_ context.Context, key string) (string, bool, error) {
+	c.lookups++
+	value, found := c.secrets[key]
+	return value, found, c.err
+}
+
+func (c *countingBackend) Name() string {
+	return "counting"
+}
+
+// blockingBackend blocks the look up of the given key until the context is done.
+type blockingBackend struct {
+	key     string
+	blocked chan struct{}
+}
+
+func (b *blockingBackend) Lookup(ctx context.Context, key string) (string, bool, error) {
+	if key != b.key {
+		return "value of " + key, true, nil
+	}
+	close(b.blocked)
+	<-ctx.Done()
+	return "", false, ctx.Err()
+}
+
+func (b *blockingBackend) Name() string {
+	return "blocking"
+}
+
+func TestSecretProvider(t *testing.T) {
+	t.Run("it should resolve the secrets from the first backend knowing them", func(t *testing.T) {
+		// GIVEN
+		dir := t.TempDir()
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "db.password"), []byte("from-file\n"), 0600))
+		t.Setenv("SECRETS_DB_PASSWORD", "from-env")
+		t.Setenv("SECRETS_API_KEY", "from-env")
+
+		resolver := New()
+		resolver.MustRegister(NewSecretProvider(WithSecretBackends(secrets.Files(dir), secrets.Env("SECRETS_"))))
+
+		// WHEN
+		password, err1 := ResolveNamed[string](resolver, "secret.db.password")
+		apiKey, err2 := ResolveNamed[string](resolver, "secret.api.key")
+
+		// THEN
+		require.NoError(t, err1)
+		require.NoError(t, err2)
+		assert.Equal(t, "from-file", password)
+		assert.Equal(t, "from-env", apiKey)
+	})
+
+	t.Run("it should not find unknown secrets", func(t *testing.T) {
+		// GIVEN
+		resolver := New()
+		resolver.MustRegister(NewSecretProvider(WithSecretBackends(&countingBackend{})))
+
+		// WHEN
+		_, found, err := TryResolveNamed[string](resolver, "secret.unknown")
+
+		// THEN
+		require.NoError(t, err)
+		assert.False(t, found)
+	})
+
+	t.Run("it should cache the secrets until their TTL", func(t *testing.T) {
+		// GIVEN
+		backend := &countingBackend{secrets: map[string]string{"token": "s3cr3t"}}
+		provider := NewSecretProvider(WithSecretBackends(backend), WithSecretCacheTTL(50*time.Millisecond))
+		name := Name{name: "secret.token", typ: StringType}
+
+		// WHEN
+		assert.True(t, provider.CanProvide(name))
+		_, err := provider.Provide(name, nil)
+		require.NoError(t, err)
+		lookupsBeforeExpiration := backend.lookups
+		time.Sleep(60 * time.Millisecond)
+		_, err = provider.Provide(name, nil)
+		require.NoError(t, err)
+
+		// THEN
+		assert.Equal(t, 1, lookupsBeforeExpiration)
+		assert.Equal(t, 2, backend.lookups)
+	})
+
+	t.Run("it should cache the unknown secrets", func(t *testing.T) {
+		// GIVEN
+		backend := &countingBackend{}
+		provider := NewSecretProvider(WithSecretBackends(backend))
+		name := Name{name: "secret.unknown", typ: StringType}
+
+		// WHEN
+		first := provider.CanProvide(name)
+		second := provider.CanProvide(name)
+
+		// THEN
+		assert.False(t, first)
+		assert.False(t, second)
+		assert.Equal(t, 1, backend.lookups)
+		assert.Empty(t, provider.ListProvidableNames())
+	})
+
+	t.Run("it should find a secret added to a backend once the miss expired", func(t *testing.T) {
+		// GIVEN
+		backend := &countingBackend{secrets: map[string]string{}}
+		provider := NewSecretProvider(WithSecretBackends(backend), WithSecretMissTTL(50*time.Millisecond))
+		name := Name{name: "secret.token", typ: StringType}
+		require.False(t, provider.CanProvide(name))
+		backend.secrets["token"] = "s3cr3t"
+
+		// WHEN
+		beforeExpiration := provider.CanProvide(name)
+		time.Sleep(60 * time.Millisecond)
+		afterExpiration := provider.CanProvide(name)
+
+		// THEN
+		assert.False(t, beforeExpiration)
+		assert.True(t, afterExpiration)
+		assert.Equal(t, 2, backend.lookups)
+	})
+
+	t.Run("it should not cache the unknown secrets without a miss TTL", func(t *testing.T) {
+		// GIVEN
+		backend := &countingBackend{}
+		provider := NewSecretProvider(WithSecretBackends(backend), WithSecretMissTTL(0))
+		name := Name{name: "secret.unknown", typ: StringType}
+
+		// WHEN
+		provider.CanProvide(name)
+		provider.CanProvide(name)
+
+		// THEN
+		assert.Equal(t, 2, backend.lookups)
+	})
+
+	t.Run("it should look up the other secrets while a backend is slow", func(t *testing.T) {
+		// GIVEN
+		backend := &blockingBackend{key: "slow", blocked: make(chan struct{})}
+		provider := NewSecretProvider(WithSecretBackends(backend), WithSecretLookupTimeout(100*time.Millisecond))
+		slow := make(chan bool, 1)
+		go func() { slow <- provider.CanProvide(Name{name: "secret.slow", typ: StringType}) }()
+		<-backend.blocked
+
+		// WHEN
+		value, err := provider.Provide(Name{name: "secret.fast", typ: StringType}, nil)
+
+		// THEN
+		require.NoError(t, err)
+		assert.Equal(t, "value of fast", value.String())
+		assert.True(t, <-slow, "the failure is reported by Provide")
+	})
+
+	t.Run("it should give up the look up after the timeout", func(t *testing.T) {
+		// GIVEN
+		backend := &blockingBackend{key: "slow", blocked: make(chan struct{})}
+		provider := NewSecretProvider(WithSecretBackends(backend), WithSecretLookupTimeout(10*time.Millisecond))
+
+		// WHEN
+		_, err := provider.Provide(Name{name: "secret.slow", typ: StringType}, nil)
+
+		// THEN
+		require.Error(t, err)
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+	})
+
+	t.Run("it should report failing backends", func(t *testing.T) {
+		// GIVEN
+		resolver := New()
+		resolver.MustRegister(NewSecretProvider(WithSecretBackends(&countingBackend{err: errors.New("vault is sealed")})))
+
+		// WHEN
+		_, err := ResolveNamed[string](resolver, "secret.db.password")
+
+		// THEN
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "vault is sealed")
+	})
+
+	t.Run("it should redact the secrets in the description of the resolver", func(t *testing.T) {
+		// GIVEN
+		backend := &countingBackend{secrets: map[string]string{"token": "s3cr3t"}}
+		resolver := New()
+		resolver.MustRegister(NewSecretProvider(WithSecretBackends(backend)))
+		MustResolveNamed[string](resolver, "secret.token")
+
+		// WHEN
+		description := resolver.Describe()
+
+		// THEN
+		assert.Contains(t, description, "secret.token")
+		assert.Contains(t, description, redactedValue)
+		assert.NotContains(t, description, "s3cr3t")
+	})
+}
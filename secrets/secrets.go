@@ -0,0 +1,172 @@
+// Package secrets contains the backends the secrets are looked up from, see godi.SecretProvider.
+package secrets
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+type (
+	// Backend looks up secrets by key, the key being the name of the secret component without the "secret." prefix.
+	Backend interface {
+		// Lookup returns the value of the secret, or false if the backend does not know it.
+		Lookup(ctx context.Context, key string) (value string, found bool, err error)
+		// Name describes the backend, it must not contain any secret.
+		Name() string
+	}
+
+	// SecretsManagerClient fetches secrets from AWS Secrets Manager.
+	//
+	// It is meant to be implemented on top of the AWS SDK, by calling GetSecretValue with the secret id,
+	// and reporting a ResourceNotFoundException as a secret not found.
+	SecretsManagerClient interface {
+		GetSecretString(ctx context.Context, secretID string) (value string, found bool, err error)
+	}
+
+	envBackend struct {
+		prefix string
+	}
+
+	filesBackend struct {
+		dir string
+	}
+
+	vaultBackend struct {
+		addr   string
+		token  string
+		mount  string
+		client *http.Client
+	}
+
+	awsSecretsManagerBackend struct {
+		client SecretsManagerClient
+	}
+)
+
+var envKeyReplacer = strings.NewReplacer(".", "_", "-", "_", "/", "_")
+
+// vaultClient is the client of the Vault servers, its timeout bounds the requests made without deadline.
+var vaultClient = &http.Client{Timeout: 10 * time.Second}
+
+// Env looks up the secrets in the env vars, the key is upper-cased and its separators replaced by underscores,
+// i.e. the secret "db.password" is read from PREFIX_DB_PASSWORD.
+func Env(prefix string) Backend {
+	return envBackend{prefix: prefix}
+}
+
+func (e envBackend) Lookup(_ context.Context, key string) (string, bool, error) {
+	value, found := os.LookupEnv(e.prefix + strings.ToUpper(envKeyReplacer.Replace(key)))
+	return value, found, nil
+}
+
+func (e envBackend) Name() string {
+	return fmt.Sprintf("env(%s*)", e.prefix)
+}
+
+// Files looks up the secrets in the files of the given directory, as mounted by docker or kubernetes,
+// i.e. the secret "db.password" is read from dir/db.password, without its trailing new line.
+func Files(dir string) Backend {
+	return filesBackend{dir: dir}
+}
+
+func (f filesBackend) Lookup(_ context.Context, key string) (string, bool, error) {
+	if !filepath.IsLocal(key) {
+		return "", false, fmt.Errorf("invalid secret key %q", key)
+	}
+	content, err := os.ReadFile(filepath.Join(f.dir, key))
+	if errors.Is(err, os.ErrNotExist) {
+		return "", false, nil
+	}
+	if err != nil {
+		return "", false, fmt.Errorf("unable to read secret %s:\n\t%w", key, err)
+	}
+	return strings.TrimRight(string(content), "\r\n"), true, nil
+}
+
+func (f filesBackend) Name() string {
+	return fmt.Sprintf("files(%s)", f.dir)
+}
+
+// Vault looks up the secrets in the KV v2 engine mounted at the given path of a HashiCorp Vault server.
+//
+// The last segment of the key is the field of the secret, and the previous ones its path,
+// i.e. the secret "app.db.password" is the field password of the secret app/db.
+// The requests are bounded by the deadline of the context of the look up, and time out after 10 seconds anyway.
+func Vault(addr string, token string, mount string) Backend {
+	return vaultBackend{
+		addr:   strings.TrimSuffix(addr, "/"),
+		token:  token,
+		mount:  strings.Trim(mount, "/"),
+		client: vaultClient,
+	}
+}
+
+func (v vaultBackend) Lookup(ctx context.Context, key string) (string, bool, error) {
+	lastDot := strings.LastIndex(key, ".")
+	if lastDot <= 0 {
+		return "", false, nil
+	}
+	path := strings.ReplaceAll(key[:lastDot], ".", "/")
+	field := key[lastDot+1:]
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf("%s/v1/%s/data/%s", v.addr, v.mount, (&url.URL{Path: path}).EscapedPath()), nil)
+	if err != nil {
+		return "", false, err
+	}
+	req.Header.Set("X-Vault-Token", v.token)
+
+	resp, err := v.client.Do(req)
+	if err != nil {
+		return "", false, fmt.Errorf("unable to fetch secret %s from vault:\n\t%w", path, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return "", false, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", false, fmt.Errorf("unable to fetch secret %s from vault: status %d", path, resp.StatusCode)
+	}
+
+	var payload struct {
+		Data struct {
+			Data map[string]any `json:"data"`
+		} `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&payload); err != nil {
+		return "", false, fmt.Errorf("unable to decode secret %s from vault:\n\t%w", path, err)
+	}
+	value, found := payload.Data.Data[field]
+	if !found {
+		return "", false, nil
+	}
+	if str, ok := value.(string); ok {
+		return str, true, nil
+	}
+	return fmt.Sprint(value), true, nil
+}
+
+func (v vaultBackend) Name() string {
+	return fmt.Sprintf("vault(%s/%s)", v.addr, v.mount)
+}
+
+// AWSSecretsManager looks up the secrets in AWS Secrets Manager, the key being the id of the secret.
+func AWSSecretsManager(client SecretsManagerClient) Backend {
+	return awsSecretsManagerBackend{client: client}
+}
+
+func (a awsSecretsManagerBackend) Lookup(ctx context.Context, key string) (string, bool, error) {
+	return a.client.GetSecretString(ctx, key)
+}
+
+func (a awsSecretsManagerBackend) Name() string {
+	return "aws-secrets-manager"
+}
@@ -0,0 +1,142 @@
+package secrets
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeSecretsManager map[string]string
+
+func (f fakeSecretsManager) GetSecretString(_ context.Context, secretID string) (string, bool, error) {
+	value, found := f[secretID]
+	return value, found, nil
+}
+
+func TestEnv(t *testing.T) {
+	t.Run("it should read the secret from the upper-cased env var", func(t *testing.T) {
+		// GIVEN
+		t.Setenv("APP_DB_PASSWORD", "s3cr3t")
+		backend := Env("APP_")
+
+		// WHEN
+		value, found, err := backend.Lookup(context.Background(), "db.password")
+
+		// THEN
+		require.NoError(t, err)
+		assert.True(t, found)
+		assert.Equal(t, "s3cr3t", value)
+	})
+
+	t.Run("it should not find unknown secrets", func(t *testing.T) {
+		// WHEN
+		_, found, err := Env("APP_").Lookup(context.Background(), "unknown")
+
+		// THEN
+		require.NoError(t, err)
+		assert.False(t, found)
+	})
+}
+
+func TestFiles(t *testing.T) {
+	t.Run("it should read the secret from the mounted file, without the trailing new line", func(t *testing.T) {
+		// GIVEN
+		dir := t.TempDir()
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "db.password"), []byte("s3cr3t\n"), 0600))
+
+		// WHEN
+		value, found, err := Files(dir).Lookup(context.Background(), "db.password")
+
+		// THEN
+		require.NoError(t, err)
+		assert.True(t, found)
+		assert.Equal(t, "s3cr3t", value)
+	})
+
+	t.Run("it should not find missing files", func(t *testing.T) {
+		// WHEN
+		_, found, err := Files(t.TempDir()).Lookup(context.Background(), "db.password")
+
+		// THEN
+		require.NoError(t, err)
+		assert.False(t, found)
+	})
+
+	t.Run("it should reject keys escaping the directory", func(t *testing.T) {
+		// WHEN
+		_, _, err := Files(t.TempDir()).Lookup(context.Background(), "../etc/passwd")
+
+		// THEN
+		require.Error(t, err)
+	})
+}
+
+func TestVault(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Vault-Token") != "token" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		if r.URL.Path != "/v1/kv/data/app/db" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write([]byte(`{"data": {"data": {"password": "s3cr3t", "port": 5432}}}`))
+	}))
+	t.Cleanup(server.Close)
+
+	t.Run("it should read the field of the secret", func(t *testing.T) {
+		// WHEN
+		value, found, err := Vault(server.URL, "token", "kv").Lookup(context.Background(), "app.db.password")
+
+		// THEN
+		require.NoError(t, err)
+		assert.True(t, found)
+		assert.Equal(t, "s3cr3t", value)
+	})
+
+	t.Run("it should not find unknown secrets or fields", func(t *testing.T) {
+		// GIVEN
+		backend := Vault(server.URL, "token", "kv")
+
+		// WHEN
+		_, unknownSecret, err1 := backend.Lookup(context.Background(), "app.cache.password")
+		_, unknownField, err2 := backend.Lookup(context.Background(), "app.db.user")
+
+		// THEN
+		require.NoError(t, err1)
+		require.NoError(t, err2)
+		assert.False(t, unknownSecret)
+		assert.False(t, unknownField)
+	})
+
+	t.Run("it should fail if vault refuses the request", func(t *testing.T) {
+		// WHEN
+		_, _, err := Vault(server.URL, "wrong", "kv").Lookup(context.Background(), "app.db.password")
+
+		// THEN
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "status 403")
+	})
+}
+
+func TestAWSSecretsManager(t *testing.T) {
+	t.Run("it should read the secret by id", func(t *testing.T) {
+		// GIVEN
+		backend := AWSSecretsManager(fakeSecretsManager{"prod/db-password": "s3cr3t"})
+
+		// WHEN
+		value, found, err := backend.Lookup(context.Background(), "prod/db-password")
+
+		// THEN
+		require.NoError(t, err)
+		assert.True(t, found)
+		assert.Equal(t, "s3cr3t", value)
+	})
+}
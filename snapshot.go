@@ -0,0 +1,121 @@
+package godi
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+)
+
+type (
+	// Snapshot lists the components instantiated by a resolver, in their instantiation order, see Resolver.Snapshot.
+	// It can be serialized as JSON, to be restored in future runs.
+	Snapshot struct {
+		Components []SnapshotEntry `json:"components"`
+	}
+
+	// SnapshotEntry identifies an instantiated component, its type being qualified by its package path.
+	SnapshotEntry struct {
+		Name    string `json:"name,omitempty"`
+		Type    string `json:"type"`
+		Version string `json:"version,omitempty"`
+	}
+)
+
+// Snapshot captures the components instantiated so far, e.g. after Warmup, in their instantiation order.
+// The transient components, which are not stored, and the components built for each consumer (see PerConsumer)
+// are not part of the snapshot.
+func (r *Resolver) Snapshot() Snapshot {
+	names := r.store.namesInOrder()
+	snapshot := Snapshot{Components: make([]SnapshotEntry, 0, len(names))}
+	for _, n := range names {
+		if n.consumer != "" {
+			continue // built again with their consumers
+		}
+		snapshot.Components = append(snapshot.Components, SnapshotEntry{
+			Name:    n.name,
+			Type:    qualifiedTypeName(n.typ),
+			Version: n.version,
+		})
+	}
+	return snapshot
+}
+
+// Restore instantiates the components of the snapshot, in the order they were instantiated when it was captured,
+// to reproduce the startup of a previous run.
+//
+// The components nothing provides anymore are reported as errors, the other ones are instantiated anyway.
+func (r *Resolver) Restore(snapshot Snapshot) error {
+	var errs []error
+	for _, entry := range snapshot.Components {
+		n, found := r.providedName(entry)
+		if !found {
+			errs = append(errs, fmt.Errorf("failed to restore component %s of type %s, nothing provides it", entry.Name, entry.Type))
+			continue
+		}
+		_, _, err := r.resolve(Request{
+			unitaryTyp: n.typ,
+			query:      queryByVersion{name: n.unversioned(), version: entry.Version},
+			validator:  validatorUniqueMandatory{},
+			collector:  collectorUnique{},
+		})
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to restore component %s:\n\t%w", n, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// providedName returns the name of the component of the snapshot, as provided by the registered providers.
+func (r *Resolver) providedName(entry SnapshotEntry) (Name, bool) {
+	for _, p := range r.providers.All() {
+		if attributesOf(p).version != entry.Version {
+			continue
+		}
+		for _, n := range p.ListProvidableNames() {
+			if n.name == entry.Name && qualifiedTypeName(n.typ) == entry.Type {
+				return n, true
+			}
+		}
+	}
+	return Name{}, false
+}
+
+// namesInOrder returns the names of the stored components, in their instantiation order.
+func (s *Store) namesInOrder() []Name {
+	var components []*storedComponent
+	s.inner.Range(func(_, raw any) bool {
+		components = append(components, raw.(*storedComponent))
+		return true
+	})
+	slices.SortFunc(components, func(a, b *storedComponent) int {
+		return cmp.Compare(a.order, b.order)
+	})
+
+	names := make([]Name, 0, len(components))
+	for _, c := range components {
+		names = append(names, c.name)
+	}
+	return names
+}
+
+// qualifiedTypeName returns the name of the type, the named types being qualified by their package path,
+// so that the types of different packages with the same name are not mixed up.
+func qualifiedTypeName(t reflect.Type) string {
+	if t.Name() != "" && t.PkgPath() != "" {
+		return t.PkgPath() + "." + t.Name()
+	}
+	switch t.Kind() {
+	case reflect.Pointer:
+		return "*" + qualifiedTypeName(t.Elem())
+	case reflect.Slice:
+		return "[]" + qualifiedTypeName(t.Elem())
+	case reflect.Array:
+		return fmt.Sprintf("[%d]%s", t.Len(), qualifiedTypeName(t.Elem()))
+	case reflect.Map:
+		return "map[" + qualifiedTypeName(t.Key()) + "]" + qualifiedTypeName(t.Elem())
+	default:
+		return t.String()
+	}
+}
@@ -0,0 +1,88 @@
+package godi
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolver_Snapshot(t *testing.T) {
+	register := func(resolver *Resolver, instantiated *[]string) {
+		resolver.MustRegister(func() *TestRepository {
+			*instantiated = append(*instantiated, "repository")
+			return &TestRepository{Data: "data"}
+		}, Named("repository"))
+		resolver.MustRegister(func(repository *TestRepository) *TestService {
+			*instantiated = append(*instantiated, "service")
+			return &TestService{Name: repository.Data}
+		}, Named("service"))
+		resolver.MustRegister(func() string {
+			*instantiated = append(*instantiated, "greeting")
+			return "hello"
+		}, Named("greeting"), Version("v2"))
+	}
+
+	t.Run("it should capture the instantiated components in their instantiation order", func(t *testing.T) {
+		// GIVEN
+		var instantiated []string
+		resolver := New()
+		register(resolver, &instantiated)
+		_ = MustResolveNamed[*TestService](resolver, "service")
+		_ = MustResolveNamed[string](resolver, "greeting")
+
+		// WHEN
+		snapshot := resolver.Snapshot()
+
+		// THEN
+		assert.Equal(t, []SnapshotEntry{
+			{Name: "repository", Type: "*github.com/a-peyrard/godi.TestRepository"},
+			{Name: "service", Type: "*github.com/a-peyrard/godi.TestService"},
+			{Name: "greeting", Type: "string", Version: "v2"},
+		}, snapshot.Components)
+	})
+
+	t.Run("it should restore the components of a snapshot in the same order", func(t *testing.T) {
+		// GIVEN
+		var captured []string
+		origin := New()
+		register(origin, &captured)
+		_ = MustResolveNamed[string](origin, "greeting")
+		_ = MustResolveNamed[*TestService](origin, "service")
+		serialized, err := json.Marshal(origin.Snapshot())
+		require.NoError(t, err)
+
+		var snapshot Snapshot
+		require.NoError(t, json.Unmarshal(serialized, &snapshot))
+		var restored []string
+		resolver := New()
+		register(resolver, &restored)
+
+		// WHEN
+		err = resolver.Restore(snapshot)
+
+		// THEN
+		require.NoError(t, err)
+		assert.Equal(t, []string{"greeting", "repository", "service"}, restored)
+		assert.Equal(t, captured, restored)
+		assert.Equal(t, snapshot, resolver.Snapshot())
+	})
+
+	t.Run("it should report the components nothing provides anymore", func(t *testing.T) {
+		// GIVEN
+		resolver := New()
+		resolver.MustRegister(func() string { return "hello" }, Named("greeting"))
+		snapshot := Snapshot{Components: []SnapshotEntry{
+			{Name: "database", Type: "*database/sql.DB"},
+			{Name: "greeting", Type: "string"},
+		}}
+
+		// WHEN
+		err := resolver.Restore(snapshot)
+
+		// THEN
+		assert.EqualError(t, err, "failed to restore component database of type *database/sql.DB, nothing provides it")
+		assert.Len(t, resolver.Snapshot().Components, 1, "the greeting is restored anyway")
+	})
+}
@@ -43,6 +43,36 @@ func (r *SortedCOWSlice[T]) All() []T {
 	return *r.data.Load()
 }
 
+// load returns the current snapshot of the slice, which is never mutated, a new one is stored on every change.
+func (r *SortedCOWSlice[T]) load() *[]T {
+	return r.data.Load()
+}
+
 func (r *SortedCOWSlice[T]) Len() int {
 	return len(*r.data.Load())
 }
+
+// Copy returns an independent copy of the slice, sharing the same comparator.
+func (r *SortedCOWSlice[T]) Copy() *SortedCOWSlice[T] {
+	cowSlice := &SortedCOWSlice[T]{
+		comparator: r.comparator,
+	}
+	cowSlice.data.Store(r.data.Load()) // the underlying slice is never mutated, so it can be shared
+	return cowSlice
+}
+
+// RemoveIf removes all the items matching the predicate.
+func (r *SortedCOWSlice[T]) RemoveIf(predicate func(item T) bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	current := *r.data.Load()
+	newSlice := make([]T, 0, len(current))
+	for _, item := range current {
+		if !predicate(item) {
+			newSlice = append(newSlice, item)
+		}
+	}
+
+	r.data.Store(&newSlice)
+}
@@ -1,50 +1,314 @@
 package godi
 
 import (
+	"cmp"
+	"container/list"
+	"context"
 	"errors"
 	"fmt"
 	"reflect"
+	"slices"
 	"sync"
+	"sync/atomic"
+	"time"
 )
 
-type Store struct {
-	inner sync.Map
-}
+type (
+	Store struct {
+		inner sync.Map // type of keys is Name, type of values is *storedComponent
+
+		mu            sync.Mutex
+		retired       []*storedComponent
+		retiredErrors []error
+		tracked       []*storedComponent
+		sequence      atomic.Uint64
+
+		// limit is the maximum number of stored components, the least recently used ones are evicted beyond it,
+		// zero means no limit, see NewBoundedStore
+		limit   int
+		onEvict func(name Name, comp reflect.Value)
+
+		// linksMu guards the usage of the components and their dependents
+		linksMu sync.Mutex
+		// lru lists the evictable components, from the most recently used to the least recently used one
+		lru *list.List
+		// dependents are the names of the stored components built with a component, keyed by its name
+		dependents map[Name]map[Name]struct{}
+	}
+
+	storedComponent struct {
+		name         Name
+		value        reflect.Value
+		expiresAt    time.Time
+		order        uint64 // instantiation order, a component is always stored after its dependencies
+		onClose      closeHook
+		dependencies []Name        // names of the stored components it was built with
+		used         *list.Element // position in the lru list, nil if not evictable
+	}
+
+	// closeHook releases a component, replacing its Close or Stop method, see OnClose.
+	closeHook func(ctx context.Context, comp reflect.Value) error
+)
 
 func NewStore() *Store {
-	return &Store{}
+	return &Store{dependents: make(map[Name]map[Name]struct{})}
+}
+
+// NewBoundedStore creates a store keeping at most limit components, the least recently used ones are evicted
+// beyond it, the given callback is notified of them (if not nil), and they are closed once no resolution
+// is in flight. The stored components built with an evicted component are evicted along with it, the dependencies
+// of the component being stored are kept even beyond the limit, and the resolvers are never evicted.
+func NewBoundedStore(limit int, onEvict func(name Name, comp reflect.Value)) *Store {
+	return &Store{limit: limit, onEvict: onEvict, lru: list.New(), dependents: make(map[Name]map[Name]struct{})}
+}
+
+// empty creates an empty store with the same limit.
+func (s *Store) empty() *Store {
+	return NewBoundedStore(s.limit, s.onEvict)
 }
 
 func (s *Store) Put(name Name, comp reflect.Value) {
-	s.inner.Store(name, comp)
+	s.PutWithTTL(name, comp, 0)
+}
+
+// PutWithTTL stores the component, which expires after the given ttl, a zero ttl means the component never expires.
+func (s *Store) PutWithTTL(name Name, comp reflect.Value, ttl time.Duration) {
+	s.put(name, comp, ttl, nil, nil)
+}
+
+// put stores the component, built with the given stored components, which are retired along with it.
+func (s *Store) put(name Name, comp reflect.Value, ttl time.Duration, onClose closeHook, dependencies []Name) {
+	stored := &storedComponent{
+		name:         name,
+		value:        comp,
+		order:        s.sequence.Add(1),
+		onClose:      onClose,
+		dependencies: dependencies,
+	}
+	if ttl > 0 {
+		stored.expiresAt = time.Now().Add(ttl)
+	}
+
+	s.linksMu.Lock()
+	defer s.linksMu.Unlock()
+	if previous, replaced := s.inner.Swap(name, stored); replaced {
+		s.unlink(previous.(*storedComponent))
+	}
+	for _, dependency := range dependencies {
+		if s.dependents[dependency] == nil {
+			s.dependents[dependency] = make(map[Name]struct{})
+		}
+		s.dependents[dependency][name] = struct{}{}
+	}
+	if s.limit > 0 && (!comp.IsValid() || comp.Type() != resolverType) {
+		stored.used = s.lru.PushFront(stored)
+		s.evictOverLimit(stored)
+	}
+}
+
+// evictOverLimit retires the least recently used components beyond the limit of the store, except the component
+// just stored and its dependencies, as it is in use, linksMu must be held.
+func (s *Store) evictOverLimit(stored *storedComponent) {
+	kept := make(map[Name]struct{}, len(stored.dependencies)+1)
+	kept[stored.name] = struct{}{}
+	for _, dependency := range stored.dependencies {
+		kept[dependency] = struct{}{}
+	}
+
+	for element := s.lru.Back(); element != nil && s.lru.Len() > s.limit; {
+		candidate := element.Value.(*storedComponent)
+		element = element.Prev()
+		if _, found := kept[candidate.name]; found {
+			continue
+		}
+		if !s.inner.CompareAndDelete(candidate.name, candidate) {
+			s.unlink(candidate)
+			continue
+		}
+		for _, evicted := range s.withDependents(candidate) {
+			if s.onEvict != nil {
+				s.onEvict(evicted.name, evicted.value)
+			}
+		}
+		// the dependents removed along with the candidate might have been the previous element
+		element = s.lru.Back()
+	}
+}
+
+// withDependents unlinks the component removed from the store, and removes the stored components built with it,
+// recursively, all of them being retired, from the dependents to the component, linksMu must be held.
+func (s *Store) withDependents(removed *storedComponent) []*storedComponent {
+	all := []*storedComponent{removed}
+	for i := 0; i < len(all); i++ {
+		current := all[i]
+		s.unlink(current)
+		for dependentName := range s.dependents[current.name] {
+			raw, found := s.inner.Load(dependentName)
+			if !found {
+				continue
+			}
+			dependent := raw.(*storedComponent)
+			if slices.Contains(dependent.dependencies, current.name) && s.inner.CompareAndDelete(dependentName, raw) {
+				all = append(all, dependent)
+			}
+		}
+		delete(s.dependents, current.name)
+	}
+	for i := len(all) - 1; i >= 0; i-- {
+		s.retire(all[i])
+	}
+	return all
+}
+
+// unlink removes the component from the lru list, linksMu must be held.
+func (s *Store) unlink(stored *storedComponent) {
+	if stored.used != nil {
+		s.lru.Remove(stored.used)
+		stored.used = nil
+	}
+}
+
+// Track keeps a reference to a closeable component which is not stored, so it is closed with the store.
+func (s *Store) Track(name Name, comp reflect.Value) {
+	s.track(name, comp, nil)
+}
+
+func (s *Store) track(name Name, comp reflect.Value, onClose closeHook) {
+	if onClose == nil && !isCloseable(comp) {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.tracked = append(s.tracked, &storedComponent{name: name, value: comp, order: s.sequence.Add(1), onClose: onClose})
 }
 
 func (s *Store) Get(name Name) (comp reflect.Value, found bool) {
 	raw, found := s.inner.Load(name)
-	if found {
-		return raw.(reflect.Value), true
+	if !found {
+		return reflect.Value{}, false
 	}
 
-	return reflect.Value{}, false
+	stored := raw.(*storedComponent)
+	if stored.isExpired() {
+		s.linksMu.Lock()
+		defer s.linksMu.Unlock()
+		// the components built with the expired one are expired along with it, so they are not served anymore
+		if s.inner.CompareAndDelete(name, raw) {
+			s.withDependents(stored)
+		}
+		return reflect.Value{}, false
+	}
+	if s.limit > 0 {
+		s.linksMu.Lock()
+		defer s.linksMu.Unlock()
+		if stored.used != nil {
+			s.lru.MoveToFront(stored.used)
+		}
+	}
+	return stored.value, true
 }
 
-func (s *Store) Close() error {
-	closeErrors := make([]error, 0)
-	s.inner.Range(func(name, rawComp any) bool {
-		comp := rawComp.(reflect.Value)
-		if comp.IsValid() && comp.Type().Implements(CloseableType) {
-			out := comp.MethodByName("Close").Call(nil)
-			if len(out) != 1 || !out[0].IsNil() {
-				closeErrors = append(
-					closeErrors,
-					fmt.Errorf("failed to close component %s:\n\t%v", name, out[0].Interface()),
-				)
+// remove removes the component from the store, without closing it.
+func (s *Store) remove(name Name) (*storedComponent, bool) {
+	s.linksMu.Lock()
+	defer s.linksMu.Unlock()
+	raw, found := s.inner.LoadAndDelete(name)
+	if !found {
+		return nil, false
+	}
+	stored := raw.(*storedComponent)
+	s.unlink(stored)
+	return stored, true
+}
+
+// removeAll removes the component from the store along with the components built for each consumer (see PerConsumer),
+// without closing them.
+func (s *Store) removeAll(name Name) []*storedComponent {
+	var removed []*storedComponent
+	if stored, found := s.remove(name); found {
+		removed = append(removed, stored)
+	}
+	s.inner.Range(func(key, raw any) bool {
+		built := key.(Name)
+		consumer := built.consumer
+		built.consumer = ""
+		if consumer != "" && built == name {
+			if stored, found := s.remove(key.(Name)); found {
+				removed = append(removed, stored)
 			}
 		}
+		return true
+	})
+	return removed
+}
+
+// restore puts back a component removed from the store.
+func (s *Store) restore(stored *storedComponent) {
+	s.linksMu.Lock()
+	defer s.linksMu.Unlock()
+	s.inner.Store(stored.name, stored)
+	if s.limit > 0 && (!stored.value.IsValid() || stored.value.Type() != resolverType) {
+		stored.used = s.lru.PushFront(stored)
+	}
+}
+
+func (s *Store) retire(stored *storedComponent) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.retired = append(s.retired, stored)
+}
+
+// CloseRetired closes the expired and evicted components, the errors are reported when closing the store.
+func (s *Store) CloseRetired() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// a component is closed before its dependencies
+	slices.SortFunc(s.retired, func(a, b *storedComponent) int {
+		return cmp.Compare(b.order, a.order)
+	})
+	for _, stored := range s.retired {
+		if err := stored.close(context.Background()); err != nil {
+			s.retiredErrors = append(s.retiredErrors, err)
+		}
+	}
+	s.retired = nil
+}
+
+func (s *Store) Close() error {
+	return s.CloseWithContext(context.Background())
+}
+
+// CloseWithContext closes the components in the reverse order of their instantiation, so a component is
+// closed before its dependencies. The components not closed yet when the context is done are left open.
+func (s *Store) CloseWithContext(ctx context.Context) error {
+	s.CloseRetired()
+
+	components := make([]*storedComponent, 0)
+	s.inner.Range(func(_, raw any) bool {
+		components = append(components, raw.(*storedComponent))
 		return true // continue iteration
 	})
 
-	return errors.Join(closeErrors...)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	components = append(components, s.tracked...)
+	s.tracked = nil
+	slices.SortFunc(components, func(a, b *storedComponent) int {
+		return cmp.Compare(b.order, a.order)
+	})
+
+	closeErrors := make([]error, 0)
+	for i, stored := range components {
+		if err := context.Cause(ctx); err != nil {
+			closeErrors = append(closeErrors, fmt.Errorf("%d components were not closed:\n\t%w", len(components)-i, err))
+			break
+		}
+		if err := stored.close(ctx); err != nil {
+			closeErrors = append(closeErrors, err)
+		}
+	}
+	return errors.Join(append(s.retiredErrors, closeErrors...)...)
 }
 
 func (s *Store) ListNames() []Name {
@@ -55,3 +319,35 @@ func (s *Store) ListNames() []Name {
 	})
 	return names
 }
+
+func (c *storedComponent) isExpired() bool {
+	return !c.expiresAt.IsZero() && time.Now().After(c.expiresAt)
+}
+
+func (c *storedComponent) close(ctx context.Context) error {
+	return closeComponent(ctx, c.name, c.value, c.onClose)
+}
+
+func isCloseable(comp reflect.Value) bool {
+	return comp.IsValid() && (comp.Type().Implements(CloseableType) || comp.Type().Implements(StoppableType))
+}
+
+// closeComponent releases the component using the hook if any, otherwise using its Stop or Close method.
+func closeComponent(ctx context.Context, name Name, comp reflect.Value, onClose closeHook) error {
+	if !comp.IsValid() {
+		return nil
+	}
+
+	var err error
+	if onClose != nil {
+		err = onClose(ctx, comp)
+	} else if stoppable, ok := comp.Interface().(Stoppable); ok && comp.Type().Implements(StoppableType) {
+		err = stoppable.Stop(ctx)
+	} else if closeable, ok := comp.Interface().(Closeable); ok && comp.Type().Implements(CloseableType) {
+		err = closeable.Close()
+	}
+	if err != nil {
+		return fmt.Errorf("failed to close component %s:\n\t%w", name, err)
+	}
+	return nil
+}
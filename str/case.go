@@ -1,41 +1,153 @@
 package str
 
-import "strings"
+import (
+	"slices"
+	"strings"
+	"unicode"
 
-// ToScreamingSnakeCase transforms a given string into screaming snake case format
-func ToScreamingSnakeCase(in string) string {
-	in = strings.TrimSpace(in)
-	if len(in) == 0 {
-		return in
+	"github.com/a-peyrard/godi/option"
+)
+
+// DefaultAcronyms are the acronyms known by the case conversions, unless replaced with WithAcronyms.
+var DefaultAcronyms = []string{
+	"ACL", "API", "ASCII", "CPU", "CSS", "DNS", "EOF", "GUID", "HTML", "HTTP", "HTTPS", "ID", "IP", "JSON", "JWT",
+	"LHS", "OS", "QPS", "RAM", "RHS", "RPC", "SLA", "SMTP", "SQL", "SSH", "TCP", "TLS", "TTL", "UDP", "UI", "UID",
+	"URI", "URL", "UUID", "VM", "XML", "XMPP", "XSRF", "XSS", "YAML",
+}
+
+// CaseOptions configures the case conversions.
+type CaseOptions struct {
+	acronyms []string
+}
+
+// WithAcronyms replaces the known acronyms, e.g. WithAcronyms(append(str.DefaultAcronyms, "GRPC")...).
+//
+// The words are always split at the end of a run of capitals followed by lower-case letters, e.g. XMLHttpRequest
+// gives XML, Http and Request, the acronyms are only needed to split consecutive acronyms (HTTPAPI), to keep the
+// plural of an acronym as a single word (IDs), and to write them in capitals in camel case (userID).
+func WithAcronyms(acronyms ...string) option.Option[CaseOptions] {
+	return func(opts *CaseOptions) {
+		opts.acronyms = acronyms
 	}
+}
+
+// ToScreamingSnakeCase transforms a given string into screaming snake case format, e.g. APIKey gives API_KEY.
+func ToScreamingSnakeCase(in string, opts ...option.Option[CaseOptions]) string {
+	return joinWords(in, "_", strings.ToUpper, opts)
+}
 
-	sb := strings.Builder{}
-	sb.Grow(len(in) + len(in)/3) // estimate space for underscores
+// ToSnakeCase transforms a given string into snake case format, e.g. APIKey gives api_key.
+func ToSnakeCase(in string, opts ...option.Option[CaseOptions]) string {
+	return joinWords(in, "_", strings.ToLower, opts)
+}
+
+// ToKebabCase transforms a given string into kebab case format, e.g. APIKey gives api-key.
+func ToKebabCase(in string, opts ...option.Option[CaseOptions]) string {
+	return joinWords(in, "-", strings.ToLower, opts)
+}
 
-	for i, b := range []byte(in) {
-		shouldWrite := true
-		needsSeparator := false
+// ToCamelCase transforms a given string into camel case format, the acronyms being written in capitals but for the
+// first word, as Go does, e.g. user_id gives userID and api_key gives apiKey.
+func ToCamelCase(in string, opts ...option.Option[CaseOptions]) string {
+	options := buildCaseOptions(opts)
+	words := splitWords(in, options.acronyms)
 
+	var sb strings.Builder
+	sb.Grow(len(in))
+	for i, word := range words {
 		switch {
-		case 'a' <= b && b <= 'z':
-			b -= 'a' - 'A' // convert to uppercase
-		case 'A' <= b && b <= 'Z':
-			needsSeparator = true
-		case b == '_' || b == '-':
-			shouldWrite = false
-			needsSeparator = true
-		case '0' <= b && b <= '9':
-			needsSeparator = true
+		case i == 0:
+			sb.WriteString(strings.ToLower(word))
+		case isAcronym(word, options.acronyms):
+			sb.WriteString(strings.ToUpper(word))
+		case isAcronymPlural(word, options.acronyms):
+			sb.WriteString(strings.ToUpper(word[:len(word)-1]) + "s")
+		default:
+			runes := []rune(strings.ToLower(word))
+			runes[0] = unicode.ToUpper(runes[0])
+			sb.WriteString(string(runes))
 		}
+	}
+	return sb.String()
+}
+
+func buildCaseOptions(opts []option.Option[CaseOptions]) *CaseOptions {
+	return option.Build(&CaseOptions{acronyms: DefaultAcronyms}, opts...)
+}
 
-		if i > 0 && needsSeparator {
-			sb.WriteByte('_')
+func joinWords(in string, separator string, transform func(string) string, opts []option.Option[CaseOptions]) string {
+	words := splitWords(in, buildCaseOptions(opts).acronyms)
+	for i, word := range words {
+		words[i] = transform(word)
+	}
+	return strings.Join(words, separator)
+}
+
+// splitWords splits the string into its words, separated by underscores, dashes or spaces, or by a change of case
+// or a number, e.g. "XMLHttpRequest2Go" gives XML, Http, Request, 2 and Go.
+func splitWords(in string, acronyms []string) []string {
+	runes := []rune(strings.TrimSpace(in))
+
+	var (
+		words []string
+		start int
+	)
+	endWord := func(end int) {
+		if end > start {
+			words = append(words, splitAcronyms(string(runes[start:end]), acronyms)...)
+		}
+		start = end
+	}
+	for i, r := range runes {
+		if r == '_' || r == '-' || unicode.IsSpace(r) {
+			endWord(i)
+			start = i + 1
+			continue
+		}
+		if i == start {
+			continue
+		}
+		prev := runes[i-1]
+		switch {
+		case unicode.IsLower(prev) && unicode.IsUpper(r),
+			unicode.IsLetter(prev) && unicode.IsDigit(r),
+			unicode.IsDigit(prev) && unicode.IsUpper(r):
+			endWord(i)
+		case unicode.IsUpper(prev) && unicode.IsUpper(r) && i+1 < len(runes) && unicode.IsLower(runes[i+1]):
+			// the last capital of a run starts the next word, e.g. the H of XMLHttp, unless it is the plural of an acronym
+			if !isAcronymPlural(string(runes[start:i+2]), acronyms) || (i+2 < len(runes) && unicode.IsLower(runes[i+2])) {
+				endWord(i)
+			}
 		}
+	}
+	endWord(len(runes))
+	return words
+}
 
-		if shouldWrite {
-			sb.WriteByte(b)
+// splitAcronyms splits a word made of consecutive acronyms, e.g. HTTPAPI gives HTTP and API, the word is kept
+// as it is if it is not made of known acronyms only.
+func splitAcronyms(word string, acronyms []string) []string {
+	if len(word) < 2 || strings.ToUpper(word) != word || isAcronym(word, acronyms) {
+		return []string{word}
+	}
+	for _, acronym := range acronyms {
+		if rest, found := strings.CutPrefix(word, strings.ToUpper(acronym)); found && rest != "" {
+			if split := splitAcronyms(rest, acronyms); len(split) > 1 || isAcronym(rest, acronyms) {
+				return append([]string{word[:len(acronym)]}, split...)
+			}
 		}
 	}
+	return []string{word}
+}
 
-	return sb.String()
+func isAcronym(word string, acronyms []string) bool {
+	return slices.ContainsFunc(acronyms, func(acronym string) bool {
+		return strings.EqualFold(acronym, word)
+	})
+}
+
+// isAcronymPlural checks if the word is the plural of an acronym, e.g. IDs.
+func isAcronymPlural(word string, acronyms []string) bool {
+	singular, plural := strings.CutSuffix(word, "s")
+	return plural && singular != "" && strings.ToUpper(singular) == singular && isAcronym(singular, acronyms)
 }
@@ -61,7 +61,7 @@ func TestToScreamingSnakeCase(t *testing.T) {
 		assert.Equal(t, "VERSION_2_RELEASE", result)
 	})
 
-	t.Run("it should handle consecutive uppercase letters", func(t *testing.T) {
+	t.Run("it should handle acronyms", func(t *testing.T) {
 		// GIVEN
 		input := "XMLHttpRequest"
 
@@ -69,7 +69,7 @@ func TestToScreamingSnakeCase(t *testing.T) {
 		result := ToScreamingSnakeCase(input)
 
 		// THEN
-		assert.Equal(t, "X_M_L_HTTP_REQUEST", result)
+		assert.Equal(t, "XML_HTTP_REQUEST", result)
 	})
 
 	t.Run("it should handle single characters", func(t *testing.T) {
@@ -131,13 +131,19 @@ func TestToScreamingSnakeCase(t *testing.T) {
 		// GIVEN
 		testCases := map[string]string{
 			"customerId":     "CUSTOMER_ID",
-			"XMLParser":      "X_M_L_PARSER",
+			"XMLParser":      "XML_PARSER",
 			"httpStatusCode": "HTTP_STATUS_CODE",
 			"fooBar":         "FOO_BAR",
 			"FooBar":         "FOO_BAR",
 			"foo_bar":        "FOO_BAR",
 			"foo-bar":        "FOO_BAR",
-			"API2Response":   "A_P_I_2_RESPONSE",
+			"API2Response":   "API_2_RESPONSE",
+			"APIKey":         "API_KEY",
+			"HTTPStatusCode": "HTTP_STATUS_CODE",
+			"userIDs":        "USER_IDS",
+			"HTTPAPIClient":  "HTTP_API_CLIENT",
+			"URL":            "URL",
+			"ID":             "ID",
 		}
 
 		for input, expected := range testCases {
@@ -149,3 +155,80 @@ func TestToScreamingSnakeCase(t *testing.T) {
 		}
 	})
 }
+
+func TestToSnakeCase(t *testing.T) {
+	t.Run("it should convert to snake_case", func(t *testing.T) {
+		// GIVEN
+		testCases := map[string]string{
+			"XMLHttpRequest": "xml_http_request",
+			"APIKey":         "api_key",
+			"customerId":     "customer_id",
+			"foo-bar":        "foo_bar",
+			"version2":       "version_2",
+		}
+
+		for input, expected := range testCases {
+			// WHEN
+			result := ToSnakeCase(input)
+
+			// THEN
+			assert.Equal(t, expected, result, "Failed for input: %s", input)
+		}
+	})
+}
+
+func TestToKebabCase(t *testing.T) {
+	t.Run("it should convert to kebab-case", func(t *testing.T) {
+		// GIVEN
+		testCases := map[string]string{
+			"XMLHttpRequest": "xml-http-request",
+			"api_key":        "api-key",
+			"FooBar":         "foo-bar",
+		}
+
+		for input, expected := range testCases {
+			// WHEN
+			result := ToKebabCase(input)
+
+			// THEN
+			assert.Equal(t, expected, result, "Failed for input: %s", input)
+		}
+	})
+}
+
+func TestToCamelCase(t *testing.T) {
+	t.Run("it should convert to camelCase, writing the acronyms in capitals", func(t *testing.T) {
+		// GIVEN
+		testCases := map[string]string{
+			"user_id":          "userID",
+			"api_key":          "apiKey",
+			"HTTP_STATUS_CODE": "httpStatusCode",
+			"XMLHttpRequest":   "xmlHTTPRequest",
+			"foo-bar":          "fooBar",
+			"userIDs":          "userIDs",
+			"":                 "",
+		}
+
+		for input, expected := range testCases {
+			// WHEN
+			result := ToCamelCase(input)
+
+			// THEN
+			assert.Equal(t, expected, result, "Failed for input: %s", input)
+		}
+	})
+}
+
+func TestWithAcronyms(t *testing.T) {
+	t.Run("it should use the configured acronyms", func(t *testing.T) {
+		// WHEN
+		snake := ToScreamingSnakeCase("GRPCAPIServer", WithAcronyms("grpc", "API"))
+		camel := ToCamelCase("grpc_client", WithAcronyms("GRPC"))
+		withoutAcronyms := ToCamelCase("user_id", WithAcronyms())
+
+		// THEN
+		assert.Equal(t, "GRPC_API_SERVER", snake)
+		assert.Equal(t, "grpcClient", camel)
+		assert.Equal(t, "userId", withoutAcronyms)
+	})
+}
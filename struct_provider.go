@@ -0,0 +1,229 @@
+package godi
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/a-peyrard/godi/option"
+)
+
+const injectTag = "godi"
+
+type (
+	// StructProvider provides a struct whose fields are injected according to their godi tags, see ResolveInto.
+	StructProvider[T any] struct {
+		name   Name
+		fields []injectedField
+
+		priority    int
+		description string
+	}
+
+	injectedField struct {
+		index   []int
+		request Request
+	}
+)
+
+// NewStructProvider creates a provider for *T, filling the fields of T tagged with godi (see ResolveInto).
+//
+// The provided component is named after the struct type, unless Named is given.
+func NewStructProvider[T any](opts ...option.Option[RegistrableOptions]) (*StructProvider[T], error) {
+	return newStructProvider[T](false, opts...)
+}
+
+// NewComponentProvider creates a provider for *T like NewStructProvider, but all the exported fields of T are
+// injected, by type unless their godi tag says otherwise, and the fields tagged with `godi:"-"` are left untouched.
+//
+// This is the provider the generator registers for the structs annotated with @component.
+func NewComponentProvider[T any](opts ...option.Option[RegistrableOptions]) (*StructProvider[T], error) {
+	return newStructProvider[T](true, opts...)
+}
+
+// MustNewComponentProvider is like NewComponentProvider, but panics if the provider can not be created.
+func MustNewComponentProvider[T any](opts ...option.Option[RegistrableOptions]) *StructProvider[T] {
+	provider, err := NewComponentProvider[T](opts...)
+	if err != nil {
+		panic(err)
+	}
+	return provider
+}
+
+func newStructProvider[T any](exported bool, opts ...option.Option[RegistrableOptions]) (*StructProvider[T], error) {
+	structTyp := reflect.TypeOf((*T)(nil)).Elem()
+	if structTyp.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("type %s is not a struct", structTyp)
+	}
+	fields, err := injectedFieldsOf(structTyp, exported)
+	if err != nil {
+		return nil, err
+	}
+
+	options := option.Build(
+		&RegistrableOptions{
+			named: structTyp.Name(),
+		},
+		opts...,
+	)
+	return &StructProvider[T]{
+		name: Name{
+			name: options.named,
+			typ:  reflect.PointerTo(structTyp),
+		},
+		fields:      fields,
+		priority:    options.priority,
+		description: options.description,
+	}, nil
+}
+
+// ResolveInto fills the fields of the struct pointed by target which are tagged with godi.
+//
+// The tag is a comma separated list of properties:
+//   - name=foo injects the component named foo, otherwise the component is looked up by type
+//   - optional leaves the field untouched if the component is not found
+//   - multiple injects all the components matching the element type of a slice or map field
+//
+// e.g. `godi:"name=db.primary,optional"`, or `godi:""` to inject by type.
+func ResolveInto(resolver *Resolver, target any) error {
+	return resolveInto(nil, resolver, target)
+}
+
+// ResolveIntoCtx fills the fields of the struct pointed by target which are tagged with godi, aborting the resolution
+// once the context is done, see ResolveInto and ResolveCtx.
+func ResolveIntoCtx(ctx context.Context, resolver *Resolver, target any) error {
+	return resolveInto(ctx, resolver, target)
+}
+
+func resolveInto(ctx context.Context, resolver *Resolver, target any) error {
+	targetVal := reflect.ValueOf(target)
+	if targetVal.Kind() != reflect.Pointer || targetVal.IsNil() || targetVal.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("target must be a non nil pointer to a struct, got %T", target)
+	}
+
+	fields, err := injectedFieldsOf(targetVal.Elem().Type(), false)
+	if err != nil {
+		return err
+	}
+	for _, field := range fields {
+		req := field.request
+		req.ctx = ctx
+		val, found, err := resolver.resolve(req)
+		if err != nil {
+			return fmt.Errorf("failed to inject field %s of %T:\n\t%w", targetVal.Elem().Type().FieldByIndex(field.index).Name, target, err)
+		}
+		if found {
+			targetVal.Elem().FieldByIndex(field.index).Set(val)
+		}
+	}
+	return nil
+}
+
+func (s *StructProvider[T]) CanProvide(name Name) bool {
+	return name.name == s.name.name && matchType(name.typ, s.name.typ)
+}
+
+func (s *StructProvider[T]) Provide(_ Name, dependencies []reflect.Value) (comp reflect.Value, err error) {
+	target := reflect.New(s.name.typ.Elem())
+	for idx, field := range s.fields {
+		if dependencies[idx].IsValid() {
+			target.Elem().FieldByIndex(field.index).Set(dependencies[idx])
+		}
+	}
+	return target, nil
+}
+
+func (s *StructProvider[T]) Dependencies() []Request {
+	requests := make([]Request, len(s.fields))
+	for idx, field := range s.fields {
+		requests[idx] = field.request
+	}
+	return requests
+}
+
+func (s *StructProvider[T]) ListProvidableNames() []Name {
+	return []Name{s.name}
+}
+
+func (s *StructProvider[T]) Priority() int {
+	return s.priority
+}
+
+func (s *StructProvider[T]) Description() string {
+	return s.description
+}
+
+func (s *StructProvider[T]) String() string {
+	return fmt.Sprintf("StructProvider(%s)", s.name.String())
+}
+
+// injectedFieldsOf lists the fields tagged with godi, or all the exported fields if exported is true.
+func injectedFieldsOf(structTyp reflect.Type, exported bool) ([]injectedField, error) {
+	var fields []injectedField
+	for i := 0; i < structTyp.NumField(); i++ {
+		field := structTyp.Field(i)
+		if isMarker(field) {
+			continue
+		}
+		tag, tagged := field.Tag.Lookup(injectTag)
+		if exported && !tagged && field.IsExported() {
+			tag, tagged = "", true
+			if named, ok := field.Tag.Lookup(nameTag); ok {
+				tag = "name=" + named
+			}
+		}
+		if !tagged || tag == "-" {
+			continue
+		}
+		if !field.IsExported() {
+			return nil, fmt.Errorf("field %s of %s is tagged for injection, but is not exported", field.Name, structTyp)
+		}
+
+		dep, err := parseInjectTag(tag)
+		if err != nil {
+			return nil, fmt.Errorf("invalid tag on field %s of %s:\n\t%w", field.Name, structTyp, err)
+		}
+		request, err := dep.build(field.Type)
+		if err != nil {
+			return nil, fmt.Errorf("failed to build dependency for field %s of %s:\n\t%w", field.Name, structTyp, err)
+		}
+		fields = append(fields, injectedField{index: field.Index, request: request})
+	}
+	return fields, nil
+}
+
+func parseInjectTag(tag string) (dependency, error) {
+	var (
+		named    string
+		optional bool
+		multiple bool
+	)
+	for _, property := range strings.Split(tag, ",") {
+		key, value, _ := strings.Cut(strings.TrimSpace(property), "=")
+		switch key {
+		case "":
+		case "name":
+			named = value
+		case "optional":
+			optional = true
+		case "multiple":
+			multiple = true
+		default:
+			return nil, fmt.Errorf("unknown property %q in tag %q", key, tag)
+		}
+	}
+
+	switch {
+	case multiple:
+		return Inject.Multiple(), nil
+	case named != "" && optional:
+		return Inject.Named(named).Optional(), nil
+	case named != "":
+		return Inject.Named(named), nil
+	case optional:
+		return Inject.Auto().Optional(), nil
+	default:
+		return Inject.Auto(), nil
+	}
+}
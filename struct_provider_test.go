@@ -0,0 +1,130 @@
+package godi
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type injectedHandler struct {
+	Service   *TestService    `godi:""`
+	Repo      *TestRepository `godi:"name=repo.primary"`
+	Fallback  *TestRepository `godi:"name=repo.fallback,optional"`
+	Names     []string        `godi:"multiple"`
+	NotTagged string
+}
+
+func TestResolveInto(t *testing.T) {
+	t.Run("it should inject the tagged fields", func(t *testing.T) {
+		// GIVEN
+		resolver := New()
+		resolver.MustRegister(NewTestService)
+		resolver.MustRegister(func() *TestRepository { return &TestRepository{Data: "primary"} }, Named("repo.primary"))
+		resolver.MustRegister(ToStaticProvider("first"), Named("name.first"))
+		handler := injectedHandler{NotTagged: "untouched"}
+
+		// WHEN
+		err := ResolveInto(resolver, &handler)
+
+		// THEN
+		require.NoError(t, err)
+		assert.NotNil(t, handler.Service)
+		assert.Equal(t, "primary", handler.Repo.Data)
+		assert.Nil(t, handler.Fallback)
+		assert.Equal(t, []string{"first"}, handler.Names)
+		assert.Equal(t, "untouched", handler.NotTagged)
+	})
+
+	t.Run("it should fail if a mandatory field can not be resolved", func(t *testing.T) {
+		// GIVEN
+		resolver := New()
+		resolver.MustRegister(NewTestService)
+		handler := injectedHandler{}
+
+		// WHEN
+		err := ResolveInto(resolver, &handler)
+
+		// THEN
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "failed to inject field Repo")
+	})
+
+	t.Run("it should reject targets which are not pointers to structs", func(t *testing.T) {
+		// WHEN
+		err := ResolveInto(New(), injectedHandler{})
+
+		// THEN
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "target must be a non nil pointer to a struct")
+	})
+}
+
+func TestStructProvider(t *testing.T) {
+	t.Run("it should provide the struct with its fields injected", func(t *testing.T) {
+		// GIVEN
+		resolver := New()
+		resolver.MustRegister(NewTestService)
+		resolver.MustRegister(NewTestRepository, Named("repo.primary"))
+		provider, err := NewStructProvider[injectedHandler](Named("handler"))
+		require.NoError(t, err)
+		resolver.MustRegister(provider)
+
+		// WHEN
+		handler, err := ResolveNamed[*injectedHandler](resolver, "handler")
+
+		// THEN
+		require.NoError(t, err)
+		assert.NotNil(t, handler.Service)
+		assert.NotNil(t, handler.Repo)
+	})
+
+	t.Run("it should reject unknown tag properties", func(t *testing.T) {
+		// GIVEN
+		type invalid struct {
+			Service *TestService `godi:"nmae=foo"`
+		}
+
+		// WHEN
+		_, err := NewStructProvider[invalid]()
+
+		// THEN
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), `unknown property "nmae"`)
+	})
+}
+
+func TestComponentProvider(t *testing.T) {
+	t.Run("it should inject all the exported fields", func(t *testing.T) {
+		// GIVEN
+		type component struct {
+			Service  *TestService
+			Repo     *TestRepository `godi:"name=repo.primary"`
+			Skipped  *TestRepository `godi:"-"`
+			internal *TestService
+		}
+		resolver := New()
+		resolver.MustRegister(NewTestService)
+		resolver.MustRegister(NewTestRepository, Named("repo.primary"))
+		resolver.MustRegister(MustNewComponentProvider[component](Named("component")))
+
+		// WHEN
+		comp, err := ResolveNamed[*component](resolver, "component")
+
+		// THEN
+		require.NoError(t, err)
+		assert.NotNil(t, comp.Service)
+		assert.NotNil(t, comp.Repo)
+		assert.Nil(t, comp.Skipped)
+		assert.Nil(t, comp.internal)
+	})
+
+	t.Run("it should reject types which are not structs", func(t *testing.T) {
+		// WHEN
+		_, err := NewComponentProvider[string]()
+
+		// THEN
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "is not a struct")
+	})
+}
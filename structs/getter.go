@@ -2,30 +2,43 @@ package structs
 
 import (
 	"fmt"
+	"github.com/a-peyrard/godi/option"
 	"github.com/a-peyrard/godi/reflectutils"
 	"reflect"
+	"strconv"
 	"strings"
 )
 
+// PathOptions configures how the paths of the fields are matched.
+type PathOptions struct {
+	ignoreCase bool
+}
+
+// IgnoreCase matches the struct fields and the map keys case-insensitively, e.g. "server.port" gets the field
+// Server.Port, as the paths coming from YAML or env sources are often lower-cased. The exact matches come first.
+func IgnoreCase() option.Option[PathOptions] {
+	return func(opts *PathOptions) {
+		opts.ignoreCase = true
+	}
+}
+
 // Get retrieves the value for the specified field from the provided struct.
 // Supports nested access using dot notation (e.g., "user.address.street").
-// Supports both struct fields and map keys.
-func Get(origin any, field string) (any, error) {
+// Supports struct fields, map keys and slice or array indexes (e.g., "servers.0.host").
+// Indexes and keys can also be given between brackets (e.g., "servers[0].host" or "labels[app.kubernetes.io/name]"),
+// the keys between brackets might contain dots.
+func Get(origin any, field string, opts ...option.Option[PathOptions]) (any, error) {
 	if origin == nil {
 		return nil, fmt.Errorf("cannot get field %s from nil origin", field)
 	}
-	if field == "" {
-		return nil, fmt.Errorf("field path cannot be empty")
+	options := option.Build(&PathOptions{}, opts...)
+	tokens, err := parsePath(field)
+	if err != nil {
+		return nil, err
 	}
-
-	tokens := strings.Split(field, ".")
 	current := origin
 
 	for i, token := range tokens {
-		if token == "" {
-			return nil, fmt.Errorf("empty token at position %d in field path %s", i, field)
-		}
-
 		valueOf := reflectutils.Deref(reflect.ValueOf(current))
 
 		if !valueOf.IsValid() {
@@ -34,14 +47,17 @@ func Get(origin any, field string) (any, error) {
 
 		switch valueOf.Kind() {
 		case reflect.Map:
-			mapValue := valueOf.MapIndex(reflect.ValueOf(token))
+			if valueOf.Type().Key().Kind() != reflect.String {
+				return nil, fmt.Errorf("cannot traverse map with keys of type %s at position %d in field path %s", valueOf.Type().Key(), i, field)
+			}
+			mapValue := valueOf.MapIndex(mapKey(valueOf, token, options))
 			if !mapValue.IsValid() {
 				return nil, fmt.Errorf("key %s not found in map at position %d in field path %s", token, i, field)
 			}
 			current = mapValue.Interface()
 
 		case reflect.Struct:
-			fieldValue := valueOf.FieldByName(token)
+			fieldValue := structField(valueOf, token, options)
 			if !fieldValue.IsValid() {
 				return nil, fmt.Errorf("field %s not found in struct %s at position %d in field path %s", token, valueOf.Type().Name(), i, field)
 			}
@@ -50,10 +66,107 @@ func Get(origin any, field string) (any, error) {
 			}
 			current = fieldValue.Interface()
 
+		case reflect.Slice, reflect.Array:
+			index, err := strconv.Atoi(token)
+			if err != nil {
+				return nil, fmt.Errorf("invalid index %s at position %d in field path %s", token, i, field)
+			}
+			if index < 0 || index >= valueOf.Len() {
+				return nil, fmt.Errorf("index %d out of range [0, %d) at position %d in field path %s", index, valueOf.Len(), i, field)
+			}
+			current = valueOf.Index(index).Interface()
+
 		default:
-			return nil, fmt.Errorf("cannot traverse field %s: expected struct or map but got %s at position %d in field path %s", token, valueOf.Kind(), i, field)
+			return nil, fmt.Errorf("cannot traverse field %s: expected struct, map or slice but got %s at position %d in field path %s", token, valueOf.Kind(), i, field)
 		}
 	}
 
 	return current, nil
 }
+
+// parsePath splits the path of a field into its tokens, the segments separated by dots, and the indexes or keys
+// between brackets, e.g. "servers[0].host" gives servers, 0 and host.
+func parsePath(field string) ([]string, error) {
+	if field == "" {
+		return nil, fmt.Errorf("field path cannot be empty")
+	}
+
+	var (
+		tokens  []string
+		segment strings.Builder
+		// bracketed is set after a closing bracket, a dot or another bracket must follow
+		bracketed bool
+	)
+	endSegment := func() error {
+		if segment.Len() == 0 {
+			return fmt.Errorf("empty token at position %d in field path %s", len(tokens), field)
+		}
+		tokens = append(tokens, segment.String())
+		segment.Reset()
+		return nil
+	}
+	for i := 0; i < len(field); i++ {
+		switch c := field[i]; {
+		case c == '.':
+			if bracketed {
+				bracketed = false
+				continue
+			}
+			if err := endSegment(); err != nil {
+				return nil, err
+			}
+		case c == '[':
+			// the path might start with a bracket, to index a slice or a map given as origin
+			if !bracketed && i > 0 {
+				if err := endSegment(); err != nil {
+					return nil, err
+				}
+			}
+			end := strings.IndexByte(field[i:], ']')
+			if end < 0 {
+				return nil, fmt.Errorf("unclosed bracket at offset %d in field path %s", i, field)
+			}
+			segment.WriteString(field[i+1 : i+end])
+			if err := endSegment(); err != nil {
+				return nil, err
+			}
+			i += end
+			bracketed = true
+		case bracketed:
+			return nil, fmt.Errorf("expected a dot or a bracket after the bracket at offset %d in field path %s", i-1, field)
+		default:
+			segment.WriteByte(c)
+		}
+	}
+	if !bracketed {
+		if err := endSegment(); err != nil {
+			return nil, err
+		}
+	}
+	return tokens, nil
+}
+
+// structField returns the field of the struct with the given name, invalid if not found.
+func structField(valueOf reflect.Value, name string, options *PathOptions) reflect.Value {
+	fieldValue := valueOf.FieldByName(name)
+	if fieldValue.IsValid() || !options.ignoreCase {
+		return fieldValue
+	}
+	return valueOf.FieldByNameFunc(func(fieldName string) bool {
+		return strings.EqualFold(fieldName, name)
+	})
+}
+
+// mapKey returns the key of the map with the given name, converted to the type of the keys of the map.
+func mapKey(valueOf reflect.Value, key string, options *PathOptions) reflect.Value {
+	keyValue := reflect.ValueOf(key).Convert(valueOf.Type().Key())
+	if !options.ignoreCase || valueOf.MapIndex(keyValue).IsValid() {
+		return keyValue
+	}
+	for _, k := range valueOf.MapKeys() {
+		if strings.EqualFold(k.String(), key) {
+			return k
+		}
+	}
+	return keyValue
+}
@@ -86,6 +86,35 @@ func TestGet(t *testing.T) {
 		assert.Equal(t, "456 Oak Ave", value)
 	})
 
+	t.Run("it should get element of slice by index", func(t *testing.T) {
+		// GIVEN
+		data := map[string]any{
+			"users": []User{{Name: "Alice"}, {Name: "Bob"}},
+		}
+
+		// WHEN
+		value, err := Get(data, "users.1.Name")
+
+		// THEN
+		require.NoError(t, err)
+		assert.Equal(t, "Bob", value)
+	})
+
+	t.Run("it should return error for out of range index", func(t *testing.T) {
+		// GIVEN
+		data := map[string]any{
+			"users": []User{{Name: "Alice"}},
+		}
+
+		// WHEN
+		value, err := Get(data, "users.1.Name")
+
+		// THEN
+		assert.Error(t, err)
+		assert.Nil(t, value)
+		assert.Contains(t, err.Error(), "index 1 out of range")
+	})
+
 	t.Run("it should return error for non-existent field", func(t *testing.T) {
 		// GIVEN
 		user := User{Name: "John"}
@@ -187,6 +216,87 @@ func TestGet(t *testing.T) {
 		// THEN
 		assert.Error(t, err)
 		assert.Nil(t, value)
-		assert.Contains(t, err.Error(), "expected struct or map but got string")
+		assert.Contains(t, err.Error(), "expected struct, map or slice but got string")
+	})
+
+	t.Run("it should get elements with the bracket notation", func(t *testing.T) {
+		// GIVEN
+		data := struct {
+			Servers []User
+			Labels  map[string]string
+		}{
+			Servers: []User{{Name: "a"}, {Name: "b"}, {Name: "c", Address: &Address{City: "Paris"}}},
+			Labels:  map[string]string{"env": "prod", "app.kubernetes.io/name": "godi"},
+		}
+
+		// WHEN
+		city, cityErr := Get(data, "Servers[2].Address.City")
+		env, envErr := Get(data, "Labels[env]")
+		name, nameErr := Get(data, "Labels[app.kubernetes.io/name]")
+
+		// THEN
+		require.NoError(t, cityErr)
+		assert.Equal(t, "Paris", city)
+		require.NoError(t, envErr)
+		assert.Equal(t, "prod", env)
+		require.NoError(t, nameErr)
+		assert.Equal(t, "godi", name)
+	})
+
+	t.Run("it should get elements of nested slices and of the origin with the bracket notation", func(t *testing.T) {
+		// GIVEN
+		matrix := [][]int{{1, 2}, {3, 4}}
+
+		// WHEN
+		value, err := Get(matrix, "[1][0]")
+
+		// THEN
+		require.NoError(t, err)
+		assert.Equal(t, 3, value)
+	})
+
+	t.Run("it should return error for invalid bracket notation", func(t *testing.T) {
+		// GIVEN
+		user := User{Name: "John"}
+
+		for path, expected := range map[string]string{
+			"Name[0":     "unclosed bracket",
+			"Name[]":     "empty token",
+			"Name[0]Age": "expected a dot or a bracket after the bracket",
+			"Name[0].":   "empty token",
+		} {
+			// WHEN
+			value, err := Get(user, path)
+
+			// THEN
+			assert.Nil(t, value, path)
+			assert.ErrorContains(t, err, expected, path)
+		}
+	})
+
+	t.Run("it should match fields and keys case-insensitively", func(t *testing.T) {
+		// GIVEN
+		data := map[string]any{
+			"Users": []User{{Name: "John", Address: &Address{City: "Lyon"}}},
+		}
+
+		// WHEN
+		value, err := Get(data, "users[0].address.city", IgnoreCase())
+
+		// THEN
+		require.NoError(t, err)
+		assert.Equal(t, "Lyon", value)
+	})
+
+	t.Run("it should match fields case-sensitively by default", func(t *testing.T) {
+		// GIVEN
+		user := User{Name: "John"}
+
+		// WHEN
+		value, err := Get(user, "name")
+
+		// THEN
+		assert.Error(t, err)
+		assert.Nil(t, value)
 	})
 }
@@ -0,0 +1,140 @@
+package structs
+
+import (
+	"fmt"
+	"github.com/a-peyrard/godi/option"
+	"reflect"
+	"strconv"
+)
+
+// Set sets the value of the specified field of the provided struct, with the same paths as Get
+// (e.g., "user.address.street", "servers[0].host" or "labels[env]").
+// The origin must be a pointer, or a map. The nil pointers and maps along the path are created,
+// but the slices are not grown, the index of an element must be in their range.
+// The value must be assignable to the field, or a number convertible to it.
+func Set(origin any, field string, value any, opts ...option.Option[PathOptions]) error {
+	if origin == nil {
+		return fmt.Errorf("cannot set field %s of nil origin", field)
+	}
+	options := option.Build(&PathOptions{}, opts...)
+	tokens, err := parsePath(field)
+	if err != nil {
+		return err
+	}
+
+	valueOf := reflect.ValueOf(origin)
+	if valueOf.Kind() != reflect.Pointer && valueOf.Kind() != reflect.Map {
+		return fmt.Errorf("cannot set field %s of %s, the origin must be a pointer or a map", field, valueOf.Type())
+	}
+	if valueOf.IsNil() {
+		return fmt.Errorf("cannot set field %s of nil origin", field)
+	}
+	return setAt(valueOf, tokens, 0, reflect.ValueOf(value), field, options)
+}
+
+// setAt sets the value at the path made of the tokens from the given position, in the current value,
+// which is either settable, or a reference (a pointer or a map).
+func setAt(current reflect.Value, tokens []string, i int, value reflect.Value, field string, options *PathOptions) error {
+	if i == len(tokens) {
+		return assign(current, value, field)
+	}
+	token := tokens[i]
+
+	switch current.Kind() {
+	case reflect.Pointer:
+		if current.IsNil() {
+			if !current.CanSet() {
+				return fmt.Errorf("encountered nil value at token %s (position %d) in field path %s", token, i, field)
+			}
+			current.Set(reflect.New(current.Type().Elem()))
+		}
+		return setAt(current.Elem(), tokens, i, value, field, options)
+
+	case reflect.Interface:
+		if current.IsNil() {
+			return fmt.Errorf("encountered nil value at token %s (position %d) in field path %s", token, i, field)
+		}
+		elem := current.Elem()
+		if elem.Kind() == reflect.Pointer || elem.Kind() == reflect.Map {
+			return setAt(elem, tokens, i, value, field, options)
+		}
+		// the value held by the interface is not addressable, a copy is modified and set back
+		if !current.CanSet() {
+			return fmt.Errorf("cannot set token %s (position %d) in field path %s, the %s is not addressable", token, i, field, elem.Type())
+		}
+		copied := reflect.New(elem.Type()).Elem()
+		copied.Set(elem)
+		if err := setAt(copied, tokens, i, value, field, options); err != nil {
+			return err
+		}
+		current.Set(copied)
+		return nil
+
+	case reflect.Map:
+		if current.Type().Key().Kind() != reflect.String {
+			return fmt.Errorf("cannot traverse map with keys of type %s at position %d in field path %s", current.Type().Key(), i, field)
+		}
+		if current.IsNil() {
+			if !current.CanSet() {
+				return fmt.Errorf("encountered nil value at token %s (position %d) in field path %s", token, i, field)
+			}
+			current.Set(reflect.MakeMap(current.Type()))
+		}
+		// the elements of a map are not addressable, a copy is modified and set back
+		key := mapKey(current, token, options)
+		elem := reflect.New(current.Type().Elem()).Elem()
+		if existing := current.MapIndex(key); existing.IsValid() {
+			elem.Set(existing)
+		}
+		if err := setAt(elem, tokens, i+1, value, field, options); err != nil {
+			return err
+		}
+		current.SetMapIndex(key, elem)
+		return nil
+
+	case reflect.Struct:
+		fieldValue := structField(current, token, options)
+		if !fieldValue.IsValid() {
+			return fmt.Errorf("field %s not found in struct %s at position %d in field path %s", token, current.Type().Name(), i, field)
+		}
+		if !fieldValue.CanSet() {
+			return fmt.Errorf("field %s in struct %s is not settable at position %d in field path %s", token, current.Type().Name(), i, field)
+		}
+		return setAt(fieldValue, tokens, i+1, value, field, options)
+
+	case reflect.Slice, reflect.Array:
+		index, err := strconv.Atoi(token)
+		if err != nil {
+			return fmt.Errorf("invalid index %s at position %d in field path %s", token, i, field)
+		}
+		if index < 0 || index >= current.Len() {
+			return fmt.Errorf("index %d out of range [0, %d) at position %d in field path %s", index, current.Len(), i, field)
+		}
+		return setAt(current.Index(index), tokens, i+1, value, field, options)
+
+	default:
+		return fmt.Errorf("cannot traverse field %s: expected struct, map or slice but got %s at position %d in field path %s", token, current.Kind(), i, field)
+	}
+}
+
+// assign sets the value to the target, converting the numbers, a nil value resets the target to its zero value.
+func assign(target reflect.Value, value reflect.Value, field string) error {
+	if !target.CanSet() {
+		return fmt.Errorf("cannot set field path %s, the %s is not addressable", field, target.Type())
+	}
+	switch {
+	case !value.IsValid():
+		target.Set(reflect.Zero(target.Type()))
+	case value.Type().AssignableTo(target.Type()):
+		target.Set(value)
+	case isNumber(value.Kind()) && isNumber(target.Kind()):
+		target.Set(value.Convert(target.Type()))
+	default:
+		return fmt.Errorf("cannot set field path %s of type %s to a value of type %s", field, target.Type(), value.Type())
+	}
+	return nil
+}
+
+func isNumber(kind reflect.Kind) bool {
+	return (kind >= reflect.Int && kind <= reflect.Float64) && kind != reflect.Uintptr
+}
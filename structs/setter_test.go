@@ -0,0 +1,147 @@
+package structs
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestSet(t *testing.T) {
+	type Address struct {
+		Street string
+		City   string
+	}
+
+	type User struct {
+		Name    string
+		Age     int
+		Address *Address
+		Tags    []string
+		Labels  map[string]string
+		private string
+	}
+
+	t.Run("it should set simple field of struct", func(t *testing.T) {
+		// GIVEN
+		user := &User{Name: "John"}
+
+		// WHEN
+		err := Set(user, "Name", "Jane")
+
+		// THEN
+		require.NoError(t, err)
+		assert.Equal(t, "Jane", user.Name)
+	})
+
+	t.Run("it should create the intermediate structs", func(t *testing.T) {
+		// GIVEN
+		user := &User{}
+
+		// WHEN
+		err := Set(user, "Address.City", "Paris")
+
+		// THEN
+		require.NoError(t, err)
+		require.NotNil(t, user.Address)
+		assert.Equal(t, "Paris", user.Address.City)
+	})
+
+	t.Run("it should set map keys, creating the nil maps", func(t *testing.T) {
+		// GIVEN
+		user := &User{}
+
+		// WHEN
+		err := Set(user, "Labels[app.kubernetes.io/name]", "godi")
+
+		// THEN
+		require.NoError(t, err)
+		assert.Equal(t, map[string]string{"app.kubernetes.io/name": "godi"}, user.Labels)
+	})
+
+	t.Run("it should set fields of structs stored in maps", func(t *testing.T) {
+		// GIVEN
+		users := map[string]User{"john": {Name: "John"}}
+
+		// WHEN
+		err := Set(users, "john.Age", 42)
+
+		// THEN
+		require.NoError(t, err)
+		assert.Equal(t, User{Name: "John", Age: 42}, users["john"])
+	})
+
+	t.Run("it should set slice elements", func(t *testing.T) {
+		// GIVEN
+		users := []User{{Name: "John"}, {Name: "Jane", Tags: []string{"a", "b"}}}
+
+		// WHEN
+		err := Set(&users, "[1].Tags[0]", "admin")
+
+		// THEN
+		require.NoError(t, err)
+		assert.Equal(t, []string{"admin", "b"}, users[1].Tags)
+	})
+
+	t.Run("it should set fields of structs held by interfaces", func(t *testing.T) {
+		// GIVEN
+		data := map[string]any{"user": User{Name: "John"}}
+
+		// WHEN
+		err := Set(data, "user.Name", "Jane")
+
+		// THEN
+		require.NoError(t, err)
+		assert.Equal(t, "Jane", data["user"].(User).Name)
+	})
+
+	t.Run("it should convert the numbers and reset to zero for nil", func(t *testing.T) {
+		// GIVEN
+		user := &User{Address: &Address{}}
+
+		// WHEN
+		ageErr := Set(user, "age", 42.0, IgnoreCase())
+		addressErr := Set(user, "Address", nil)
+
+		// THEN
+		require.NoError(t, ageErr)
+		assert.Equal(t, 42, user.Age)
+		require.NoError(t, addressErr)
+		assert.Nil(t, user.Address)
+	})
+
+	t.Run("it should return error for invalid assignments", func(t *testing.T) {
+		// GIVEN
+		user := &User{Tags: []string{"a"}}
+
+		for path, expected := range map[string]string{
+			"Name":          "cannot set field path Name of type string to a value of type int",
+			"NonExistent":   "field NonExistent not found in struct User",
+			"private":       "is not settable",
+			"Tags[1]":       "index 1 out of range",
+			"Name.Length":   "expected struct, map or slice but got string",
+			"Address..City": "empty token",
+		} {
+			// WHEN
+			err := Set(user, path, 42)
+
+			// THEN
+			assert.ErrorContains(t, err, expected, path)
+		}
+	})
+
+	t.Run("it should return error for an origin which is not a pointer nor a map", func(t *testing.T) {
+		// WHEN
+		err := Set(User{}, "Name", "Jane")
+
+		// THEN
+		assert.ErrorContains(t, err, "the origin must be a pointer or a map")
+	})
+
+	t.Run("it should return error for nil origin", func(t *testing.T) {
+		// WHEN
+		err := Set(nil, "Name", "Jane")
+
+		// THEN
+		assert.ErrorContains(t, err, "nil origin")
+	})
+}
@@ -0,0 +1,142 @@
+package godi
+
+import (
+	"cmp"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+)
+
+// maxNameSuggestions is the maximum number of names suggested for a mistyped name.
+const maxNameSuggestions = 3
+
+// withSuggestions adds "did you mean" hints to the error of a query which found nothing, e.g. for a typo in a name.
+func (r *Resolver) withSuggestions(err error, q query, results []*queryResult) error {
+	if len(results) > 0 {
+		return err
+	}
+	hints := r.suggestionsFor(q)
+	if len(hints) == 0 {
+		return err
+	}
+	return fmt.Errorf("%w\n\tdid you mean %s?", err, strings.Join(hints, ", or "))
+}
+
+// suggestionsFor lists the registered components close to the ones looked for by the query.
+func (r *Resolver) suggestionsFor(q query) []string {
+	switch typed := q.(type) {
+	case queryByName:
+		return r.suggestionsForName(typed.name)
+	case queryByVersion:
+		return r.suggestionsForVersion(typed.name, typed.version)
+	case queryByType:
+		return r.suggestionsForType(typed.typ)
+	default:
+		return nil
+	}
+}
+
+func (r *Resolver) suggestionsForName(lookFor Name) []string {
+	type scored struct {
+		name     string
+		distance int
+	}
+
+	var (
+		hints    []string
+		similars []scored
+		seen     = make(map[string]bool)
+	)
+	for _, n := range r.providableNames() {
+		switch {
+		case n.name == lookFor.name && !matchType(lookFor.typ, n.typ):
+			// same name, but another type, e.g. a pointer instead of a value
+			hints = append(hints, fmt.Sprintf("%s registered with type %s", n.name, n.typ))
+		case n.name != lookFor.name && matchType(lookFor.typ, n.typ) && !seen[n.name]:
+			seen[n.name] = true
+			similars = append(similars, scored{name: n.name, distance: levenshtein(lookFor.name, n.name)})
+		}
+	}
+
+	// only suggest the names which look like a typo
+	similars = slices.DeleteFunc(similars, func(s scored) bool {
+		return s.distance > max(2, len(lookFor.name)/3)
+	})
+	slices.SortFunc(similars, func(s1, s2 scored) int {
+		if res := cmp.Compare(s1.distance, s2.distance); res != 0 {
+			return res
+		}
+		return cmp.Compare(s1.name, s2.name)
+	})
+	for i, s := range similars {
+		if i == maxNameSuggestions {
+			break
+		}
+		hints = append(hints, fmt.Sprintf("%q of type %s", s.name, lookFor.typ))
+	}
+	return hints
+}
+
+func (r *Resolver) suggestionsForVersion(lookFor Name, version string) []string {
+	var hints []string
+	for _, p := range r.providers.All() {
+		if v := attributesOf(p).version; v != version && p.CanProvide(lookFor) {
+			hints = append(hints, fmt.Sprintf("version %q of %s", v, lookFor.name))
+		}
+	}
+	return hints
+}
+
+func (r *Resolver) suggestionsForType(lookFor reflect.Type) []string {
+	var (
+		hints []string
+		seen  = make(map[reflect.Type]bool)
+	)
+	for _, n := range r.providableNames() {
+		if seen[n.typ] {
+			continue
+		}
+		switch {
+		case n.typ == reflect.PointerTo(lookFor) || (lookFor.Kind() == reflect.Pointer && n.typ == lookFor.Elem()):
+			hints = append(hints, fmt.Sprintf("type %s, which is registered", n.typ))
+		case n.typ.Kind() == reflect.Interface && lookFor.Implements(n.typ):
+			hints = append(hints, fmt.Sprintf("interface %s, which is registered and implemented by %s", n.typ, lookFor))
+		default:
+			continue
+		}
+		seen[n.typ] = true
+	}
+	return hints
+}
+
+// providableNames lists the names of all the components the registered providers can provide.
+func (r *Resolver) providableNames() []Name {
+	var names []Name
+	for _, p := range r.providers.All() {
+		names = append(names, p.ListProvidableNames()...)
+	}
+	return names
+}
+
+// levenshtein computes the edit distance between two strings.
+func levenshtein(s1, s2 string) int {
+	r1, r2 := []rune(s1), []rune(s2)
+	previous := make([]int, len(r2)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+	for i := 1; i <= len(r1); i++ {
+		current := make([]int, len(r2)+1)
+		current[0] = i
+		for j := 1; j <= len(r2); j++ {
+			substitution := previous[j-1]
+			if r1[i-1] != r2[j-1] {
+				substitution++
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, substitution)
+		}
+		previous = current
+	}
+	return previous[len(r2)]
+}
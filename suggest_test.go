@@ -0,0 +1,107 @@
+package godi
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type greeter interface {
+	fmt.Stringer
+}
+
+type englishGreeter struct{}
+
+func (englishGreeter) String() string { return "hello" }
+
+func TestResolver_Suggestions(t *testing.T) {
+	t.Run("it should suggest the names close to a mistyped name", func(t *testing.T) {
+		// GIVEN
+		resolver := New()
+		resolver.MustRegister(ToStaticProvider("postgres://"), Named("database.url"))
+		resolver.MustRegister(ToStaticProvider("redis://"), Named("cache.url"))
+
+		// WHEN
+		_, err := ResolveNamed[string](resolver, "databse.url")
+
+		// THEN
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), `did you mean "database.url" of type string?`)
+		assert.NotContains(t, err.Error(), "cache.url")
+	})
+
+	t.Run("it should suggest the same name registered with another type", func(t *testing.T) {
+		// GIVEN
+		resolver := New()
+		resolver.MustRegister(ToStaticProvider(&TestService{}), Named("service"))
+
+		// WHEN
+		_, err := ResolveNamed[TestService](resolver, "service")
+
+		// THEN
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "did you mean service registered with type *godi.TestService?")
+	})
+
+	t.Run("it should suggest the pointer type of a value type", func(t *testing.T) {
+		// GIVEN
+		resolver := New()
+		resolver.MustRegister(NewTestService)
+
+		// WHEN
+		_, err := Resolve[TestService](resolver)
+
+		// THEN
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "did you mean type *godi.TestService, which is registered?")
+	})
+
+	t.Run("it should suggest the registered interface implemented by the type", func(t *testing.T) {
+		// GIVEN
+		resolver := New()
+		resolver.MustRegister(func() greeter { return englishGreeter{} })
+
+		// WHEN
+		_, err := Resolve[englishGreeter](resolver)
+
+		// THEN
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "did you mean interface godi.greeter, which is registered and implemented by godi.englishGreeter?")
+	})
+
+	t.Run("it should suggest the registered versions", func(t *testing.T) {
+		// GIVEN
+		resolver := New()
+		resolver.MustRegister(ToStaticProvider(&TestService{}), Named("service"), Version("v1"))
+
+		// WHEN
+		_, err := ResolveVersion[*TestService](resolver, "service", "v2")
+
+		// THEN
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), `did you mean version "v1" of service?`)
+	})
+
+	t.Run("it should add the suggestions to the validation errors", func(t *testing.T) {
+		// GIVEN
+		resolver := New()
+		resolver.MustRegister(ToStaticProvider("postgres://"), Named("database.url"))
+		resolver.MustRegister(func(string) int { return 0 }, Dependencies(Inject.Named("database.ulr")))
+
+		// WHEN
+		err := resolver.Validate()
+
+		// THEN
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), `did you mean "database.url" of type string?`)
+	})
+
+	t.Run("it should compute the edit distance", func(t *testing.T) {
+		assert.Equal(t, 0, levenshtein("url", "url"))
+		assert.Equal(t, 1, levenshtein("databse", "database"))
+		assert.Equal(t, 2, levenshtein("ulr", "url"))
+		assert.Equal(t, 3, levenshtein("", "abc"))
+	})
+}
@@ -1,5 +1,9 @@
 package godi
 
+// ToStaticProvider returns a provider of the given value, e.g. a constant or an instance built outside the resolver.
+//
+// As for any component, the value is closed with the resolver if it is closeable or stoppable, unless it is
+// registered with OwnedByContainer(false).
 func ToStaticProvider[T any](value T) func() T {
 	return func() T {
 		return value
@@ -1,8 +1,10 @@
 package godi
 
 import (
-	"github.com/stretchr/testify/require"
 	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestToStaticProvider(t *testing.T) {
@@ -29,4 +31,64 @@ func TestToStaticProvider(t *testing.T) {
 		require.NoError(t, err)
 		require.Equal(t, 42, intResolved)
 	})
+
+	t.Run("it should close the static values with the resolver by default", func(t *testing.T) {
+		// GIVEN
+		resolver := New()
+		reader := &closeableReader{}
+		resolver.MustRegister(ToStaticProvider(reader), Named("reader"))
+		_ = MustResolveNamed[*closeableReader](resolver, "reader")
+
+		// WHEN
+		err := resolver.Close()
+
+		// THEN
+		require.NoError(t, err)
+		assert.True(t, reader.closed)
+	})
+
+	t.Run("it should leave open the static values not owned by the resolver", func(t *testing.T) {
+		// GIVEN
+		resolver := New()
+		reader := &closeableReader{}
+		resolver.MustRegister(ToStaticProvider(reader), Named("reader"), OwnedByContainer(false))
+		_ = MustResolveNamed[*closeableReader](resolver, "reader")
+
+		// WHEN
+		err := resolver.Close()
+
+		// THEN
+		require.NoError(t, err)
+		assert.False(t, reader.closed)
+	})
+
+	t.Run("it should leave open the bound values not owned by the resolver, even with a close hook", func(t *testing.T) {
+		// GIVEN
+		resolver := New()
+		reader := &closeableReader{}
+		require.NoError(t, Bind(resolver, reader, OwnedByContainer(false)))
+		_ = MustResolve[*closeableReader](resolver)
+
+		// WHEN
+		err := resolver.Close()
+
+		// THEN
+		require.NoError(t, err)
+		assert.False(t, reader.closed)
+	})
+
+	t.Run("it should close the values owned back by the resolver", func(t *testing.T) {
+		// GIVEN
+		resolver := New()
+		reader := &closeableReader{}
+		resolver.MustRegister(ToStaticProvider(reader), OwnedByContainer(false), OwnedByContainer(true))
+		_ = MustResolve[*closeableReader](resolver)
+
+		// WHEN
+		err := resolver.Close()
+
+		// THEN
+		require.NoError(t, err)
+		assert.True(t, reader.closed)
+	})
 }
@@ -0,0 +1,125 @@
+package godi
+
+import (
+	"cmp"
+	"fmt"
+	"reflect"
+	"slices"
+)
+
+type queryByTag struct {
+	typ reflect.Type
+	tag string
+}
+
+// ResolveTagged resolves the components of type T tagged with the given tag, see Tagged.
+//
+// The components are ordered by priority, then by registration order.
+func ResolveTagged[T any](resolver *Resolver, tag string) ([]T, error) {
+	var zero []T
+	lookFor := reflect.TypeOf((*T)(nil)).Elem()
+	if lookFor == nil {
+		return zero, fmt.Errorf("type %T is not a valid type", zero)
+	}
+
+	val, _, err := resolveTyped[[]T](
+		resolver,
+		Request{
+			unitaryTyp: lookFor,
+			query:      queryByTag{typ: lookFor, tag: tag},
+			validator:  validatorMultiple{},
+			collector:  collectorMultipleAsSlice{},
+		},
+	)
+	return val, err
+}
+
+type taggedDependencyBuilder struct {
+	tag string
+}
+
+// Tagged injects the components tagged with the given tag, as a slice ordered by priority then registration order,
+// or as a map by name.
+func (i *injectBuilder) Tagged(tag string) dependency {
+	return taggedDependencyBuilder{tag: tag}
+}
+
+func (t taggedDependencyBuilder) build(targetTyp reflect.Type) (r Request, err error) {
+	var collector collector
+	switch targetTyp.Kind() {
+	case reflect.Slice:
+		collector = collectorMultipleAsSlice{}
+	case reflect.Map:
+		collector = collectorMultipleAsMap{}
+	default:
+		return r, fmt.Errorf("tagged dependencies can only be used with slice or map types, got %s", targetTyp)
+	}
+	elemTyp := targetTyp.Elem()
+	return Request{
+		unitaryTyp: elemTyp,
+		query: queryByTag{
+			typ: elemTyp,
+			tag: t.tag,
+		},
+		validator: validatorMultiple{},
+		collector: collector,
+	}, nil
+}
+
+func (q queryByTag) find(r *Resolver) ([]*queryResult, error) {
+	candidates := r.candidatesFor(q)
+	results := resultsOf(candidates)
+	for _, result := range results {
+		if storedComp, found := r.store.Get(result.name); found {
+			result.component = &storedComp
+		}
+	}
+	return results, nil
+}
+
+func (q queryByTag) plan(lookup providerLookup) []candidate {
+	type ranked struct {
+		candidate
+		position int
+	}
+
+	// find the tagged names matching the type, the first provider (in priority order) of a name wins
+	rankedCandidates := make([]ranked, 0)
+	seen := make(map[Name]bool)
+	for position, provider := range lookup.providersOfType(q.typ) {
+		if !slices.Contains(attributesOf(provider).tags, q.tag) {
+			continue
+		}
+		for _, n := range provider.ListProvidableNames() {
+			if !seen[n] && attributesOf(provider).matchesQueriedType(q.typ, n.typ) {
+				seen[n] = true
+				rankedCandidates = append(rankedCandidates, ranked{
+					candidate: candidate{
+						name:     versionedName(n, provider),
+						provider: provider,
+					},
+					position: position,
+				})
+			}
+		}
+	}
+
+	// providers are sorted by decreasing priority, the latest registered first for a same priority,
+	// so reversing the positions within a priority gives the registration order
+	slices.SortStableFunc(rankedCandidates, func(c1, c2 ranked) int {
+		if p1, p2 := c1.provider.Priority(), c2.provider.Priority(); p1 != p2 {
+			return cmp.Compare(p2, p1)
+		}
+		return cmp.Compare(c2.position, c1.position)
+	})
+
+	candidates := make([]candidate, 0, len(rankedCandidates))
+	for _, c := range rankedCandidates {
+		candidates = append(candidates, c.candidate)
+	}
+	return candidates
+}
+
+func (q queryByTag) String() string {
+	return fmt.Sprintf("<type~=%s & tag=%s>", q.typ.String(), q.tag)
+}
@@ -0,0 +1,99 @@
+package godi
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolver_Tagged(t *testing.T) {
+	t.Run("it should resolve only the tagged components, in registration order", func(t *testing.T) {
+		// GIVEN
+		resolver := New()
+		resolver.MustRegister(ToStaticProvider(&TestService{Name: "auth"}), Named("auth"), Tagged("middleware"))
+		resolver.MustRegister(ToStaticProvider(&TestService{Name: "cache"}), Named("cache"))
+		resolver.MustRegister(ToStaticProvider(&TestService{Name: "logging"}), Named("logging"), Tagged("middleware"))
+		resolver.MustRegister(ToStaticProvider(&TestService{Name: "metrics"}), Named("metrics"), Tagged("middleware", "metrics"))
+
+		// WHEN
+		middlewares, err := ResolveTagged[*TestService](resolver, "middleware")
+
+		// THEN
+		require.NoError(t, err)
+		assert.Equal(t, []string{"auth", "logging", "metrics"}, namesOf(middlewares))
+	})
+
+	t.Run("it should order the tagged components by priority first", func(t *testing.T) {
+		// GIVEN
+		resolver := New()
+		resolver.MustRegister(ToStaticProvider(&TestService{Name: "auth"}), Named("auth"), Tagged("middleware"))
+		resolver.MustRegister(ToStaticProvider(&TestService{Name: "recover"}), Named("recover"), Tagged("middleware"), Priority(10))
+		resolver.MustRegister(ToStaticProvider(&TestService{Name: "logging"}), Named("logging"), Tagged("middleware"))
+
+		// WHEN
+		middlewares, err := ResolveTagged[*TestService](resolver, "middleware")
+
+		// THEN
+		require.NoError(t, err)
+		assert.Equal(t, []string{"recover", "auth", "logging"}, namesOf(middlewares))
+	})
+
+	t.Run("it should inject the tagged components", func(t *testing.T) {
+		// GIVEN
+		resolver := New()
+		resolver.MustRegister(ToStaticProvider(&TestService{Name: "auth"}), Named("auth"), Tagged("middleware"))
+		resolver.MustRegister(ToStaticProvider(&TestService{Name: "cache"}), Named("cache"))
+		resolver.MustRegister(ToStaticProvider(&TestService{Name: "logging"}), Named("logging"), Tagged("middleware"))
+		resolver.MustRegister(
+			func(middlewares []*TestService, byName map[string]*TestService) []string {
+				assert.Len(t, byName, 2)
+				return namesOf(middlewares)
+			},
+			Dependencies(Inject.Tagged("middleware"), Inject.Tagged("middleware")),
+		)
+
+		// WHEN
+		names, err := Resolve[[]string](resolver)
+
+		// THEN
+		require.NoError(t, err)
+		assert.Equal(t, []string{"auth", "logging"}, names)
+	})
+
+	t.Run("it should inject an empty slice if nothing is tagged", func(t *testing.T) {
+		// GIVEN
+		resolver := New()
+		resolver.MustRegister(ToStaticProvider(&TestService{Name: "cache"}), Named("cache"))
+
+		// WHEN
+		services, err := ResolveTagged[*TestService](resolver, "middleware")
+
+		// THEN
+		require.NoError(t, err)
+		assert.Empty(t, services)
+	})
+
+	t.Run("it should reject a tagged dependency which is not a slice or a map", func(t *testing.T) {
+		// GIVEN
+		resolver := New()
+
+		// WHEN
+		err := resolver.Register(
+			func(*TestService) string { return "" },
+			Dependencies(Inject.Tagged("middleware")),
+		)
+
+		// THEN
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "tagged dependencies can only be used with slice or map types")
+	})
+}
+
+func namesOf(services []*TestService) []string {
+	names := make([]string, 0, len(services))
+	for _, s := range services {
+		names = append(names, s.Name)
+	}
+	return names
+}
@@ -0,0 +1,300 @@
+// Package tenants isolates the components of the tenants of a SaaS backend, each tenant getting its own child
+// resolver of the application resolver, see Manager.
+package tenants
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/a-peyrard/godi"
+	"github.com/a-peyrard/godi/option"
+)
+
+type (
+	// Manager creates, caches and closes a child resolver per tenant, keyed by the tenant ID.
+	//
+	// By default the resolver of a tenant is a fork of the application resolver (see godi.Resolver.Fork), so all the
+	// components are built again for each tenant, with the providers of its overlay (see WithOverlay) injected
+	// everywhere. With WithScopes, the resolver of a tenant is a scope instead (see godi.Resolver.NewScope), sharing
+	// the singletons of the application.
+	Manager struct {
+		resolver *godi.Resolver
+		options  *ManagerOptions
+
+		mu      sync.Mutex
+		tenants map[string]*tenant
+		closed  bool
+		stop    chan struct{}
+		stopped sync.WaitGroup
+	}
+
+	ManagerOptions struct {
+		overlay     func(tenantID string) godi.Registry
+		idleTimeout time.Duration
+		scopes      bool
+	}
+
+	tenant struct {
+		// ready is closed once the resolver is created, or failed to be created
+		ready    chan struct{}
+		resolver *godi.Resolver
+		err      error
+		lastUsed atomic.Int64
+	}
+)
+
+// IDName is the name of the ID of the tenant, registered in the resolver of each tenant.
+const IDName = "tenants.id"
+
+// ErrClosed is returned by the manager once closed.
+var ErrClosed = errors.New("the tenants manager is closed")
+
+// WithOverlay registers the components of the registry returned for each tenant in its resolver, e.g. the
+// configuration of the tenant, overriding the ones of the application resolver.
+func WithOverlay(overlay func(tenantID string) godi.Registry) option.Option[ManagerOptions] {
+	return func(opts *ManagerOptions) {
+		opts.overlay = overlay
+	}
+}
+
+// WithIdleTimeout closes the resolvers of the tenants not used for the given duration, they are created again
+// on their next use. A tenant is used when its resolver is returned by Manager.Resolver, so the resolvers must not
+// be kept by the callers longer than the timeout, they would be closed while still held.
+func WithIdleTimeout(timeout time.Duration) option.Option[ManagerOptions] {
+	return func(opts *ManagerOptions) {
+		opts.idleTimeout = timeout
+	}
+}
+
+// WithScopes creates a scope of the application resolver for each tenant, instead of a fork, so the singletons
+// are shared by all the tenants, and only the scoped components are built for each tenant (see godi.Scoped).
+func WithScopes() option.Option[ManagerOptions] {
+	return func(opts *ManagerOptions) {
+		opts.scopes = true
+	}
+}
+
+// NewManager creates a manager of the tenants of the application resolver. The manager must be closed to release
+// the resolvers of the tenants, e.g. by registering it in the application resolver.
+func NewManager(resolver *godi.Resolver, opts ...option.Option[ManagerOptions]) *Manager {
+	m := &Manager{
+		resolver: resolver,
+		options:  option.Build(&ManagerOptions{}, opts...),
+		tenants:  make(map[string]*tenant),
+		stop:     make(chan struct{}),
+	}
+	if m.options.idleTimeout > 0 {
+		m.stopped.Add(1)
+		go m.evictIdle()
+	}
+	return m
+}
+
+// Resolve resolves a component of type T for the tenant, see godi.Resolve.
+func Resolve[T any](m *Manager, tenantID string) (T, error) {
+	resolver, err := m.Resolver(tenantID)
+	if err != nil {
+		var zero T
+		return zero, err
+	}
+	return godi.Resolve[T](resolver)
+}
+
+// ResolveNamed resolves a named component of type T for the tenant, see godi.ResolveNamed.
+func ResolveNamed[T any](m *Manager, tenantID string, name string) (T, error) {
+	resolver, err := m.Resolver(tenantID)
+	if err != nil {
+		var zero T
+		return zero, err
+	}
+	return godi.ResolveNamed[T](resolver, name)
+}
+
+// Resolver returns the resolver of the tenant, creating it on its first use. The resolver is closed when the tenant
+// is evicted, see WithIdleTimeout, so it should be looked up for each use rather than kept.
+func (m *Manager) Resolver(tenantID string) (*godi.Resolver, error) {
+	m.mu.Lock()
+	if m.closed {
+		m.mu.Unlock()
+		return nil, ErrClosed
+	}
+	t, found := m.tenants[tenantID]
+	if !found {
+		t = &tenant{ready: make(chan struct{})}
+		m.tenants[tenantID] = t
+	}
+	// the use is recorded along with the lookup, so the tenant can not be found idle before it is returned
+	t.lastUsed.Store(time.Now().UnixNano())
+	m.mu.Unlock()
+
+	if !found {
+		t.resolver, t.err = m.create(tenantID)
+		t.lastUsed.Store(time.Now().UnixNano())
+		close(t.ready)
+		if t.err != nil {
+			m.forget(tenantID, t)
+		}
+	}
+	<-t.ready
+	if t.err != nil {
+		return nil, t.err
+	}
+	t.lastUsed.Store(time.Now().UnixNano())
+	return t.resolver, nil
+}
+
+// Tenants returns the IDs of the tenants whose resolver is created, sorted.
+func (m *Manager) Tenants() []string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	ids := make([]string, 0, len(m.tenants))
+	for id := range m.tenants {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// Evict closes the resolver of the tenant, it is created again on its next use. Nothing is done if the resolver
+// of the tenant is not created.
+func (m *Manager) Evict(tenantID string) error {
+	m.mu.Lock()
+	t, found := m.tenants[tenantID]
+	if found {
+		delete(m.tenants, tenantID)
+	}
+	m.mu.Unlock()
+	if !found {
+		return nil
+	}
+	return t.close(context.Background(), tenantID)
+}
+
+// Close closes the resolvers of all the tenants, the manager can not be used anymore.
+func (m *Manager) Close() error {
+	return m.CloseWithContext(context.Background())
+}
+
+// CloseWithContext closes the resolvers of all the tenants, see godi.Resolver.CloseWithContext.
+func (m *Manager) CloseWithContext(ctx context.Context) error {
+	m.mu.Lock()
+	if m.closed {
+		m.mu.Unlock()
+		return nil
+	}
+	m.closed = true
+	tenants := m.tenants
+	m.tenants = nil
+	m.mu.Unlock()
+
+	close(m.stop)
+	m.stopped.Wait()
+
+	var errs []error
+	for id, t := range tenants {
+		if err := t.close(ctx, id); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (m *Manager) create(tenantID string) (*godi.Resolver, error) {
+	var resolver *godi.Resolver
+	if m.options.scopes {
+		resolver = m.resolver.NewScope()
+	} else {
+		resolver = m.resolver.Fork()
+	}
+	if err := m.install(tenantID, resolver); err != nil {
+		return nil, errors.Join(
+			fmt.Errorf("failed to create the resolver of tenant %s:\n\t%w", tenantID, err),
+			resolver.Close(),
+		)
+	}
+	return resolver, nil
+}
+
+// install registers the ID and the overlay of the tenant, the generated registries panic if a registration fails.
+func (m *Manager) install(tenantID string, resolver *godi.Resolver) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("%v", r)
+		}
+	}()
+
+	registries := []godi.Registry{registryFunc(func(r *godi.Resolver) {
+		r.MustRegister(godi.ToStaticProvider(tenantID), godi.Named(IDName))
+	})}
+	if m.options.overlay != nil {
+		registries = append(registries, m.options.overlay(tenantID))
+	}
+	return godi.Compose(registries...).Install(resolver)
+}
+
+// forget drops the tenant, unless it was replaced meanwhile.
+func (m *Manager) forget(tenantID string, t *tenant) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.tenants[tenantID] == t {
+		delete(m.tenants, tenantID)
+	}
+}
+
+// evictIdle closes the resolvers of the idle tenants periodically, until the manager is closed.
+func (m *Manager) evictIdle() {
+	defer m.stopped.Done()
+	ticker := time.NewTicker(max(m.options.idleTimeout/2, time.Millisecond))
+	defer ticker.Stop()
+	for {
+		select {
+		case <-m.stop:
+			return
+		case now := <-ticker.C:
+			for id, t := range m.dropIdleSince(now.Add(-m.options.idleTimeout)) {
+				_ = t.close(context.Background(), id)
+			}
+		}
+	}
+}
+
+// dropIdleSince drops the tenants whose resolver is created, and not used since the given time.
+func (m *Manager) dropIdleSince(since time.Time) map[string]*tenant {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	idle := make(map[string]*tenant)
+	for id, t := range m.tenants {
+		select {
+		case <-t.ready:
+			if t.err == nil && t.lastUsed.Load() < since.UnixNano() {
+				idle[id] = t
+				delete(m.tenants, id)
+			}
+		default: // still being created
+		}
+	}
+	return idle
+}
+
+func (t *tenant) close(ctx context.Context, tenantID string) error {
+	<-t.ready
+	if t.resolver == nil {
+		return nil
+	}
+	if err := t.resolver.CloseWithContext(ctx); err != nil {
+		return fmt.Errorf("failed to close the resolver of tenant %s:\n\t%w", tenantID, err)
+	}
+	return nil
+}
+
+// registryFunc is a registry registering the components with a function.
+type registryFunc func(resolver *godi.Resolver)
+
+func (f registryFunc) Register(resolver *godi.Resolver) {
+	f(resolver)
+}
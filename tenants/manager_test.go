@@ -0,0 +1,200 @@
+package tenants
+
+import (
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/a-peyrard/godi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type (
+	tenantConfig struct{ database string }
+
+	repository struct {
+		database string
+		closed   atomic.Bool
+	}
+)
+
+func (r *repository) Close() error {
+	r.closed.Store(true)
+	return nil
+}
+
+func newApplication() *godi.Resolver {
+	resolver := godi.New()
+	resolver.MustRegister(func() *tenantConfig { return &tenantConfig{database: "shared"} })
+	resolver.MustRegister(func(cfg *tenantConfig) *repository { return &repository{database: cfg.database} })
+	return resolver
+}
+
+func configOverlay(tenantID string) godi.Registry {
+	return registryFunc(func(r *godi.Resolver) {
+		r.MustRegister(func() *tenantConfig { return &tenantConfig{database: "db-" + tenantID} })
+	})
+}
+
+func TestManager(t *testing.T) {
+	t.Run("it should create a resolver per tenant, with its overlay", func(t *testing.T) {
+		// GIVEN
+		m := NewManager(newApplication(), WithOverlay(configOverlay))
+		defer m.Close()
+
+		// WHEN
+		acme, acmeErr := Resolve[*repository](m, "acme")
+		globex, globexErr := Resolve[*repository](m, "globex")
+
+		// THEN
+		require.NoError(t, acmeErr)
+		require.NoError(t, globexErr)
+		assert.Equal(t, "db-acme", acme.database)
+		assert.Equal(t, "db-globex", globex.database)
+		assert.Equal(t, []string{"acme", "globex"}, m.Tenants())
+	})
+
+	t.Run("it should cache the resolver of a tenant", func(t *testing.T) {
+		// GIVEN
+		m := NewManager(newApplication())
+		defer m.Close()
+		first, err := Resolve[*repository](m, "acme")
+		require.NoError(t, err)
+
+		// WHEN
+		second, err := Resolve[*repository](m, "acme")
+
+		// THEN
+		require.NoError(t, err)
+		assert.Same(t, first, second)
+	})
+
+	t.Run("it should register the ID of the tenant", func(t *testing.T) {
+		// GIVEN
+		m := NewManager(newApplication())
+		defer m.Close()
+
+		// WHEN
+		id, err := ResolveNamed[string](m, "acme", IDName)
+
+		// THEN
+		require.NoError(t, err)
+		assert.Equal(t, "acme", id)
+	})
+
+	t.Run("it should share the singletons of the application with scopes", func(t *testing.T) {
+		// GIVEN
+		application := newApplication()
+		m := NewManager(application, WithScopes())
+		defer m.Close()
+
+		// WHEN
+		acme, err := Resolve[*repository](m, "acme")
+
+		// THEN
+		require.NoError(t, err)
+		assert.Same(t, godi.MustResolve[*repository](application), acme)
+	})
+
+	t.Run("it should close the resolver of an evicted tenant, and create it again", func(t *testing.T) {
+		// GIVEN
+		m := NewManager(newApplication())
+		defer m.Close()
+		evicted, err := Resolve[*repository](m, "acme")
+		require.NoError(t, err)
+
+		// WHEN
+		err = m.Evict("acme")
+
+		// THEN
+		require.NoError(t, err)
+		assert.True(t, evicted.closed.Load())
+		assert.Empty(t, m.Tenants())
+		recreated, err := Resolve[*repository](m, "acme")
+		require.NoError(t, err)
+		assert.NotSame(t, evicted, recreated)
+	})
+
+	t.Run("it should close the resolvers of the idle tenants", func(t *testing.T) {
+		// GIVEN
+		m := NewManager(newApplication(), WithIdleTimeout(20*time.Millisecond))
+		defer m.Close()
+		idle, err := Resolve[*repository](m, "acme")
+		require.NoError(t, err)
+
+		// WHEN
+		// THEN
+		assert.Eventually(t, idle.closed.Load, time.Second, 5*time.Millisecond)
+		assert.Empty(t, m.Tenants())
+	})
+
+	t.Run("it should record the use of a tenant as soon as it is looked up", func(t *testing.T) {
+		// GIVEN
+		creating := make(chan struct{})
+		created := make(chan struct{})
+		m := NewManager(newApplication(), WithOverlay(func(string) godi.Registry {
+			close(creating)
+			<-created
+			return configOverlay("acme")
+		}))
+		defer m.Close()
+		done := make(chan error, 1)
+		go func() {
+			_, err := m.Resolver("acme")
+			done <- err
+		}()
+
+		// WHEN
+		<-creating
+		m.mu.Lock()
+		lastUsed := m.tenants["acme"].lastUsed.Load()
+		m.mu.Unlock()
+		close(created)
+
+		// THEN
+		assert.NotZero(t, lastUsed, "the tenant would be found idle as soon as it is ready")
+		require.NoError(t, <-done)
+	})
+
+	t.Run("it should close the resolvers of all the tenants once closed", func(t *testing.T) {
+		// GIVEN
+		m := NewManager(newApplication())
+		acme, _ := Resolve[*repository](m, "acme")
+		globex, _ := Resolve[*repository](m, "globex")
+
+		// WHEN
+		err := m.Close()
+
+		// THEN
+		require.NoError(t, err)
+		assert.True(t, acme.closed.Load())
+		assert.True(t, globex.closed.Load())
+		_, err = m.Resolver("acme")
+		assert.ErrorIs(t, err, ErrClosed)
+	})
+
+	t.Run("it should report the failure of the overlay, and try again on the next use", func(t *testing.T) {
+		// GIVEN
+		attempts := 0
+		m := NewManager(newApplication(), WithOverlay(func(tenantID string) godi.Registry {
+			return registryFunc(func(r *godi.Resolver) {
+				attempts++
+				if err := r.Register(errors.New("not a provider")); err != nil {
+					panic(err)
+				}
+			})
+		}))
+		defer m.Close()
+
+		// WHEN
+		_, err := m.Resolver("acme")
+
+		// THEN
+		require.Error(t, err)
+		assert.Empty(t, m.Tenants())
+		_, _ = m.Resolver("acme")
+		assert.Equal(t, 2, attempts)
+	})
+}
@@ -0,0 +1,147 @@
+package godi
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/a-peyrard/godi/option"
+)
+
+type (
+	// Tracer is notified when the resolution of a request begins, and when it ends, see WithTracer.
+	//
+	// The resolutions of the dependencies are nested in the resolution of the component depending on them,
+	// and might be concurrent (see WithParallelResolution), so tracers must be safe for concurrent use.
+	Tracer interface {
+		Begin(event TraceEvent)
+		End(event TraceEvent)
+	}
+
+	// TraceEvent describes the resolution of a request.
+	TraceEvent struct {
+		// ID identifies the resolution, the begin and end events of a resolution share the same ID.
+		ID uint64
+		// Request is the resolved request.
+		Request Request
+		// Depth is the nesting depth of the resolution, 0 for a resolution which is not a dependency.
+		Depth int
+		// Start is the time the resolution began.
+		Start time.Time
+
+		// Duration is the duration of the resolution, only set when it ends.
+		Duration time.Duration
+		// Err is the error of the resolution if any, only set when it ends.
+		Err error
+	}
+
+	// TimingReport is a Tracer recording the resolutions, to report where the startup of a container spends its time.
+	TimingReport struct {
+		mu     sync.Mutex
+		events []*TraceEvent
+		byID   map[uint64]*TraceEvent
+	}
+)
+
+const timingReportBarWidth = 20
+
+var traceSequence atomic.Uint64
+
+// WithTracer notifies the tracer of every resolution made by the resolver, and its scopes and forks.
+func WithTracer(tracer Tracer) option.Option[ResolverOptions] {
+	return func(opts *ResolverOptions) {
+		opts.tracer = tracer
+	}
+}
+
+// trace notifies the tracer of the resolver (if any) and logs the resolution, the returned function must be called
+// when the resolution ends.
+func (r *Resolver) trace(req Request, depth int) func(err error) {
+	if _, nop := r.logger.(nopLogger); nop && r.tracer == nil {
+		return func(error) {}
+	}
+
+	event := TraceEvent{
+		ID:      traceSequence.Add(1),
+		Request: req,
+		Depth:   depth,
+		Start:   time.Now(),
+	}
+	if r.tracer != nil {
+		r.tracer.Begin(event)
+	}
+	return func(err error) {
+		event.Duration = time.Since(event.Start)
+		event.Err = err
+		if r.tracer != nil {
+			r.tracer.End(event)
+		}
+		if err != nil {
+			r.logger.Debug("request resolution failed", "request", req, "depth", depth, "duration", event.Duration, "error", err)
+		} else {
+			r.logger.Debug("request resolved", "request", req, "depth", depth, "duration", event.Duration)
+		}
+	}
+}
+
+// NewTimingReport creates a tracer recording the resolutions, see WithTracer.
+func NewTimingReport() *TimingReport {
+	return &TimingReport{
+		byID: make(map[uint64]*TraceEvent),
+	}
+}
+
+func (t *TimingReport) Begin(event TraceEvent) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	recorded := &event
+	t.events = append(t.events, recorded)
+	t.byID[event.ID] = recorded
+}
+
+func (t *TimingReport) End(event TraceEvent) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if recorded, found := t.byID[event.ID]; found {
+		*recorded = event
+	}
+}
+
+// String renders the resolutions in the order they began, nested by depth, with a bar showing the share
+// of the duration of the resolution in the duration of its root resolution.
+func (t *TimingReport) String() string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	var (
+		b     strings.Builder
+		total time.Duration
+	)
+	for _, event := range t.events {
+		if event.Depth == 0 {
+			total = event.Duration
+		}
+
+		bar := 0
+		if total > 0 {
+			bar = int(event.Duration * timingReportBarWidth / total)
+		}
+		b.WriteString(fmt.Sprintf(
+			"[%s%s] %10s %s%s",
+			strings.Repeat("#", bar),
+			strings.Repeat(" ", timingReportBarWidth-bar),
+			event.Duration.Round(time.Microsecond),
+			strings.Repeat("  ", event.Depth),
+			event.Request,
+		))
+		if event.Err != nil {
+			b.WriteString(" (failed)")
+		}
+		b.WriteString("\n")
+	}
+	return b.String()
+}
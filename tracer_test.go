@@ -0,0 +1,79 @@
+package godi
+
+import (
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type recordingTracer struct {
+	mu     sync.Mutex
+	events []string
+}
+
+func (t *recordingTracer) Begin(event TraceEvent) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.events = append(t.events, strings.Repeat(">", event.Depth+1)+" "+event.Request.query.String())
+}
+
+func (t *recordingTracer) End(event TraceEvent) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.events = append(t.events, strings.Repeat("<", event.Depth+1)+" "+event.Request.query.String())
+}
+
+func TestResolver_WithTracer(t *testing.T) {
+	t.Run("it should notify the tracer of the nested resolutions", func(t *testing.T) {
+		// GIVEN
+		tracer := &recordingTracer{}
+		resolver := New(WithTracer(tracer))
+		resolver.MustRegister(NewTestService)
+		resolver.MustRegister(NewTestRepository)
+		resolver.MustRegister(NewTestController)
+
+		// WHEN
+		_, err := Resolve[*TestController](resolver)
+
+		// THEN
+		require.NoError(t, err)
+		assert.Equal(t, []string{
+			"> <type~=*godi.TestController>",
+			">> <type~=*godi.TestService>",
+			"<< <type~=*godi.TestService>",
+			">> <type~=*godi.TestRepository>",
+			"<< <type~=*godi.TestRepository>",
+			"< <type~=*godi.TestController>",
+		}, tracer.events)
+	})
+
+	t.Run("it should report the timings of the resolutions", func(t *testing.T) {
+		// GIVEN
+		report := NewTimingReport()
+		resolver := New(WithTracer(report))
+		resolver.MustRegister(NewTestService)
+		resolver.MustRegister(func() (string, error) { return "", errors.New("boom") }, Named("failing"))
+		resolver.MustRegister(NewTestRepository)
+		resolver.MustRegister(NewTestController)
+
+		// WHEN
+		_, err1 := Resolve[*TestController](resolver)
+		_, err2 := ResolveNamed[string](resolver, "failing")
+
+		// THEN
+		require.NoError(t, err1)
+		require.Error(t, err2)
+		lines := strings.Split(strings.TrimSpace(report.String()), "\n")
+		require.Len(t, lines, 4)
+		assert.True(t, strings.HasPrefix(lines[0], "[####################]"), lines[0])
+		assert.Contains(t, lines[0], " {q=<type~=*godi.TestController>")
+		assert.Contains(t, lines[1], "   {q=<type~=*godi.TestService>")
+		assert.Contains(t, lines[2], "   {q=<type~=*godi.TestRepository>")
+		assert.Contains(t, lines[3], "name=failing")
+		assert.True(t, strings.HasSuffix(lines[3], "(failed)"), lines[3])
+	})
+}
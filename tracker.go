@@ -1,7 +1,13 @@
 package godi
 
 import (
+	"context"
 	"fmt"
+	"maps"
+	"reflect"
+	"runtime"
+	"slices"
+	"strings"
 
 	"github.com/a-peyrard/godi/set"
 )
@@ -10,24 +16,50 @@ type (
 	Tracker struct {
 		visited set.Set[Name]
 		stack   []Name
+
+		// providers are the providers of the components of the stack, if known, to describe the cycles
+		providers map[Name]Provider
+
+		// ctx is the context of the resolution, if any, see ResolveCtx
+		ctx context.Context
+
+		// bound is the construction of the component on top of the stack, see boundResolution
+		bound *boundResolution
 	}
 )
 
 func NewTracker() *Tracker {
 	return &Tracker{
-		visited: set.New[Name](),
-		stack:   make([]Name, 0),
+		visited:   set.New[Name](),
+		stack:     make([]Name, 0),
+		providers: make(map[Name]Provider),
 	}
 }
 
 func NewTrackerFrom(other *Tracker) *Tracker {
 	return &Tracker{
-		visited: set.NewFromSlice(other.visited.ToSlice()),
-		stack:   other.stack,
+		visited:   set.NewFromSlice(other.visited.ToSlice()),
+		stack:     slices.Clone(other.stack), // the trackers of sibling dependencies might be used concurrently
+		providers: maps.Clone(other.providers),
+		ctx:       other.ctx,
+		bound:     other.bound, // the factories injected into the construction continue it, see collectorFactory
+	}
+}
+
+// contextErr returns the error of the resolution context, if it is done.
+func (tracker *Tracker) contextErr() error {
+	if tracker.ctx == nil {
+		return nil
 	}
+	return context.Cause(tracker.ctx)
 }
 
 func (tracker *Tracker) Push(n Name) error {
+	return tracker.pushProvided(n, nil)
+}
+
+// pushProvided pushes the component provided by the given provider, which is reported if the component is in a cycle.
+func (tracker *Tracker) pushProvided(n Name, p Provider) error {
 	if tracker.visited.Contains(n) {
 		cycle := []Name{n}
 		for i := len(tracker.stack) - 1; i >= 0; i-- {
@@ -37,10 +69,15 @@ func (tracker *Tracker) Push(n Name) error {
 			}
 		}
 
-		return fmt.Errorf("cycle found:\n%s", formatCycle(cycle))
+		return fmt.Errorf("cycle found: %s", formatCycle(cycle, func(n Name) Provider {
+			return tracker.providers[n]
+		}))
 	}
 	tracker.visited.Add(n)
 	tracker.stack = append(tracker.stack, n)
+	if p != nil {
+		tracker.providers[n] = p
+	}
 
 	return nil
 }
@@ -52,28 +89,65 @@ func (tracker *Tracker) Pop() Name {
 	n := tracker.stack[len(tracker.stack)-1]
 	tracker.stack = tracker.stack[:len(tracker.stack)-1]
 	tracker.visited.Remove(n)
+	delete(tracker.providers, n)
 
 	return n
 }
 
-func formatCycle(cycle []Name) string {
-	str := ""
-	tabs := 0
-	for i := len(cycle) - 1; i >= 0; i-- {
-		prefix := ""
-		if i != len(cycle)-1 {
-			prefix = " -> "
+// formatCycle describes the cycle, given from the last component to the first one (which is also the last one),
+// with the providers of the components and a hint on how to break the cycle.
+func formatCycle(cycle []Name, providerOf func(Name) Provider) string {
+	chain := slices.Clone(cycle)
+	slices.Reverse(chain)
+
+	var b strings.Builder
+	for i, n := range chain {
+		if i > 0 {
+			b.WriteString(" -> ")
 		}
-		str += fmt.Sprintf("%s%s%s\n", generateTabs(tabs), prefix, cycle[i])
-		tabs++
+		b.WriteString(n.String())
 	}
-	return str
+	b.WriteString("\n")
+	for _, n := range chain[:len(chain)-1] {
+		if p := providerOf(n); p != nil {
+			b.WriteString(fmt.Sprintf("\t%s is provided by %s\n", n, describeProvider(p)))
+		}
+	}
+	if len(chain) > 1 {
+		b.WriteString(fmt.Sprintf(
+			"hint: break the cycle by injecting the *godi.Resolver in %s instead of %s, and resolving it once %s is built",
+			chain[len(chain)-2], chain[len(chain)-1], chain[len(chain)-2],
+		))
+	}
+	return b.String()
 }
 
-func generateTabs(n int) string {
-	str := ""
-	for i := 0; i < n; i++ {
-		str += "\t"
+// factoryOf returns the factory method of the provider, if any.
+func factoryOf(p Provider) reflect.Value {
+	switch factoryMethod := p.(type) {
+	case *FactoryMethodProvider:
+		return factoryMethod.factory
+	case *factoryResultsProvider:
+		return factoryMethod.factory
+	}
+	return reflect.Value{}
+}
+
+// describeProvider describes the provider, with the name and the source location of its function if any.
+func describeProvider(p Provider) string {
+	if attributed, ok := p.(*attributedProvider); ok {
+		p = attributed.Provider
+	}
+	if multi, ok := p.(*multiResultProvider); ok {
+		p = multi.call
+	}
+	if factory := factoryOf(p); factory.IsValid() {
+		fn := runtime.FuncForPC(factory.Pointer())
+		file, line := fn.FileLine(fn.Entry())
+		return fmt.Sprintf("%s (%s:%d)", fn.Name(), file, line)
+	}
+	if stringer, ok := p.(fmt.Stringer); ok {
+		return stringer.String()
 	}
-	return str
+	return fmt.Sprintf("%T", p)
 }
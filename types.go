@@ -1,17 +1,27 @@
 package godi
 
 import (
+	"context"
 	"fmt"
 	"reflect"
+	"time"
 )
 
 var (
-	StringType    = TypeOf[string]()
-	ProviderType  = TypeOf[Provider]()
-	DecoratorType = TypeOf[Decorator]()
-	ErrorType     = TypeOf[error]()
-	CloseableType = TypeOf[Closeable]()
-	StringerType  = TypeOf[fmt.Stringer]()
+	StringType          = TypeOf[string]()
+	ProviderType        = TypeOf[Provider]()
+	DecoratorType       = TypeOf[Decorator]()
+	ErrorType           = TypeOf[error]()
+	CloseableType       = TypeOf[Closeable]()
+	StoppableType       = TypeOf[Stoppable]()
+	PostConstructorType = TypeOf[PostConstructor]()
+	StringerType        = TypeOf[fmt.Stringer]()
+	ContextType         = TypeOf[context.Context]()
+
+	boolType     = TypeOf[bool]()
+	intType      = TypeOf[int]()
+	durationType = TypeOf[time.Duration]()
+	resolverType = TypeOf[*Resolver]()
 )
 
 func matchType(queryType, providedType reflect.Type) bool {
@@ -0,0 +1,88 @@
+package godi
+
+import (
+	"errors"
+	"fmt"
+)
+
+type visitState int
+
+const (
+	visiting visitState = iota + 1
+	visited
+)
+
+// Validate walks the dependencies of all the registered providers and decorators, reporting all the missing
+// dependencies, ambiguous matches and cycles, without ever calling a provider.
+//
+// It is meant to be called once everything is registered, e.g. in a test, to catch wiring errors before the
+// first resolution.
+func (r *Resolver) Validate() error {
+	var (
+		errs      []error
+		states    = make(map[Name]visitState)
+		providers = make(map[Name]Provider)
+		visit     func(name Name, p Provider, path []Name)
+	)
+
+	validateRequests := func(requests []Request, owner string, name Name, path []Name) {
+		for _, req := range requests {
+			if req.resolutionContext || req.injectionContext {
+				continue // might be given by the resolution itself
+			}
+			results, err := req.query.find(r)
+			if err == nil {
+				err = req.validator.validate(results)
+			}
+			if err != nil {
+				errs = append(errs, fmt.Errorf("unable to resolve dependency %v of %s:\n\t%w", req, owner, r.withSuggestions(err, req.query, results)))
+				continue
+			}
+			for _, result := range results {
+				if result.provider != nil {
+					visit(result.name, result.provider, append(path, name))
+				}
+			}
+		}
+	}
+
+	visit = func(name Name, p Provider, path []Name) {
+		switch states[name] {
+		case visited:
+			return
+		case visiting:
+			cycle := []Name{name}
+			for i := len(path) - 1; i >= 0; i-- {
+				cycle = append(cycle, path[i])
+				if path[i] == name {
+					break
+				}
+			}
+			errs = append(errs, fmt.Errorf("cycle found: %s", formatCycle(cycle, func(n Name) Provider {
+				return providers[n]
+			})))
+			return
+		}
+
+		states[name] = visiting
+		providers[name] = p
+		validateRequests(p.Dependencies(), fmt.Sprintf("component %s provided by %s", name, p), name, path)
+		if decorators, found := r.decorators.Load(name.unversioned()); found {
+			for _, d := range decorators.(*SortedCOWSlice[Decorator]).All() {
+				validateRequests(d.Dependencies(), fmt.Sprintf("decorator %s of component %s", d, name), name, path)
+			}
+		}
+		states[name] = visited
+	}
+
+	for _, p := range r.providers.All() {
+		for _, n := range p.ListProvidableNames() {
+			visit(versionedName(n, p), p, nil)
+		}
+	}
+	for _, d := range r.typeDecorators() {
+		validateRequests(d.Dependencies(), fmt.Sprintf("decorator %s", d), d.ForName(), nil)
+	}
+
+	return errors.Join(errs...)
+}
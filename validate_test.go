@@ -0,0 +1,72 @@
+package godi
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolver_Validate(t *testing.T) {
+	t.Run("it should accept a valid graph", func(t *testing.T) {
+		// GIVEN
+		resolver := New()
+		resolver.MustRegister(NewTestService)
+		resolver.MustRegister(NewTestRepository)
+		resolver.MustRegister(NewTestController)
+
+		// WHEN
+		err := resolver.Validate()
+
+		// THEN
+		require.NoError(t, err)
+	})
+
+	t.Run("it should report all the missing dependencies without calling any provider", func(t *testing.T) {
+		// GIVEN
+		resolver := New()
+		called := false
+		resolver.MustRegister(func(service *TestService, repo *TestRepository) *TestController {
+			called = true
+			return &TestController{}
+		})
+
+		// WHEN
+		err := resolver.Validate()
+
+		// THEN
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "*godi.TestService")
+		assert.Contains(t, err.Error(), "*godi.TestRepository")
+		assert.False(t, called)
+	})
+
+	t.Run("it should report ambiguous matches", func(t *testing.T) {
+		// GIVEN
+		resolver := New()
+		resolver.MustRegister(NewTestService, Named("service.first"))
+		resolver.MustRegister(NewTestService, Named("service.second"))
+		resolver.MustRegister(func(service *TestService) *TestController { return &TestController{} })
+
+		// WHEN
+		err := resolver.Validate()
+
+		// THEN
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "multiple providers found")
+	})
+
+	t.Run("it should report cycles", func(t *testing.T) {
+		// GIVEN
+		resolver := New()
+		resolver.MustRegister(func(*TestRepository) *TestService { return nil })
+		resolver.MustRegister(func(*TestService) *TestRepository { return nil })
+
+		// WHEN
+		err := resolver.Validate()
+
+		// THEN
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "cycle found")
+	})
+}
@@ -0,0 +1,132 @@
+package godi
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/a-peyrard/godi/fn"
+)
+
+type queryByVersion struct {
+	name    Name
+	version string
+}
+
+// ResolveVersion attempts to resolve the given version of a named component of type T from the resolver.
+func ResolveVersion[T any](resolver *Resolver, name string, version string) (T, error) {
+	var zero T
+	lookFor := reflect.TypeOf((*T)(nil)).Elem()
+	if lookFor == nil {
+		return zero, fmt.Errorf("type %T is not a valid type", zero)
+	}
+
+	val, _, err := resolveTyped[T](
+		resolver,
+		Request{
+			unitaryTyp: lookFor,
+			query: queryByVersion{
+				name:    Name{name: name, typ: lookFor},
+				version: version,
+			},
+			validator: validatorUniqueMandatory{},
+			collector: collectorUnique{},
+		},
+	)
+	return val, err
+}
+
+func (q queryByVersion) find(r *Resolver) ([]*queryResult, error) {
+	name := q.name.withVersion(q.version)
+	if comp, found := r.store.Get(name); found {
+		return []*queryResult{
+			{
+				name:      name,
+				component: &comp,
+				provider:  nil,
+			},
+		}, nil
+	}
+
+	return resultsOf(r.candidatesFor(q)), nil
+}
+
+func (q queryByVersion) plan(lookup providerLookup) []candidate {
+	for _, provider := range lookup.providersNamed(q.name.name) {
+		if attributesOf(provider).version == q.version && provider.CanProvide(q.name) {
+			return []candidate{
+				{
+					name:     q.name.withVersion(q.version),
+					provider: provider,
+				},
+			}
+		}
+	}
+
+	return []candidate{}
+}
+
+func (q queryByVersion) String() string {
+	return fmt.Sprintf("<type~=%s & name=%s & version=%s>", q.name.typ.String(), q.name.name, q.version)
+}
+
+func (n Name) withVersion(version string) Name {
+	n.version = version
+	return n
+}
+
+func (n Name) unversioned() Name {
+	n.version = ""
+	return n
+}
+
+// versionedName returns the name under which the component provided by the provider is stored,
+// so that all the resolutions of a given version share the same instance.
+func versionedName(n Name, p Provider) Name {
+	return n.withVersion(attributesOf(p).version)
+}
+
+// compareProviders compares the providers by priority, then by version.
+func compareProviders(p1, p2 Provider) fn.ComparisonResult {
+	if res := compareByPriority(p1, p2); res != fn.Equal {
+		return res
+	}
+	return compareVersions(attributesOf(p1).version, attributesOf(p2).version)
+}
+
+// compareVersions compares two versions made of dot separated parts, ignoring a leading "v".
+//
+// Numeric parts are compared as numbers, others lexicographically, an empty version is the lowest.
+func compareVersions(v1, v2 string) fn.ComparisonResult {
+	if v1 == v2 {
+		return fn.Equal
+	}
+	parts1 := strings.Split(strings.TrimPrefix(v1, "v"), ".")
+	parts2 := strings.Split(strings.TrimPrefix(v2, "v"), ".")
+	for i := 0; i < len(parts1) && i < len(parts2); i++ {
+		if res := compareVersionParts(parts1[i], parts2[i]); res != fn.Equal {
+			return res
+		}
+	}
+	return compareInts(len(parts1), len(parts2))
+}
+
+func compareVersionParts(p1, p2 string) fn.ComparisonResult {
+	n1, err1 := strconv.Atoi(p1)
+	n2, err2 := strconv.Atoi(p2)
+	if err1 == nil && err2 == nil {
+		return compareInts(n1, n2)
+	}
+	return fn.ComparisonResult(strings.Compare(p1, p2))
+}
+
+func compareInts(i1, i2 int) fn.ComparisonResult {
+	if i1 < i2 {
+		return fn.Less
+	}
+	if i1 > i2 {
+		return fn.Greater
+	}
+	return fn.Equal
+}
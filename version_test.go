@@ -0,0 +1,84 @@
+package godi
+
+import (
+	"testing"
+
+	"github.com/a-peyrard/godi/fn"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolver_Version(t *testing.T) {
+	t.Run("it should resolve the highest version by default", func(t *testing.T) {
+		// GIVEN
+		resolver := New()
+		resolver.MustRegister(ToStaticProvider(&TestService{Name: "v2"}), Named("service"), Version("v2"))
+		resolver.MustRegister(ToStaticProvider(&TestService{Name: "v10"}), Named("service"), Version("v10"))
+		resolver.MustRegister(ToStaticProvider(&TestService{Name: "v1"}), Named("service"), Version("v1"))
+
+		// WHEN
+		service, err := ResolveNamed[*TestService](resolver, "service")
+
+		// THEN
+		require.NoError(t, err)
+		assert.Equal(t, "v10", service.Name)
+	})
+
+	t.Run("it should resolve an explicit version", func(t *testing.T) {
+		// GIVEN
+		resolver := New()
+		resolver.MustRegister(ToStaticProvider(&TestService{Name: "v1"}), Named("service"), Version("v1"))
+		resolver.MustRegister(ToStaticProvider(&TestService{Name: "v2"}), Named("service"), Version("v2"))
+
+		// WHEN
+		v1, err1 := ResolveVersion[*TestService](resolver, "service", "v1")
+		v2, err2 := ResolveVersion[*TestService](resolver, "service", "v2")
+		latest, err3 := ResolveNamed[*TestService](resolver, "service")
+
+		// THEN
+		require.NoError(t, err1)
+		require.NoError(t, err2)
+		require.NoError(t, err3)
+		assert.Equal(t, "v1", v1.Name)
+		assert.Equal(t, "v2", v2.Name)
+		assert.Same(t, v2, latest)
+	})
+
+	t.Run("it should fail if the version does not exist", func(t *testing.T) {
+		// GIVEN
+		resolver := New()
+		resolver.MustRegister(ToStaticProvider(&TestService{Name: "v1"}), Named("service"), Version("v1"))
+
+		// WHEN
+		_, err := ResolveVersion[*TestService](resolver, "service", "v3")
+
+		// THEN
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "no providers found")
+	})
+
+	t.Run("it should show the versions in the description", func(t *testing.T) {
+		// GIVEN
+		resolver := New()
+		resolver.MustRegister(ToStaticProvider(&TestService{Name: "v1"}), Named("service"), Version("v1"))
+
+		// WHEN
+		description := resolver.Describe()
+
+		// THEN
+		assert.Contains(t, description, "version=v1")
+	})
+}
+
+func TestCompareVersions(t *testing.T) {
+	t.Run("it should compare numeric parts as numbers", func(t *testing.T) {
+		assert.Equal(t, fn.Less, compareVersions("v2", "v10"))
+		assert.Equal(t, fn.Greater, compareVersions("1.10.0", "1.9.3"))
+		assert.Equal(t, fn.Less, compareVersions("1.2", "1.2.1"))
+		assert.Equal(t, fn.Equal, compareVersions("v1", "v1"))
+	})
+
+	t.Run("it should consider an empty version as the lowest", func(t *testing.T) {
+		assert.Equal(t, fn.Less, compareVersions("", "v1"))
+	})
+}
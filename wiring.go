@@ -0,0 +1,57 @@
+package godi
+
+import "fmt"
+
+// checkWiring rejects the provider if it would replace a wired component, or if one of its wired components would
+// replace a component, as the components built by a wiring are not injected by the resolver, see Wired.
+func (r *Resolver) checkWiring(provider Provider) error {
+	wiring := attributesOf(provider).wiredBy
+	for _, existing := range r.providers.All() {
+		existingWiring := attributesOf(existing).wiredBy
+		if existingWiring == wiring {
+			continue
+		}
+		if existingWiring != "" && replaces(provider, existing) {
+			return errReplacesWired(provider, existing)
+		}
+		if wiring != "" && replaces(existing, provider) {
+			return errReplacesWired(existing, provider)
+		}
+	}
+	return nil
+}
+
+func errReplacesWired(override Provider, wired Provider) error {
+	return fmt.Errorf(
+		"%s would replace the components of %s, which is wired by %s, generate the registry without -wire to replace them",
+		describeProvider(override), describeProvider(wired), attributesOf(wired).wiredBy,
+	)
+}
+
+// checkNotWired returns an error if the component with the given name is wired, so the action can not be done
+// on it, e.g. refresh, see Wired.
+func (r *Resolver) checkNotWired(action string, name string) error {
+	for _, p := range r.providers.All() {
+		if wiring := attributesOf(p).wiredBy; wiring != "" && providesName(p, name) {
+			return fmt.Errorf("unable to %s %s, it is wired by %s, generate the registry without -wire to %s it", action, name, wiring, action)
+		}
+	}
+	return nil
+}
+
+// replaces checks if the override provides one of the components of the provider, with the same name,
+// or by type if the override is not named, which changes what is injected in place of the component.
+func replaces(override Provider, p Provider) bool {
+	overrideNames := override.ListProvidableNames()
+	for _, n := range p.ListProvidableNames() {
+		if override.CanProvide(n) {
+			return true
+		}
+		for _, o := range overrideNames {
+			if covers(override, o, p, n) || (!isNamed(override, o) && matchType(n.typ, o.typ)) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,124 @@
+package godi
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testWiring struct {
+	service *TestService
+}
+
+// newWiredResolver registers a wiring as the generator does with -wire, the service being taken from it.
+func newWiredResolver(builds *int) *Resolver {
+	resolver := New()
+	resolver.MustRegister(func() *testWiring {
+		*builds++
+		return &testWiring{service: &TestService{Name: "wired"}}
+	}, Named("registry.Registry.wiring"), Hidden())
+	resolver.MustRegister(func(w *testWiring) *TestService {
+		return w.service
+	}, Named("service"), Wired("registry.Registry.wiring"))
+	return resolver
+}
+
+func TestResolver_Wired(t *testing.T) {
+	t.Run("it should not refresh a wired component", func(t *testing.T) {
+		// GIVEN
+		builds := 0
+		resolver := newWiredResolver(&builds)
+		service := MustResolveNamed[*TestService](resolver, "service")
+
+		// WHEN
+		err := resolver.Refresh("service")
+
+		// THEN
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "unable to refresh service, it is wired by registry.Registry.wiring")
+		assert.Same(t, service, MustResolveNamed[*TestService](resolver, "service"))
+		assert.Equal(t, 1, builds)
+	})
+
+	t.Run("it should not evict a wired component", func(t *testing.T) {
+		// GIVEN
+		builds := 0
+		resolver := newWiredResolver(&builds)
+
+		// WHEN
+		err := resolver.Evict("service")
+
+		// THEN
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "unable to evict service, it is wired by registry.Registry.wiring")
+	})
+
+	t.Run("it should reject an override of a wired component", func(t *testing.T) {
+		// GIVEN
+		builds := 0
+		resolver := newWiredResolver(&builds)
+
+		// WHEN
+		_, err := resolver.Override(func() *TestService { return &TestService{Name: "override"} }, Named("service"))
+
+		// THEN
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "which is wired by registry.Registry.wiring")
+		assert.Equal(t, "wired", MustResolveNamed[*TestService](resolver, "service").Name)
+	})
+
+	t.Run("it should reject a provider of a higher priority for a wired component", func(t *testing.T) {
+		// GIVEN
+		builds := 0
+		resolver := newWiredResolver(&builds)
+
+		// WHEN
+		err := resolver.Register(func() *TestService { return &TestService{} }, Named("service"), Priority(10))
+
+		// THEN
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "which is wired by registry.Registry.wiring")
+	})
+
+	t.Run("it should reject a wired component replacing a registered one", func(t *testing.T) {
+		// GIVEN
+		resolver := New()
+		resolver.MustRegister(func() *TestService { return &TestService{} })
+
+		// WHEN
+		err := resolver.Register(func(w *testWiring) *TestService {
+			return w.service
+		}, Named("service"), Wired("registry.Registry.wiring"))
+
+		// THEN
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "which is wired by registry.Registry.wiring")
+	})
+
+	t.Run("it should reject an override of a wired component in a fork", func(t *testing.T) {
+		// GIVEN
+		builds := 0
+		fork := newWiredResolver(&builds).Fork()
+
+		// WHEN
+		err := fork.Register(func() *TestService { return &TestService{} }, Named("service"))
+
+		// THEN
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "which is wired by registry.Registry.wiring")
+	})
+
+	t.Run("it should keep the components of other names", func(t *testing.T) {
+		// GIVEN
+		builds := 0
+		resolver := newWiredResolver(&builds)
+
+		// WHEN
+		err := resolver.Register(func() *TestService { return &TestService{Name: "other"} }, Named("other"))
+
+		// THEN
+		require.NoError(t, err)
+		assert.Equal(t, "other", MustResolveNamed[*TestService](resolver, "other").Name)
+	})
+}